@@ -34,6 +34,8 @@ var (
 	portFlag      int
 	refreshCache  bool
 	cacheMaxAge   int
+	fixturesMode  string
+	fixturesDir   string
 )
 
 func main() {
@@ -66,6 +68,8 @@ to customize behavior:
   NATS_DOCS_SYNCP_ENABLED       Enable Synadia documentation (true/false)
   NATS_DOCS_SYNCP_BASE_URL      Synadia documentation URL
   NATS_DOCS_SYNCP_FETCH_TIMEOUT Synadia fetch timeout in seconds
+  NATS_DOCS_HTTP_FIXTURES_MODE  Record or replay HTTP requests (off, record, replay)
+  NATS_DOCS_HTTP_FIXTURES_DIR   Directory of recorded HTTP requests
 
 Command-line flags override environment variables.
 Optionally provide a config file with --config for convenience.`,
@@ -81,6 +85,8 @@ Optionally provide a config file with --config for convenience.`,
 	rootCmd.Flags().IntVarP(&portFlag, "port", "p", 0, "Port for network transports (SSE, StreamableHTTP)")
 	rootCmd.Flags().BoolVar(&refreshCache, "refresh-cache", false, "Force refresh documentation cache on startup")
 	rootCmd.Flags().IntVar(&cacheMaxAge, "cache-max-age", 0, "Maximum cache age in days (0=use default)")
+	rootCmd.Flags().StringVar(&fixturesMode, "http-fixtures", "", "Record or replay HTTP requests (off, record, replay)")
+	rootCmd.Flags().StringVar(&fixturesDir, "http-fixtures-dir", "", "Directory of recorded HTTP requests")
 
 	// Execute command
 	if err := rootCmd.Execute(); err != nil {
@@ -142,6 +148,19 @@ func runServer(cmd *cobra.Command, args []string) error {
 		cfg.CacheMaxAge = cacheMaxAge
 	}
 
+	// Override HTTP fixture settings from command line flags if provided
+	if fixturesMode != "" || fixturesDir != "" {
+		if fixturesMode != "" {
+			cfg.HTTPFixturesMode = fixturesMode
+		}
+		if fixturesDir != "" {
+			cfg.HTTPFixturesDir = fixturesDir
+		}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid HTTP fixture configuration: %w", err)
+		}
+	}
+
 	// Validate transport configuration
 	if err := cfg.ValidateTransport(); err != nil {
 		return fmt.Errorf("invalid transport configuration: %w", err)
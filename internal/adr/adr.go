@@ -0,0 +1,287 @@
+// Package adr parses the Architecture Decision Records of the
+// nats-io/nats-architecture-and-design repository. Each ADR is a markdown file named
+// ADR-<number>.md that starts with a title, a "Metadata | Value" table (date,
+// authors, status, tags) and optionally a revision history table.
+package adr
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// Metadata keys stored on ADR documents
+const (
+	MetaNumber    = "adr_number"
+	MetaTitle     = "adr_title"
+	MetaStatus    = "adr_status"
+	MetaAuthors   = "adr_authors"
+	MetaTags      = "adr_tags"
+	MetaDate      = "adr_date"
+	MetaUpdated   = "adr_updated"
+	MetaRevisions = "adr_revisions"
+)
+
+// fileRe matches ADR file names and captures the ADR number
+var fileRe = regexp.MustCompile(`(?i)^ADR-0*(\d+)\.md$`)
+
+// numberRe matches an ADR reference such as "ADR-8", "adr 8" or "8"
+var numberRe = regexp.MustCompile(`(?i)^(?:ADR[-\s_]*)?0*(\d+)$`)
+
+// ADR is a parsed Architecture Decision Record
+type ADR struct {
+	Number    int        // ADR number from the file name
+	Title     string     // First level-one heading
+	Status    string     // Status row (e.g., "Approved", "Implemented")
+	Authors   []string   // Author(s) row, split on commas
+	Tags      []string   // Tags row, split on commas
+	Date      string     // Date row
+	Updated   string     // Updated row, if present
+	Revisions []Revision // Revision history table, in document order
+}
+
+// Revision is a row of an ADR's revision history table
+type Revision struct {
+	Revision string `json:"revision"`
+	Date     string `json:"date"`
+	Author   string `json:"author"`
+	Info     string `json:"info"`
+}
+
+// IsADRPath reports whether a repository path is an ADR file (e.g., "adr/ADR-8.md")
+func IsADRPath(p string) bool {
+	return fileRe.MatchString(path.Base(p))
+}
+
+// ParseNumber parses an ADR reference such as "ADR-8", "adr-0008" or "8"
+func ParseNumber(ref string) (int, error) {
+	m := numberRe.FindStringSubmatch(strings.TrimSpace(ref))
+	if m == nil {
+		return 0, fmt.Errorf("invalid ADR number: %q", ref)
+	}
+	return strconv.Atoi(m[1])
+}
+
+// Parse extracts the ADR number, title, metadata table and revision history from
+// the markdown content of the ADR file at filePath.
+func Parse(content []byte, filePath string) (*ADR, error) {
+	m := fileRe.FindStringSubmatch(path.Base(filePath))
+	if m == nil {
+		return nil, fmt.Errorf("not an ADR file: %s", filePath)
+	}
+	number, err := strconv.Atoi(m[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid ADR number in %s: %w", filePath, err)
+	}
+
+	a := &ADR{Number: number}
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if a.Title == "" && strings.HasPrefix(line, "# ") {
+			a.Title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+			continue
+		}
+
+		if !isTableRow(line) {
+			continue
+		}
+
+		// Collect the whole table starting at this line
+		var rows [][]string
+		for ; i < len(lines) && isTableRow(strings.TrimSpace(lines[i])); i++ {
+			row := strings.TrimSpace(lines[i])
+			if isDelimiterRow(row) {
+				continue
+			}
+			rows = append(rows, splitRow(row))
+		}
+
+		switch {
+		case isMetadataTable(rows):
+			a.applyMetadata(rows[1:])
+		case isRevisionTable(rows):
+			a.Revisions = append(a.Revisions, parseRevisions(rows)...)
+		}
+	}
+
+	if a.Title == "" {
+		a.Title = fmt.Sprintf("ADR-%d", number)
+	}
+
+	return a, nil
+}
+
+// applyMetadata sets the ADR fields from the rows of a "Metadata | Value" table
+func (a *ADR) applyMetadata(rows [][]string) {
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		value := row[1]
+		switch strings.ToLower(strings.Trim(row[0], "* ")) {
+		case "date":
+			a.Date = value
+		case "updated":
+			a.Updated = value
+		case "author", "authors", "author(s)":
+			a.Authors = splitList(value)
+		case "status":
+			a.Status = value
+		case "tags", "tag":
+			a.Tags = splitList(value)
+		}
+	}
+}
+
+// isMetadataTable reports whether a table's header is "Metadata | Value"
+func isMetadataTable(rows [][]string) bool {
+	return len(rows) > 0 && len(rows[0]) >= 2 && strings.EqualFold(rows[0][0], "metadata")
+}
+
+// isRevisionTable reports whether a table's header starts with a "Revision" column
+func isRevisionTable(rows [][]string) bool {
+	return len(rows) > 0 && len(rows[0]) >= 2 && strings.EqualFold(rows[0][0], "revision")
+}
+
+// parseRevisions maps revision table rows to revisions by header name
+func parseRevisions(rows [][]string) []Revision {
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.ToLower(name)] = i
+	}
+	cell := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	var revisions []Revision
+	for _, row := range rows[1:] {
+		rev := Revision{
+			Revision: cell(row, "revision"),
+			Date:     cell(row, "date"),
+			Author:   cell(row, "author"),
+			Info:     cell(row, "info"),
+		}
+		if rev.Author == "" {
+			rev.Author = cell(row, "authors")
+		}
+		if rev.Info == "" {
+			rev.Info = cell(row, "description")
+		}
+		if rev.Revision == "" && rev.Info == "" {
+			continue
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions
+}
+
+// isTableRow reports whether a trimmed line is a markdown table row
+func isTableRow(line string) bool {
+	return strings.HasPrefix(line, "|")
+}
+
+// isDelimiterRow reports whether a table row is the header delimiter (e.g., "|---|:--|")
+func isDelimiterRow(line string) bool {
+	return strings.Trim(line, "|-: \t") == ""
+}
+
+// splitRow splits a markdown table row into trimmed cells
+func splitRow(line string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	cells := strings.Split(line, "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+	return cells
+}
+
+// splitList splits a comma-separated cell into trimmed, non-empty values
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Name returns the ADR's canonical name (e.g., "ADR-8")
+func (a *ADR) Name() string {
+	return fmt.Sprintf("ADR-%d", a.Number)
+}
+
+// HasStatus reports whether the ADR's status matches status, ignoring case.
+// A status such as "Partially Implemented" also matches "implemented".
+func (a *ADR) HasStatus(status string) bool {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status == "" {
+		return true
+	}
+	own := strings.ToLower(a.Status)
+	if own == status {
+		return true
+	}
+	for _, word := range strings.Fields(own) {
+		if word == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Metadata returns the ADR fields as document metadata
+func (a *ADR) Metadata() map[string]string {
+	meta := map[string]string{
+		MetaNumber:  strconv.Itoa(a.Number),
+		MetaTitle:   a.Title,
+		MetaStatus:  a.Status,
+		MetaAuthors: strings.Join(a.Authors, ", "),
+		MetaTags:    strings.Join(a.Tags, ", "),
+		MetaDate:    a.Date,
+		MetaUpdated: a.Updated,
+	}
+	if len(a.Revisions) > 0 {
+		if data, err := json.Marshal(a.Revisions); err == nil {
+			meta[MetaRevisions] = string(data)
+		}
+	}
+	return meta
+}
+
+// FromDocument restores an ADR from a document's metadata. It returns nil when the
+// document is not an ADR.
+func FromDocument(doc *index.Document) *ADR {
+	if doc == nil || doc.Metadata == nil {
+		return nil
+	}
+	number, err := strconv.Atoi(doc.Metadata[MetaNumber])
+	if err != nil {
+		return nil
+	}
+
+	a := &ADR{
+		Number:  number,
+		Title:   doc.Metadata[MetaTitle],
+		Status:  doc.Metadata[MetaStatus],
+		Authors: splitList(doc.Metadata[MetaAuthors]),
+		Tags:    splitList(doc.Metadata[MetaTags]),
+		Date:    doc.Metadata[MetaDate],
+		Updated: doc.Metadata[MetaUpdated],
+	}
+	if data := doc.Metadata[MetaRevisions]; data != "" {
+		_ = json.Unmarshal([]byte(data), &a.Revisions)
+	}
+	return a
+}
@@ -0,0 +1,163 @@
+package adr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+const testADR = `# JetStream Direct Get
+
+|Metadata|Value|
+|--------|-----|
+|Date    |2022-08-03|
+|Author  |@ripienaar, @tbeets|
+|Status  |Partially Implemented|
+|Tags    |jetstream, client, server|
+|Updated |2023-01-20|
+
+| Revision | Date       | Author     | Info                    |
+|----------|------------|------------|-------------------------|
+| 1        | 2022-08-03 | @tbeets    | Initial design          |
+| 2        | 2023-01-20 | @ripienaar | Add batch requests      |
+
+## Context and Problem Statement
+
+Reading messages from a stream should not go through the leader.
+`
+
+func TestIsADRPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"adr/ADR-31.md", true},
+		{"adr/adr-0008.md", true},
+		{"ADR-1.md", true},
+		{"adr/README.md", false},
+		{"adr/ADR-31.txt", false},
+		{"docs/ADR-template.md", false},
+	}
+	for _, tt := range tests {
+		if got := IsADRPath(tt.path); got != tt.want {
+			t.Errorf("IsADRPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    int
+		wantErr bool
+	}{
+		{ref: "8", want: 8},
+		{ref: "ADR-8", want: 8},
+		{ref: "adr 0031", want: 31},
+		{ref: " ADR_12 ", want: 12},
+		{ref: "jetstream", wantErr: true},
+		{ref: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseNumber(tt.ref)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseNumber(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseNumber(%q) = %d, want %d", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	a, err := Parse([]byte(testADR), "adr/ADR-31.md")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	want := &ADR{
+		Number:  31,
+		Title:   "JetStream Direct Get",
+		Status:  "Partially Implemented",
+		Authors: []string{"@ripienaar", "@tbeets"},
+		Tags:    []string{"jetstream", "client", "server"},
+		Date:    "2022-08-03",
+		Updated: "2023-01-20",
+		Revisions: []Revision{
+			{Revision: "1", Date: "2022-08-03", Author: "@tbeets", Info: "Initial design"},
+			{Revision: "2", Date: "2023-01-20", Author: "@ripienaar", Info: "Add batch requests"},
+		},
+	}
+	if !reflect.DeepEqual(a, want) {
+		t.Errorf("Parse() = %+v, want %+v", a, want)
+	}
+
+	if _, err := Parse([]byte(testADR), "adr/README.md"); err == nil {
+		t.Error("expected error for non-ADR path")
+	}
+}
+
+func TestParseWithoutMetadata(t *testing.T) {
+	a, err := Parse([]byte("Some text without a heading.\n"), "adr/ADR-2.md")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if a.Title != "ADR-2" || a.Status != "" || a.Revisions != nil {
+		t.Errorf("unexpected ADR: %+v", a)
+	}
+}
+
+func TestHasStatus(t *testing.T) {
+	a := &ADR{Status: "Partially Implemented"}
+	for status, want := range map[string]bool{
+		"":                      true,
+		"partially implemented": true,
+		"Implemented":           true,
+		"approved":              false,
+	} {
+		if got := a.HasStatus(status); got != want {
+			t.Errorf("HasStatus(%q) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	a, err := Parse([]byte(testADR), "adr/ADR-31.md")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	docs := []*index.Document{
+		{ID: "nats-architecture-and-design/adr/ADR-31.md", Title: "ADR-31", Metadata: a.Metadata(), LastUpdated: time.Now()},
+		{ID: "nats-architecture-and-design/README.md", Title: "README", LastUpdated: time.Now()},
+	}
+	catalog := NewCatalog(docs)
+
+	if catalog.Count() != 1 {
+		t.Fatalf("expected 1 ADR, got %d", catalog.Count())
+	}
+
+	record := catalog.Get(31)
+	if record == nil {
+		t.Fatal("expected ADR-31 in catalog")
+	}
+	if !reflect.DeepEqual(record.ADR, a) {
+		t.Errorf("restored ADR = %+v, want %+v", record.ADR, a)
+	}
+	if record.Document != docs[0] {
+		t.Error("expected record to reference its document")
+	}
+
+	if catalog.Get(1) != nil {
+		t.Error("expected no ADR-1")
+	}
+	if catalog.ByDocumentID(docs[0].ID) != record {
+		t.Error("expected lookup by document ID")
+	}
+	if catalog.ByDocumentID(docs[1].ID) != nil {
+		t.Error("expected README not to be an ADR")
+	}
+}
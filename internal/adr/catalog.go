@@ -0,0 +1,66 @@
+package adr
+
+import (
+	"sort"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// Record pairs a parsed ADR with the document it was indexed as
+type Record struct {
+	ADR      *ADR
+	Document *index.Document
+}
+
+// Catalog resolves ADR numbers and document IDs to ADRs. It is built from index
+// documents so it can be restored from the documentation cache, and is immutable
+// once created so it is safe for concurrent use.
+type Catalog struct {
+	records  []*Record
+	byNumber map[int]*Record
+	byDocID  map[string]*Record
+}
+
+// NewCatalog creates a catalog from index documents. Documents without ADR
+// metadata are ignored. When several documents share a number the first one wins.
+func NewCatalog(docs []*index.Document) *Catalog {
+	c := &Catalog{
+		byNumber: make(map[int]*Record),
+		byDocID:  make(map[string]*Record),
+	}
+	for _, doc := range docs {
+		a := FromDocument(doc)
+		if a == nil {
+			continue
+		}
+		record := &Record{ADR: a, Document: doc}
+		c.byDocID[doc.ID] = record
+		if _, exists := c.byNumber[a.Number]; exists {
+			continue
+		}
+		c.byNumber[a.Number] = record
+		c.records = append(c.records, record)
+	}
+
+	sort.Slice(c.records, func(i, j int) bool {
+		return c.records[i].ADR.Number < c.records[j].ADR.Number
+	})
+
+	return c
+}
+
+// Count returns the number of ADRs in the catalog
+func (c *Catalog) Count() int {
+	return len(c.records)
+}
+
+// Get returns the ADR with the given number, or nil if there is none
+func (c *Catalog) Get(number int) *Record {
+	return c.byNumber[number]
+}
+
+// ByDocumentID returns the ADR indexed under a document ID, or nil if the document
+// is not an ADR
+func (c *Catalog) ByDocumentID(id string) *Record {
+	return c.byDocID[id]
+}
@@ -8,12 +8,13 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
 	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
 )
 
 const (
 	// cacheVersion is the current cache format version
-	cacheVersion = "1.0"
+	cacheVersion = "1.4"
 	// cacheDirPermissions is the permissions for the cache directory
 	cacheDirPermissions = 0755
 	// cacheFilePermissions is the permissions for cache files
@@ -28,6 +29,13 @@ type CachedDocuments struct {
 	CachedAt      time.Time         `json:"cached_at"`
 	DocumentCount int               `json:"document_count"`
 	Documents     []*index.Document `json:"documents"`
+
+	// Validators maps each fetched page or file to the values used to revalidate it,
+	// so refreshes only download and re-parse what changed
+	Validators map[string]fetcher.Validator `json:"validators,omitempty"`
+
+	// Failures lists the pages that failed to fetch, so the next fetch retries them first
+	Failures []fetcher.PageFailure `json:"failures,omitempty"`
 }
 
 // Cache handles reading/writing documentation cache to disk
@@ -66,6 +74,18 @@ func (c *Cache) getCachePath(source string) string {
 
 // Save persists documentation to cache with atomic writes
 func (c *Cache) Save(source string, sourceURL string, docs []*index.Document) error {
+	return c.SaveWithValidators(source, sourceURL, docs, nil)
+}
+
+// SaveWithValidators persists documentation to cache like Save, along with the
+// validators of the pages or files the documents were parsed from
+func (c *Cache) SaveWithValidators(source string, sourceURL string, docs []*index.Document, validators map[string]fetcher.Validator) error {
+	return c.SaveWithFailures(source, sourceURL, docs, validators, nil)
+}
+
+// SaveWithFailures persists documentation to cache like SaveWithValidators, along with
+// the pages that failed to fetch
+func (c *Cache) SaveWithFailures(source string, sourceURL string, docs []*index.Document, validators map[string]fetcher.Validator, failures []fetcher.PageFailure) error {
 	if source == "" {
 		return fmt.Errorf("source cannot be empty")
 	}
@@ -78,6 +98,8 @@ func (c *Cache) Save(source string, sourceURL string, docs []*index.Document) er
 		CachedAt:      time.Now(),
 		DocumentCount: len(docs),
 		Documents:     docs,
+		Validators:    validators,
+		Failures:      failures,
 	}
 
 	// Marshal to JSON with indentation
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
 	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
 )
 
@@ -432,3 +433,51 @@ func TestCacheAtomicWrite(t *testing.T) {
 func marshalForTesting(cached *CachedDocuments) ([]byte, error) {
 	return json.MarshalIndent(cached, "", "  ")
 }
+
+func TestCacheSaveWithValidators(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	c, _ := NewCache(tmpDir, logger)
+
+	docs := []*index.Document{{ID: "page", Title: "Page"}}
+	validators := map[string]fetcher.Validator{
+		"/page": {ETag: `"abc"`, LastMod: "2024-01-01"},
+	}
+
+	if err := c.SaveWithValidators("nats", "https://docs.nats.io", docs, validators); err != nil {
+		t.Fatalf("SaveWithValidators failed: %v", err)
+	}
+
+	cached, err := c.Load("nats")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := cached.Validators["/page"]; got != validators["/page"] {
+		t.Errorf("Validator mismatch: got %+v, want %+v", got, validators["/page"])
+	}
+}
+
+func TestCacheSaveWithFailures(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	c, _ := NewCache(tmpDir, logger)
+
+	docs := []*index.Document{{ID: "page", Title: "Page"}}
+	failures := []fetcher.PageFailure{
+		{Path: "/broken", URL: "https://docs.nats.io/broken", Error: "unexpected status code: HTTP 404"},
+	}
+
+	if err := c.SaveWithFailures("nats", "https://docs.nats.io", docs, nil, failures); err != nil {
+		t.Fatalf("SaveWithFailures failed: %v", err)
+	}
+
+	cached, err := c.Load("nats")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(cached.Failures) != 1 || cached.Failures[0] != failures[0] {
+		t.Errorf("Failures mismatch: got %+v, want %+v", cached.Failures, failures)
+	}
+}
@@ -4,12 +4,19 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
+	"path"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/j4ng5y/nats-docs-mcp-server/internal/classifier"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/parser"
 	"github.com/spf13/viper"
 )
 
@@ -20,32 +27,95 @@ type Config struct {
 	LogLevel string // Log level: debug, info, warn, error (default: info)
 
 	// Documentation settings
-	DocsBaseURL    string // Base URL for NATS documentation (default: https://docs.nats.io)
-	FetchTimeout   int    // Timeout for fetching documentation in seconds (default: 30)
-	MaxConcurrent  int    // Maximum concurrent fetches (default: 5)
-	CacheDir       string // Directory for caching fetched documentation (default: ~/.cache/nats-mcp)
-	CacheMaxAge    int    // Maximum age of cache in days before auto-refresh (default: 7)
-	RefreshCache   bool   // Force refresh cache on startup (default: false)
+	DocsBaseURL         string           // Base URL for NATS documentation (default: https://docs.nats.io)
+	FetchTimeout        int              // Timeout for fetching documentation in seconds (default: 30)
+	MaxConcurrent       int              // Maximum requests in flight at once (default: 5)
+	RequestsPerSecond   float64          // Maximum requests per second across all sources (default: 10)
+	MaxPerHost          int              // Maximum requests in flight per host; 0 means max_concurrent (default: 0)
+	CacheDir            string           // Directory for caching fetched documentation (default: ~/.cache/nats-mcp)
+	CacheMaxAge         int              // Maximum age of cache in days before auto-refresh (default: 7)
+	RefreshCache        bool             // Force refresh cache on startup (default: false)
+	DocsIncludePaths    []string         // Globs of NATS documentation page paths to fetch; every page when empty
+	DocsExcludePaths    []string         // Globs of NATS documentation page paths to skip
+	DocsDiscovery       string           // How NATS documentation pages are discovered: auto, sitemap or crawl (default: auto)
+	DocsCrawlMaxDepth   int              // Maximum links followed from the NATS base URL when crawling (default: 5)
+	DocsCrawlMaxPages   int              // Maximum NATS pages discovered when crawling (default: 1000)
+	DocsMinSuccessRatio float64          // Share of NATS pages that must be fetched to use partial results (default: 0.9)
+	DocsNetwork         NetworkConfig    // Proxy and TLS settings for NATS documentation requests
+	DocsAuth            AuthConfig       // Credentials for NATS documentation requests
+	DocsExtraction      ExtractionConfig // Which parts of NATS documentation pages are indexed
 
 	// Search settings
 	MaxSearchResults int // Maximum number of search results to return (default: 50)
 
+	// HTTP fixture settings, for offline development and reproducible tests
+	HTTPFixturesMode string // Whether requests are recorded or replayed: off, record or replay (default: off)
+	HTTPFixturesDir  string // Directory of recorded request/response pairs; required unless off
+
+	// Circuit breaker settings, applied per host across all sources
+	BreakerEnabled        bool    // Fail fast on hosts whose requests keep failing (default: true)
+	BreakerFailureRatio   float64 // Share of failed attempts that opens a host's breaker (default: 0.5)
+	BreakerMinRequests    int     // Attempts counted before a breaker may open (default: 5)
+	BreakerWindow         int     // Recent attempts counted per host (default: 20)
+	BreakerCoolDown       int     // Seconds an open breaker rejects requests before probing (default: 30)
+	BreakerHalfOpenProbes int     // Probe requests let through after the cool-down (default: 1)
+
 	// Transport settings
 	TransportType string // Transport type: stdio, sse, streamablehttp (default: stdio)
 	Host          string // Host to bind for network transports (default: localhost)
 	Port          int    // Port to bind for network transports (default: 0)
 
 	// Synadia documentation settings
-	SynadiaEnabled      bool     // Enable Synadia documentation support (default: false)
-	SynadiaBaseURL      string   // Base URL for Synadia documentation (default: https://docs.synadia.com)
-	SynadiaFetchTimeout int      // Timeout for fetching Synadia documentation in seconds (default: 30)
+	SynadiaEnabled         bool             // Enable Synadia documentation support (default: false)
+	SynadiaBaseURL         string           // Base URL for Synadia documentation (default: https://docs.synadia.com)
+	SynadiaFetchTimeout    int              // Timeout for fetching Synadia documentation in seconds (default: 30)
+	SynadiaIncludePaths    []string         // Globs of Synadia documentation page paths to fetch; every page when empty
+	SynadiaExcludePaths    []string         // Globs of Synadia documentation page paths to skip
+	SynadiaDiscovery       string           // How Synadia documentation pages are discovered: auto, sitemap or crawl (default: auto)
+	SynadiaCrawlMaxDepth   int              // Maximum links followed from the Synadia base URL when crawling (default: 5)
+	SynadiaCrawlMaxPages   int              // Maximum Synadia pages discovered when crawling (default: 1000)
+	SynadiaMinSuccessRatio float64          // Share of Synadia pages that must be fetched to use partial results (default: 0.9)
+	SynadiaNetwork         NetworkConfig    // Proxy and TLS settings for Synadia documentation requests
+	SynadiaAuth            AuthConfig       // Credentials for Synadia documentation requests
+	SynadiaExtraction      ExtractionConfig // Which parts of Synadia documentation pages are indexed
 
 	// GitHub documentation settings
-	GitHubEnabled      bool     // Enable GitHub documentation support (default: false)
-	GitHubToken        string   // GitHub Personal Access Token for authentication
-	GitHubRepositories []string // GitHub repositories to index (default: nats-io/nats-server, nats-io/nats.docs, nats-io/nats)
-	GitHubBranch       string   // Default branch to fetch from (default: main)
-	GitHubFetchTimeout int      // Timeout for fetching GitHub documentation in seconds (default: 30)
+	GitHubEnabled         bool               // Enable GitHub documentation support (default: false)
+	GitHubToken           string             // GitHub Personal Access Token for authentication
+	GitHubRepositories    []GitHubRepository // GitHub repositories to index (default: nats-io/nats-server, nats-io/nats.docs, nats-io/nats)
+	GitHubBranch          string             // Default branch to fetch from (default: main)
+	GitHubFetchTimeout    int                // Timeout for fetching GitHub documentation in seconds (default: 30)
+	GitHubFetchStrategy   string             // How repository files are fetched: api or archive (default: api)
+	GitHubMinSuccessRatio float64            // Share of repository files that must be fetched to use partial results (default: 0.9)
+	GitHubForges          []RepositoryForge  // Forge settings of repositories hosted outside github.com
+	GitHubNetwork         NetworkConfig      // Proxy and TLS settings for repository requests
+
+	// JetStream API schema settings
+	JetStreamSchemasEnabled    bool   // Enable JetStream API JSON Schema indexing (default: false)
+	JetStreamSchemasPath       string // Local directory with schema files; takes precedence over the repository
+	JetStreamSchemasRepository string // GitHub repository publishing the schemas (default: nats-io/jsm.go)
+	JetStreamSchemasRef        string // Branch or tag to fetch schemas from (default: main)
+	JetStreamSchemasDir        string // Directory of the schemas within the repository (default: schemas/jetstream/api/v1)
+
+	// Go API reference settings
+	GoAPIEnabled bool       // Enable Go API reference indexing (default: false)
+	GoAPIModules []GoModule // Local Go source trees to index (e.g., a nats.go checkout)
+
+	// NATS error definition settings
+	ErrorsEnabled          bool   // Enable NATS error definition indexing (default: false)
+	ErrorsServerPath       string // Local errors.json file; takes precedence over the server repository
+	ErrorsServerRepository string // GitHub repository with the server error definitions (default: nats-io/nats-server)
+	ErrorsServerRef        string // Branch or tag to fetch server error definitions from (default: main)
+	ErrorsServerFile       string // Path of the definitions within the repository (default: server/errors.json)
+	ErrorsClientPath       string // Local client library checkout; takes precedence over the client repository
+	ErrorsClientRepository string // GitHub repository with client error variables (default: nats-io/nats.go)
+	ErrorsClientRef        string // Branch or tag to fetch client sources from (default: main)
+
+	// Release notes settings
+	ReleaseNotesEnabled      bool     // Enable release notes indexing (default: false)
+	ReleaseNotesPath         string   // Local mirror of release notes; takes precedence over the repositories
+	ReleaseNotesRepositories []string // GitHub repositories whose releases are indexed (default: nats-io/nats-server)
+	ReleaseNotesRef          string   // Branch or tag to read CHANGELOG files from when a repository has no releases (default: main)
 
 	// Classification keywords
 	SynadiaKeywords []string // Keywords that classify queries as Synadia-specific
@@ -53,6 +123,218 @@ type Config struct {
 	GitHubKeywords []string // Keywords that classify queries as GitHub-specific
 }
 
+// GoModule identifies a local Go source tree indexed as Go API reference.
+type GoModule struct {
+	Path       string `mapstructure:"path"`        // Local directory of the module or vendored package tree
+	ImportPath string `mapstructure:"import_path"` // Import path of Path; read from Path/go.mod when empty
+}
+
+// NetworkConfig holds the proxy and TLS settings of one documentation source, for
+// networks that only reach the internet through a proxy or intercept TLS. Unset
+// fields keep the defaults: HTTP_PROXY, HTTPS_PROXY and NO_PROXY from the
+// environment, the system certificate pool and TLS 1.2.
+type NetworkConfig struct {
+	ProxyURL      string   `mapstructure:"proxy_url"`       // Proxy for every request (http, https or socks5); overrides HTTP(S)_PROXY
+	NoProxy       []string `mapstructure:"no_proxy"`        // Hosts, domains (".example.com") or CIDR ranges reached directly; overrides NO_PROXY
+	CAFiles       []string `mapstructure:"ca_files"`        // PEM files of certificate authorities trusted in addition to the system pool
+	ClientCert    string   `mapstructure:"client_cert"`     // PEM client certificate presented for mutual TLS
+	ClientKey     string   `mapstructure:"client_key"`      // PEM private key of ClientCert
+	TLSMinVersion string   `mapstructure:"tls_min_version"` // Minimum TLS version: 1.0, 1.1, 1.2 or 1.3
+}
+
+// AuthConfig holds the credentials of a documentation site that requires them. Secrets
+// are never read from configuration files: they come from environment variables
+// (e.g., NATS_DOCS_SYNADIA_AUTH_PASSWORD) or from the files named by the *_file keys,
+// and Resolve loads them.
+type AuthConfig struct {
+	Username        string            `mapstructure:"username"`          // Basic auth user name
+	Password        string            `mapstructure:"-"`                 // Basic auth password; environment only
+	PasswordFile    string            `mapstructure:"password_file"`     // File holding the basic auth password
+	BearerToken     string            `mapstructure:"-"`                 // Bearer token; environment only
+	BearerTokenFile string            `mapstructure:"bearer_token_file"` // File holding the bearer token
+	Headers         map[string]string `mapstructure:"headers"`           // Non-secret static headers (e.g., a tenant ID)
+	HeadersFile     string            `mapstructure:"headers_file"`      // File of "Name: value" lines for secret headers (e.g., an API key)
+	CookieFile      string            `mapstructure:"cookie_file"`       // Netscape cookies.txt file seeding the cookie jar
+}
+
+// ExtractionConfig selects the content of a documentation site's HTML pages, leaving
+// out navigation, sidebars, footers and other site chrome. The built-in profile of
+// the site's host is used unless Profile names another; the other fields adjust it.
+type ExtractionConfig struct {
+	Profile       string   `mapstructure:"profile"`        // Built-in profile: auto, nats-docs, synadia-docs or none (default: auto)
+	Content       []string `mapstructure:"content"`        // Selectors of the content root, tried in order; replace the profile's
+	Remove        []string `mapstructure:"remove"`         // Selectors of elements dropped in addition to the profile's
+	TitleSuffixes []string `mapstructure:"title_suffixes"` // Site names stripped from page titles in addition to the profile's
+}
+
+// ParserProfile returns the extraction profile of the site at baseURL
+func (e ExtractionConfig) ParserProfile(baseURL string) parser.Profile {
+	profile := parser.ProfileForURL(baseURL)
+	if e.Profile != "" && e.Profile != "auto" {
+		profile, _ = parser.BuiltinProfile(e.Profile)
+	}
+	if len(e.Content) > 0 {
+		profile.Content = e.Content
+	}
+	profile.Remove = append(append([]string(nil), profile.Remove...), e.Remove...)
+	profile.TitleSuffixes = append(append([]string(nil), profile.TitleSuffixes...), e.TitleSuffixes...)
+	return profile
+}
+
+// validate checks the profile name and selectors. Errors are prefixed with key.
+func (e ExtractionConfig) validate(key, baseURL string) []string {
+	names := parser.BuiltinProfileNames()
+	if _, ok := parser.BuiltinProfile(e.Profile); e.Profile != "" && e.Profile != "auto" && !ok {
+		return []string{fmt.Sprintf("%s.profile must be auto or one of %s, got: %s", key, strings.Join(names, ", "), e.Profile)}
+	}
+	if err := e.ParserProfile(baseURL).Validate(); err != nil {
+		return []string{fmt.Sprintf("%s has an invalid selector: %v", key, err)}
+	}
+	return nil
+}
+
+// IsZero reports whether no credential is configured
+func (a AuthConfig) IsZero() bool {
+	return a.Username == "" && a.Password == "" && a.PasswordFile == "" &&
+		a.BearerToken == "" && a.BearerTokenFile == "" && len(a.Headers) == 0 &&
+		a.HeadersFile == "" && a.CookieFile == ""
+}
+
+// Resolve returns a copy of a with the secrets of PasswordFile, BearerTokenFile and
+// HeadersFile loaded into Password, BearerToken and Headers. Trailing newlines of
+// secret files are dropped.
+func (a AuthConfig) Resolve() (AuthConfig, error) {
+	if a.PasswordFile != "" {
+		password, err := readSecretFile(a.PasswordFile)
+		if err != nil {
+			return a, fmt.Errorf("password_file: %w", err)
+		}
+		a.Password = password
+	}
+	if a.BearerTokenFile != "" {
+		token, err := readSecretFile(a.BearerTokenFile)
+		if err != nil {
+			return a, fmt.Errorf("bearer_token_file: %w", err)
+		}
+		a.BearerToken = token
+	}
+
+	headers := make(map[string]string, len(a.Headers))
+	for name, value := range a.Headers {
+		headers[name] = value
+	}
+	if a.HeadersFile != "" {
+		content, err := readSecretFile(a.HeadersFile)
+		if err != nil {
+			return a, fmt.Errorf("headers_file: %w", err)
+		}
+		for i, line := range strings.Split(content, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			name, value, ok := strings.Cut(line, ":")
+			if !ok {
+				return a, fmt.Errorf("headers_file line %d must be in format 'Name: value'", i+1)
+			}
+			headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+	a.Headers = headers
+
+	return a, nil
+}
+
+// String describes the credentials without revealing any secret
+func (a AuthConfig) String() string {
+	var parts []string
+	if a.Username != "" {
+		parts = append(parts, "username="+a.Username)
+	}
+	if a.Password != "" || a.PasswordFile != "" {
+		parts = append(parts, "password=[REDACTED]")
+	}
+	if a.BearerToken != "" || a.BearerTokenFile != "" {
+		parts = append(parts, "bearer_token=[REDACTED]")
+	}
+	if len(a.Headers) > 0 || a.HeadersFile != "" {
+		names := make([]string, 0, len(a.Headers))
+		for name := range a.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if a.HeadersFile != "" {
+			names = append(names, "[headers_file]")
+		}
+		parts = append(parts, "headers="+strings.Join(names, ","))
+	}
+	if a.CookieFile != "" {
+		parts = append(parts, "cookie_file="+a.CookieFile)
+	}
+	return strings.Join(parts, " ")
+}
+
+// LogValue logs the credentials without revealing any secret
+func (a AuthConfig) LogValue() slog.Value {
+	return slog.StringValue(a.String())
+}
+
+// GitHubRepository is a repository indexed as GitHub documentation. In configuration
+// files an entry is either an "owner/repo[@ref]" string or a mapping of these fields.
+type GitHubRepository struct {
+	Owner       string   `mapstructure:"owner"`        // Repository owner (e.g., nats-io)
+	Name        string   `mapstructure:"name"`         // Repository name (e.g., nats-server)
+	Ref         string   `mapstructure:"ref"`          // Branch or tag indexed as the default version (default: github.default_branch)
+	Include     []string `mapstructure:"include"`      // Globs of files to index, relative to DocRoot; every documentation file when empty
+	Exclude     []string `mapstructure:"exclude"`      // Globs of files to skip, relative to DocRoot
+	DocRoot     string   `mapstructure:"doc_root"`     // Directory holding the documentation; the whole repository when empty
+	DisplayName string   `mapstructure:"display_name"` // Name shown with search results and retrieved documents
+	Versions    []string `mapstructure:"versions"`     // Additional branches or tags of this repository indexed as separate versions
+}
+
+// FullName returns the repository in "owner/repo" format
+func (r GitHubRepository) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// ParseGitHubRepository parses an "owner/repo" or "owner/repo@ref" string. A string
+// without a slash yields an empty owner, which Validate reports.
+func ParseGitHubRepository(spec string) GitHubRepository {
+	spec = strings.TrimSpace(spec)
+	var repo GitHubRepository
+	if at := strings.LastIndex(spec, "@"); at >= 0 {
+		spec, repo.Ref = spec[:at], spec[at+1:]
+	}
+	if owner, name, ok := strings.Cut(spec, "/"); ok {
+		repo.Owner, repo.Name = owner, name
+	} else {
+		repo.Name = spec
+	}
+	return repo
+}
+
+// RepositoryForge describes where a repository is hosted when it is not on github.com,
+// e.g. on GitHub Enterprise Server, Gitea or GitLab. It applies wherever the repository
+// is configured (GitHub documentation, JetStream schemas, error definitions and release notes).
+type RepositoryForge struct {
+	Repository string `mapstructure:"repository"` // Repository in "owner/repo" format
+	Type       string `mapstructure:"type"`       // Forge type: github, gitea or gitlab (default: github)
+	APIURL     string `mapstructure:"api_url"`    // Base URL of the REST API (e.g., https://ghe.example.com/api/v3)
+	WebURL     string `mapstructure:"web_url"`    // File URL template with {owner}, {repo}, {ref} and {path}; derived from api_url when empty
+	Token      string `mapstructure:"token"`      // Token for api_url; github.token is only sent to api.github.com
+}
+
+// ForgeFor returns the forge settings of an "owner/repo" repository, matched
+// case-insensitively, and whether any are configured
+func (c *Config) ForgeFor(repository string) (RepositoryForge, bool) {
+	for _, forge := range c.GitHubForges {
+		if strings.EqualFold(forge.Repository, repository) {
+			return forge, true
+		}
+	}
+	return RepositoryForge{}, false
+}
+
 // NewConfig creates a new Config with default values for all optional parameters.
 // This ensures that the server can run with sensible defaults without requiring
 // explicit configuration.
@@ -62,36 +344,91 @@ func NewConfig() *Config {
 		LogLevel: "info",
 
 		// Documentation defaults
-		DocsBaseURL:   "https://docs.nats.io",
-		FetchTimeout:  30,
-		MaxConcurrent: 5,
-		CacheDir:      "",
-		CacheMaxAge:   7,
-		RefreshCache:  false,
+		DocsBaseURL:         "https://docs.nats.io",
+		FetchTimeout:        30,
+		MaxConcurrent:       5,
+		RequestsPerSecond:   10,
+		MaxPerHost:          0,
+		CacheDir:            "",
+		CacheMaxAge:         7,
+		RefreshCache:        false,
+		DocsIncludePaths:    nil,
+		DocsExcludePaths:    nil,
+		DocsDiscovery:       "auto",
+		DocsCrawlMaxDepth:   5,
+		DocsCrawlMaxPages:   1000,
+		DocsMinSuccessRatio: 0.9,
 
 		// Search defaults
 		MaxSearchResults: 50,
 
+		// HTTP fixture defaults
+		HTTPFixturesMode: "off",
+
+		// Circuit breaker defaults
+		BreakerEnabled:        true,
+		BreakerFailureRatio:   0.5,
+		BreakerMinRequests:    5,
+		BreakerWindow:         20,
+		BreakerCoolDown:       30,
+		BreakerHalfOpenProbes: 1,
+
 		// Transport defaults
 		TransportType: "stdio",
 		Host:          "localhost",
 		Port:          0,
 
 		// Synadia defaults
-		SynadiaEnabled:      false, // Disabled by default for backward compatibility
-		SynadiaBaseURL:      "https://docs.synadia.com",
-		SynadiaFetchTimeout: 30,
+		SynadiaEnabled:         false, // Disabled by default for backward compatibility
+		SynadiaBaseURL:         "https://docs.synadia.com",
+		SynadiaFetchTimeout:    30,
+		SynadiaIncludePaths:    nil,
+		SynadiaExcludePaths:    nil,
+		SynadiaDiscovery:       "auto",
+		SynadiaCrawlMaxDepth:   5,
+		SynadiaCrawlMaxPages:   1000,
+		SynadiaMinSuccessRatio: 0.9,
 
 		// GitHub defaults
 		GitHubEnabled: false, // Disabled by default
 		GitHubToken:  "",
-		GitHubRepositories: []string{
-			"nats-io/nats-server",
-			"nats-io/nats.docs",
-			"nats-io/nats",
+		GitHubRepositories: []GitHubRepository{
+			{Owner: "nats-io", Name: "nats-server"},
+			{Owner: "nats-io", Name: "nats.docs"},
+			{Owner: "nats-io", Name: "nats"},
 		},
-		GitHubBranch:       "main",
-		GitHubFetchTimeout: 30,
+		GitHubBranch:          "main",
+		GitHubFetchTimeout:    30,
+		GitHubFetchStrategy:   "api",
+		GitHubMinSuccessRatio: 0.9,
+		GitHubForges:          nil,
+
+		// JetStream API schema defaults
+		JetStreamSchemasEnabled:    false, // Disabled by default
+		JetStreamSchemasPath:       "",
+		JetStreamSchemasRepository: "nats-io/jsm.go",
+		JetStreamSchemasRef:        "main",
+		JetStreamSchemasDir:        "schemas/jetstream/api/v1",
+
+		// Go API reference defaults
+		GoAPIEnabled: false, // Disabled by default
+		GoAPIModules: nil,
+
+		// NATS error definition defaults
+		ErrorsEnabled:          false, // Disabled by default
+		ErrorsServerPath:       "",
+		ErrorsServerRepository: "nats-io/nats-server",
+		ErrorsServerRef:        "main",
+		ErrorsServerFile:       "server/errors.json",
+		ErrorsClientPath:       "",
+		ErrorsClientRepository: "nats-io/nats.go",
+		ErrorsClientRef:        "main",
+
+		// Release notes defaults
+		ReleaseNotesEnabled:      false, // Disabled by default
+		ReleaseNotesPath:         "",
+		ReleaseNotesRepositories: []string{"nats-io/nats-server"},
+		ReleaseNotesRef:          "main",
 
 		// Classification keyword defaults
 		SynadiaKeywords:  classifier.DefaultSyadiaKeywords(),
@@ -148,12 +485,75 @@ func LoadFromFile(configPath string) (*Config, error) {
 	if v.IsSet("max_concurrent") {
 		cfg.MaxConcurrent = v.GetInt("max_concurrent")
 	}
+	if v.IsSet("requests_per_second") {
+		cfg.RequestsPerSecond = v.GetFloat64("requests_per_second")
+	}
+	if v.IsSet("max_per_host") {
+		cfg.MaxPerHost = v.GetInt("max_per_host")
+	}
+	if v.IsSet("docs_include_paths") {
+		cfg.DocsIncludePaths = v.GetStringSlice("docs_include_paths")
+	}
+	if v.IsSet("docs_exclude_paths") {
+		cfg.DocsExcludePaths = v.GetStringSlice("docs_exclude_paths")
+	}
+	if v.IsSet("docs_discovery") {
+		cfg.DocsDiscovery = v.GetString("docs_discovery")
+	}
+	if v.IsSet("docs_crawl_max_depth") {
+		cfg.DocsCrawlMaxDepth = v.GetInt("docs_crawl_max_depth")
+	}
+	if v.IsSet("docs_crawl_max_pages") {
+		cfg.DocsCrawlMaxPages = v.GetInt("docs_crawl_max_pages")
+	}
+	if v.IsSet("docs_min_success_ratio") {
+		cfg.DocsMinSuccessRatio = v.GetFloat64("docs_min_success_ratio")
+	}
+	if v.IsSet("docs_network") {
+		if err := v.UnmarshalKey("docs_network", &cfg.DocsNetwork); err != nil {
+			return nil, fmt.Errorf("failed to parse docs_network: %w", err)
+		}
+	}
+	if v.IsSet("docs_auth") {
+		if err := unmarshalAuth(v, "docs_auth", &cfg.DocsAuth); err != nil {
+			return nil, err
+		}
+	}
+	if v.IsSet("docs_extraction") {
+		if err := v.UnmarshalKey("docs_extraction", &cfg.DocsExtraction); err != nil {
+			return nil, fmt.Errorf("failed to parse docs_extraction: %w", err)
+		}
+	}
 	if v.IsSet("cache_dir") {
 		cfg.CacheDir = v.GetString("cache_dir")
 	}
 	if v.IsSet("max_search_results") {
 		cfg.MaxSearchResults = v.GetInt("max_search_results")
 	}
+	if v.IsSet("http_fixtures.mode") {
+		cfg.HTTPFixturesMode = v.GetString("http_fixtures.mode")
+	}
+	if v.IsSet("http_fixtures.dir") {
+		cfg.HTTPFixturesDir = v.GetString("http_fixtures.dir")
+	}
+	if v.IsSet("circuit_breaker.enabled") {
+		cfg.BreakerEnabled = v.GetBool("circuit_breaker.enabled")
+	}
+	if v.IsSet("circuit_breaker.failure_ratio") {
+		cfg.BreakerFailureRatio = v.GetFloat64("circuit_breaker.failure_ratio")
+	}
+	if v.IsSet("circuit_breaker.min_requests") {
+		cfg.BreakerMinRequests = v.GetInt("circuit_breaker.min_requests")
+	}
+	if v.IsSet("circuit_breaker.window") {
+		cfg.BreakerWindow = v.GetInt("circuit_breaker.window")
+	}
+	if v.IsSet("circuit_breaker.cool_down") {
+		cfg.BreakerCoolDown = v.GetInt("circuit_breaker.cool_down")
+	}
+	if v.IsSet("circuit_breaker.half_open_probes") {
+		cfg.BreakerHalfOpenProbes = v.GetInt("circuit_breaker.half_open_probes")
+	}
 	// Transport settings
 	if v.IsSet("transport_type") {
 		cfg.TransportType = v.GetString("transport_type")
@@ -175,6 +575,39 @@ func LoadFromFile(configPath string) (*Config, error) {
 	if v.IsSet("synadia.fetch_timeout") {
 		cfg.SynadiaFetchTimeout = v.GetInt("synadia.fetch_timeout")
 	}
+	if v.IsSet("synadia.include_paths") {
+		cfg.SynadiaIncludePaths = v.GetStringSlice("synadia.include_paths")
+	}
+	if v.IsSet("synadia.exclude_paths") {
+		cfg.SynadiaExcludePaths = v.GetStringSlice("synadia.exclude_paths")
+	}
+	if v.IsSet("synadia.discovery") {
+		cfg.SynadiaDiscovery = v.GetString("synadia.discovery")
+	}
+	if v.IsSet("synadia.crawl_max_depth") {
+		cfg.SynadiaCrawlMaxDepth = v.GetInt("synadia.crawl_max_depth")
+	}
+	if v.IsSet("synadia.crawl_max_pages") {
+		cfg.SynadiaCrawlMaxPages = v.GetInt("synadia.crawl_max_pages")
+	}
+	if v.IsSet("synadia.min_success_ratio") {
+		cfg.SynadiaMinSuccessRatio = v.GetFloat64("synadia.min_success_ratio")
+	}
+	if v.IsSet("synadia.network") {
+		if err := v.UnmarshalKey("synadia.network", &cfg.SynadiaNetwork); err != nil {
+			return nil, fmt.Errorf("failed to parse synadia.network: %w", err)
+		}
+	}
+	if v.IsSet("synadia.auth") {
+		if err := unmarshalAuth(v, "synadia.auth", &cfg.SynadiaAuth); err != nil {
+			return nil, err
+		}
+	}
+	if v.IsSet("synadia.extraction") {
+		if err := v.UnmarshalKey("synadia.extraction", &cfg.SynadiaExtraction); err != nil {
+			return nil, fmt.Errorf("failed to parse synadia.extraction: %w", err)
+		}
+	}
 	if v.IsSet("classification.synadia_keywords") {
 		cfg.SynadiaKeywords = v.GetStringSlice("classification.synadia_keywords")
 	}
@@ -193,7 +626,11 @@ func LoadFromFile(configPath string) (*Config, error) {
 		cfg.GitHubToken = v.GetString("github.token")
 	}
 	if v.IsSet("github.repositories") {
-		cfg.GitHubRepositories = v.GetStringSlice("github.repositories")
+		repos, err := loadGitHubRepositories(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse github.repositories: %w", err)
+		}
+		cfg.GitHubRepositories = repos
 	}
 	if v.IsSet("github.default_branch") {
 		cfg.GitHubBranch = v.GetString("github.default_branch")
@@ -201,6 +638,93 @@ func LoadFromFile(configPath string) (*Config, error) {
 	if v.IsSet("github.fetch_timeout") {
 		cfg.GitHubFetchTimeout = v.GetInt("github.fetch_timeout")
 	}
+	if v.IsSet("github.fetch_strategy") {
+		cfg.GitHubFetchStrategy = v.GetString("github.fetch_strategy")
+	}
+	if v.IsSet("github.min_success_ratio") {
+		cfg.GitHubMinSuccessRatio = v.GetFloat64("github.min_success_ratio")
+	}
+	if v.IsSet("github.forges") {
+		var forges []RepositoryForge
+		if err := v.UnmarshalKey("github.forges", &forges); err != nil {
+			return nil, fmt.Errorf("failed to parse github.forges: %w", err)
+		}
+		cfg.GitHubForges = forges
+	}
+	if v.IsSet("github.network") {
+		if err := v.UnmarshalKey("github.network", &cfg.GitHubNetwork); err != nil {
+			return nil, fmt.Errorf("failed to parse github.network: %w", err)
+		}
+	}
+
+	// JetStream API schema settings
+	if v.IsSet("jetstream_schemas.enabled") {
+		cfg.JetStreamSchemasEnabled = v.GetBool("jetstream_schemas.enabled")
+	}
+	if v.IsSet("jetstream_schemas.path") {
+		cfg.JetStreamSchemasPath = v.GetString("jetstream_schemas.path")
+	}
+	if v.IsSet("jetstream_schemas.repository") {
+		cfg.JetStreamSchemasRepository = v.GetString("jetstream_schemas.repository")
+	}
+	if v.IsSet("jetstream_schemas.ref") {
+		cfg.JetStreamSchemasRef = v.GetString("jetstream_schemas.ref")
+	}
+	if v.IsSet("jetstream_schemas.dir") {
+		cfg.JetStreamSchemasDir = v.GetString("jetstream_schemas.dir")
+	}
+
+	// Go API reference settings
+	if v.IsSet("go_api.enabled") {
+		cfg.GoAPIEnabled = v.GetBool("go_api.enabled")
+	}
+	if v.IsSet("go_api.modules") {
+		var modules []GoModule
+		if err := v.UnmarshalKey("go_api.modules", &modules); err != nil {
+			return nil, fmt.Errorf("failed to parse go_api.modules: %w", err)
+		}
+		cfg.GoAPIModules = modules
+	}
+
+	// NATS error definition settings
+	if v.IsSet("nats_errors.enabled") {
+		cfg.ErrorsEnabled = v.GetBool("nats_errors.enabled")
+	}
+	if v.IsSet("nats_errors.server.path") {
+		cfg.ErrorsServerPath = v.GetString("nats_errors.server.path")
+	}
+	if v.IsSet("nats_errors.server.repository") {
+		cfg.ErrorsServerRepository = v.GetString("nats_errors.server.repository")
+	}
+	if v.IsSet("nats_errors.server.ref") {
+		cfg.ErrorsServerRef = v.GetString("nats_errors.server.ref")
+	}
+	if v.IsSet("nats_errors.server.file") {
+		cfg.ErrorsServerFile = v.GetString("nats_errors.server.file")
+	}
+	if v.IsSet("nats_errors.client.path") {
+		cfg.ErrorsClientPath = v.GetString("nats_errors.client.path")
+	}
+	if v.IsSet("nats_errors.client.repository") {
+		cfg.ErrorsClientRepository = v.GetString("nats_errors.client.repository")
+	}
+	if v.IsSet("nats_errors.client.ref") {
+		cfg.ErrorsClientRef = v.GetString("nats_errors.client.ref")
+	}
+
+	// Release notes settings
+	if v.IsSet("release_notes.enabled") {
+		cfg.ReleaseNotesEnabled = v.GetBool("release_notes.enabled")
+	}
+	if v.IsSet("release_notes.path") {
+		cfg.ReleaseNotesPath = v.GetString("release_notes.path")
+	}
+	if v.IsSet("release_notes.repositories") {
+		cfg.ReleaseNotesRepositories = v.GetStringSlice("release_notes.repositories")
+	}
+	if v.IsSet("release_notes.ref") {
+		cfg.ReleaseNotesRef = v.GetString("release_notes.ref")
+	}
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
@@ -241,12 +765,75 @@ func LoadWithFlags(configPath string, flags map[string]interface{}) (*Config, er
 		if v.IsSet("max_concurrent") {
 			cfg.MaxConcurrent = v.GetInt("max_concurrent")
 		}
+		if v.IsSet("requests_per_second") {
+			cfg.RequestsPerSecond = v.GetFloat64("requests_per_second")
+		}
+		if v.IsSet("max_per_host") {
+			cfg.MaxPerHost = v.GetInt("max_per_host")
+		}
+		if v.IsSet("docs_include_paths") {
+			cfg.DocsIncludePaths = v.GetStringSlice("docs_include_paths")
+		}
+		if v.IsSet("docs_exclude_paths") {
+			cfg.DocsExcludePaths = v.GetStringSlice("docs_exclude_paths")
+		}
+		if v.IsSet("docs_discovery") {
+			cfg.DocsDiscovery = v.GetString("docs_discovery")
+		}
+		if v.IsSet("docs_crawl_max_depth") {
+			cfg.DocsCrawlMaxDepth = v.GetInt("docs_crawl_max_depth")
+		}
+		if v.IsSet("docs_crawl_max_pages") {
+			cfg.DocsCrawlMaxPages = v.GetInt("docs_crawl_max_pages")
+		}
+		if v.IsSet("docs_min_success_ratio") {
+			cfg.DocsMinSuccessRatio = v.GetFloat64("docs_min_success_ratio")
+		}
+		if v.IsSet("docs_network") {
+			if err := v.UnmarshalKey("docs_network", &cfg.DocsNetwork); err != nil {
+				return nil, fmt.Errorf("failed to parse docs_network: %w", err)
+			}
+		}
+		if v.IsSet("docs_auth") {
+			if err := unmarshalAuth(v, "docs_auth", &cfg.DocsAuth); err != nil {
+				return nil, err
+			}
+		}
+		if v.IsSet("docs_extraction") {
+			if err := v.UnmarshalKey("docs_extraction", &cfg.DocsExtraction); err != nil {
+				return nil, fmt.Errorf("failed to parse docs_extraction: %w", err)
+			}
+		}
 		if v.IsSet("cache_dir") {
 			cfg.CacheDir = v.GetString("cache_dir")
 		}
 		if v.IsSet("max_search_results") {
 			cfg.MaxSearchResults = v.GetInt("max_search_results")
 		}
+		if v.IsSet("http_fixtures.mode") {
+			cfg.HTTPFixturesMode = v.GetString("http_fixtures.mode")
+		}
+		if v.IsSet("http_fixtures.dir") {
+			cfg.HTTPFixturesDir = v.GetString("http_fixtures.dir")
+		}
+		if v.IsSet("circuit_breaker.enabled") {
+			cfg.BreakerEnabled = v.GetBool("circuit_breaker.enabled")
+		}
+		if v.IsSet("circuit_breaker.failure_ratio") {
+			cfg.BreakerFailureRatio = v.GetFloat64("circuit_breaker.failure_ratio")
+		}
+		if v.IsSet("circuit_breaker.min_requests") {
+			cfg.BreakerMinRequests = v.GetInt("circuit_breaker.min_requests")
+		}
+		if v.IsSet("circuit_breaker.window") {
+			cfg.BreakerWindow = v.GetInt("circuit_breaker.window")
+		}
+		if v.IsSet("circuit_breaker.cool_down") {
+			cfg.BreakerCoolDown = v.GetInt("circuit_breaker.cool_down")
+		}
+		if v.IsSet("circuit_breaker.half_open_probes") {
+			cfg.BreakerHalfOpenProbes = v.GetInt("circuit_breaker.half_open_probes")
+		}
 		// Transport settings
 		if v.IsSet("transport_type") {
 			cfg.TransportType = v.GetString("transport_type")
@@ -267,6 +854,39 @@ func LoadWithFlags(configPath string, flags map[string]interface{}) (*Config, er
 		if v.IsSet("synadia.fetch_timeout") {
 			cfg.SynadiaFetchTimeout = v.GetInt("synadia.fetch_timeout")
 		}
+		if v.IsSet("synadia.include_paths") {
+			cfg.SynadiaIncludePaths = v.GetStringSlice("synadia.include_paths")
+		}
+		if v.IsSet("synadia.exclude_paths") {
+			cfg.SynadiaExcludePaths = v.GetStringSlice("synadia.exclude_paths")
+		}
+		if v.IsSet("synadia.discovery") {
+			cfg.SynadiaDiscovery = v.GetString("synadia.discovery")
+		}
+		if v.IsSet("synadia.crawl_max_depth") {
+			cfg.SynadiaCrawlMaxDepth = v.GetInt("synadia.crawl_max_depth")
+		}
+		if v.IsSet("synadia.crawl_max_pages") {
+			cfg.SynadiaCrawlMaxPages = v.GetInt("synadia.crawl_max_pages")
+		}
+		if v.IsSet("synadia.min_success_ratio") {
+			cfg.SynadiaMinSuccessRatio = v.GetFloat64("synadia.min_success_ratio")
+		}
+		if v.IsSet("synadia.network") {
+			if err := v.UnmarshalKey("synadia.network", &cfg.SynadiaNetwork); err != nil {
+				return nil, fmt.Errorf("failed to parse synadia.network: %w", err)
+			}
+		}
+		if v.IsSet("synadia.auth") {
+			if err := unmarshalAuth(v, "synadia.auth", &cfg.SynadiaAuth); err != nil {
+				return nil, err
+			}
+		}
+		if v.IsSet("synadia.extraction") {
+			if err := v.UnmarshalKey("synadia.extraction", &cfg.SynadiaExtraction); err != nil {
+				return nil, fmt.Errorf("failed to parse synadia.extraction: %w", err)
+			}
+		}
 		if v.IsSet("classification.synadia_keywords") {
 			cfg.SynadiaKeywords = v.GetStringSlice("classification.synadia_keywords")
 		}
@@ -296,6 +916,16 @@ func LoadWithFlags(configPath string, flags map[string]interface{}) (*Config, er
 			cfg.MaxConcurrent = intVal
 		}
 	}
+	if val, ok := flags["requests_per_second"]; ok && val != nil {
+		if floatVal, ok := val.(float64); ok {
+			cfg.RequestsPerSecond = floatVal
+		}
+	}
+	if val, ok := flags["max_per_host"]; ok && val != nil {
+		if intVal, ok := val.(int); ok {
+			cfg.MaxPerHost = intVal
+		}
+	}
 	if val, ok := flags["cache_dir"]; ok && val != nil {
 		if strVal, ok := val.(string); ok {
 			cfg.CacheDir = strVal
@@ -306,6 +936,16 @@ func LoadWithFlags(configPath string, flags map[string]interface{}) (*Config, er
 			cfg.MaxSearchResults = intVal
 		}
 	}
+	if val, ok := flags["http_fixtures_mode"]; ok && val != nil {
+		if strVal, ok := val.(string); ok {
+			cfg.HTTPFixturesMode = strVal
+		}
+	}
+	if val, ok := flags["http_fixtures_dir"]; ok && val != nil {
+		if strVal, ok := val.(string); ok {
+			cfg.HTTPFixturesDir = strVal
+		}
+	}
 	// Transport settings
 	if val, ok := flags["transport_type"]; ok && val != nil {
 		if strVal, ok := val.(string); ok {
@@ -381,6 +1021,43 @@ func loadFromEnv(cfg *Config) {
 			cfg.MaxConcurrent = intVal
 		}
 	}
+	if val := getEnv("REQUESTS_PER_SECOND"); val != "" {
+		if floatVal, err := strconv.ParseFloat(val, 64); err == nil {
+			cfg.RequestsPerSecond = floatVal
+		}
+	}
+	if val := getEnv("MAX_PER_HOST"); val != "" {
+		if intVal, err := strconv.Atoi(val); err == nil {
+			cfg.MaxPerHost = intVal
+		}
+	}
+	loadNetworkFromEnv(getEnv, "DOCS_", &cfg.DocsNetwork)
+	loadAuthFromEnv(getEnv, "DOCS_AUTH_", &cfg.DocsAuth)
+	loadExtractionFromEnv(getEnv, "DOCS_EXTRACTION_", &cfg.DocsExtraction)
+	if val := getEnv("DOCS_INCLUDE_PATHS"); val != "" {
+		cfg.DocsIncludePaths = splitList(val)
+	}
+	if val := getEnv("DOCS_EXCLUDE_PATHS"); val != "" {
+		cfg.DocsExcludePaths = splitList(val)
+	}
+	if val := getEnv("DOCS_DISCOVERY"); val != "" {
+		cfg.DocsDiscovery = val
+	}
+	if val := getEnv("DOCS_CRAWL_MAX_DEPTH"); val != "" {
+		if intVal, err := strconv.Atoi(val); err == nil {
+			cfg.DocsCrawlMaxDepth = intVal
+		}
+	}
+	if val := getEnv("DOCS_CRAWL_MAX_PAGES"); val != "" {
+		if intVal, err := strconv.Atoi(val); err == nil {
+			cfg.DocsCrawlMaxPages = intVal
+		}
+	}
+	if val := getEnv("DOCS_MIN_SUCCESS_RATIO"); val != "" {
+		if floatVal, err := strconv.ParseFloat(val, 64); err == nil {
+			cfg.DocsMinSuccessRatio = floatVal
+		}
+	}
 	if val := getEnv("CACHE_DIR"); val != "" {
 		cfg.CacheDir = val
 	}
@@ -394,6 +1071,40 @@ func loadFromEnv(cfg *Config) {
 			cfg.MaxSearchResults = intVal
 		}
 	}
+	if val := getEnv("HTTP_FIXTURES_MODE"); val != "" {
+		cfg.HTTPFixturesMode = val
+	}
+	if val := getEnv("HTTP_FIXTURES_DIR"); val != "" {
+		cfg.HTTPFixturesDir = val
+	}
+	if val := getEnv("CIRCUIT_BREAKER_ENABLED"); val != "" {
+		cfg.BreakerEnabled = val == "true" || val == "1" || val == "yes"
+	}
+	if val := getEnv("CIRCUIT_BREAKER_FAILURE_RATIO"); val != "" {
+		if floatVal, err := strconv.ParseFloat(val, 64); err == nil {
+			cfg.BreakerFailureRatio = floatVal
+		}
+	}
+	if val := getEnv("CIRCUIT_BREAKER_MIN_REQUESTS"); val != "" {
+		if intVal, err := strconv.Atoi(val); err == nil {
+			cfg.BreakerMinRequests = intVal
+		}
+	}
+	if val := getEnv("CIRCUIT_BREAKER_WINDOW"); val != "" {
+		if intVal, err := strconv.Atoi(val); err == nil {
+			cfg.BreakerWindow = intVal
+		}
+	}
+	if val := getEnv("CIRCUIT_BREAKER_COOL_DOWN"); val != "" {
+		if intVal, err := strconv.Atoi(val); err == nil {
+			cfg.BreakerCoolDown = intVal
+		}
+	}
+	if val := getEnv("CIRCUIT_BREAKER_HALF_OPEN_PROBES"); val != "" {
+		if intVal, err := strconv.Atoi(val); err == nil {
+			cfg.BreakerHalfOpenProbes = intVal
+		}
+	}
 
 	// Transport settings
 	if val := getEnv("TRANSPORT_TYPE"); val != "" {
@@ -420,6 +1131,33 @@ func loadFromEnv(cfg *Config) {
 			cfg.SynadiaFetchTimeout = intVal
 		}
 	}
+	loadNetworkFromEnv(getEnv, "SYNADIA_", &cfg.SynadiaNetwork)
+	loadAuthFromEnv(getEnv, "SYNADIA_AUTH_", &cfg.SynadiaAuth)
+	loadExtractionFromEnv(getEnv, "SYNADIA_EXTRACTION_", &cfg.SynadiaExtraction)
+	if val := getEnv("SYNADIA_INCLUDE_PATHS"); val != "" {
+		cfg.SynadiaIncludePaths = splitList(val)
+	}
+	if val := getEnv("SYNADIA_EXCLUDE_PATHS"); val != "" {
+		cfg.SynadiaExcludePaths = splitList(val)
+	}
+	if val := getEnv("SYNADIA_DISCOVERY"); val != "" {
+		cfg.SynadiaDiscovery = val
+	}
+	if val := getEnv("SYNADIA_CRAWL_MAX_DEPTH"); val != "" {
+		if intVal, err := strconv.Atoi(val); err == nil {
+			cfg.SynadiaCrawlMaxDepth = intVal
+		}
+	}
+	if val := getEnv("SYNADIA_CRAWL_MAX_PAGES"); val != "" {
+		if intVal, err := strconv.Atoi(val); err == nil {
+			cfg.SynadiaCrawlMaxPages = intVal
+		}
+	}
+	if val := getEnv("SYNADIA_MIN_SUCCESS_RATIO"); val != "" {
+		if floatVal, err := strconv.ParseFloat(val, 64); err == nil {
+			cfg.SynadiaMinSuccessRatio = floatVal
+		}
+	}
 
 	// GitHub settings
 	if val := getEnv("GITHUB_ENABLED"); val != "" {
@@ -429,10 +1167,9 @@ func loadFromEnv(cfg *Config) {
 		cfg.GitHubToken = val
 	}
 	if val := getEnv("GITHUB_REPOSITORIES"); val != "" {
-		cfg.GitHubRepositories = strings.Split(val, ",")
-		// Trim whitespace from each repo
-		for i := range cfg.GitHubRepositories {
-			cfg.GitHubRepositories[i] = strings.TrimSpace(cfg.GitHubRepositories[i])
+		cfg.GitHubRepositories = nil
+		for _, spec := range strings.Split(val, ",") {
+			cfg.GitHubRepositories = append(cfg.GitHubRepositories, ParseGitHubRepository(spec))
 		}
 	}
 	if val := getEnv("GITHUB_BRANCH"); val != "" {
@@ -443,6 +1180,115 @@ func loadFromEnv(cfg *Config) {
 			cfg.GitHubFetchTimeout = intVal
 		}
 	}
+	loadNetworkFromEnv(getEnv, "GITHUB_", &cfg.GitHubNetwork)
+	if val := getEnv("GITHUB_FETCH_STRATEGY"); val != "" {
+		cfg.GitHubFetchStrategy = val
+	}
+	if val := getEnv("GITHUB_MIN_SUCCESS_RATIO"); val != "" {
+		if floatVal, err := strconv.ParseFloat(val, 64); err == nil {
+			cfg.GitHubMinSuccessRatio = floatVal
+		}
+	}
+	if val := getEnv("GITHUB_FORGES"); val != "" {
+		// Comma-separated list of "owner/repo=type|api_url|web_url|token" entries;
+		// trailing fields may be omitted
+		cfg.GitHubForges = nil
+		for _, entry := range strings.Split(val, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			repository, settings, _ := strings.Cut(entry, "=")
+			fields := append(strings.Split(settings, "|"), "", "", "", "")
+			cfg.GitHubForges = append(cfg.GitHubForges, RepositoryForge{
+				Repository: strings.TrimSpace(repository),
+				Type:       strings.TrimSpace(fields[0]),
+				APIURL:     strings.TrimSpace(fields[1]),
+				WebURL:     strings.TrimSpace(fields[2]),
+				Token:      strings.TrimSpace(fields[3]),
+			})
+		}
+	}
+
+	// JetStream API schema settings
+	if val := getEnv("JETSTREAM_SCHEMAS_ENABLED"); val != "" {
+		cfg.JetStreamSchemasEnabled = val == "true" || val == "1" || val == "yes"
+	}
+	if val := getEnv("JETSTREAM_SCHEMAS_PATH"); val != "" {
+		cfg.JetStreamSchemasPath = val
+	}
+	if val := getEnv("JETSTREAM_SCHEMAS_REPOSITORY"); val != "" {
+		cfg.JetStreamSchemasRepository = val
+	}
+	if val := getEnv("JETSTREAM_SCHEMAS_REF"); val != "" {
+		cfg.JetStreamSchemasRef = val
+	}
+	if val := getEnv("JETSTREAM_SCHEMAS_DIR"); val != "" {
+		cfg.JetStreamSchemasDir = val
+	}
+
+	// Go API reference settings
+	if val := getEnv("GO_API_ENABLED"); val != "" {
+		cfg.GoAPIEnabled = val == "true" || val == "1" || val == "yes"
+	}
+	if val := getEnv("GO_API_MODULES"); val != "" {
+		// Comma-separated list of "path" or "path=import/path" entries
+		cfg.GoAPIModules = nil
+		for _, entry := range strings.Split(val, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			path, importPath, _ := strings.Cut(entry, "=")
+			cfg.GoAPIModules = append(cfg.GoAPIModules, GoModule{
+				Path:       strings.TrimSpace(path),
+				ImportPath: strings.TrimSpace(importPath),
+			})
+		}
+	}
+
+	// NATS error definition settings
+	if val := getEnv("NATS_ERRORS_ENABLED"); val != "" {
+		cfg.ErrorsEnabled = val == "true" || val == "1" || val == "yes"
+	}
+	if val := getEnv("NATS_ERRORS_SERVER_PATH"); val != "" {
+		cfg.ErrorsServerPath = val
+	}
+	if val := getEnv("NATS_ERRORS_SERVER_REPOSITORY"); val != "" {
+		cfg.ErrorsServerRepository = val
+	}
+	if val := getEnv("NATS_ERRORS_SERVER_REF"); val != "" {
+		cfg.ErrorsServerRef = val
+	}
+	if val := getEnv("NATS_ERRORS_SERVER_FILE"); val != "" {
+		cfg.ErrorsServerFile = val
+	}
+	if val := getEnv("NATS_ERRORS_CLIENT_PATH"); val != "" {
+		cfg.ErrorsClientPath = val
+	}
+	if val := getEnv("NATS_ERRORS_CLIENT_REPOSITORY"); val != "" {
+		cfg.ErrorsClientRepository = val
+	}
+	if val := getEnv("NATS_ERRORS_CLIENT_REF"); val != "" {
+		cfg.ErrorsClientRef = val
+	}
+
+	// Release notes settings
+	if val := getEnv("RELEASE_NOTES_ENABLED"); val != "" {
+		cfg.ReleaseNotesEnabled = val == "true" || val == "1" || val == "yes"
+	}
+	if val := getEnv("RELEASE_NOTES_PATH"); val != "" {
+		cfg.ReleaseNotesPath = val
+	}
+	if val := getEnv("RELEASE_NOTES_REPOSITORIES"); val != "" {
+		cfg.ReleaseNotesRepositories = strings.Split(val, ",")
+		for i := range cfg.ReleaseNotesRepositories {
+			cfg.ReleaseNotesRepositories[i] = strings.TrimSpace(cfg.ReleaseNotesRepositories[i])
+		}
+	}
+	if val := getEnv("RELEASE_NOTES_REF"); val != "" {
+		cfg.ReleaseNotesRef = val
+	}
 
 	// Classification keywords - comma-separated lists
 	if val := getEnv("SYNADIA_KEYWORDS"); val != "" {
@@ -563,11 +1409,167 @@ func (c *Config) Validate() error {
 		errors = append(errors, fmt.Sprintf("max_concurrent must be positive, got: %d", c.MaxConcurrent))
 	}
 
+	// Validate request rate (must be positive) and per-host limit (zero means max_concurrent)
+	if c.RequestsPerSecond <= 0 {
+		errors = append(errors, fmt.Sprintf("requests_per_second must be positive, got: %g", c.RequestsPerSecond))
+	}
+	if c.MaxPerHost < 0 {
+		errors = append(errors, fmt.Sprintf("max_per_host must not be negative, got: %d", c.MaxPerHost))
+	}
+
+	// Validate page path filters of the documentation sites
+	pathFilters := []struct {
+		key      string
+		patterns []string
+	}{
+		{"docs_include_paths", c.DocsIncludePaths},
+		{"docs_exclude_paths", c.DocsExcludePaths},
+		{"synadia.include_paths", c.SynadiaIncludePaths},
+		{"synadia.exclude_paths", c.SynadiaExcludePaths},
+	}
+	for _, filter := range pathFilters {
+		for _, pattern := range filter.patterns {
+			if !isValidGlob(pattern) {
+				errors = append(errors, fmt.Sprintf("%s has an invalid pattern: %q", filter.key, pattern))
+			}
+		}
+	}
+
+	// Validate page discovery of the documentation sites
+	discoveries := []struct {
+		prefix   string
+		mode     string
+		maxDepth int
+		maxPages int
+	}{
+		{"docs_", c.DocsDiscovery, c.DocsCrawlMaxDepth, c.DocsCrawlMaxPages},
+		{"synadia.", c.SynadiaDiscovery, c.SynadiaCrawlMaxDepth, c.SynadiaCrawlMaxPages},
+	}
+	for _, discovery := range discoveries {
+		if discovery.mode != "auto" && discovery.mode != "sitemap" && discovery.mode != "crawl" {
+			errors = append(errors, fmt.Sprintf("%sdiscovery must be auto, sitemap or crawl, got: %q", discovery.prefix, discovery.mode))
+		}
+		if discovery.maxDepth <= 0 {
+			errors = append(errors, fmt.Sprintf("%scrawl_max_depth must be positive, got: %d", discovery.prefix, discovery.maxDepth))
+		}
+		if discovery.maxPages <= 0 {
+			errors = append(errors, fmt.Sprintf("%scrawl_max_pages must be positive, got: %d", discovery.prefix, discovery.maxPages))
+		}
+	}
+
+	// Validate the share of pages each documentation site must deliver
+	successRatios := []struct {
+		key   string
+		ratio float64
+	}{
+		{"docs_min_success_ratio", c.DocsMinSuccessRatio},
+		{"synadia.min_success_ratio", c.SynadiaMinSuccessRatio},
+		{"github.min_success_ratio", c.GitHubMinSuccessRatio},
+	}
+	for _, sr := range successRatios {
+		if sr.ratio < 0 || sr.ratio > 1 {
+			errors = append(errors, fmt.Sprintf("%s must be between 0 and 1, got: %g", sr.key, sr.ratio))
+		}
+	}
+
+	// Validate proxy and TLS settings of every source
+	networks := []struct {
+		prefix  string
+		network NetworkConfig
+	}{
+		{"docs_network", c.DocsNetwork},
+		{"synadia.network", c.SynadiaNetwork},
+		{"github.network", c.GitHubNetwork},
+	}
+	for _, n := range networks {
+		errors = append(errors, n.network.validate(n.prefix)...)
+	}
+
+	// Validate credentials of the documentation sites, loading their secret files
+	auths := []struct {
+		key  string
+		auth AuthConfig
+	}{
+		{"docs_auth", c.DocsAuth},
+		{"synadia.auth", c.SynadiaAuth},
+	}
+	for _, a := range auths {
+		errors = append(errors, a.auth.validate(a.key)...)
+	}
+
+	// Validate content extraction of the documentation sites
+	errors = append(errors, c.DocsExtraction.validate("docs_extraction", c.DocsBaseURL)...)
+	errors = append(errors, c.SynadiaExtraction.validate("synadia.extraction", c.SynadiaBaseURL)...)
+
+	// Validate GitHub fetch strategy (applies to every source fetched from GitHub)
+	if c.GitHubFetchStrategy != "api" && c.GitHubFetchStrategy != "archive" {
+		errors = append(errors, fmt.Sprintf("github.fetch_strategy must be api or archive, got: %q", c.GitHubFetchStrategy))
+	}
+
+	// Validate repository forges (apply to every source fetched from a repository)
+	seenForges := make(map[string]bool)
+	for i, forge := range c.GitHubForges {
+		if !isOwnerRepo(forge.Repository) {
+			errors = append(errors, fmt.Sprintf("github.forges[%d].repository must be in format 'owner/repo', got: %s", i, forge.Repository))
+		} else if seenForges[strings.ToLower(forge.Repository)] {
+			errors = append(errors, fmt.Sprintf("github.forges[%d].repository is configured more than once: %s", i, forge.Repository))
+		}
+		seenForges[strings.ToLower(forge.Repository)] = true
+
+		switch forge.Type {
+		case "", "github":
+		case "gitea", "gitlab":
+			if forge.APIURL == "" {
+				errors = append(errors, fmt.Sprintf("github.forges[%d].api_url is required for %s", i, forge.Type))
+			}
+		default:
+			errors = append(errors, fmt.Sprintf("github.forges[%d].type must be github, gitea or gitlab, got: %q", i, forge.Type))
+		}
+		if forge.APIURL != "" && !isHTTPURL(forge.APIURL) {
+			errors = append(errors, fmt.Sprintf("github.forges[%d].api_url must be an http or https URL, got: %s", i, forge.APIURL))
+		}
+		if forge.WebURL != "" && (!isHTTPURL(forge.WebURL) || !strings.HasSuffix(forge.WebURL, "{path}")) {
+			errors = append(errors, fmt.Sprintf("github.forges[%d].web_url must be an http or https URL ending in {path}, got: %s", i, forge.WebURL))
+		}
+	}
+
 	// Validate max search results (must be positive)
 	if c.MaxSearchResults <= 0 {
 		errors = append(errors, fmt.Sprintf("max_search_results must be positive, got: %d", c.MaxSearchResults))
 	}
 
+	// Validate HTTP fixtures
+	switch c.HTTPFixturesMode {
+	case "off":
+	case "record", "replay":
+		if c.HTTPFixturesDir == "" {
+			errors = append(errors, fmt.Sprintf("http_fixtures.dir is required in %s mode", c.HTTPFixturesMode))
+		} else if info, err := os.Stat(c.HTTPFixturesDir); c.HTTPFixturesMode == "replay" && (err != nil || !info.IsDir()) {
+			errors = append(errors, fmt.Sprintf("http_fixtures.dir must be an existing directory in replay mode, got: %s", c.HTTPFixturesDir))
+		}
+	default:
+		errors = append(errors, fmt.Sprintf("http_fixtures.mode must be off, record or replay, got: %q", c.HTTPFixturesMode))
+	}
+
+	// Validate circuit breaker settings
+	if c.BreakerEnabled {
+		if c.BreakerFailureRatio <= 0 || c.BreakerFailureRatio > 1 {
+			errors = append(errors, fmt.Sprintf("circuit_breaker.failure_ratio must be greater than 0 and at most 1, got: %g", c.BreakerFailureRatio))
+		}
+		if c.BreakerMinRequests <= 0 {
+			errors = append(errors, fmt.Sprintf("circuit_breaker.min_requests must be positive, got: %d", c.BreakerMinRequests))
+		}
+		if c.BreakerWindow < c.BreakerMinRequests {
+			errors = append(errors, fmt.Sprintf("circuit_breaker.window must be at least min_requests, got: %d", c.BreakerWindow))
+		}
+		if c.BreakerCoolDown <= 0 {
+			errors = append(errors, fmt.Sprintf("circuit_breaker.cool_down must be positive, got: %d", c.BreakerCoolDown))
+		}
+		if c.BreakerHalfOpenProbes <= 0 {
+			errors = append(errors, fmt.Sprintf("circuit_breaker.half_open_probes must be positive, got: %d", c.BreakerHalfOpenProbes))
+		}
+	}
+
 	// Validate docs base URL
 	if c.DocsBaseURL == "" {
 		errors = append(errors, "docs_base_url cannot be empty")
@@ -629,9 +1631,40 @@ func (c *Config) Validate() error {
 		if len(c.GitHubRepositories) == 0 {
 			errors = append(errors, "github.repositories cannot be empty when GitHub is enabled")
 		}
-		for _, repo := range c.GitHubRepositories {
-			if !strings.Contains(repo, "/") {
-				errors = append(errors, fmt.Sprintf("github.repositories must be in format 'owner/repo', got: %s", repo))
+		seenRepos := make(map[string]bool)
+		for i, repo := range c.GitHubRepositories {
+			if repo.Owner == "" || repo.Name == "" || strings.Contains(repo.Name, "/") {
+				errors = append(errors, fmt.Sprintf("github.repositories must be in format 'owner/repo', got: %s", strings.TrimPrefix(repo.FullName(), "/")))
+			} else if seenRepos[strings.ToLower(repo.FullName())] {
+				errors = append(errors, fmt.Sprintf("github.repositories[%d] is configured more than once: %s", i, repo.FullName()))
+			}
+			seenRepos[strings.ToLower(repo.FullName())] = true
+
+			if strings.ContainsAny(repo.Ref, " \t") {
+				errors = append(errors, fmt.Sprintf("github.repositories[%d].ref must be a branch or tag name, got: %q", i, repo.Ref))
+			}
+			for _, pattern := range append(append([]string{}, repo.Include...), repo.Exclude...) {
+				if !isValidGlob(pattern) {
+					errors = append(errors, fmt.Sprintf("github.repositories[%d] has an invalid include or exclude pattern: %q", i, pattern))
+				}
+			}
+
+			// Validate additional versions against the repository's default version
+			defaultRef := repo.Ref
+			if defaultRef == "" {
+				defaultRef = c.GitHubBranch
+			}
+			seenVersions := make(map[string]bool)
+			for _, version := range repo.Versions {
+				switch {
+				case version == "" || strings.ContainsAny(version, " \t"):
+					errors = append(errors, fmt.Sprintf("github.repositories[%d].versions entries must be branch or tag names, got: %q", i, version))
+				case version == defaultRef:
+					errors = append(errors, fmt.Sprintf("github.repositories[%d].versions must not repeat the repository's default version: %s", i, version))
+				case seenVersions[version]:
+					errors = append(errors, fmt.Sprintf("github.repositories[%d].versions lists %s more than once", i, version))
+				}
+				seenVersions[version] = true
 			}
 		}
 
@@ -651,6 +1684,69 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate JetStream API schema configuration (only if enabled)
+	if c.JetStreamSchemasEnabled && c.JetStreamSchemasPath == "" {
+		// Without a local path the schemas are fetched from GitHub
+		if !isOwnerRepo(c.JetStreamSchemasRepository) {
+			errors = append(errors, fmt.Sprintf("jetstream_schemas.repository must be in format 'owner/repo', got: %s", c.JetStreamSchemasRepository))
+		}
+		if c.JetStreamSchemasRef == "" {
+			errors = append(errors, "jetstream_schemas.ref cannot be empty when jetstream_schemas.path is not set")
+		}
+	}
+
+	// Validate Go API reference configuration (only if enabled)
+	if c.GoAPIEnabled {
+		if len(c.GoAPIModules) == 0 {
+			errors = append(errors, "go_api.modules cannot be empty when go_api is enabled")
+		}
+		for i, module := range c.GoAPIModules {
+			if module.Path == "" {
+				errors = append(errors, fmt.Sprintf("go_api.modules[%d].path cannot be empty", i))
+			}
+		}
+	}
+
+	// Validate NATS error definition configuration (only if enabled)
+	if c.ErrorsEnabled {
+		// Without a local path each side is fetched from GitHub
+		if c.ErrorsServerPath == "" {
+			if !isOwnerRepo(c.ErrorsServerRepository) {
+				errors = append(errors, fmt.Sprintf("nats_errors.server.repository must be in format 'owner/repo', got: %s", c.ErrorsServerRepository))
+			}
+			if c.ErrorsServerRef == "" {
+				errors = append(errors, "nats_errors.server.ref cannot be empty when nats_errors.server.path is not set")
+			}
+			if c.ErrorsServerFile == "" {
+				errors = append(errors, "nats_errors.server.file cannot be empty when nats_errors.server.path is not set")
+			}
+		}
+		if c.ErrorsClientPath == "" {
+			if !isOwnerRepo(c.ErrorsClientRepository) {
+				errors = append(errors, fmt.Sprintf("nats_errors.client.repository must be in format 'owner/repo', got: %s", c.ErrorsClientRepository))
+			}
+			if c.ErrorsClientRef == "" {
+				errors = append(errors, "nats_errors.client.ref cannot be empty when nats_errors.client.path is not set")
+			}
+		}
+	}
+
+	// Validate release notes configuration (only if enabled)
+	if c.ReleaseNotesEnabled && c.ReleaseNotesPath == "" {
+		// Without a local mirror the releases are fetched from GitHub
+		if len(c.ReleaseNotesRepositories) == 0 {
+			errors = append(errors, "release_notes.repositories cannot be empty when release_notes.path is not set")
+		}
+		for _, repo := range c.ReleaseNotesRepositories {
+			if !isOwnerRepo(repo) {
+				errors = append(errors, fmt.Sprintf("release_notes.repositories must be in format 'owner/repo', got: %s", repo))
+			}
+		}
+		if c.ReleaseNotesRef == "" {
+			errors = append(errors, "release_notes.ref cannot be empty when release_notes.path is not set")
+		}
+	}
+
 	// If there are validation errors, return them all
 	if len(errors) > 0 {
 		return fmt.Errorf("configuration validation failed: %s", strings.Join(errors, "; "))
@@ -659,6 +1755,230 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// isOwnerRepo reports whether repo is in "owner/repo" format
+func isOwnerRepo(repo string) bool {
+	parts := strings.Split(repo, "/")
+	return len(parts) == 2 && parts[0] != "" && parts[1] != ""
+}
+
+// isValidGlob reports whether pattern is a valid path.Match pattern, where "**"
+// segments match any number of directories
+func isValidGlob(pattern string) bool {
+	if pattern == "" {
+		return false
+	}
+	_, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), "")
+	return err == nil
+}
+
+// splitList splits a comma-separated environment value, trimming each item and
+// dropping empty ones
+func splitList(val string) []string {
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// loadNetworkFromEnv reads the proxy and TLS settings of one source from the
+// environment variables starting with prefix (e.g., DOCS_PROXY_URL)
+func loadNetworkFromEnv(getEnv func(string) string, prefix string, network *NetworkConfig) {
+	if val := getEnv(prefix + "PROXY_URL"); val != "" {
+		network.ProxyURL = val
+	}
+	if val := getEnv(prefix + "NO_PROXY"); val != "" {
+		network.NoProxy = splitList(val)
+	}
+	if val := getEnv(prefix + "CA_FILES"); val != "" {
+		network.CAFiles = splitList(val)
+	}
+	if val := getEnv(prefix + "CLIENT_CERT"); val != "" {
+		network.ClientCert = val
+	}
+	if val := getEnv(prefix + "CLIENT_KEY"); val != "" {
+		network.ClientKey = val
+	}
+	if val := getEnv(prefix + "TLS_MIN_VERSION"); val != "" {
+		network.TLSMinVersion = val
+	}
+}
+
+// validate checks the proxy and TLS settings, reading the certificate files so a
+// missing or malformed file is reported at startup. Errors are prefixed with key.
+func (n NetworkConfig) validate(key string) []string {
+	var errors []string
+
+	if n.ProxyURL != "" {
+		u, err := url.Parse(n.ProxyURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") || u.Host == "" {
+			errors = append(errors, fmt.Sprintf("%s.proxy_url must be an http, https or socks5 URL, got: %s", key, n.ProxyURL))
+		}
+	}
+
+	for _, file := range n.CAFiles {
+		pem, err := os.ReadFile(file)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s.ca_files cannot be read: %v", key, err))
+		} else if !x509.NewCertPool().AppendCertsFromPEM(pem) {
+			errors = append(errors, fmt.Sprintf("%s.ca_files has no PEM certificate: %s", key, file))
+		}
+	}
+
+	if (n.ClientCert == "") != (n.ClientKey == "") {
+		errors = append(errors, fmt.Sprintf("%s.client_cert and %s.client_key must be set together", key, key))
+	} else if n.ClientCert != "" {
+		if _, err := tls.LoadX509KeyPair(n.ClientCert, n.ClientKey); err != nil {
+			errors = append(errors, fmt.Sprintf("%s.client_cert and client_key cannot be loaded: %v", key, err))
+		}
+	}
+
+	switch n.TLSMinVersion {
+	case "", "1.0", "1.1", "1.2", "1.3":
+	default:
+		errors = append(errors, fmt.Sprintf("%s.tls_min_version must be 1.0, 1.1, 1.2 or 1.3, got: %q", key, n.TLSMinVersion))
+	}
+
+	return errors
+}
+
+// loadExtractionFromEnv reads the content extraction settings of one source from the
+// environment variables starting with prefix
+func loadExtractionFromEnv(getEnv func(string) string, prefix string, extraction *ExtractionConfig) {
+	if val := getEnv(prefix + "PROFILE"); val != "" {
+		extraction.Profile = val
+	}
+	if val := getEnv(prefix + "CONTENT"); val != "" {
+		extraction.Content = splitList(val)
+	}
+	if val := getEnv(prefix + "REMOVE"); val != "" {
+		extraction.Remove = splitList(val)
+	}
+	if val := getEnv(prefix + "TITLE_SUFFIXES"); val != "" {
+		extraction.TitleSuffixes = splitList(val)
+	}
+}
+
+// unmarshalAuth decodes the credentials at key, rejecting secrets written into the
+// configuration file
+func unmarshalAuth(v *viper.Viper, key string, auth *AuthConfig) error {
+	for _, secret := range []string{"password", "bearer_token"} {
+		if v.IsSet(key + "." + secret) {
+			return fmt.Errorf("%s.%s cannot be set in a configuration file; use %s_file or an environment variable", key, secret, secret)
+		}
+	}
+	if err := v.UnmarshalKey(key, auth); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", key, err)
+	}
+	return nil
+}
+
+// loadAuthFromEnv reads the credentials of one source from the environment variables
+// starting with prefix (e.g., SYNADIA_AUTH_PASSWORD or SYNADIA_AUTH_PASSWORD_FILE)
+func loadAuthFromEnv(getEnv func(string) string, prefix string, auth *AuthConfig) {
+	if val := getEnv(prefix + "USERNAME"); val != "" {
+		auth.Username = val
+	}
+	if val := getEnv(prefix + "PASSWORD"); val != "" {
+		auth.Password = val
+	}
+	if val := getEnv(prefix + "PASSWORD_FILE"); val != "" {
+		auth.PasswordFile = val
+	}
+	if val := getEnv(prefix + "BEARER_TOKEN"); val != "" {
+		auth.BearerToken = val
+	}
+	if val := getEnv(prefix + "BEARER_TOKEN_FILE"); val != "" {
+		auth.BearerTokenFile = val
+	}
+	if val := getEnv(prefix + "HEADERS_FILE"); val != "" {
+		auth.HeadersFile = val
+	}
+	if val := getEnv(prefix + "COOKIE_FILE"); val != "" {
+		auth.CookieFile = val
+	}
+}
+
+// validate checks the credentials, loading the secret files so a missing file is
+// reported at startup. Errors are prefixed with key and never include a secret.
+func (a AuthConfig) validate(key string) []string {
+	if a.IsZero() {
+		return nil
+	}
+
+	resolved, err := a.Resolve()
+	if err != nil {
+		return []string{fmt.Sprintf("%s.%v", key, err)}
+	}
+
+	var errors []string
+	basic := resolved.Username != "" || resolved.Password != ""
+	if basic && (resolved.Username == "" || resolved.Password == "") {
+		errors = append(errors, fmt.Sprintf("%s.username and password must be set together", key))
+	}
+	if basic && resolved.BearerToken != "" {
+		errors = append(errors, fmt.Sprintf("%s cannot use both basic auth and a bearer token", key))
+	}
+	for name, value := range resolved.Headers {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") || strings.ContainsAny(value, "\r\n") {
+			errors = append(errors, fmt.Sprintf("%s.headers has an invalid header: %q", key, name))
+		}
+	}
+	if a.CookieFile != "" {
+		if _, err := os.Stat(a.CookieFile); err != nil {
+			errors = append(errors, fmt.Sprintf("%s.cookie_file cannot be read: %v", key, err))
+		}
+	}
+	return errors
+}
+
+// readSecretFile reads a file holding a secret, dropping trailing newlines
+func readSecretFile(file string) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// loadGitHubRepositories reads github.repositories, whose entries are either
+// "owner/repo[@ref]" strings or mappings of GitHubRepository fields
+func loadGitHubRepositories(v *viper.Viper) ([]GitHubRepository, error) {
+	entries, ok := v.Get("github.repositories").([]interface{})
+	if !ok {
+		var repos []GitHubRepository
+		for _, spec := range v.GetStringSlice("github.repositories") {
+			repos = append(repos, ParseGitHubRepository(spec))
+		}
+		return repos, nil
+	}
+
+	// Expand string entries so the whole list decodes as mappings
+	normalized := make([]interface{}, len(entries))
+	for i, entry := range entries {
+		if spec, ok := entry.(string); ok {
+			repo := ParseGitHubRepository(spec)
+			entry = map[string]interface{}{"owner": repo.Owner, "name": repo.Name, "ref": repo.Ref}
+		}
+		normalized[i] = entry
+	}
+	v.Set("github.repositories", normalized)
+
+	var repos []GitHubRepository
+	if err := v.UnmarshalKey("github.repositories", &repos); err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+// isHTTPURL reports whether raw is an absolute http or https URL
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
 // GetCacheDir returns the cache directory, using default if not configured.
 // It expands ~ to the user's home directory and returns a sensible default
 // if the user's home directory cannot be determined.
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Tests for documentation site credentials
+
+func writeSecret(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return file
+}
+
+func TestAuthConfig_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	auth := AuthConfig{
+		Username:     "alice",
+		PasswordFile: writeSecret(t, dir, "password", "s3cret\n"),
+		Headers:      map[string]string{"x-portal-tenant": "acme"},
+		HeadersFile:  writeSecret(t, dir, "headers", "# portal API key\nX-Api-Key: k3y\n\n"),
+	}
+
+	resolved, err := auth.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if resolved.Password != "s3cret" {
+		t.Errorf("expected password from file without trailing newline, got %q", resolved.Password)
+	}
+	if resolved.Headers["X-Api-Key"] != "k3y" || resolved.Headers["x-portal-tenant"] != "acme" {
+		t.Errorf("unexpected headers: %v", resolved.Headers)
+	}
+	if len(auth.Headers) != 1 {
+		t.Errorf("Resolve modified the configured headers: %v", auth.Headers)
+	}
+
+	for _, out := range []string{resolved.String(), resolved.LogValue().String()} {
+		if strings.Contains(out, "s3cret") || strings.Contains(out, "k3y") {
+			t.Errorf("secret not redacted: %s", out)
+		}
+		if !strings.Contains(out, "alice") || !strings.Contains(out, "X-Api-Key") {
+			t.Errorf("expected user name and header names: %s", out)
+		}
+	}
+}
+
+func TestValidate_Auth(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := writeSecret(t, dir, "token", "t0ken")
+
+	cfg := NewConfig()
+	cfg.SynadiaAuth = AuthConfig{BearerTokenFile: tokenFile, Headers: map[string]string{"X-Tenant": "acme"}}
+	cfg.DocsAuth = AuthConfig{Username: "alice", Password: "s3cret"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("valid credentials rejected: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"missing password file", func(c *Config) { c.SynadiaAuth.PasswordFile = filepath.Join(dir, "missing") }},
+		{"username without password", func(c *Config) { c.DocsAuth.Username = "alice" }},
+		{"basic and bearer", func(c *Config) { c.DocsAuth = AuthConfig{Username: "a", Password: "b", BearerToken: "c"} }},
+		{"invalid header name", func(c *Config) { c.SynadiaAuth.Headers = map[string]string{"X Tenant": "acme"} }},
+		{"malformed headers file", func(c *Config) { c.SynadiaAuth.HeadersFile = writeSecret(t, dir, "bad", "X-Api-Key k3y") }},
+		{"missing cookie file", func(c *Config) { c.DocsAuth.CookieFile = filepath.Join(dir, "cookies.txt") }},
+	}
+	for _, tt := range tests {
+		cfg := NewConfig()
+		tt.modify(cfg)
+		err := cfg.Validate()
+		if err == nil {
+			t.Errorf("%s: expected validation error", tt.name)
+		} else if strings.Contains(err.Error(), "s3cret") || strings.Contains(err.Error(), "t0ken") {
+			t.Errorf("%s: validation error reveals a secret: %v", tt.name, err)
+		}
+	}
+}
+
+func TestLoadFromFile_Auth(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := writeSecret(t, dir, "password", "s3cret")
+
+	configPath := filepath.Join(dir, "config.yaml")
+	configContent := `
+synadia:
+  auth:
+    username: alice
+    password_file: ` + passwordFile + `
+    headers:
+      X-Portal-Tenant: acme
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.SynadiaAuth.Username != "alice" || cfg.SynadiaAuth.PasswordFile != passwordFile {
+		t.Errorf("unexpected synadia auth: %v", cfg.SynadiaAuth)
+	}
+	if cfg.SynadiaAuth.Headers["x-portal-tenant"] != "acme" {
+		t.Errorf("unexpected synadia headers: %v", cfg.SynadiaAuth.Headers)
+	}
+
+	// Secrets are rejected in configuration files
+	if err := os.WriteFile(configPath, []byte("docs_auth:\n  username: alice\n  password: s3cret\n"), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+	if _, err := LoadFromFile(configPath); err == nil {
+		t.Error("expected an error for a password in the configuration file")
+	}
+}
+
+func TestLoadFromEnv_Auth(t *testing.T) {
+	t.Setenv("NATS_DOCS_DOCS_AUTH_BEARER_TOKEN", "t0ken")
+	t.Setenv("NATS_DOCS_SYNADIA_AUTH_USERNAME", "alice")
+	t.Setenv("NATS_DOCS_SYNADIA_AUTH_PASSWORD", "s3cret")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.DocsAuth.BearerToken != "t0ken" {
+		t.Errorf("expected bearer token from environment, got %v", cfg.DocsAuth)
+	}
+	if cfg.SynadiaAuth.Username != "alice" || cfg.SynadiaAuth.Password != "s3cret" {
+		t.Errorf("expected basic auth from environment, got %v", cfg.SynadiaAuth)
+	}
+}
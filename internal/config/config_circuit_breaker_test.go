@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests for circuit breaker configuration
+
+func TestNewConfig_CircuitBreakerDefaults(t *testing.T) {
+	cfg := NewConfig()
+
+	if !cfg.BreakerEnabled {
+		t.Error("BreakerEnabled should default to true")
+	}
+	if cfg.BreakerFailureRatio != 0.5 || cfg.BreakerMinRequests != 5 || cfg.BreakerWindow != 20 {
+		t.Errorf("unexpected breaker thresholds: %+v", cfg)
+	}
+	if cfg.BreakerCoolDown != 30 || cfg.BreakerHalfOpenProbes != 1 {
+		t.Errorf("unexpected breaker recovery settings: %+v", cfg)
+	}
+}
+
+func TestValidate_CircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"zero failure ratio", func(c *Config) { c.BreakerFailureRatio = 0 }},
+		{"failure ratio above one", func(c *Config) { c.BreakerFailureRatio = 1.5 }},
+		{"zero min requests", func(c *Config) { c.BreakerMinRequests = 0 }},
+		{"window below min requests", func(c *Config) { c.BreakerWindow = 3 }},
+		{"zero cool-down", func(c *Config) { c.BreakerCoolDown = 0 }},
+		{"zero half-open probes", func(c *Config) { c.BreakerHalfOpenProbes = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			tt.modify(cfg)
+			if err := cfg.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+
+			// Settings of a disabled breaker are not validated
+			cfg.BreakerEnabled = false
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("expected disabled breaker to be valid, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadFromFile_CircuitBreaker(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+circuit_breaker:
+  failure_ratio: 0.8
+  min_requests: 10
+  window: 40
+  cool_down: 120
+  half_open_probes: 2
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if !cfg.BreakerEnabled || cfg.BreakerFailureRatio != 0.8 || cfg.BreakerMinRequests != 10 ||
+		cfg.BreakerWindow != 40 || cfg.BreakerCoolDown != 120 || cfg.BreakerHalfOpenProbes != 2 {
+		t.Errorf("config file values not applied: %+v", cfg)
+	}
+}
+
+func TestLoadFromEnv_CircuitBreaker(t *testing.T) {
+	t.Setenv("NATS_DOCS_CIRCUIT_BREAKER_ENABLED", "false")
+	t.Setenv("NATS_DOCS_CIRCUIT_BREAKER_COOL_DOWN", "5")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.BreakerEnabled || cfg.BreakerCoolDown != 5 {
+		t.Errorf("environment variables not applied: %+v", cfg)
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests for documentation site page discovery settings
+
+func TestNewConfig_DiscoveryDefaults(t *testing.T) {
+	cfg := NewConfig()
+	if cfg.DocsDiscovery != "auto" || cfg.SynadiaDiscovery != "auto" {
+		t.Errorf("expected auto discovery by default, got %q and %q", cfg.DocsDiscovery, cfg.SynadiaDiscovery)
+	}
+	if cfg.DocsCrawlMaxDepth != 5 || cfg.DocsCrawlMaxPages != 1000 {
+		t.Errorf("unexpected crawl limit defaults: %d, %d", cfg.DocsCrawlMaxDepth, cfg.DocsCrawlMaxPages)
+	}
+}
+
+func TestValidate_Discovery(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"unknown docs mode", func(c *Config) { c.DocsDiscovery = "spider" }},
+		{"unknown synadia mode", func(c *Config) { c.SynadiaDiscovery = "" }},
+		{"zero crawl depth", func(c *Config) { c.DocsCrawlMaxDepth = 0 }},
+		{"negative crawl pages", func(c *Config) { c.SynadiaCrawlMaxPages = -1 }},
+	}
+	for _, tt := range tests {
+		cfg := NewConfig()
+		tt.modify(cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", tt.name)
+		}
+	}
+
+	cfg := NewConfig()
+	cfg.DocsDiscovery = "crawl"
+	cfg.SynadiaDiscovery = "sitemap"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("valid discovery modes rejected: %v", err)
+	}
+}
+
+func TestLoadFromFile_Discovery(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+docs_discovery: crawl
+docs_crawl_max_depth: 3
+docs_crawl_max_pages: 200
+synadia:
+  discovery: sitemap
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.DocsDiscovery != "crawl" || cfg.DocsCrawlMaxDepth != 3 || cfg.DocsCrawlMaxPages != 200 {
+		t.Errorf("unexpected docs discovery: %q, %d, %d", cfg.DocsDiscovery, cfg.DocsCrawlMaxDepth, cfg.DocsCrawlMaxPages)
+	}
+	if cfg.SynadiaDiscovery != "sitemap" {
+		t.Errorf("expected synadia sitemap discovery, got %q", cfg.SynadiaDiscovery)
+	}
+}
+
+func TestLoadFromEnv_Discovery(t *testing.T) {
+	t.Setenv("NATS_DOCS_SYNADIA_DISCOVERY", "crawl")
+	t.Setenv("NATS_DOCS_SYNADIA_CRAWL_MAX_PAGES", "50")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.SynadiaDiscovery != "crawl" || cfg.SynadiaCrawlMaxPages != 50 {
+		t.Errorf("unexpected synadia discovery from environment: %q, %d", cfg.SynadiaDiscovery, cfg.SynadiaCrawlMaxPages)
+	}
+}
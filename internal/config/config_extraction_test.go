@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Tests for the content extraction profiles of the documentation sites
+
+func TestExtractionConfig_ParserProfile(t *testing.T) {
+	cfg := NewConfig()
+
+	if got := cfg.DocsExtraction.ParserProfile(cfg.DocsBaseURL).Name; got != "nats-docs" {
+		t.Errorf("expected the nats-docs profile for the default NATS base URL, got %q", got)
+	}
+	if got := cfg.SynadiaExtraction.ParserProfile(cfg.SynadiaBaseURL).Name; got != "synadia-docs" {
+		t.Errorf("expected the synadia-docs profile for the default Synadia base URL, got %q", got)
+	}
+
+	extraction := ExtractionConfig{
+		Profile:       "none",
+		Content:       []string{"#content"},
+		Remove:        []string{".ad"},
+		TitleSuffixes: []string{" | Mirror"},
+	}
+	profile := extraction.ParserProfile(cfg.DocsBaseURL)
+	if profile.Name != "none" {
+		t.Errorf("expected the named profile to override the host's, got %q", profile.Name)
+	}
+	if !reflect.DeepEqual(profile.Content, []string{"#content"}) ||
+		!reflect.DeepEqual(profile.Remove, []string{".ad"}) ||
+		!reflect.DeepEqual(profile.TitleSuffixes, []string{" | Mirror"}) {
+		t.Errorf("configured selectors not applied: %+v", profile)
+	}
+
+	// Removed elements and title suffixes add to the built-in profile's
+	profile = ExtractionConfig{Remove: []string{".ad"}}.ParserProfile(cfg.DocsBaseURL)
+	if len(profile.Remove) < 2 || profile.Remove[len(profile.Remove)-1] != ".ad" || len(profile.Content) == 0 {
+		t.Errorf("expected the built-in selectors to be kept, got %+v", profile)
+	}
+}
+
+func TestValidate_Extraction(t *testing.T) {
+	cfg := NewConfig()
+	cfg.DocsExtraction.Profile = "wiki"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for an unknown docs_extraction.profile")
+	}
+
+	cfg = NewConfig()
+	cfg.SynadiaExtraction.Remove = []string{"div >"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for an invalid synadia.extraction selector")
+	}
+
+	cfg = NewConfig()
+	cfg.DocsExtraction = ExtractionConfig{Profile: "auto", Content: []string{"main > article"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected a valid extraction config, got: %v", err)
+	}
+}
+
+func TestLoadFromFile_Extraction(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+docs_extraction:
+  profile: none
+  content: ["main"]
+  remove: [".banner", "#feedback"]
+synadia:
+  extraction:
+    title_suffixes: [" | Control Plane"]
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	want := ExtractionConfig{Profile: "none", Content: []string{"main"}, Remove: []string{".banner", "#feedback"}}
+	if !reflect.DeepEqual(cfg.DocsExtraction, want) {
+		t.Errorf("docs_extraction = %+v, want %+v", cfg.DocsExtraction, want)
+	}
+	if !reflect.DeepEqual(cfg.SynadiaExtraction.TitleSuffixes, []string{" | Control Plane"}) {
+		t.Errorf("synadia.extraction not applied: %+v", cfg.SynadiaExtraction)
+	}
+}
+
+func TestLoadFromEnv_Extraction(t *testing.T) {
+	t.Setenv("NATS_DOCS_DOCS_EXTRACTION_PROFILE", "nats-docs")
+	t.Setenv("NATS_DOCS_DOCS_EXTRACTION_REMOVE", ".banner, #feedback")
+	t.Setenv("NATS_DOCS_SYNADIA_EXTRACTION_CONTENT", "article")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.DocsExtraction.Profile != "nats-docs" || !reflect.DeepEqual(cfg.DocsExtraction.Remove, []string{".banner", "#feedback"}) {
+		t.Errorf("docs extraction environment variables not applied: %+v", cfg.DocsExtraction)
+	}
+	if !reflect.DeepEqual(cfg.SynadiaExtraction.Content, []string{"article"}) {
+		t.Errorf("synadia extraction environment variables not applied: %+v", cfg.SynadiaExtraction)
+	}
+}
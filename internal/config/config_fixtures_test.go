@@ -0,0 +1,63 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Tests for HTTP record/replay fixture settings
+
+func TestValidate_HTTPFixtures(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := []struct{ mode, dir string }{
+		{"off", ""},
+		{"record", filepath.Join(dir, "new")},
+		{"replay", dir},
+	}
+	for _, v := range valid {
+		cfg := NewConfig()
+		cfg.HTTPFixturesMode, cfg.HTTPFixturesDir = v.mode, v.dir
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("%s mode rejected: %v", v.mode, err)
+		}
+	}
+
+	invalid := []struct{ mode, dir string }{
+		{"rewind", dir},
+		{"record", ""},
+		{"replay", filepath.Join(dir, "missing")},
+	}
+	for _, v := range invalid {
+		cfg := NewConfig()
+		cfg.HTTPFixturesMode, cfg.HTTPFixturesDir = v.mode, v.dir
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation error for mode %q with dir %q", v.mode, v.dir)
+		}
+	}
+}
+
+func TestLoadWithFlags_HTTPFixtures(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NATS_DOCS_HTTP_FIXTURES_MODE", "record")
+	t.Setenv("NATS_DOCS_HTTP_FIXTURES_DIR", filepath.Join(dir, "env"))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.HTTPFixturesMode != "record" || cfg.HTTPFixturesDir != filepath.Join(dir, "env") {
+		t.Errorf("unexpected fixtures from environment: %q, %q", cfg.HTTPFixturesMode, cfg.HTTPFixturesDir)
+	}
+
+	cfg, err = LoadWithFlags("", map[string]interface{}{
+		"http_fixtures_mode": "replay",
+		"http_fixtures_dir":  dir,
+	})
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.HTTPFixturesMode != "replay" || cfg.HTTPFixturesDir != dir {
+		t.Errorf("expected flags to override the environment, got %q, %q", cfg.HTTPFixturesMode, cfg.HTTPFixturesDir)
+	}
+}
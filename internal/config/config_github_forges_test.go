@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Tests for per-repository forge configuration
+
+func TestValidate_GitHubForges(t *testing.T) {
+	cfg := NewConfig()
+	cfg.GitHubForges = []RepositoryForge{
+		{Repository: "ext/auth", APIURL: "https://ghe.example.com/api/v3"},
+		{Repository: "ext/kv", Type: "gitea", APIURL: "https://git.example.com/api/v1",
+			WebURL: "https://git.example.com/{owner}/{repo}/src/tag/{ref}/{path}"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("valid forges rejected: %v", err)
+	}
+
+	invalid := []RepositoryForge{
+		{Repository: "ext", APIURL: "https://ghe.example.com/api/v3"},
+		{Repository: "ext/auth", Type: "bitbucket", APIURL: "https://bitbucket.example.com"},
+		{Repository: "ext/auth", Type: "gitlab"},
+		{Repository: "ext/auth", APIURL: "ghe.example.com"},
+		{Repository: "ext/auth", WebURL: "https://ghe.example.com/{owner}/{repo}"},
+	}
+	for _, forge := range invalid {
+		cfg.GitHubForges = []RepositoryForge{forge}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation error for %+v", forge)
+		}
+	}
+
+	cfg.GitHubForges = []RepositoryForge{{Repository: "ext/auth"}, {Repository: "EXT/auth"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for a repository configured twice")
+	}
+}
+
+func TestForgeFor(t *testing.T) {
+	cfg := NewConfig()
+	cfg.GitHubForges = []RepositoryForge{{Repository: "Ext/Auth", Type: "gitea"}}
+
+	if forge, ok := cfg.ForgeFor("ext/auth"); !ok || forge.Type != "gitea" {
+		t.Errorf("expected case-insensitive match, got %+v, %v", forge, ok)
+	}
+	if _, ok := cfg.ForgeFor("nats-io/nats-server"); ok {
+		t.Error("expected no forge for an unconfigured repository")
+	}
+}
+
+func TestLoadFromFile_GitHubForges(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+github:
+  forges:
+    - repository: ext/auth
+      api_url: https://ghe.example.com/api/v3
+      token: ghe-token
+    - repository: ext/kv
+      type: gitlab
+      api_url: https://gitlab.example.com/api/v4
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	want := []RepositoryForge{
+		{Repository: "ext/auth", APIURL: "https://ghe.example.com/api/v3", Token: "ghe-token"},
+		{Repository: "ext/kv", Type: "gitlab", APIURL: "https://gitlab.example.com/api/v4"},
+	}
+	if !reflect.DeepEqual(cfg.GitHubForges, want) {
+		t.Errorf("unexpected forges: %+v", cfg.GitHubForges)
+	}
+}
+
+func TestLoadFromEnv_GitHubForges(t *testing.T) {
+	t.Setenv("NATS_DOCS_GITHUB_FORGES", "ext/auth=github|https://ghe.example.com/api/v3, ext/kv=gitea|https://git.example.com/api/v1||gitea-token")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	want := []RepositoryForge{
+		{Repository: "ext/auth", Type: "github", APIURL: "https://ghe.example.com/api/v3"},
+		{Repository: "ext/kv", Type: "gitea", APIURL: "https://git.example.com/api/v1", Token: "gitea-token"},
+	}
+	if !reflect.DeepEqual(cfg.GitHubForges, want) {
+		t.Errorf("unexpected forges from environment: %+v", cfg.GitHubForges)
+	}
+}
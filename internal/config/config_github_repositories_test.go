@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Tests for structured GitHub repository configuration
+
+func TestParseGitHubRepository(t *testing.T) {
+	tests := []struct {
+		spec string
+		want GitHubRepository
+	}{
+		{"nats-io/nats-server", GitHubRepository{Owner: "nats-io", Name: "nats-server"}},
+		{" nats-io/nats.docs@v2.10 ", GitHubRepository{Owner: "nats-io", Name: "nats.docs", Ref: "v2.10"}},
+		{"nats-server", GitHubRepository{Name: "nats-server"}},
+	}
+	for _, tt := range tests {
+		if got := ParseGitHubRepository(tt.spec); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseGitHubRepository(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestValidate_GitHubRepositories(t *testing.T) {
+	cfg := NewConfig()
+	cfg.GitHubEnabled = true
+	cfg.GitHubToken = "token"
+	cfg.GitHubRepositories = []GitHubRepository{
+		{Owner: "nats-io", Name: "nats-server", Ref: "v2.11.0", DocRoot: "doc",
+			Include: []string{"**/*.md", "adr/*.rst"}, Exclude: []string{"testdata/**"}},
+		{Owner: "nats-io", Name: "nats.docs"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("valid repositories rejected: %v", err)
+	}
+
+	invalid := [][]GitHubRepository{
+		{{Name: "nats-server"}},
+		{{Owner: "nats-io"}},
+		{{Owner: "nats-io", Name: "nats-server", Ref: "main branch"}},
+		{{Owner: "nats-io", Name: "nats-server", Include: []string{"doc/[.md"}}},
+		{{Owner: "nats-io", Name: "nats-server", Exclude: []string{""}}},
+		{{Owner: "nats-io", Name: "nats-server"}, {Owner: "NATS-io", Name: "nats-server"}},
+	}
+	for _, repos := range invalid {
+		cfg.GitHubRepositories = repos
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation error for %+v", repos)
+		}
+	}
+}
+
+func TestLoadFromFile_GitHubRepositories(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+github:
+  repositories:
+    - nats-io/nats.docs
+    - nats-io/nats@v2.10.0
+    - owner: nats-io
+      name: nats-server
+      ref: main
+      doc_root: doc
+      include: ["**/*.md", "**/*.rst"]
+      exclude: [testdata, CHANGELOG.md]
+      display_name: NATS Server Design Docs
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	want := []GitHubRepository{
+		{Owner: "nats-io", Name: "nats.docs"},
+		{Owner: "nats-io", Name: "nats", Ref: "v2.10.0"},
+		{Owner: "nats-io", Name: "nats-server", Ref: "main", DocRoot: "doc",
+			Include: []string{"**/*.md", "**/*.rst"}, Exclude: []string{"testdata", "CHANGELOG.md"},
+			DisplayName: "NATS Server Design Docs"},
+	}
+	if !reflect.DeepEqual(cfg.GitHubRepositories, want) {
+		t.Errorf("unexpected repositories: %+v", cfg.GitHubRepositories)
+	}
+}
+
+func TestLoadFromEnv_GitHubRepositories(t *testing.T) {
+	t.Setenv("NATS_DOCS_GITHUB_REPOSITORIES", "nats-io/nats-server@v2.11.0, nats-io/nats.docs")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	want := []GitHubRepository{
+		{Owner: "nats-io", Name: "nats-server", Ref: "v2.11.0"},
+		{Owner: "nats-io", Name: "nats.docs"},
+	}
+	if !reflect.DeepEqual(cfg.GitHubRepositories, want) {
+		t.Errorf("unexpected repositories from environment: %+v", cfg.GitHubRepositories)
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests for the GitHub fetch strategy configuration
+
+func TestNewConfig_GitHubFetchStrategyDefault(t *testing.T) {
+	if cfg := NewConfig(); cfg.GitHubFetchStrategy != "api" {
+		t.Errorf("GitHubFetchStrategy should default to api, got %q", cfg.GitHubFetchStrategy)
+	}
+}
+
+func TestValidate_GitHubFetchStrategy(t *testing.T) {
+	cfg := NewConfig()
+	cfg.GitHubFetchStrategy = "archive"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("archive strategy rejected: %v", err)
+	}
+
+	cfg.GitHubFetchStrategy = "clone"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for unknown fetch strategy")
+	}
+}
+
+func TestLoadFromFile_GitHubFetchStrategy(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+github:
+  enabled: true
+  token: test-token
+  fetch_strategy: archive
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.GitHubFetchStrategy != "archive" {
+		t.Errorf("expected archive strategy, got %q", cfg.GitHubFetchStrategy)
+	}
+}
+
+func TestLoadFromEnv_GitHubFetchStrategy(t *testing.T) {
+	t.Setenv("NATS_DOCS_GITHUB_FETCH_STRATEGY", "archive")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.GitHubFetchStrategy != "archive" {
+		t.Errorf("expected archive strategy from environment, got %q", cfg.GitHubFetchStrategy)
+	}
+}
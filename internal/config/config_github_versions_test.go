@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Tests for multi-version GitHub documentation configuration
+
+func TestNewConfig_GitHubVersionsDefault(t *testing.T) {
+	for _, repo := range NewConfig().GitHubRepositories {
+		if len(repo.Versions) != 0 {
+			t.Errorf("%s versions should default to empty, got %v", repo.FullName(), repo.Versions)
+		}
+	}
+}
+
+func TestValidate_GitHubVersions(t *testing.T) {
+	newConfig := func(repos ...GitHubRepository) *Config {
+		cfg := NewConfig()
+		cfg.GitHubEnabled = true
+		cfg.GitHubToken = "test-token"
+		cfg.GitHubRepositories = repos
+		return cfg
+	}
+
+	cfg := newConfig(
+		GitHubRepository{Owner: "nats-io", Name: "nats-server", Versions: []string{"v2.10.0", "release/v2.11"}},
+		GitHubRepository{Owner: "nats-io", Name: "nats.docs", Ref: "master", Versions: []string{"main"}},
+	)
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("valid versions rejected: %v", err)
+	}
+
+	for _, invalid := range []GitHubRepository{
+		{Owner: "nats-io", Name: "nats-server", Versions: []string{""}},
+		{Owner: "nats-io", Name: "nats-server", Versions: []string{"v2 10"}},
+		{Owner: "nats-io", Name: "nats-server", Versions: []string{"main"}},
+		{Owner: "nats-io", Name: "nats-server", Ref: "v2.11.0", Versions: []string{"v2.11.0"}},
+		{Owner: "nats-io", Name: "nats-server", Versions: []string{"v2.10.0", "v2.10.0"}},
+	} {
+		if err := newConfig(invalid).Validate(); err == nil {
+			t.Errorf("expected validation error for versions %q of ref %q", invalid.Versions, invalid.Ref)
+		}
+	}
+}
+
+func TestLoadFromFile_GitHubVersions(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+github:
+  enabled: true
+  token: test-token
+  repositories:
+    - owner: nats-io
+      name: nats-server
+      versions:
+        - v2.10.0
+        - v2.11.0
+    - nats-io/nats.docs
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if len(cfg.GitHubRepositories) != 2 {
+		t.Fatalf("expected 2 repositories, got %+v", cfg.GitHubRepositories)
+	}
+	if !reflect.DeepEqual(cfg.GitHubRepositories[0].Versions, []string{"v2.10.0", "v2.11.0"}) {
+		t.Errorf("unexpected nats-server versions: %v", cfg.GitHubRepositories[0].Versions)
+	}
+	if len(cfg.GitHubRepositories[1].Versions) != 0 {
+		t.Errorf("versions of one repository must not apply to another, got %v", cfg.GitHubRepositories[1].Versions)
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests for Go API reference configuration
+
+func TestNewConfig_GoAPIDefaults(t *testing.T) {
+	cfg := NewConfig()
+
+	if cfg.GoAPIEnabled {
+		t.Error("GoAPIEnabled should default to false")
+	}
+	if len(cfg.GoAPIModules) != 0 {
+		t.Errorf("GoAPIModules should default to empty, got %v", cfg.GoAPIModules)
+	}
+}
+
+func TestValidate_GoAPIRequiresModules(t *testing.T) {
+	cfg := NewConfig()
+	cfg.GoAPIEnabled = true
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error without modules")
+	}
+
+	cfg.GoAPIModules = []GoModule{{ImportPath: "github.com/nats-io/nats.go"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for module without path")
+	}
+
+	cfg.GoAPIModules = []GoModule{{Path: "/src/nats.go"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+}
+
+func TestLoadFromFile_GoAPI(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+go_api:
+  enabled: true
+  modules:
+    - path: /src/nats.go
+    - path: ./vendor/github.com/nats-io/jsm.go
+      import_path: github.com/nats-io/jsm.go
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if !cfg.GoAPIEnabled {
+		t.Error("Go API reference should be enabled")
+	}
+	want := []GoModule{
+		{Path: "/src/nats.go"},
+		{Path: "./vendor/github.com/nats-io/jsm.go", ImportPath: "github.com/nats-io/jsm.go"},
+	}
+	if len(cfg.GoAPIModules) != len(want) {
+		t.Fatalf("expected %d modules, got %v", len(want), cfg.GoAPIModules)
+	}
+	for i := range want {
+		if cfg.GoAPIModules[i] != want[i] {
+			t.Errorf("module %d: expected %+v, got %+v", i, want[i], cfg.GoAPIModules[i])
+		}
+	}
+}
+
+func TestLoadFromEnv_GoAPI(t *testing.T) {
+	t.Setenv("NATS_DOCS_GO_API_ENABLED", "true")
+	t.Setenv("NATS_DOCS_GO_API_MODULES", "/src/nats.go, ./vendor/jsm=github.com/nats-io/jsm.go")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if !cfg.GoAPIEnabled || len(cfg.GoAPIModules) != 2 {
+		t.Fatalf("environment variables not applied: %+v", cfg)
+	}
+	if cfg.GoAPIModules[0] != (GoModule{Path: "/src/nats.go"}) {
+		t.Errorf("unexpected first module: %+v", cfg.GoAPIModules[0])
+	}
+	if cfg.GoAPIModules[1] != (GoModule{Path: "./vendor/jsm", ImportPath: "github.com/nats-io/jsm.go"}) {
+		t.Errorf("unexpected second module: %+v", cfg.GoAPIModules[1])
+	}
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ============================================================================
+// Unit Tests for JetStream API Schema Configuration
+// ============================================================================
+
+func TestNewConfig_JetStreamSchemasDefaults(t *testing.T) {
+	cfg := NewConfig()
+
+	if cfg.JetStreamSchemasEnabled {
+		t.Error("JetStreamSchemasEnabled should default to false")
+	}
+	if cfg.JetStreamSchemasRepository != "nats-io/jsm.go" {
+		t.Errorf("unexpected default repository: %s", cfg.JetStreamSchemasRepository)
+	}
+	if cfg.JetStreamSchemasRef != "main" {
+		t.Errorf("unexpected default ref: %s", cfg.JetStreamSchemasRef)
+	}
+	if cfg.JetStreamSchemasDir != "schemas/jetstream/api/v1" {
+		t.Errorf("unexpected default dir: %s", cfg.JetStreamSchemasDir)
+	}
+}
+
+func TestValidate_JetStreamSchemasEnabledWithDefaults(t *testing.T) {
+	cfg := NewConfig()
+	cfg.JetStreamSchemasEnabled = true
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("default JetStream schema config should validate: %v", err)
+	}
+}
+
+func TestValidate_JetStreamSchemasInvalidRepository(t *testing.T) {
+	cfg := NewConfig()
+	cfg.JetStreamSchemasEnabled = true
+	cfg.JetStreamSchemasRepository = "jsm.go"
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("repository without owner should fail validation")
+	}
+}
+
+func TestValidate_JetStreamSchemasEmptyRef(t *testing.T) {
+	cfg := NewConfig()
+	cfg.JetStreamSchemasEnabled = true
+	cfg.JetStreamSchemasRef = ""
+
+	if err := cfg.Validate(); err == nil {
+		t.Error("empty ref should fail validation when fetching from GitHub")
+	}
+}
+
+func TestValidate_JetStreamSchemasLocalPathSkipsRepository(t *testing.T) {
+	cfg := NewConfig()
+	cfg.JetStreamSchemasEnabled = true
+	cfg.JetStreamSchemasPath = "/src/jsm.go/schemas"
+	cfg.JetStreamSchemasRepository = ""
+	cfg.JetStreamSchemasRef = ""
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("local path should not require a repository: %v", err)
+	}
+}
+
+func TestLoadFromFile_JetStreamSchemas(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+jetstream_schemas:
+  enabled: true
+  repository: example/schemas
+  ref: v1.2.3
+  dir: api
+  path: /src/schemas
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if !cfg.JetStreamSchemasEnabled {
+		t.Error("JetStream schemas should be enabled")
+	}
+	if cfg.JetStreamSchemasRepository != "example/schemas" {
+		t.Errorf("unexpected repository: %s", cfg.JetStreamSchemasRepository)
+	}
+	if cfg.JetStreamSchemasRef != "v1.2.3" {
+		t.Errorf("unexpected ref: %s", cfg.JetStreamSchemasRef)
+	}
+	if cfg.JetStreamSchemasDir != "api" {
+		t.Errorf("unexpected dir: %s", cfg.JetStreamSchemasDir)
+	}
+	if cfg.JetStreamSchemasPath != "/src/schemas" {
+		t.Errorf("unexpected path: %s", cfg.JetStreamSchemasPath)
+	}
+}
+
+func TestLoadFromEnv_JetStreamSchemas(t *testing.T) {
+	t.Setenv("NATS_DOCS_JETSTREAM_SCHEMAS_ENABLED", "true")
+	t.Setenv("NATS_DOCS_JETSTREAM_SCHEMAS_PATH", "/src/schemas")
+	t.Setenv("NATS_DOCS_JETSTREAM_SCHEMAS_REPOSITORY", "example/schemas")
+	t.Setenv("NATS_DOCS_JETSTREAM_SCHEMAS_REF", "dev")
+	t.Setenv("NATS_DOCS_JETSTREAM_SCHEMAS_DIR", "api")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if !cfg.JetStreamSchemasEnabled || cfg.JetStreamSchemasPath != "/src/schemas" ||
+		cfg.JetStreamSchemasRepository != "example/schemas" || cfg.JetStreamSchemasRef != "dev" ||
+		cfg.JetStreamSchemasDir != "api" {
+		t.Errorf("environment variables not applied: %+v", cfg)
+	}
+}
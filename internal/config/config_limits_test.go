@@ -0,0 +1,76 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests for request limit configuration
+
+func TestNewConfig_LimitDefaults(t *testing.T) {
+	cfg := NewConfig()
+
+	if cfg.RequestsPerSecond != 10 {
+		t.Errorf("RequestsPerSecond should default to 10, got %g", cfg.RequestsPerSecond)
+	}
+	if cfg.MaxPerHost != 0 {
+		t.Errorf("MaxPerHost should default to 0, got %d", cfg.MaxPerHost)
+	}
+}
+
+func TestValidate_Limits(t *testing.T) {
+	cfg := NewConfig()
+	cfg.RequestsPerSecond = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for zero requests_per_second")
+	}
+
+	cfg = NewConfig()
+	cfg.MaxPerHost = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for negative max_per_host")
+	}
+
+	cfg = NewConfig()
+	cfg.RequestsPerSecond = 2.5
+	cfg.MaxPerHost = 2
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid limits, got: %v", err)
+	}
+}
+
+func TestLoadFromFile_Limits(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+max_concurrent: 8
+requests_per_second: 2.5
+max_per_host: 3
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.MaxConcurrent != 8 || cfg.RequestsPerSecond != 2.5 || cfg.MaxPerHost != 3 {
+		t.Errorf("config file values not applied: %+v", cfg)
+	}
+}
+
+func TestLoadFromEnv_Limits(t *testing.T) {
+	t.Setenv("NATS_DOCS_REQUESTS_PER_SECOND", "4")
+	t.Setenv("NATS_DOCS_MAX_PER_HOST", "2")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.RequestsPerSecond != 4 || cfg.MaxPerHost != 2 {
+		t.Errorf("environment variables not applied: %+v", cfg)
+	}
+}
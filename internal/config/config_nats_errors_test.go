@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests for NATS error definition configuration
+
+func TestNewConfig_NATSErrorsDefaults(t *testing.T) {
+	cfg := NewConfig()
+
+	if cfg.ErrorsEnabled {
+		t.Error("ErrorsEnabled should default to false")
+	}
+	if cfg.ErrorsServerRepository != "nats-io/nats-server" || cfg.ErrorsServerRef != "main" ||
+		cfg.ErrorsServerFile != "server/errors.json" {
+		t.Errorf("unexpected server defaults: %s@%s:%s", cfg.ErrorsServerRepository, cfg.ErrorsServerRef, cfg.ErrorsServerFile)
+	}
+	if cfg.ErrorsClientRepository != "nats-io/nats.go" || cfg.ErrorsClientRef != "main" {
+		t.Errorf("unexpected client defaults: %s@%s", cfg.ErrorsClientRepository, cfg.ErrorsClientRef)
+	}
+}
+
+func TestValidate_NATSErrors(t *testing.T) {
+	cfg := NewConfig()
+	cfg.ErrorsEnabled = true
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("defaults should be valid when enabled: %v", err)
+	}
+
+	cfg.ErrorsServerRepository = "nats-server"
+	cfg.ErrorsClientRef = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for invalid repository and empty ref")
+	}
+
+	// Local paths replace the repository settings
+	cfg.ErrorsServerPath = "/src/nats-server/server/errors.json"
+	cfg.ErrorsClientPath = "/src/nats.go"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("local paths should not require repositories: %v", err)
+	}
+}
+
+func TestLoadFromFile_NATSErrors(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+nats_errors:
+  enabled: true
+  server:
+    path: /src/errors.json
+    repository: example/server
+    ref: v2.11.0
+    file: errors.json
+  client:
+    path: /src/nats.go
+    repository: example/client
+    ref: v1.40.0
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if !cfg.ErrorsEnabled || cfg.ErrorsServerPath != "/src/errors.json" ||
+		cfg.ErrorsServerRepository != "example/server" || cfg.ErrorsServerRef != "v2.11.0" ||
+		cfg.ErrorsServerFile != "errors.json" || cfg.ErrorsClientPath != "/src/nats.go" ||
+		cfg.ErrorsClientRepository != "example/client" || cfg.ErrorsClientRef != "v1.40.0" {
+		t.Errorf("config file values not applied: %+v", cfg)
+	}
+}
+
+func TestLoadFromEnv_NATSErrors(t *testing.T) {
+	t.Setenv("NATS_DOCS_NATS_ERRORS_ENABLED", "true")
+	t.Setenv("NATS_DOCS_NATS_ERRORS_SERVER_PATH", "/src/errors.json")
+	t.Setenv("NATS_DOCS_NATS_ERRORS_CLIENT_REPOSITORY", "example/client")
+	t.Setenv("NATS_DOCS_NATS_ERRORS_CLIENT_REF", "dev")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if !cfg.ErrorsEnabled || cfg.ErrorsServerPath != "/src/errors.json" ||
+		cfg.ErrorsClientRepository != "example/client" || cfg.ErrorsClientRef != "dev" {
+		t.Errorf("environment variables not applied: %+v", cfg)
+	}
+}
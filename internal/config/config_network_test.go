@@ -0,0 +1,146 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Tests for per-source proxy and TLS settings
+
+// writeCertPair writes a self-signed certificate and its key to dir
+func writeCertPair(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "corp-ca"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestValidate_Network(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertPair(t, dir)
+
+	cfg := NewConfig()
+	cfg.DocsNetwork = NetworkConfig{
+		ProxyURL:      "http://proxy.corp.example:3128",
+		NoProxy:       []string{".corp.example"},
+		CAFiles:       []string{certFile},
+		TLSMinVersion: "1.3",
+	}
+	cfg.GitHubNetwork = NetworkConfig{ProxyURL: "socks5://127.0.0.1:1080", ClientCert: certFile, ClientKey: keyFile}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("valid network settings rejected: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"proxy scheme", func(c *Config) { c.DocsNetwork.ProxyURL = "ftp://proxy.corp.example" }},
+		{"proxy without host", func(c *Config) { c.SynadiaNetwork.ProxyURL = "http://" }},
+		{"missing CA file", func(c *Config) { c.GitHubNetwork.CAFiles = []string{filepath.Join(dir, "missing.pem")} }},
+		{"CA file without certificate", func(c *Config) { c.DocsNetwork.CAFiles = []string{keyFile} }},
+		{"cert without key", func(c *Config) { c.SynadiaNetwork.ClientCert = certFile }},
+		{"key without cert", func(c *Config) { c.SynadiaNetwork.ClientKey = keyFile }},
+		{"swapped cert and key", func(c *Config) { c.GitHubNetwork.ClientCert, c.GitHubNetwork.ClientKey = keyFile, certFile }},
+		{"TLS version", func(c *Config) { c.DocsNetwork.TLSMinVersion = "1.4" }},
+	}
+	for _, tt := range tests {
+		cfg := NewConfig()
+		tt.modify(cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", tt.name)
+		}
+	}
+}
+
+func TestLoadFromFile_Network(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertPair(t, dir)
+
+	configPath := filepath.Join(dir, "config.yaml")
+	configContent := `
+docs_network:
+  proxy_url: http://proxy.corp.example:3128
+  no_proxy: [".corp.example", "10.0.0.0/8"]
+synadia:
+  network:
+    tls_min_version: "1.3"
+github:
+  network:
+    ca_files: [` + certFile + `]
+    client_cert: ` + certFile + `
+    client_key: ` + keyFile + `
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.DocsNetwork.ProxyURL != "http://proxy.corp.example:3128" || len(cfg.DocsNetwork.NoProxy) != 2 {
+		t.Errorf("unexpected docs network: %+v", cfg.DocsNetwork)
+	}
+	if cfg.SynadiaNetwork.TLSMinVersion != "1.3" {
+		t.Errorf("unexpected synadia network: %+v", cfg.SynadiaNetwork)
+	}
+	if len(cfg.GitHubNetwork.CAFiles) != 1 || cfg.GitHubNetwork.ClientCert != certFile || cfg.GitHubNetwork.ClientKey != keyFile {
+		t.Errorf("unexpected github network: %+v", cfg.GitHubNetwork)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("loaded network settings rejected: %v", err)
+	}
+}
+
+func TestLoadFromEnv_Network(t *testing.T) {
+	t.Setenv("NATS_DOCS_SYNADIA_PROXY_URL", "https://proxy.corp.example")
+	t.Setenv("NATS_DOCS_SYNADIA_NO_PROXY", "localhost, .corp.example")
+	t.Setenv("NATS_DOCS_GITHUB_TLS_MIN_VERSION", "1.3")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.SynadiaNetwork.ProxyURL != "https://proxy.corp.example" || len(cfg.SynadiaNetwork.NoProxy) != 2 {
+		t.Errorf("unexpected synadia network from environment: %+v", cfg.SynadiaNetwork)
+	}
+	if cfg.GitHubNetwork.TLSMinVersion != "1.3" || cfg.DocsNetwork.ProxyURL != "" {
+		t.Errorf("unexpected network settings from environment: %+v, %+v", cfg.GitHubNetwork, cfg.DocsNetwork)
+	}
+}
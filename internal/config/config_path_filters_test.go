@@ -0,0 +1,76 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Tests for documentation site page path filters
+
+func TestValidate_PathFilters(t *testing.T) {
+	cfg := NewConfig()
+	cfg.DocsIncludePaths = []string{"nats-concepts/**", "running-a-nats-service"}
+	cfg.SynadiaExcludePaths = []string{"**/legacy/**", "*.pdf"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("valid path filters rejected: %v", err)
+	}
+
+	cfg.DocsExcludePaths = []string{"reference/["}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for an invalid exclude pattern")
+	}
+
+	cfg.DocsExcludePaths = nil
+	cfg.SynadiaIncludePaths = []string{""}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for an empty include pattern")
+	}
+}
+
+func TestLoadFromFile_PathFilters(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+docs_include_paths:
+  - nats-concepts/**
+docs_exclude_paths:
+  - legacy
+synadia:
+  exclude_paths:
+    - "**/*.pdf"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if want := []string{"nats-concepts/**"}; !reflect.DeepEqual(cfg.DocsIncludePaths, want) {
+		t.Errorf("expected docs include paths %v, got %v", want, cfg.DocsIncludePaths)
+	}
+	if want := []string{"legacy"}; !reflect.DeepEqual(cfg.DocsExcludePaths, want) {
+		t.Errorf("expected docs exclude paths %v, got %v", want, cfg.DocsExcludePaths)
+	}
+	if want := []string{"**/*.pdf"}; !reflect.DeepEqual(cfg.SynadiaExcludePaths, want) {
+		t.Errorf("expected synadia exclude paths %v, got %v", want, cfg.SynadiaExcludePaths)
+	}
+}
+
+func TestLoadFromEnv_PathFilters(t *testing.T) {
+	t.Setenv("NATS_DOCS_DOCS_EXCLUDE_PATHS", "legacy/**, ,*.pdf")
+	t.Setenv("NATS_DOCS_SYNADIA_INCLUDE_PATHS", "platform/**")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if want := []string{"legacy/**", "*.pdf"}; !reflect.DeepEqual(cfg.DocsExcludePaths, want) {
+		t.Errorf("expected docs exclude paths %v, got %v", want, cfg.DocsExcludePaths)
+	}
+	if want := []string{"platform/**"}; !reflect.DeepEqual(cfg.SynadiaIncludePaths, want) {
+		t.Errorf("expected synadia include paths %v, got %v", want, cfg.SynadiaIncludePaths)
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Tests for release notes configuration
+
+func TestNewConfig_ReleaseNotesDefaults(t *testing.T) {
+	cfg := NewConfig()
+
+	if cfg.ReleaseNotesEnabled {
+		t.Error("ReleaseNotesEnabled should default to false")
+	}
+	if cfg.ReleaseNotesPath != "" {
+		t.Errorf("ReleaseNotesPath should default to empty, got %q", cfg.ReleaseNotesPath)
+	}
+	if !reflect.DeepEqual(cfg.ReleaseNotesRepositories, []string{"nats-io/nats-server"}) {
+		t.Errorf("unexpected default repositories: %v", cfg.ReleaseNotesRepositories)
+	}
+	if cfg.ReleaseNotesRef != "main" {
+		t.Errorf("ReleaseNotesRef should default to main, got %q", cfg.ReleaseNotesRef)
+	}
+}
+
+func TestValidate_ReleaseNotes(t *testing.T) {
+	cfg := NewConfig()
+	cfg.ReleaseNotesEnabled = true
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("defaults should be valid when enabled: %v", err)
+	}
+
+	cfg.ReleaseNotesRepositories = []string{"nats-server"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for invalid repository")
+	}
+
+	cfg.ReleaseNotesRepositories = nil
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for empty repositories")
+	}
+
+	// A local mirror replaces the repository settings
+	cfg.ReleaseNotesPath = "/src/release-notes"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("local path should not require repositories: %v", err)
+	}
+}
+
+func TestLoadFromFile_ReleaseNotes(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+release_notes:
+  enabled: true
+  path: /src/release-notes
+  repositories:
+    - nats-io/nats-server
+    - nats-io/nats.go
+  ref: release/v2.11
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if !cfg.ReleaseNotesEnabled || cfg.ReleaseNotesPath != "/src/release-notes" || cfg.ReleaseNotesRef != "release/v2.11" ||
+		!reflect.DeepEqual(cfg.ReleaseNotesRepositories, []string{"nats-io/nats-server", "nats-io/nats.go"}) {
+		t.Errorf("config file values not applied: %+v", cfg)
+	}
+}
+
+func TestLoadFromEnv_ReleaseNotes(t *testing.T) {
+	t.Setenv("NATS_DOCS_RELEASE_NOTES_ENABLED", "true")
+	t.Setenv("NATS_DOCS_RELEASE_NOTES_REPOSITORIES", "nats-io/nats-server, nats-io/nats.js")
+	t.Setenv("NATS_DOCS_RELEASE_NOTES_REF", "dev")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if !cfg.ReleaseNotesEnabled || cfg.ReleaseNotesRef != "dev" ||
+		!reflect.DeepEqual(cfg.ReleaseNotesRepositories, []string{"nats-io/nats-server", "nats-io/nats.js"}) {
+		t.Errorf("environment variables not applied: %+v", cfg)
+	}
+}
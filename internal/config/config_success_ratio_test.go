@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests for the minimum share of pages each documentation site must deliver
+
+func TestNewConfig_MinSuccessRatioDefaults(t *testing.T) {
+	cfg := NewConfig()
+
+	if cfg.DocsMinSuccessRatio != 0.9 || cfg.SynadiaMinSuccessRatio != 0.9 || cfg.GitHubMinSuccessRatio != 0.9 {
+		t.Errorf("min success ratios should default to 0.9, got %g, %g and %g", cfg.DocsMinSuccessRatio, cfg.SynadiaMinSuccessRatio, cfg.GitHubMinSuccessRatio)
+	}
+}
+
+func TestValidate_MinSuccessRatio(t *testing.T) {
+	for _, ratio := range []float64{-0.1, 1.5} {
+		cfg := NewConfig()
+		cfg.DocsMinSuccessRatio = ratio
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation error for docs_min_success_ratio %g", ratio)
+		}
+
+		cfg = NewConfig()
+		cfg.SynadiaMinSuccessRatio = ratio
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation error for synadia.min_success_ratio %g", ratio)
+		}
+
+		cfg = NewConfig()
+		cfg.GitHubMinSuccessRatio = ratio
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected validation error for github.min_success_ratio %g", ratio)
+		}
+	}
+
+	cfg := NewConfig()
+	cfg.DocsMinSuccessRatio = 0
+	cfg.SynadiaMinSuccessRatio = 1
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected the bounds to be valid, got: %v", err)
+	}
+}
+
+func TestLoadFromFile_MinSuccessRatio(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+docs_min_success_ratio: 0.75
+synadia:
+  min_success_ratio: 0.5
+github:
+  min_success_ratio: 0.25
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.DocsMinSuccessRatio != 0.75 || cfg.SynadiaMinSuccessRatio != 0.5 || cfg.GitHubMinSuccessRatio != 0.25 {
+		t.Errorf("config file values not applied: %g, %g, %g", cfg.DocsMinSuccessRatio, cfg.SynadiaMinSuccessRatio, cfg.GitHubMinSuccessRatio)
+	}
+}
+
+func TestLoadFromEnv_MinSuccessRatio(t *testing.T) {
+	t.Setenv("NATS_DOCS_DOCS_MIN_SUCCESS_RATIO", "1")
+	t.Setenv("NATS_DOCS_SYNADIA_MIN_SUCCESS_RATIO", "0.8")
+	t.Setenv("NATS_DOCS_GITHUB_MIN_SUCCESS_RATIO", "0.6")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.DocsMinSuccessRatio != 1 || cfg.SynadiaMinSuccessRatio != 0.8 || cfg.GitHubMinSuccessRatio != 0.6 {
+		t.Errorf("environment variables not applied: %g, %g, %g", cfg.DocsMinSuccessRatio, cfg.SynadiaMinSuccessRatio, cfg.GitHubMinSuccessRatio)
+	}
+}
@@ -0,0 +1,86 @@
+package configref
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// Catalog resolves configuration option names to options. It is built from index
+// documents so it can be restored from the documentation cache, and is immutable
+// once created so it is safe for concurrent use.
+type Catalog struct {
+	options []*Option
+}
+
+// NewCatalog creates a catalog from option documents. Documents without
+// configuration option metadata are ignored. Options documented on several pages
+// are merged, keeping the first non-empty value of every field.
+func NewCatalog(docs []*index.Document) *Catalog {
+	byPath := make(map[string]*Option)
+	var options []*Option
+
+	for _, doc := range docs {
+		option := OptionFromDocument(doc)
+		if option == nil {
+			continue
+		}
+
+		existing, ok := byPath[option.Path()]
+		if !ok {
+			byPath[option.Path()] = option
+			options = append(options, option)
+			continue
+		}
+		if existing.Type == "" {
+			existing.Type = option.Type
+		}
+		if existing.Default == "" {
+			existing.Default = option.Default
+		}
+		if existing.Description == "" {
+			existing.Description = option.Description
+		}
+	}
+
+	sort.Slice(options, func(i, j int) bool {
+		return options[i].Path() < options[j].Path()
+	})
+
+	return &Catalog{options: options}
+}
+
+// Count returns the number of options in the catalog
+func (c *Catalog) Count() int {
+	return len(c.options)
+}
+
+// Lookup finds options by name or fully qualified path, case-insensitively.
+// Exact matches on the name (e.g., "max_payload") or path (e.g., "jetstream.store_dir")
+// are returned when present; otherwise options whose name or path starts with the
+// query are returned. Whitespace separates path segments, so "jetstream store_dir"
+// equals "jetstream.store_dir".
+func (c *Catalog) Lookup(query string) []*Option {
+	key := strings.ToLower(strings.Join(strings.Fields(query), "."))
+	if key == "" {
+		return nil
+	}
+
+	var exact, prefix []*Option
+	for _, option := range c.options {
+		name := strings.ToLower(option.Name)
+		path := strings.ToLower(option.Path())
+		switch {
+		case key == name || key == path:
+			exact = append(exact, option)
+		case strings.HasPrefix(name, key) || strings.HasPrefix(path, key):
+			prefix = append(prefix, option)
+		}
+	}
+
+	if len(exact) > 0 {
+		return exact
+	}
+	return prefix
+}
@@ -0,0 +1,382 @@
+// Package configref extracts the nats-server configuration reference (the option
+// tables of the server configuration pages) into a structured option catalogue,
+// so questions like "what is the default for max_payload" get precise answers.
+package configref
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+	"golang.org/x/net/html"
+)
+
+// Metadata keys stored on index.Document for configuration option documents
+const (
+	MetaName    = "config_option_name"    // Option name (e.g., "store_dir")
+	MetaBlock   = "config_option_block"   // Dotted block path (e.g., "jetstream"), empty for top-level options
+	MetaType    = "config_option_type"    // Value type (e.g., "string", "duration")
+	MetaDefault = "config_option_default" // Default value as documented
+)
+
+// DocIDPrefix is the prefix of all configuration option document IDs
+const DocIDPrefix = "nats-config/"
+
+// Option is a single nats-server configuration option
+type Option struct {
+	Name        string // Option name (e.g., "max_payload")
+	Block       string // Dotted path of the enclosing block (e.g., "cluster.tls"), empty for top-level
+	Type        string // Value type, documented or inferred from the default
+	Default     string // Documented default value
+	Description string // Option description
+	SourceURL   string // Page the option was extracted from
+}
+
+// Path returns the fully qualified option name (e.g., "jetstream.store_dir")
+func (o *Option) Path() string {
+	if o.Block == "" {
+		return o.Name
+	}
+	return o.Block + "." + o.Name
+}
+
+// IsConfigPage reports whether a documentation page path belongs to the server
+// configuration reference (the "Running a NATS service" section of docs.nats.io)
+func IsConfigPage(path string) bool {
+	return strings.Contains(path, "/running-a-nats-service/")
+}
+
+// knownBlocks lists configuration blocks that headings are mapped to
+var knownBlocks = map[string]bool{
+	"accounts":      true,
+	"authorization": true,
+	"cluster":       true,
+	"gateway":       true,
+	"gateways":      true,
+	"jetstream":     true,
+	"leafnodes":     true,
+	"mqtt":          true,
+	"ocsp":          true,
+	"ocsp_cache":    true,
+	"permissions":   true,
+	"remotes":       true,
+	"resolver":      true,
+	"resolver_tls":  true,
+	"tls":           true,
+	"tpm":           true,
+	"users":         true,
+	"websocket":     true,
+}
+
+// blockAliases maps heading spellings to configuration block names
+var blockAliases = map[string]string{
+	"leafnode":   "leafnodes",
+	"leaf_nodes": "leafnodes",
+	"leaf_node":  "leafnodes",
+	"web_socket": "websocket",
+}
+
+// headingNoise lists words stripped from headings before matching block names
+var headingNoise = map[string]bool{
+	"configuration": true,
+	"config":        true,
+	"block":         true,
+	"map":           true,
+	"options":       true,
+	"properties":    true,
+	"section":       true,
+}
+
+// optionNameRe matches plausible option names
+var optionNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_\-]*$`)
+
+// ExtractHTML extracts configuration options from the option tables of an HTML page.
+// Both <table> markup and ARIA tables (role="table", "row", "cell") are recognized.
+// A table is treated as an option table when its header has a property/name column
+// and a description or default column. The block of each option is derived from the
+// enclosing headings (e.g., a table under "jetstream Configuration Block").
+//
+// Parameters:
+//   - content: Raw HTML of the page
+//   - sourceURL: URL of the page, recorded on every option
+//
+// Returns the extracted options in document order.
+func ExtractHTML(content []byte, sourceURL string) ([]*Option, error) {
+	root, err := html.Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	type heading struct {
+		level int
+		text  string
+	}
+	var headings []heading
+	var options []*Option
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if level := headingLevel(n.Data); level > 0 {
+				for len(headings) > 0 && headings[len(headings)-1].level >= level {
+					headings = headings[:len(headings)-1]
+				}
+				headings = append(headings, heading{level: level, text: nodeText(n)})
+				return
+			}
+			if n.Data == "table" || attr(n, "role") == "table" {
+				var blocks []string
+				for _, h := range headings {
+					if block := headingBlock(h.text); block != "" {
+						blocks = append(blocks, block)
+					}
+				}
+				options = append(options, extractTable(n, strings.Join(blocks, "."), sourceURL)...)
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	return options, nil
+}
+
+// extractTable converts the rows of an option table into options
+func extractTable(table *html.Node, block, sourceURL string) []*Option {
+	rows := tableRows(table)
+	if len(rows) < 2 {
+		return nil
+	}
+
+	nameCol, descCol, defaultCol, typeCol := -1, -1, -1, -1
+	for i, header := range rows[0] {
+		h := strings.ToLower(header)
+		switch {
+		case nameCol < 0 && (strings.Contains(h, "property") || strings.Contains(h, "option") ||
+			strings.Contains(h, "name") || strings.Contains(h, "field") || strings.Contains(h, "setting")):
+			nameCol = i
+		case descCol < 0 && strings.Contains(h, "description"):
+			descCol = i
+		case defaultCol < 0 && strings.Contains(h, "default"):
+			defaultCol = i
+		case typeCol < 0 && strings.Contains(h, "type"):
+			typeCol = i
+		}
+	}
+	if nameCol < 0 || (descCol < 0 && defaultCol < 0) {
+		return nil
+	}
+
+	cell := func(row []string, col int) string {
+		if col < 0 || col >= len(row) {
+			return ""
+		}
+		return row[col]
+	}
+
+	var options []*Option
+	for _, row := range rows[1:] {
+		name := cleanValue(cell(row, nameCol))
+		if !optionNameRe.MatchString(name) {
+			continue
+		}
+
+		option := &Option{
+			Name:        name,
+			Block:       block,
+			Type:        strings.ToLower(cleanValue(cell(row, typeCol))),
+			Default:     cleanDefault(cell(row, defaultCol)),
+			Description: cell(row, descCol),
+			SourceURL:   sourceURL,
+		}
+		if option.Type == "" {
+			option.Type = inferType(option.Default)
+		}
+		options = append(options, option)
+	}
+	return options
+}
+
+// tableRows returns the text of every cell, row by row, without descending into
+// nested tables
+func tableRows(table *html.Node) [][]string {
+	var rows [][]string
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if c.Data == "table" || attr(c, "role") == "table" {
+				continue
+			}
+			if c.Data == "tr" || attr(c, "role") == "row" {
+				var cells []string
+				collectCells(c, &cells)
+				if len(cells) > 0 {
+					rows = append(rows, cells)
+				}
+				continue
+			}
+			walk(c)
+		}
+	}
+	walk(table)
+
+	return rows
+}
+
+// collectCells appends the text of every cell within a row
+func collectCells(n *html.Node, cells *[]string) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		role := attr(c, "role")
+		if c.Data == "td" || c.Data == "th" || role == "cell" || role == "columnheader" || role == "rowheader" {
+			*cells = append(*cells, nodeText(c))
+			continue
+		}
+		collectCells(c, cells)
+	}
+}
+
+// headingBlock maps a heading such as "`cluster` Configuration Block" to a block
+// name, or returns "" when the heading does not name a configuration block
+func headingBlock(text string) string {
+	var words []string
+	for _, word := range strings.Fields(strings.ToLower(strings.Trim(text, " `"))) {
+		word = strings.Trim(word, "`'\"")
+		if word != "" && !headingNoise[word] {
+			words = append(words, word)
+		}
+	}
+	name := strings.Join(words, "_")
+	if alias, ok := blockAliases[name]; ok {
+		name = alias
+	}
+	if knownBlocks[name] {
+		return name
+	}
+	return ""
+}
+
+// cleanValue trims whitespace, backticks and quotes from a cell
+func cleanValue(s string) string {
+	return strings.Trim(strings.TrimSpace(s), "`'\" ")
+}
+
+// cleanDefault normalizes a default cell, treating placeholders as no default
+func cleanDefault(s string) string {
+	s = strings.TrimSpace(strings.Trim(strings.TrimSpace(s), "`"))
+	switch strings.ToLower(s) {
+	case "", "-", "n/a", "none":
+		return ""
+	}
+	return s
+}
+
+var (
+	integerRe  = regexp.MustCompile(`^-?[0-9]+$`)
+	durationRe = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h)([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))*$`)
+	sizeRe     = regexp.MustCompile(`(?i)^[0-9]+(\.[0-9]+)?\s*(b|kb|kib|mb|mib|gb|gib|tb|tib)$`)
+)
+
+// inferType infers the value type of an option from its documented default
+func inferType(def string) string {
+	// Durations and sizes are often documented as quoted strings (e.g., "2m")
+	def = strings.Trim(def, `"`)
+	switch {
+	case def == "":
+		return ""
+	case def == "true" || def == "false":
+		return "boolean"
+	case integerRe.MatchString(def):
+		return "number"
+	case durationRe.MatchString(def):
+		return "duration"
+	case sizeRe.MatchString(def):
+		return "size"
+	case strings.HasPrefix(def, "["):
+		return "array"
+	case strings.HasPrefix(def, "{"):
+		return "map"
+	default:
+		return "string"
+	}
+}
+
+// headingLevel returns the heading level (1-6) or 0 if tag is not a heading
+func headingLevel(tag string) int {
+	if len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6' {
+		return int(tag[1] - '0')
+	}
+	return 0
+}
+
+// attr returns the value of an attribute, or "" if it is not set
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// nodeText returns the whitespace-collapsed text content of a node
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteString(" ")
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// Document converts the option into a document for caching
+func (o *Option) Document() *index.Document {
+	return &index.Document{
+		ID:          DocIDPrefix + o.Path(),
+		Title:       o.Path(),
+		URL:         o.SourceURL,
+		Content:     o.Description,
+		LastUpdated: time.Now(),
+		Metadata: map[string]string{
+			MetaName:    o.Name,
+			MetaBlock:   o.Block,
+			MetaType:    o.Type,
+			MetaDefault: o.Default,
+		},
+	}
+}
+
+// OptionFromDocument restores an option from a document created by Option.Document.
+// It returns nil for documents without configuration option metadata.
+func OptionFromDocument(doc *index.Document) *Option {
+	if doc == nil || doc.Metadata[MetaName] == "" {
+		return nil
+	}
+	return &Option{
+		Name:        doc.Metadata[MetaName],
+		Block:       doc.Metadata[MetaBlock],
+		Type:        doc.Metadata[MetaType],
+		Default:     doc.Metadata[MetaDefault],
+		Description: doc.Content,
+		SourceURL:   doc.URL,
+	}
+}
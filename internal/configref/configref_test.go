@@ -0,0 +1,194 @@
+package configref
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+const testConfigPage = `<html><head><title>Configuring NATS Server</title></head><body>
+<h1>Configuring NATS Server</h1>
+<h2>Limits</h2>
+<table>
+  <thead><tr><th>Property</th><th>Description</th><th>Default</th><th>Reloadable</th></tr></thead>
+  <tbody>
+    <tr><td><code>max_payload</code></td><td>Maximum number of bytes in a message payload.</td><td><code>1MB</code></td><td>Yes</td></tr>
+    <tr><td><code>max_connections</code></td><td>Maximum number of active client connections.</td><td>64K</td><td>Yes</td></tr>
+    <tr><td><code>ping_interval</code></td><td>Duration at which pings are sent to clients.</td><td><code>"2m"</code></td><td>Yes</td></tr>
+    <tr><td>See the TLS section</td><td>Not an option</td><td></td><td></td></tr>
+  </tbody>
+</table>
+<h2><code>jetstream</code> Configuration Block</h2>
+<div role="table">
+  <div role="row"><div role="columnheader">Property</div><div role="columnheader">Description</div><div role="columnheader">Default</div></div>
+  <div role="row"><div role="cell">store_dir</div><div role="cell">Directory to use for JetStream storage.</div><div role="cell">/tmp/nats/jetstream</div></div>
+  <div role="row"><div role="cell">max_memory_store</div><div role="cell">Maximum size of the memory storage.</div><div role="cell">75% of available memory</div></div>
+</div>
+<h3>TLS Map</h3>
+<table>
+  <tr><th>Property</th><th>Type</th><th>Description</th></tr>
+  <tr><td>verify</td><td>boolean</td><td>Require client certificates.</td></tr>
+</table>
+<h2>Examples</h2>
+<table>
+  <tr><th>Command</th><th>Result</th></tr>
+  <tr><td>nats-server -c x.conf</td><td>runs</td></tr>
+</table>
+</body></html>`
+
+func TestExtractHTML(t *testing.T) {
+	options, err := ExtractHTML([]byte(testConfigPage), "https://docs.nats.io/running-a-nats-service/configuration")
+	if err != nil {
+		t.Fatalf("ExtractHTML failed: %v", err)
+	}
+
+	want := []Option{
+		{Name: "max_payload", Block: "", Type: "size", Default: "1MB"},
+		{Name: "max_connections", Block: "", Type: "string", Default: "64K"},
+		{Name: "ping_interval", Block: "", Type: "duration", Default: `"2m"`},
+		{Name: "store_dir", Block: "jetstream", Type: "string", Default: "/tmp/nats/jetstream"},
+		{Name: "max_memory_store", Block: "jetstream", Type: "string", Default: "75% of available memory"},
+		{Name: "verify", Block: "jetstream.tls", Type: "boolean", Default: ""},
+	}
+
+	if len(options) != len(want) {
+		for _, o := range options {
+			t.Logf("option: %+v", *o)
+		}
+		t.Fatalf("expected %d options, got %d", len(want), len(options))
+	}
+
+	for i, w := range want {
+		got := options[i]
+		if got.Name != w.Name || got.Block != w.Block || got.Type != w.Type || got.Default != w.Default {
+			t.Errorf("option %d: expected %+v, got %+v", i, w, *got)
+		}
+		if got.SourceURL != "https://docs.nats.io/running-a-nats-service/configuration" {
+			t.Errorf("option %d: unexpected source URL %s", i, got.SourceURL)
+		}
+	}
+
+	if options[0].Description != "Maximum number of bytes in a message payload." {
+		t.Errorf("unexpected description: %q", options[0].Description)
+	}
+}
+
+func TestHeadingBlock(t *testing.T) {
+	tests := []struct {
+		heading string
+		want    string
+	}{
+		{"cluster Configuration Block", "cluster"},
+		{"`leafnodes` configuration", "leafnodes"},
+		{"Leaf Node Configuration", "leafnodes"},
+		{"TLS Map", "tls"},
+		{"Clustering", ""},
+		{"Connectivity", ""},
+	}
+
+	for _, tt := range tests {
+		if got := headingBlock(tt.heading); got != tt.want {
+			t.Errorf("headingBlock(%q) = %q, want %q", tt.heading, got, tt.want)
+		}
+	}
+}
+
+func TestInferType(t *testing.T) {
+	tests := []struct {
+		def  string
+		want string
+	}{
+		{"", ""},
+		{"true", "boolean"},
+		{"4222", "number"},
+		{"2s", "duration"},
+		{"1h30m", "duration"},
+		{"64MB", "size"},
+		{"[]", "array"},
+		{"{}", "map"},
+		{"0.0.0.0", "string"},
+	}
+
+	for _, tt := range tests {
+		if got := inferType(tt.def); got != tt.want {
+			t.Errorf("inferType(%q) = %q, want %q", tt.def, got, tt.want)
+		}
+	}
+}
+
+func TestCatalogLookup(t *testing.T) {
+	options := []*Option{
+		{Name: "max_payload", Default: "1MB", SourceURL: "https://docs.nats.io/a"},
+		{Name: "max_pending", Default: "64MB"},
+		{Name: "store_dir", Block: "jetstream", Default: "/tmp/nats/jetstream"},
+		{Name: "max_memory_store", Block: "jetstream"},
+		// Duplicate from a second page fills in missing fields
+		{Name: "max_memory_store", Block: "jetstream", Default: "75% of memory", Description: "Memory limit"},
+	}
+
+	var docs []*index.Document
+	for _, option := range options {
+		docs = append(docs, option.Document())
+	}
+	// Documents without option metadata are ignored
+	docs = append(docs, &index.Document{ID: "other", Title: "Other"})
+
+	catalog := NewCatalog(docs)
+	if catalog.Count() != 4 {
+		t.Fatalf("expected 4 options after merging, got %d", catalog.Count())
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"max_payload", []string{"max_payload"}},
+		{"MAX_PAYLOAD", []string{"max_payload"}},
+		{"store_dir", []string{"jetstream.store_dir"}},
+		{"jetstream store_dir", []string{"jetstream.store_dir"}},
+		{"max_p", []string{"max_payload", "max_pending"}},
+		{"jetstream.", []string{"jetstream.max_memory_store", "jetstream.store_dir"}},
+		{"unknown", nil},
+		{"  ", nil},
+	}
+
+	for _, tt := range tests {
+		var paths []string
+		for _, option := range catalog.Lookup(tt.query) {
+			paths = append(paths, option.Path())
+		}
+		if strings.Join(paths, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Lookup(%q) = %v, want %v", tt.query, paths, tt.want)
+		}
+	}
+
+	merged := catalog.Lookup("jetstream.max_memory_store")[0]
+	if merged.Default != "75% of memory" || merged.Description != "Memory limit" {
+		t.Errorf("duplicate options not merged: %+v", *merged)
+	}
+}
+
+func TestOptionDocumentRoundTrip(t *testing.T) {
+	option := &Option{
+		Name:        "store_dir",
+		Block:       "jetstream",
+		Type:        "string",
+		Default:     "/tmp/nats/jetstream",
+		Description: "Directory to use for JetStream storage.",
+		SourceURL:   "https://docs.nats.io/running-a-nats-service/configuration",
+	}
+
+	doc := option.Document()
+	if doc.ID != "nats-config/jetstream.store_dir" {
+		t.Errorf("unexpected ID: %s", doc.ID)
+	}
+
+	restored := OptionFromDocument(doc)
+	if restored == nil || *restored != *option {
+		t.Errorf("round trip mismatch: %+v", restored)
+	}
+	if OptionFromDocument(&index.Document{ID: "x"}) != nil {
+		t.Error("expected nil for document without option metadata")
+	}
+}
@@ -0,0 +1,236 @@
+package errref
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// maxFuzzyResults limits the number of entries returned for message matches
+const maxFuzzyResults = 5
+
+// minFuzzyScore is the minimum word overlap for a fuzzy message match
+const minFuzzyScore = 0.5
+
+// Catalog resolves error codes, constant names and messages to error entries.
+// It is built from index documents so it can be restored from the documentation
+// cache, and is immutable once created so it is safe for concurrent use.
+type Catalog struct {
+	entries  []*Entry
+	patterns []*regexp.Regexp // Message templates compiled to patterns, by entry
+	words    [][]string       // Normalized message words, by entry
+}
+
+// NewCatalog creates a catalog from error documents. Documents without error
+// metadata are ignored.
+func NewCatalog(docs []*index.Document) *Catalog {
+	c := &Catalog{}
+	for _, doc := range docs {
+		if entry := EntryFromDocument(doc); entry != nil {
+			c.entries = append(c.entries, entry)
+		}
+	}
+
+	sort.SliceStable(c.entries, func(i, j int) bool {
+		if c.entries[i].Kind != c.entries[j].Kind {
+			return c.entries[i].Kind > c.entries[j].Kind // server before client
+		}
+		return c.entries[i].QualifiedName() < c.entries[j].QualifiedName()
+	})
+
+	c.patterns = make([]*regexp.Regexp, len(c.entries))
+	c.words = make([][]string, len(c.entries))
+	for i, entry := range c.entries {
+		c.patterns[i] = messagePattern(entry.Message)
+		c.words[i] = messageWords(entry.Message)
+	}
+
+	return c
+}
+
+// Count returns the number of entries in the catalog
+func (c *Catalog) Count() int {
+	return len(c.entries)
+}
+
+// Lookup resolves a query to error entries. Numeric queries match the JetStream API
+// error code (e.g., "10058"), falling back to the HTTP-style status code. Otherwise
+// the query is matched case-insensitively against constant names (with or without
+// the package, e.g., "ErrNoResponders" or "nats.ErrNoResponders") and, failing that,
+// fuzzily against error messages, so a logged line such as "nats: no responders
+// available for request" finds its definition.
+func (c *Catalog) Lookup(query string) []*Entry {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	if code, err := strconv.Atoi(query); err == nil {
+		return c.lookupCode(code)
+	}
+
+	if entries := c.lookupConstant(query); len(entries) > 0 {
+		return entries
+	}
+
+	return c.lookupMessage(query)
+}
+
+// lookupCode finds entries by JetStream API error code, then by status code
+func (c *Catalog) lookupCode(code int) []*Entry {
+	var byErrorCode, byStatus []*Entry
+	for _, entry := range c.entries {
+		switch {
+		case entry.ErrorCode == code:
+			byErrorCode = append(byErrorCode, entry)
+		case entry.Code == code:
+			byStatus = append(byStatus, entry)
+		}
+	}
+	if len(byErrorCode) > 0 {
+		return byErrorCode
+	}
+	return byStatus
+}
+
+// lookupConstant finds entries by constant name
+func (c *Catalog) lookupConstant(query string) []*Entry {
+	var result []*Entry
+	for _, entry := range c.entries {
+		if strings.EqualFold(query, entry.Constant) || strings.EqualFold(query, entry.QualifiedName()) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// lookupMessage finds entries whose message matches the query. Messages contained
+// in the query (or matching it as a template) score highest; otherwise entries are
+// ranked by word overlap.
+func (c *Catalog) lookupMessage(query string) []*Entry {
+	normalized := normalizeMessage(query)
+	queryWords := messageWords(query)
+	if normalized == "" {
+		return nil
+	}
+
+	type scored struct {
+		entry *Entry
+		score float64
+	}
+	var matches []scored
+	for i, entry := range c.entries {
+		message := normalizeMessage(entry.Message)
+		if message == "" {
+			continue
+		}
+
+		var score float64
+		switch {
+		case normalized == message:
+			score = 3
+		case c.patterns[i] != nil && c.patterns[i].MatchString(normalized):
+			score = 2
+		case strings.Contains(normalized, message) || strings.Contains(message, normalized):
+			score = 1 + float64(min(len(normalized), len(message)))/float64(max(len(normalized), len(message)))
+		default:
+			score = wordOverlap(queryWords, c.words[i])
+		}
+
+		if score >= minFuzzyScore {
+			matches = append(matches, scored{entry: entry, score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	var result []*Entry
+	for _, m := range matches {
+		if len(result) == maxFuzzyResults {
+			break
+		}
+		result = append(result, m.entry)
+	}
+	return result
+}
+
+// placeholderRe matches {placeholders} in server error description templates
+var placeholderRe = regexp.MustCompile(`\{[^}]*\}`)
+
+// normalizeMessage lowercases a message, strips the "nats: " prefix, template
+// placeholders and punctuation, and collapses whitespace
+func normalizeMessage(message string) string {
+	message = strings.ToLower(strings.TrimSpace(message))
+	message = strings.TrimPrefix(message, "nats: ")
+	message = placeholderRe.ReplaceAllString(message, " ")
+
+	var b strings.Builder
+	for _, r := range message {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// messageWords returns the distinct normalized words of a message
+func messageWords(message string) []string {
+	seen := make(map[string]bool)
+	var words []string
+	for _, word := range strings.Fields(normalizeMessage(message)) {
+		if word == "nats" || seen[word] {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	return words
+}
+
+// messagePattern compiles a description template with {placeholders} into a pattern
+// matching normalized messages, or returns nil for messages without placeholders
+func messagePattern(message string) *regexp.Regexp {
+	if !placeholderRe.MatchString(message) {
+		return nil
+	}
+
+	var parts []string
+	for _, literal := range placeholderRe.Split(strings.TrimPrefix(strings.ToLower(message), "nats: "), -1) {
+		parts = append(parts, regexp.QuoteMeta(normalizeMessage(literal)))
+	}
+	pattern := strings.Join(parts, ".*")
+	if strings.Trim(strings.ReplaceAll(pattern, ".*", ""), " ") == "" {
+		return nil
+	}
+
+	re, err := regexp.Compile("^" + pattern + "$")
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+// wordOverlap returns the Dice coefficient of two word sets
+func wordOverlap(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(b))
+	for _, word := range b {
+		set[word] = true
+	}
+	common := 0
+	for _, word := range a {
+		if set[word] {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
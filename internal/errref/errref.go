@@ -0,0 +1,272 @@
+// Package errref ingests NATS error definitions — the nats-server JetStream error
+// definition file (server/errors.json) and the error variables of client libraries
+// such as nats.go — so logged errors and API error codes can be explained.
+package errref
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// Metadata keys stored on index.Document for error documents
+const (
+	MetaKind      = "nats_error_kind"       // "server" or "client"
+	MetaConstant  = "nats_error_constant"   // Constant or variable name (e.g., "JSStreamNotFoundErr")
+	MetaPackage   = "nats_error_package"    // Declaring Go package of client errors (e.g., "nats")
+	MetaCode      = "nats_error_code"       // HTTP-style status code of server errors
+	MetaErrorCode = "nats_error_error_code" // JetStream API error code (e.g., "10059")
+	MetaMessage   = "nats_error_message"    // Error message or description template
+	MetaComment   = "nats_error_comment"    // Explanation from the definition
+	MetaHelp      = "nats_error_help"       // Additional help text
+	MetaDocURL    = "nats_error_doc_url"    // Documentation URL given by the definition
+)
+
+// DocIDPrefix is the prefix of all error document IDs
+const DocIDPrefix = "nats-error/"
+
+// Error kinds
+const (
+	KindServer = "server"
+	KindClient = "client"
+)
+
+// Entry is a single NATS error definition
+type Entry struct {
+	Kind      string // KindServer or KindClient
+	Constant  string // Constant or variable name
+	Package   string // Declaring Go package (client errors only)
+	Code      int    // HTTP-style status code (server errors only)
+	ErrorCode int    // JetStream API error code (server errors only)
+	Message   string // Error message; server descriptions may contain {placeholders}
+	Comment   string // Explanation from the definition
+	Help      string // Additional help text
+	DocURL    string // Documentation URL given by the definition
+	SourceURL string // File the definition was read from
+}
+
+// serverError mirrors an entry of nats-server's server/errors.json
+type serverError struct {
+	Constant    string `json:"constant"`
+	Code        int    `json:"code"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+	Comment     string `json:"comment"`
+	Help        string `json:"help"`
+	URL         string `json:"url"`
+	Deprecates  string `json:"deprecates"`
+}
+
+// ParseServerErrors parses nats-server's JetStream error definition file.
+//
+// Parameters:
+//   - content: Contents of server/errors.json
+//   - sourceURL: URL of the file, recorded on every entry
+//
+// Returns one entry per error definition.
+func ParseServerErrors(content []byte, sourceURL string) ([]*Entry, error) {
+	var defs []serverError
+	if err := json.Unmarshal(content, &defs); err != nil {
+		return nil, fmt.Errorf("failed to parse error definitions: %w", err)
+	}
+
+	entries := make([]*Entry, 0, len(defs))
+	for _, def := range defs {
+		if def.Constant == "" {
+			continue
+		}
+		entries = append(entries, &Entry{
+			Kind:      KindServer,
+			Constant:  def.Constant,
+			Code:      def.Code,
+			ErrorCode: def.ErrorCode,
+			Message:   def.Description,
+			Comment:   def.Comment,
+			Help:      def.Help,
+			DocURL:    def.URL,
+			SourceURL: sourceURL,
+		})
+	}
+	return entries, nil
+}
+
+// IsClientSourcePath reports whether a repository path is a Go source file that may
+// declare client errors (tests, examples and internal packages are excluded)
+func IsClientSourcePath(p string) bool {
+	if !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+		return false
+	}
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		switch dir {
+		case "test", "tests", "testdata", "examples", "example", "internal", "vendor", "bench":
+			return false
+		}
+	}
+	return true
+}
+
+// ParseClientErrors extracts exported error variables (names starting with "Err")
+// from a Go source file, such as `ErrNoResponders = errors.New("nats: no responders
+// available for request")`. The message is the first string literal of the value,
+// which also covers wrapped and struct-based errors.
+//
+// Parameters:
+//   - content: Go source
+//   - filePath: Path of the file, used for parse errors
+//   - sourceURL: URL of the file, recorded on every entry
+func ParseClientErrors(content []byte, filePath, sourceURL string) ([]*Entry, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, content, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range vs.Names {
+				if !name.IsExported() || !strings.HasPrefix(name.Name, "Err") || i >= len(vs.Values) {
+					continue
+				}
+				message := firstStringLiteral(vs.Values[i])
+				if message == "" {
+					continue
+				}
+
+				comment := vs.Doc.Text()
+				if comment == "" && len(gen.Specs) == 1 {
+					comment = gen.Doc.Text()
+				}
+
+				entries = append(entries, &Entry{
+					Kind:      KindClient,
+					Constant:  name.Name,
+					Package:   file.Name.Name,
+					Message:   message,
+					Comment:   strings.TrimSpace(comment),
+					SourceURL: sourceURL,
+				})
+			}
+		}
+	}
+	return entries, nil
+}
+
+// firstStringLiteral returns the value of the first string literal in an expression
+func firstStringLiteral(expr ast.Expr) string {
+	var result string
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if result != "" {
+			return false
+		}
+		if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if value, err := strconv.Unquote(lit.Value); err == nil {
+				result = value
+			}
+			return false
+		}
+		return true
+	})
+	return result
+}
+
+// QualifiedName returns the constant name, qualified by package for client errors
+func (e *Entry) QualifiedName() string {
+	if e.Package != "" {
+		return e.Package + "." + e.Constant
+	}
+	return e.Constant
+}
+
+// Document converts the entry into a searchable document
+func (e *Entry) Document() *index.Document {
+	var content strings.Builder
+	content.WriteString(e.Message)
+	for _, text := range []string{e.Comment, e.Help} {
+		if text != "" {
+			content.WriteString("\n")
+			content.WriteString(text)
+		}
+	}
+
+	title := fmt.Sprintf("%s: %s", e.QualifiedName(), e.Message)
+	if e.ErrorCode != 0 {
+		title = fmt.Sprintf("%s (%d): %s", e.Constant, e.ErrorCode, e.Message)
+	}
+
+	metadata := map[string]string{
+		MetaKind:     e.Kind,
+		MetaConstant: e.Constant,
+		MetaMessage:  e.Message,
+	}
+	if e.Package != "" {
+		metadata[MetaPackage] = e.Package
+	}
+	if e.Code != 0 {
+		metadata[MetaCode] = strconv.Itoa(e.Code)
+	}
+	if e.ErrorCode != 0 {
+		metadata[MetaErrorCode] = strconv.Itoa(e.ErrorCode)
+	}
+	if e.Comment != "" {
+		metadata[MetaComment] = e.Comment
+	}
+	if e.Help != "" {
+		metadata[MetaHelp] = e.Help
+	}
+	if e.DocURL != "" {
+		metadata[MetaDocURL] = e.DocURL
+	}
+
+	return &index.Document{
+		ID:          DocIDPrefix + e.Kind + "/" + e.QualifiedName(),
+		Title:       title,
+		URL:         e.SourceURL,
+		Content:     content.String(),
+		LastUpdated: time.Now(),
+		Sections: []index.Section{
+			{Heading: title, Content: content.String(), Level: 1},
+		},
+		Metadata: metadata,
+	}
+}
+
+// EntryFromDocument restores an entry from a document created by Entry.Document.
+// It returns nil for documents without error metadata.
+func EntryFromDocument(doc *index.Document) *Entry {
+	if doc == nil || doc.Metadata[MetaConstant] == "" {
+		return nil
+	}
+
+	code, _ := strconv.Atoi(doc.Metadata[MetaCode])
+	errorCode, _ := strconv.Atoi(doc.Metadata[MetaErrorCode])
+
+	return &Entry{
+		Kind:      doc.Metadata[MetaKind],
+		Constant:  doc.Metadata[MetaConstant],
+		Package:   doc.Metadata[MetaPackage],
+		Code:      code,
+		ErrorCode: errorCode,
+		Message:   doc.Metadata[MetaMessage],
+		Comment:   doc.Metadata[MetaComment],
+		Help:      doc.Metadata[MetaHelp],
+		DocURL:    doc.Metadata[MetaDocURL],
+		SourceURL: doc.URL,
+	}
+}
@@ -0,0 +1,256 @@
+package errref
+
+import (
+	"testing"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+const testServerErrors = `[
+  {
+    "constant": "JSStreamNotFoundErr",
+    "code": 404,
+    "error_code": 10059,
+    "description": "stream not found",
+    "comment": "",
+    "help": "",
+    "url": "",
+    "deprecates": ""
+  },
+  {
+    "constant": "JSConsumerNotFoundErr",
+    "code": 404,
+    "error_code": 10014,
+    "description": "consumer not found",
+    "comment": "",
+    "help": "",
+    "url": "",
+    "deprecates": ""
+  },
+  {
+    "constant": "JSStreamNameExistErr",
+    "code": 400,
+    "error_code": 10058,
+    "description": "stream name already in use with a different configuration",
+    "comment": "Stream name already in use",
+    "help": "Use a different stream name or update the existing stream",
+    "url": "https://docs.nats.io/nats-concepts/jetstream/streams",
+    "deprecates": ""
+  },
+  {
+    "constant": "JSStreamCreateErrF",
+    "code": 500,
+    "error_code": 10049,
+    "description": "{err}",
+    "comment": "Generic stream creation error string",
+    "help": "",
+    "url": "",
+    "deprecates": ""
+  },
+  {
+    "constant": "JSStreamWrongLastSequenceErrF",
+    "code": 400,
+    "error_code": 10071,
+    "description": "wrong last sequence: {seq}",
+    "comment": "",
+    "help": "",
+    "url": "",
+    "deprecates": ""
+  }
+]`
+
+const testClientSource = `package nats
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors
+var (
+	ErrConnectionClosed = errors.New("nats: connection closed")
+	// ErrNoResponders is returned when no responders are available for a request.
+	ErrNoResponders = errors.New("nats: no responders available for request")
+	ErrTimeout      = errors.New("nats: timeout")
+	ErrBadSubject   = fmt.Errorf("%w: invalid subject", ErrInvalidArg)
+	errUnexported   = errors.New("nats: unexported")
+	ErrStreamNotFound JetStreamError = &jsError{apiErr: &APIError{ErrorCode: 10059, Description: "stream not found"}, message: "stream not found"}
+	DefaultOptions = GetDefaultOptions()
+)
+
+// ErrInvalidArg is returned for invalid arguments.
+var ErrInvalidArg = errors.New("nats: invalid argument")
+`
+
+func TestParseServerErrors(t *testing.T) {
+	entries, err := ParseServerErrors([]byte(testServerErrors), "https://github.com/nats-io/nats-server/blob/main/server/errors.json")
+	if err != nil {
+		t.Fatalf("ParseServerErrors failed: %v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 entries, got %d", len(entries))
+	}
+
+	e := entries[2]
+	if e.Kind != KindServer || e.Constant != "JSStreamNameExistErr" || e.Code != 400 || e.ErrorCode != 10058 {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if e.Help == "" || e.DocURL == "" || e.Comment == "" {
+		t.Errorf("expected comment, help and URL: %+v", e)
+	}
+
+	if _, err := ParseServerErrors([]byte("{"), ""); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestParseClientErrors(t *testing.T) {
+	entries, err := ParseClientErrors([]byte(testClientSource), "nats.go", "https://github.com/nats-io/nats.go/blob/main/nats.go")
+	if err != nil {
+		t.Fatalf("ParseClientErrors failed: %v", err)
+	}
+
+	want := map[string]string{
+		"ErrConnectionClosed": "nats: connection closed",
+		"ErrNoResponders":     "nats: no responders available for request",
+		"ErrTimeout":          "nats: timeout",
+		"ErrBadSubject":       "%w: invalid subject",
+		"ErrStreamNotFound":   "stream not found",
+		"ErrInvalidArg":       "nats: invalid argument",
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(entries), entries)
+	}
+	for _, e := range entries {
+		if want[e.Constant] != e.Message {
+			t.Errorf("%s: expected message %q, got %q", e.Constant, want[e.Constant], e.Message)
+		}
+		if e.Kind != KindClient || e.Package != "nats" {
+			t.Errorf("%s: unexpected kind/package %s/%s", e.Constant, e.Kind, e.Package)
+		}
+		switch e.Constant {
+		case "ErrNoResponders":
+			if e.Comment != "ErrNoResponders is returned when no responders are available for a request." {
+				t.Errorf("unexpected comment: %q", e.Comment)
+			}
+		case "ErrInvalidArg":
+			if e.Comment != "ErrInvalidArg is returned for invalid arguments." {
+				t.Errorf("unexpected comment: %q", e.Comment)
+			}
+		}
+	}
+
+	if _, err := ParseClientErrors([]byte("package"), "bad.go", ""); err == nil {
+		t.Error("expected error for invalid Go source")
+	}
+}
+
+func TestIsClientSourcePath(t *testing.T) {
+	tests := map[string]bool{
+		"nats.go":                   true,
+		"jetstream/errors.go":       true,
+		"nats_test.go":              false,
+		"examples/nats-pub/main.go": false,
+		"internal/parser/parse.go":  false,
+		"test/js_test.go":           false,
+		"README.md":                 false,
+	}
+	for p, want := range tests {
+		if got := IsClientSourcePath(p); got != want {
+			t.Errorf("IsClientSourcePath(%q) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func newTestCatalog(t *testing.T) *Catalog {
+	t.Helper()
+
+	server, err := ParseServerErrors([]byte(testServerErrors), "server/errors.json")
+	if err != nil {
+		t.Fatalf("ParseServerErrors failed: %v", err)
+	}
+	client, err := ParseClientErrors([]byte(testClientSource), "nats.go", "nats.go")
+	if err != nil {
+		t.Fatalf("ParseClientErrors failed: %v", err)
+	}
+
+	var docs []*index.Document
+	for _, e := range append(server, client...) {
+		docs = append(docs, e.Document())
+	}
+	return NewCatalog(docs)
+}
+
+func TestCatalogLookup(t *testing.T) {
+	catalog := newTestCatalog(t)
+	if catalog.Count() != 11 {
+		t.Fatalf("expected 11 entries, got %d", catalog.Count())
+	}
+
+	tests := []struct {
+		query string
+		want  string // qualified name of the first result, empty for no result
+	}{
+		{"10058", "JSStreamNameExistErr"},
+		{"10059", "JSStreamNotFoundErr"},
+		{"500", "JSStreamCreateErrF"},
+		{"99999", ""},
+		{"JSStreamNotFoundErr", "JSStreamNotFoundErr"},
+		{"jsstreamnotfounderr", "JSStreamNotFoundErr"},
+		{"ErrNoResponders", "nats.ErrNoResponders"},
+		{"nats.ErrTimeout", "nats.ErrTimeout"},
+		{"nats: no responders available for request", "nats.ErrNoResponders"},
+		{"error: nats: no responders available for request (subject foo)", "nats.ErrNoResponders"},
+		{"No Responders Available", "nats.ErrNoResponders"},
+		{"wrong last sequence: 42", "JSStreamWrongLastSequenceErrF"},
+		{"stream name already in use", "JSStreamNameExistErr"},
+		{"completely unrelated words", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		entries := catalog.Lookup(tt.query)
+		got := ""
+		if len(entries) > 0 {
+			got = entries[0].QualifiedName()
+		}
+		if got != tt.want {
+			t.Errorf("Lookup(%q) first result = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	// Status codes return every entry with that code
+	if entries := catalog.Lookup("404"); len(entries) != 2 {
+		t.Errorf("expected 2 entries for status 404, got %d", len(entries))
+	}
+}
+
+func TestEntryDocumentRoundTrip(t *testing.T) {
+	entry := &Entry{
+		Kind:      KindServer,
+		Constant:  "JSStreamNameExistErr",
+		Code:      400,
+		ErrorCode: 10058,
+		Message:   "stream name already in use with a different configuration",
+		Comment:   "Stream name already in use",
+		Help:      "Use a different stream name",
+		DocURL:    "https://docs.nats.io/nats-concepts/jetstream/streams",
+		SourceURL: "https://github.com/nats-io/nats-server/blob/main/server/errors.json",
+	}
+
+	doc := entry.Document()
+	if doc.ID != "nats-error/server/JSStreamNameExistErr" {
+		t.Errorf("unexpected ID: %s", doc.ID)
+	}
+	if doc.Title != "JSStreamNameExistErr (10058): stream name already in use with a different configuration" {
+		t.Errorf("unexpected title: %s", doc.Title)
+	}
+
+	restored := EntryFromDocument(doc)
+	if restored == nil || *restored != *entry {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", restored, entry)
+	}
+	if EntryFromDocument(&index.Document{ID: "other"}) != nil {
+		t.Error("expected nil for document without error metadata")
+	}
+}
@@ -0,0 +1,105 @@
+package examples
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// metaLanguage is the metadata key holding the language group of an indexed example
+const metaLanguage = "language"
+
+// Result is an example matching a search, with its relevance score
+type Result struct {
+	Example *Example
+	Score   float64
+}
+
+// Catalog searches the code examples of indexed documents. It is immutable once
+// created, so it is safe for concurrent use.
+type Catalog struct {
+	examples map[string]*Example
+	index    *index.DocumentationIndex
+}
+
+// Documents are the documents of one documentation source
+type Documents struct {
+	Source    string // Name of the source, e.g. "NATS"
+	Documents []*index.Document
+}
+
+// NewCatalog creates a catalog of the examples in the documents of each source, e.g.
+// the documents of the NATS, Synadia and GitHub indices
+func NewCatalog(sources ...Documents) *Catalog {
+	c := &Catalog{
+		examples: make(map[string]*Example),
+		index:    index.NewDocumentationIndex(),
+	}
+	for _, source := range sources {
+		for _, doc := range source.Documents {
+			for _, example := range Extract(source.Source, doc) {
+				if _, exists := c.examples[example.ID]; exists {
+					continue
+				}
+				if err := c.index.Index(example.document()); err != nil {
+					continue
+				}
+				c.examples[example.ID] = example
+			}
+		}
+	}
+	return c
+}
+
+// Count returns the number of examples in the catalog
+func (c *Catalog) Count() int {
+	return len(c.examples)
+}
+
+// Search returns up to limit examples matching query, most relevant first. A
+// language group from ParseLanguage restricts the examples to that language; an
+// empty one matches every example.
+func (c *Catalog) Search(query, language string, limit int) ([]Result, error) {
+	var filter index.SearchFilter
+	if language != "" {
+		filter = func(doc *index.Document) bool {
+			return doc.Metadata[metaLanguage] == language
+		}
+	}
+
+	hits, err := c.index.SearchFiltered(query, limit, filter)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Result, 0, len(hits))
+	for _, hit := range hits {
+		if example, ok := c.examples[hit.DocumentID]; ok {
+			results = append(results, Result{Example: example, Score: hit.Relevance})
+		}
+	}
+	return results, nil
+}
+
+// document converts the example into a searchable document. Identifiers in the code
+// are also indexed split into words, so "pull consumer" finds PullSubscribe.
+func (e *Example) document() *index.Document {
+	content := strings.Join([]string{e.Heading, e.Caption, e.Explanation, e.Code, splitIdentifiers(e.Code)}, "\n")
+	return &index.Document{
+		ID:       e.ID,
+		Title:    e.Title,
+		URL:      e.URL,
+		Content:  content,
+		Metadata: map[string]string{metaLanguage: e.Group()},
+	}
+}
+
+// identifierBoundary matches the boundary between the words of a camelCase or
+// PascalCase identifier
+var identifierBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])|([A-Z])([A-Z][a-z])`)
+
+// splitIdentifiers returns the words of the camelCase identifiers in code, e.g.
+// "Pull Subscribe" for PullSubscribe
+func splitIdentifiers(code string) string {
+	return identifierBoundary.ReplaceAllString(code, "$1$3 $2$4")
+}
@@ -0,0 +1,218 @@
+// Package examples extracts the code examples of indexed documentation: the code
+// blocks of HTML pages and documentation files, and the testable examples of Go
+// example files. Each example keeps the prose of the section it appears in, so a hit
+// can be explained without retrieving the whole page.
+package examples
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/parser"
+)
+
+// maxExplanation is the maximum length in bytes of an example's explanation
+const maxExplanation = 600
+
+// Example is a code block of an indexed document
+type Example struct {
+	ID          string // Source, document ID and position of the block, e.g. "NATS:jetstream/consumers#2"
+	Source      string // Documentation source of the document, e.g. "NATS" or "Synadia"
+	DocID       string // ID of the document holding the example
+	Title       string // Title of the document
+	Heading     string // Heading of the section holding the example
+	URL         string // Source URL of the document
+	Language    string // Normalised language of the code, e.g. "typescript"; empty when unknown
+	Caption     string // Title of the block, e.g. a file name
+	Code        string // Code of the example
+	Explanation string // Prose of the section around the example, without code
+}
+
+// Group returns the language group an example is searched under
+func (e *Example) Group() string {
+	return languageGroup(e.Language)
+}
+
+// languageGroup returns the language group of a normalised language
+func languageGroup(language string) string {
+	if group, ok := languageGroups[language]; ok {
+		return group
+	}
+	return language
+}
+
+// languageGroups maps normalised code block languages to the language group they
+// are searched under. Languages without a group are searched under their own name.
+var languageGroups = map[string]string{
+	"go":            "go",
+	"javascript":    "javascript",
+	"typescript":    "javascript",
+	"python":        "python",
+	"rust":          "rust",
+	"java":          "java",
+	"shell":         "shell",
+	"bash":          "shell",
+	"zsh":           "shell",
+	"console":       "shell",
+	"shell-session": "shell",
+	"shellsession":  "shell",
+	"cli":           "shell",
+	"terminal":      "shell",
+}
+
+// Languages returns the language groups that examples can be filtered by
+func Languages() []string {
+	seen := make(map[string]bool)
+	var groups []string
+	for _, group := range languageGroups {
+		if !seen[group] {
+			seen[group] = true
+			groups = append(groups, group)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// ParseLanguage returns the language group of a language name, e.g. "go" for
+// "golang", "javascript" for "ts" and "shell" for "cli". It fails for languages
+// that no example can have.
+func ParseLanguage(name string) (string, error) {
+	language := parser.NormalizeLanguage(name)
+	if group, ok := languageGroups[language]; ok {
+		return group, nil
+	}
+	if language == "" {
+		return "", fmt.Errorf("empty language")
+	}
+	return "", fmt.Errorf("unknown language %q (supported: %s)", name, strings.Join(Languages(), ", "))
+}
+
+// cliCommand matches the first line of an unlabelled block that runs a NATS command,
+// e.g. "nats stream ls" or "$ nats-server -js"
+var cliCommand = regexp.MustCompile(`^(?:\$\s*|>\s*)?(?:nats|nats-server|nsc|nats-top)(?:\s|$)`)
+
+// blockLanguage returns the normalised language of a code block. Unlabelled blocks
+// take the language named by their caption (e.g. the "Go" tab of a tabbed example)
+// and are treated as shell sessions when they run a NATS command.
+func blockLanguage(block index.CodeBlock) string {
+	if block.Language != "" {
+		return parser.NormalizeLanguage(block.Language)
+	}
+	if language := parser.NormalizeLanguage(block.Caption); languageGroups[language] != "" {
+		return language
+	}
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(block.Content), "\n")
+	if cliCommand.MatchString(strings.TrimSpace(firstLine)) {
+		return "shell"
+	}
+	return ""
+}
+
+// HasLanguage reports whether a document has a code example in a language group
+// from ParseLanguage. Code blocks are classified as by Extract, so a document has an
+// example in a group exactly when a Catalog search in that group can return one.
+func HasLanguage(doc *index.Document, group string) bool {
+	for _, section := range doc.Sections {
+		for _, block := range section.CodeBlocks {
+			if strings.TrimSpace(block.Content) != "" && languageGroup(blockLanguage(block)) == group {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Extract returns the examples of a document from a documentation source, one per
+// non-empty code block. Example IDs include the source, since sources can hold
+// documents with the same ID.
+func Extract(source string, doc *index.Document) []*Example {
+	var result []*Example
+	for _, section := range doc.Sections {
+		if len(section.CodeBlocks) == 0 {
+			continue
+		}
+		explanation := explain(section.Content)
+		for _, block := range section.CodeBlocks {
+			if strings.TrimSpace(block.Content) == "" {
+				continue
+			}
+			result = append(result, &Example{
+				ID:          fmt.Sprintf("%s:%s#%d", source, doc.ID, len(result)+1),
+				Source:      source,
+				DocID:       doc.ID,
+				Title:       doc.Title,
+				Heading:     section.Heading,
+				URL:         doc.URL,
+				Language:    blockLanguage(block),
+				Caption:     block.Caption,
+				Code:        block.Content,
+				Explanation: explanation,
+			})
+		}
+	}
+	return result
+}
+
+// explain returns the prose of section content: fenced code blocks and tables are
+// removed and the remaining paragraphs are shortened to maxExplanation at a word boundary
+func explain(content string) string {
+	var paragraphs []string
+	var paragraph []string
+	fence := ""
+	flush := func() {
+		if len(paragraph) > 0 {
+			paragraphs = append(paragraphs, strings.Join(paragraph, " "))
+			paragraph = nil
+		}
+	}
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, "`~") == "" {
+				fence = ""
+			}
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" {
+			flush()
+			fence = marker
+			continue
+		}
+		// Blank lines end paragraphs, and Markdown table rows are data, not prose
+		if trimmed == "" || strings.HasPrefix(trimmed, "|") {
+			flush()
+			continue
+		}
+		paragraph = append(paragraph, trimmed)
+	}
+	flush()
+
+	explanation := strings.Join(paragraphs, "\n\n")
+	if len(explanation) <= maxExplanation {
+		return explanation
+	}
+	cut := strings.LastIndexAny(explanation[:maxExplanation], " \n")
+	if cut <= 0 {
+		cut = maxExplanation
+	}
+	return strings.TrimSpace(explanation[:cut]) + "..."
+}
+
+// fenceMarker returns the backtick or tilde run opening a fenced code block, or an
+// empty string when line does not open one
+func fenceMarker(line string) string {
+	for _, char := range []string{"`", "~"} {
+		n := 0
+		for strings.HasPrefix(line[n:], char) {
+			n++
+		}
+		if n >= 3 {
+			return line[:n]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package examples
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// consumersDoc is a documentation page with examples in several languages
+var consumersDoc = &index.Document{
+	ID:    "using-nats/consumers",
+	Title: "Pull Consumers",
+	URL:   "https://docs.nats.io/using-nats/consumers",
+	Sections: []index.Section{
+		{
+			Heading: "Fetching messages",
+			Content: "A pull consumer fetches messages in batches.\n\n```go\nsub, _ := js.PullSubscribe(\"ORDERS.*\", \"worker\")\nmsgs, _ := sub.Fetch(10)\n```\n\n```ts\nconst msgs = await c.fetch({ max_messages: 10 });\n```\n\nAcknowledge each message once processed.",
+			CodeBlocks: []index.CodeBlock{
+				{Language: "go", Content: "sub, _ := js.PullSubscribe(\"ORDERS.*\", \"worker\")\nmsgs, _ := sub.Fetch(10)"},
+				{Language: "typescript", Content: "const msgs = await c.fetch({ max_messages: 10 });"},
+			},
+		},
+		{
+			Heading: "From the command line",
+			Content: "```\nnats consumer next ORDERS worker --count 10\n```",
+			CodeBlocks: []index.CodeBlock{
+				{Content: "nats consumer next ORDERS worker --count 10"},
+			},
+		},
+		{Heading: "Overview", Content: "No code here."},
+	},
+}
+
+func TestExtract(t *testing.T) {
+	examples := Extract("NATS", consumersDoc)
+	if len(examples) != 3 {
+		t.Fatalf("expected 3 examples, got %d", len(examples))
+	}
+
+	goExample := examples[0]
+	if goExample.ID != "NATS:using-nats/consumers#1" || goExample.Source != "NATS" || goExample.Heading != "Fetching messages" || goExample.URL != consumersDoc.URL {
+		t.Errorf("unexpected example: %+v", goExample)
+	}
+	if goExample.Explanation != "A pull consumer fetches messages in batches.\n\nAcknowledge each message once processed." {
+		t.Errorf("expected the section prose without code, got %q", goExample.Explanation)
+	}
+
+	if examples[1].Language != "typescript" || examples[1].Group() != "javascript" {
+		t.Errorf("expected a TypeScript example in the javascript group, got %q", examples[1].Language)
+	}
+	if examples[2].Language != "shell" || examples[2].Explanation != "" {
+		t.Errorf("expected the unlabelled nats command to be a shell example, got %+v", examples[2])
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	tests := map[string]string{
+		"golang": "go",
+		"js":     "javascript",
+		"TS":     "javascript",
+		"py":     "python",
+		"rust":   "rust",
+		"java":   "java",
+		"cli":    "shell",
+		"bash":   "shell",
+	}
+	for name, want := range tests {
+		if got, err := ParseLanguage(name); err != nil || got != want {
+			t.Errorf("ParseLanguage(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseLanguage("cobol"); err == nil {
+		t.Error("expected an error for an unsupported language")
+	}
+}
+
+func TestCatalogSearch(t *testing.T) {
+	catalog := NewCatalog(Documents{Source: "NATS", Documents: []*index.Document{consumersDoc}}, Documents{Source: "Synadia"})
+	if catalog.Count() != 3 {
+		t.Fatalf("expected 3 examples, got %d", catalog.Count())
+	}
+
+	// Identifiers are split, so "pull subscribe" finds PullSubscribe
+	results, err := catalog.Search("pull subscribe fetch", "go", 5)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || !strings.Contains(results[0].Example.Code, "PullSubscribe") {
+		t.Errorf("expected the Go example, got %+v", results)
+	}
+
+	results, err = catalog.Search("fetch messages", "javascript", 5)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Example.Language != "typescript" {
+		t.Errorf("expected the TypeScript example, got %+v", results)
+	}
+
+	results, err = catalog.Search("consumer next", "shell", 5)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Example.Heading != "From the command line" {
+		t.Errorf("expected the CLI example, got %+v", results)
+	}
+
+	results, err = catalog.Search("fetch", "rust", 5)
+	if err != nil || len(results) != 0 {
+		t.Errorf("expected no Rust examples, got %+v, %v", results, err)
+	}
+}
+
+func TestCatalogKeepsCollidingDocumentIDs(t *testing.T) {
+	synadiaDoc := *consumersDoc
+	synadiaDoc.URL = "https://docs.synadia.com/using-nats/consumers"
+	catalog := NewCatalog(
+		Documents{Source: "NATS", Documents: []*index.Document{consumersDoc}},
+		Documents{Source: "Synadia", Documents: []*index.Document{&synadiaDoc}},
+	)
+	if catalog.Count() != 6 {
+		t.Fatalf("expected the examples of both sources, got %d", catalog.Count())
+	}
+
+	results, err := catalog.Search("pull subscribe fetch", "go", 5)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	sources := make(map[string]string)
+	for _, result := range results {
+		sources[result.Example.Source] = result.Example.URL
+	}
+	if len(results) != 2 || sources["NATS"] != consumersDoc.URL || sources["Synadia"] != synadiaDoc.URL {
+		t.Errorf("expected the Go example of each source, got %+v", results)
+	}
+}
@@ -0,0 +1,214 @@
+package fetcher
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Auth holds the credentials of a documentation source that requires them, e.g. a
+// customer portal or an internal wiki export. Credentials are only sent to the host
+// of the source's base URL, never to other hosts reached through links or redirects.
+type Auth struct {
+	Username    string            // Basic auth user name; sent with Password
+	Password    string            // Basic auth password
+	BearerToken string            // Sent as "Authorization: Bearer <token>"
+	Headers     map[string]string // Static headers added to every request, e.g. an API key
+	CookieFile  string            // Netscape cookies.txt file seeding the source's cookie jar
+}
+
+// IsZero reports whether no credential is configured
+func (a Auth) IsZero() bool {
+	return a.Username == "" && a.Password == "" && a.BearerToken == "" &&
+		len(a.Headers) == 0 && a.CookieFile == ""
+}
+
+// String describes the configured credentials without revealing any secret
+func (a Auth) String() string {
+	var parts []string
+	if a.Username != "" || a.Password != "" {
+		parts = append(parts, fmt.Sprintf("basic(user=%s, password=%s)", a.Username, redacted(a.Password)))
+	}
+	if a.BearerToken != "" {
+		parts = append(parts, "bearer("+redacted(a.BearerToken)+")")
+	}
+	if len(a.Headers) > 0 {
+		parts = append(parts, "headers("+strings.Join(a.headerNames(), ", ")+")")
+	}
+	if a.CookieFile != "" {
+		parts = append(parts, "cookies("+a.CookieFile+")")
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, " ")
+}
+
+// MarshalZerologObject logs the credentials without revealing any secret
+func (a Auth) MarshalZerologObject(e *zerolog.Event) {
+	if a.Username != "" {
+		e.Str("username", a.Username)
+	}
+	if a.Password != "" {
+		e.Str("password", redacted(a.Password))
+	}
+	if a.BearerToken != "" {
+		e.Str("bearer_token", redacted(a.BearerToken))
+	}
+	if len(a.Headers) > 0 {
+		e.Strs("headers", a.headerNames())
+	}
+	if a.CookieFile != "" {
+		e.Str("cookie_file", a.CookieFile)
+	}
+}
+
+// headerNames returns the sorted names of the static headers; their values may be secret
+func (a Auth) headerNames() []string {
+	names := make([]string, 0, len(a.Headers))
+	for name := range a.Headers {
+		names = append(names, http.CanonicalHeaderKey(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// redacted stands in for a secret in logs and errors
+func redacted(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "[REDACTED]"
+}
+
+// WithAuth returns a client that sends the credentials of auth with every request to
+// the host of baseURL and keeps cookies in a jar seeded from auth.CookieFile. It shares
+// the transport, timeout, retry policy and every request limit of c.
+//
+// Returns an error when baseURL has no host or the cookie file cannot be read.
+func (c *HTTPClient) WithAuth(baseURL string, auth Auth) (*HTTPClient, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q", baseURL)
+	}
+
+	base := c.client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	derived := *c
+	derived.client = &http.Client{
+		Timeout:   c.client.Timeout,
+		Transport: &authTransport{base: base, host: strings.ToLower(u.Host), auth: auth},
+	}
+
+	if auth.CookieFile != "" {
+		jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cookie jar: %w", err)
+		}
+		if err := loadCookieFile(jar, auth.CookieFile); err != nil {
+			return nil, err
+		}
+		derived.client.Jar = jar
+	}
+
+	return &derived, nil
+}
+
+// authTransport adds the credentials of a source to the requests sent to its host
+type authTransport struct {
+	base http.RoundTripper
+	host string // Lowercase host, with port when the base URL has one
+	auth Auth
+}
+
+// RoundTrip sends req through the base transport, with the credentials added when
+// req targets the source's host. Other requests are sent unchanged.
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.ToLower(req.URL.Host) != t.host {
+		return t.base.RoundTrip(req)
+	}
+
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	for name, value := range t.auth.Headers {
+		req.Header.Set(name, value)
+	}
+	switch {
+	case t.auth.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+t.auth.BearerToken)
+	case t.auth.Username != "" || t.auth.Password != "":
+		credentials := base64.StdEncoding.EncodeToString([]byte(t.auth.Username + ":" + t.auth.Password))
+		req.Header.Set("Authorization", "Basic "+credentials)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// loadCookieFile adds the cookies of a Netscape cookies.txt file, as exported by
+// browsers and curl, to jar. Expired cookies are skipped.
+func loadCookieFile(jar http.CookieJar, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open cookie file: %w", err)
+	}
+	defer f.Close()
+
+	now := time.Now()
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		httpOnly := strings.HasPrefix(text, "#HttpOnly_")
+		text = strings.TrimPrefix(text, "#HttpOnly_")
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		// domain, include subdomains, path, secure, expiry, name, value
+		fields := strings.Split(text, "\t")
+		if len(fields) != 7 {
+			return fmt.Errorf("cookie file %s line %d: expected 7 tab-separated fields, got %d", file, line, len(fields))
+		}
+		expiry, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return fmt.Errorf("cookie file %s line %d: invalid expiry %q", file, line, fields[4])
+		}
+
+		cookie := &http.Cookie{
+			Name:     fields[5],
+			Value:    fields[6],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			HttpOnly: httpOnly,
+		}
+		if expiry > 0 {
+			cookie.Expires = time.Unix(expiry, 0)
+			if cookie.Expires.Before(now) {
+				continue
+			}
+		}
+
+		// Cookies for subdomains carry a Domain attribute; host-only cookies do not
+		host := strings.TrimPrefix(fields[0], ".")
+		if strings.EqualFold(fields[1], "TRUE") {
+			cookie.Domain = host
+		}
+		jar.SetCookies(&url.URL{Scheme: "https", Host: host, Path: "/"}, []*http.Cookie{cookie})
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read cookie file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// TestWithAuthScopesCredentialsToSourceHost verifies that basic auth and static
+// headers reach the source's host and no other host
+func TestWithAuthScopesCredentialsToSourceHost(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || r.Header.Get("X-Portal-Tenant") != "" {
+			t.Errorf("Credentials leaked to another host: %v", r.Header)
+		}
+		_, _ = w.Write([]byte("public"))
+	}))
+	defer other.Close()
+
+	portal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "alice" || password != "s3cret" || r.Header.Get("X-Portal-Tenant") != "acme" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, other.URL+"/public", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("private"))
+	}))
+	defer portal.Close()
+
+	base := NewHTTPClient(5*time.Second, 0, 5)
+	ctx := context.Background()
+
+	if _, err := base.Fetch(ctx, portal.URL+"/page"); err == nil {
+		t.Error("Expected the portal to reject requests without credentials")
+	}
+
+	client, err := base.WithAuth(portal.URL, Auth{
+		Username: "alice",
+		Password: "s3cret",
+		Headers:  map[string]string{"x-portal-tenant": "acme"},
+	})
+	if err != nil {
+		t.Fatalf("WithAuth failed: %v", err)
+	}
+	body, err := client.Fetch(ctx, portal.URL+"/page")
+	if err != nil || string(body) != "private" {
+		t.Fatalf("Expected authenticated fetch to succeed, got %q, %v", body, err)
+	}
+
+	// Neither a redirect nor a direct request carries the credentials elsewhere
+	if _, err := client.Fetch(ctx, portal.URL+"/moved"); err != nil {
+		t.Errorf("Redirected fetch failed: %v", err)
+	}
+	if _, err := client.Fetch(ctx, other.URL+"/public"); err != nil {
+		t.Errorf("Fetch of another host failed: %v", err)
+	}
+
+	if client.inFlight != base.inFlight || client.rateLimiter != base.rateLimiter {
+		t.Error("Expected WithAuth to share request limits")
+	}
+}
+
+func TestWithAuthBearerToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer wiki-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("wiki"))
+	}))
+	defer server.Close()
+
+	client, err := NewHTTPClient(5*time.Second, 0, 5).WithAuth(server.URL, Auth{BearerToken: "wiki-token"})
+	if err != nil {
+		t.Fatalf("WithAuth failed: %v", err)
+	}
+	if _, err := client.Fetch(context.Background(), server.URL); err != nil {
+		t.Errorf("Expected bearer token fetch to succeed, got: %v", err)
+	}
+}
+
+// TestWithAuthCookieJar verifies that cookies from a cookies.txt file are sent and
+// that cookies set by the server are kept for later requests
+func TestWithAuthCookieJar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("portal_session"); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "csrf", Value: "token", Path: "/"})
+			return
+		}
+		if _, err := r.Cookie("csrf"); err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("portal"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	cookieFile := filepath.Join(dir, "cookies.txt")
+	expired := time.Now().Add(-time.Hour).Unix()
+	cookies := "# Netscape HTTP Cookie File\n" +
+		"#HttpOnly_127.0.0.1\tFALSE\t/\tFALSE\t0\tportal_session\tabc123\n" +
+		fmt.Sprintf("127.0.0.1\tFALSE\t/\tFALSE\t%d\tcsrf\tstale\n", expired)
+	if err := os.WriteFile(cookieFile, []byte(cookies), 0600); err != nil {
+		t.Fatalf("failed to write cookie file: %v", err)
+	}
+
+	client, err := NewHTTPClient(5*time.Second, 0, 5).WithAuth(server.URL, Auth{CookieFile: cookieFile})
+	if err != nil {
+		t.Fatalf("WithAuth failed: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := client.Fetch(ctx, server.URL+"/docs"); err == nil {
+		t.Error("Expected the expired csrf cookie to be skipped")
+	}
+	if _, err := client.Fetch(ctx, server.URL+"/login"); err != nil {
+		t.Fatalf("Expected the session cookie to be sent, got: %v", err)
+	}
+	if _, err := client.Fetch(ctx, server.URL+"/docs"); err != nil {
+		t.Errorf("Expected the cookie set by the server to be kept, got: %v", err)
+	}
+
+	malformed := filepath.Join(dir, "malformed.txt")
+	if err := os.WriteFile(malformed, []byte("127.0.0.1 FALSE / FALSE 0 name value\n"), 0600); err != nil {
+		t.Fatalf("failed to write cookie file: %v", err)
+	}
+	if _, err := NewHTTPClient(5*time.Second, 0, 5).WithAuth(server.URL, Auth{CookieFile: malformed}); err == nil {
+		t.Error("Expected an error for a malformed cookie file")
+	}
+	if _, err := NewHTTPClient(5*time.Second, 0, 5).WithAuth("docs", Auth{BearerToken: "x"}); err == nil {
+		t.Error("Expected an error for a base URL without host")
+	}
+}
+
+func TestAuthRedactsSecrets(t *testing.T) {
+	auth := Auth{
+		Username:    "alice",
+		Password:    "s3cret",
+		BearerToken: "t0ken",
+		Headers:     map[string]string{"x-api-key": "k3y"},
+	}
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	logger.Info().Object("auth", auth).Msg("auth")
+
+	for _, out := range []string{auth.String(), buf.String()} {
+		for _, secret := range []string{"s3cret", "t0ken", "k3y"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("Secret %q not redacted: %s", secret, out)
+			}
+		}
+		if !strings.Contains(out, "alice") || !strings.Contains(out, "X-Api-Key") {
+			t.Errorf("Expected user name and header names in output: %s", out)
+		}
+	}
+
+	if !(Auth{}).IsZero() || auth.IsZero() {
+		t.Error("IsZero reported the wrong result")
+	}
+}
+
+// TestNewMultiSourceFetcherInvalidAuth verifies that credentials that cannot be set up
+// fail the fetcher instead of fetching without them
+func TestNewMultiSourceFetcherInvalidAuth(t *testing.T) {
+	malformed := filepath.Join(t.TempDir(), "cookies.txt")
+	if err := os.WriteFile(malformed, []byte("127.0.0.1 FALSE / FALSE 0 name value\n"), 0600); err != nil {
+		t.Fatalf("failed to write cookie file: %v", err)
+	}
+	broken := FetchConfig{BaseURL: "https://portal.example", Auth: Auth{CookieFile: malformed}}
+
+	if _, err := NewMultiSourceFetcher(broken, FetchConfig{}, GitHubFetchConfig{}, zerolog.Nop()); err == nil || !strings.Contains(err.Error(), "NATS") {
+		t.Errorf("Expected the NATS credentials to fail the fetcher, got %v", err)
+	}
+	if _, err := NewMultiSourceFetcher(FetchConfig{}, broken, GitHubFetchConfig{}, zerolog.Nop()); err == nil || !strings.Contains(err.Error(), "Synadia") {
+		t.Errorf("Expected the Synadia credentials to fail the fetcher, got %v", err)
+	}
+}
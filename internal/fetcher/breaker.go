@@ -0,0 +1,338 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is matched by errors.Is for every CircuitOpenError
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerState is the state of the circuit breaker of a host
+type BreakerState string
+
+// Circuit breaker states
+const (
+	BreakerClosed   BreakerState = "closed"    // Requests are sent and their outcomes counted
+	BreakerOpen     BreakerState = "open"      // Requests fail fast until the cool-down ends
+	BreakerHalfOpen BreakerState = "half-open" // A few probe requests decide whether to close again
+)
+
+// Circuit breaker defaults, applied to zero BreakerConfig fields
+const (
+	defaultBreakerWindow         = 20
+	defaultBreakerMinRequests    = 5
+	defaultBreakerFailureRatio   = 0.5
+	defaultBreakerCoolDown       = 30 * time.Second
+	defaultBreakerHalfOpenProbes = 1
+)
+
+// BreakerConfig configures the per-host circuit breakers of an HTTPClient. A host
+// whose recent requests fail too often stops receiving requests for a cool-down,
+// so a dead source fails fast instead of retrying every page.
+type BreakerConfig struct {
+	Window         int           // Recent attempts whose outcomes are counted (default: 20)
+	MinRequests    int           // Attempts in the window before the breaker may open (default: 5)
+	FailureRatio   float64       // Share of failed attempts in the window that opens the breaker (default: 0.5)
+	CoolDown       time.Duration // How long an open breaker rejects requests (default: 30s)
+	HalfOpenProbes int           // Requests let through once the cool-down ends (default: 1)
+
+	// OnStateChange, when set, is called outside the breaker lock whenever a host
+	// changes state
+	OnStateChange func(host string, from, to BreakerState)
+}
+
+// withDefaults returns bc with defaults for unset fields
+func (bc BreakerConfig) withDefaults() BreakerConfig {
+	if bc.Window <= 0 {
+		bc.Window = defaultBreakerWindow
+	}
+	if bc.MinRequests <= 0 {
+		bc.MinRequests = defaultBreakerMinRequests
+	}
+	if bc.MinRequests > bc.Window {
+		bc.MinRequests = bc.Window
+	}
+	if bc.FailureRatio <= 0 || bc.FailureRatio > 1 {
+		bc.FailureRatio = defaultBreakerFailureRatio
+	}
+	if bc.CoolDown <= 0 {
+		bc.CoolDown = defaultBreakerCoolDown
+	}
+	if bc.HalfOpenProbes <= 0 {
+		bc.HalfOpenProbes = defaultBreakerHalfOpenProbes
+	}
+	return bc
+}
+
+// CircuitOpenError reports that a request was not sent because the circuit breaker
+// of its host is open
+type CircuitOpenError struct {
+	Host  string    // Host whose breaker rejected the request
+	Until time.Time // When probe requests are let through again; zero while probes are in flight
+	Cause string    // Last failure seen on the host
+}
+
+// Error implements the error interface
+func (e *CircuitOpenError) Error() string {
+	msg := "circuit breaker open for " + e.Host
+	if !e.Until.IsZero() {
+		msg += " until " + e.Until.Format(time.RFC3339)
+	}
+	if e.Cause != "" {
+		msg += " (last error: " + e.Cause + ")"
+	}
+	return msg
+}
+
+// Is makes errors.Is(err, ErrCircuitOpen) true for every CircuitOpenError
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// HostStatus is a snapshot of the circuit breaker of a host
+type HostStatus struct {
+	Host      string
+	State     BreakerState
+	Requests  int       // Attempts counted in the window
+	Failures  int       // Failed attempts counted in the window
+	Trips     int       // Times the breaker opened
+	OpenUntil time.Time // End of the cool-down while open
+	LastError string    // Last failure seen on the host
+}
+
+// circuitBreakers holds the breaker of every host an HTTPClient has sent requests to.
+// A nil *circuitBreakers lets every request through.
+type circuitBreakers struct {
+	config BreakerConfig
+	now    func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*hostBreaker
+}
+
+// hostBreaker is the breaker of one host. Outcomes are kept in a ring of the last
+// config.Window attempts while closed.
+type hostBreaker struct {
+	state     BreakerState
+	outcomes  []bool // true for a failed attempt
+	next      int
+	failures  int
+	probes    int // Probe requests in flight while half-open
+	trips     int
+	openUntil time.Time
+	lastError string
+}
+
+func newCircuitBreakers(config BreakerConfig) *circuitBreakers {
+	return &circuitBreakers{
+		config: config.withDefaults(),
+		now:    time.Now,
+		hosts:  make(map[string]*hostBreaker),
+	}
+}
+
+// WithCircuitBreaker returns a client that tracks the outcome of requests per host
+// and fails fast with a CircuitOpenError while a host's breaker is open. Clients
+// later derived from it share the breakers.
+func (c *HTTPClient) WithCircuitBreaker(config BreakerConfig) *HTTPClient {
+	derived := *c
+	derived.breakers = newCircuitBreakers(config)
+	return &derived
+}
+
+// BreakerStatus returns the circuit breaker state of every host the client has sent
+// requests to, sorted by host, or nil when the client has no circuit breaker
+func (c *HTTPClient) BreakerStatus() []HostStatus {
+	return c.breakers.status()
+}
+
+// host returns the breaker of host, creating a closed one; b.mu must be held
+func (b *circuitBreakers) host(host string) *hostBreaker {
+	hb, ok := b.hosts[host]
+	if !ok {
+		hb = &hostBreaker{state: BreakerClosed, outcomes: make([]bool, 0, b.config.Window)}
+		b.hosts[host] = hb
+	}
+	return hb
+}
+
+// allow reports whether a request to host may be sent. Once the cool-down of an open
+// breaker ends, up to HalfOpenProbes requests are let through as probes; probe is
+// true for them and their outcome decides whether the breaker closes.
+func (b *circuitBreakers) allow(host string) (probe bool, err error) {
+	if b == nil {
+		return false, nil
+	}
+
+	b.mu.Lock()
+	hb := b.host(host)
+	var changed bool
+	if hb.state == BreakerOpen {
+		if b.now().Before(hb.openUntil) {
+			err := &CircuitOpenError{Host: host, Until: hb.openUntil, Cause: hb.lastError}
+			b.mu.Unlock()
+			return false, err
+		}
+		hb.state, hb.probes = BreakerHalfOpen, 0
+		changed = true
+	}
+	if hb.state == BreakerHalfOpen {
+		if hb.probes >= b.config.HalfOpenProbes {
+			err := &CircuitOpenError{Host: host, Cause: hb.lastError}
+			b.mu.Unlock()
+			if changed {
+				b.notify(host, BreakerOpen, BreakerHalfOpen)
+			}
+			return false, err
+		}
+		hb.probes++
+		probe = true
+	}
+	b.mu.Unlock()
+
+	if changed {
+		b.notify(host, BreakerOpen, BreakerHalfOpen)
+	}
+	return probe, nil
+}
+
+// check returns a CircuitOpenError while the breaker of host is open and its
+// cool-down runs, without changing its state, so a retry does not wait out its
+// backoff only to be rejected
+func (b *circuitBreakers) check(host string) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	hb, ok := b.hosts[host]
+	if ok && hb.state == BreakerOpen && b.now().Before(hb.openUntil) {
+		return &CircuitOpenError{Host: host, Until: hb.openUntil, Cause: hb.lastError}
+	}
+	return nil
+}
+
+// record counts the outcome of an attempt allowed by allow. A failed probe opens the
+// breaker for another cool-down and a successful one closes it. A closed breaker
+// opens once MinRequests attempts are in the window and the share of failures
+// reaches FailureRatio.
+func (b *circuitBreakers) record(host string, probe bool, failure error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	hb := b.host(host)
+	from := hb.state
+	if failure != nil {
+		hb.lastError = failure.Error()
+	}
+
+	switch {
+	case probe:
+		hb.probes--
+		if hb.state != BreakerHalfOpen {
+			break
+		}
+		if failure != nil {
+			b.open(hb)
+		} else {
+			hb.state = BreakerClosed
+			hb.outcomes, hb.next, hb.failures = hb.outcomes[:0], 0, 0
+		}
+	case hb.state == BreakerClosed:
+		failed := failure != nil
+		if len(hb.outcomes) < b.config.Window {
+			hb.outcomes = append(hb.outcomes, failed)
+		} else {
+			if hb.outcomes[hb.next] {
+				hb.failures--
+			}
+			hb.outcomes[hb.next] = failed
+			hb.next = (hb.next + 1) % b.config.Window
+		}
+		if failed {
+			hb.failures++
+		}
+		requests := len(hb.outcomes)
+		if requests >= b.config.MinRequests && float64(hb.failures)/float64(requests) >= b.config.FailureRatio {
+			b.open(hb)
+		}
+	}
+	to := hb.state
+	b.mu.Unlock()
+
+	if from != to {
+		b.notify(host, from, to)
+	}
+}
+
+// release gives back a probe whose attempt ended without an outcome, e.g. because
+// the request was cancelled
+func (b *circuitBreakers) release(host string, probe bool) {
+	if b == nil || !probe {
+		return
+	}
+	b.mu.Lock()
+	b.host(host).probes--
+	b.mu.Unlock()
+}
+
+// open moves hb to the open state for a cool-down; b.mu must be held
+func (b *circuitBreakers) open(hb *hostBreaker) {
+	hb.state = BreakerOpen
+	hb.openUntil = b.now().Add(b.config.CoolDown)
+	hb.trips++
+	hb.outcomes, hb.next, hb.failures = hb.outcomes[:0], 0, 0
+}
+
+func (b *circuitBreakers) notify(host string, from, to BreakerState) {
+	if b.config.OnStateChange != nil {
+		b.config.OnStateChange(host, from, to)
+	}
+}
+
+func (b *circuitBreakers) status() []HostStatus {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statuses := make([]HostStatus, 0, len(b.hosts))
+	for host, hb := range b.hosts {
+		status := HostStatus{
+			Host:      host,
+			State:     hb.state,
+			Requests:  len(hb.outcomes),
+			Failures:  hb.failures,
+			Trips:     hb.trips,
+			LastError: hb.lastError,
+		}
+		if hb.state == BreakerOpen {
+			status.OpenUntil = hb.openUntil
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Host < statuses[j].Host })
+	return statuses
+}
+
+// breakerFailure returns the failure counted against a host for an attempt that got
+// resp status or failed with err, or nil when the host answered. Client errors and
+// rate limits show the host is up, so only network errors and 5xx responses count.
+func breakerFailure(status int, err error) error {
+	if err != nil {
+		return err
+	}
+	if status >= 500 {
+		return fmt.Errorf("server error: HTTP %d", status)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// TestCircuitBreakerStates walks a host's breaker through opening, the cool-down,
+// a failed probe and a successful one
+func TestCircuitBreakerStates(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var transitions []BreakerState
+	b := newCircuitBreakers(BreakerConfig{
+		Window:       4,
+		MinRequests:  3,
+		FailureRatio: 0.5,
+		CoolDown:     time.Minute,
+		OnStateChange: func(host string, from, to BreakerState) {
+			transitions = append(transitions, to)
+		},
+	})
+	b.now = func() time.Time { return now }
+	failure := errors.New("connection refused")
+
+	// One failure in three attempts stays below the ratio
+	for _, err := range []error{nil, failure, nil} {
+		if _, allowErr := b.allow("docs.synadia.com"); allowErr != nil {
+			t.Fatalf("Expected a closed breaker to allow requests, got: %v", allowErr)
+		}
+		b.record("docs.synadia.com", false, err)
+	}
+	if state := b.status()[0].State; state != BreakerClosed {
+		t.Fatalf("Expected closed breaker, got %s", state)
+	}
+
+	// A second failure reaches the ratio over the window
+	b.record("docs.synadia.com", false, failure)
+	_, err := b.allow("docs.synadia.com")
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected a CircuitOpenError, got: %v", err)
+	}
+	if !openErr.Until.Equal(now.Add(time.Minute)) || openErr.Cause != "connection refused" {
+		t.Errorf("Unexpected open error: %+v", openErr)
+	}
+
+	// Other hosts are unaffected
+	if _, err := b.allow("docs.nats.io"); err != nil {
+		t.Errorf("Expected another host to be allowed, got: %v", err)
+	}
+
+	// After the cool-down one probe is let through; a failed probe reopens
+	now = now.Add(time.Minute)
+	probe, err := b.allow("docs.synadia.com")
+	if err != nil || !probe {
+		t.Fatalf("Expected a probe after the cool-down, got %v, %v", probe, err)
+	}
+	if _, err := b.allow("docs.synadia.com"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected requests beyond the probe to fail fast, got: %v", err)
+	}
+	b.record("docs.synadia.com", true, failure)
+	if _, err := b.allow("docs.synadia.com"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected a failed probe to reopen the breaker, got: %v", err)
+	}
+
+	// A successful probe closes the breaker
+	now = now.Add(time.Minute)
+	probe, _ = b.allow("docs.synadia.com")
+	b.record("docs.synadia.com", probe, nil)
+	status := b.status()
+	if status[1].Host != "docs.synadia.com" || status[1].State != BreakerClosed || status[1].Trips != 2 || status[1].Requests != 0 {
+		t.Errorf("Unexpected status after recovery: %+v", status[1])
+	}
+
+	want := []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}
+	if len(transitions) != len(want) {
+		t.Fatalf("Expected transitions %v, got %v", want, transitions)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("Expected transitions %v, got %v", want, transitions)
+			break
+		}
+	}
+}
+
+// TestHTTPClientCircuitBreakerFailsFast verifies that a dead host stops retries and
+// later requests fail without reaching it, while client errors do not count
+func TestHTTPClientCircuitBreakerFailsFast(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	// Client errors show the host is up
+	healthy := NewHTTPClient(5*time.Second, 0, 5).WithCircuitBreaker(BreakerConfig{MinRequests: 2})
+	for i := 0; i < 3; i++ {
+		_, _ = healthy.Fetch(ctx, server.URL+"/missing")
+	}
+	if status := healthy.BreakerStatus(); len(status) != 1 || status[0].State != BreakerClosed || status[0].Failures != 0 || status[0].Requests != 3 {
+		t.Fatalf("Expected a closed breaker after client errors, got %+v", status)
+	}
+
+	client := NewHTTPClient(5*time.Second, 5, 5).WithCircuitBreaker(BreakerConfig{MinRequests: 2})
+	derived := client.WithTransport(http.DefaultTransport)
+
+	start := time.Now()
+	_, err := derived.Fetch(ctx, server.URL+"/page")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected the retries to stop at the open breaker, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Expected the open breaker to cut the retries short, took %v", elapsed)
+	}
+
+	sent := requests.Load()
+	if _, err := client.Fetch(ctx, server.URL+"/other"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected a later request to fail fast, got: %v", err)
+	}
+	if requests.Load() != sent {
+		t.Error("Expected no request to reach a host with an open breaker")
+	}
+
+	status := client.BreakerStatus()
+	if status[0].State != BreakerOpen || status[0].Trips != 1 || status[0].OpenUntil.IsZero() {
+		t.Errorf("Unexpected breaker status: %+v", status[0])
+	}
+	if NewHTTPClient(time.Second, 0, 1).BreakerStatus() != nil {
+		t.Error("Expected no status without a circuit breaker")
+	}
+}
+
+// TestMultiSourceFetcherBreaker verifies that the breaker option applies to every
+// source and that circuit breakers are disabled without it
+func TestMultiSourceFetcherBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	natsConfig := FetchConfig{BaseURL: server.URL + "/nats", FetchTimeout: 5 * time.Second, MaxConcurrent: 5}
+	syadiaConfig := FetchConfig{BaseURL: server.URL + "/synadia", FetchTimeout: 5 * time.Second, MaxConcurrent: 5}
+	ctx := context.Background()
+
+	msf, err := NewMultiSourceFetcher(natsConfig, syadiaConfig, GitHubFetchConfig{}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewMultiSourceFetcher failed: %v", err)
+	}
+	_, _ = msf.natsFetcher.client.Fetch(ctx, natsConfig.BaseURL)
+	if status := msf.BreakerStatus(); len(status) != 0 {
+		t.Errorf("Expected no circuit breakers without the option, got %+v", status)
+	}
+
+	msf, err = NewMultiSourceFetcher(natsConfig, syadiaConfig, GitHubFetchConfig{}, zerolog.Nop(),
+		WithBreaker(BreakerConfig{MinRequests: 2}))
+	if err != nil {
+		t.Fatalf("NewMultiSourceFetcher failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		_, _ = msf.natsFetcher.client.Fetch(ctx, natsConfig.BaseURL)
+	}
+	if _, err := msf.syadiaFetcher.client.Fetch(ctx, syadiaConfig.BaseURL); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected the breaker opened by one source to stop another, got: %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+)
+
+// Page discovery modes of a documentation source
+const (
+	DiscoveryAuto    = "auto"    // Read the sitemaps, crawling when the site has none (default)
+	DiscoverySitemap = "sitemap" // Read the sitemaps only
+	DiscoveryCrawl   = "crawl"   // Crawl links from the base URL only
+)
+
+// Default crawl limits, used when CrawlLimits fields are zero
+const (
+	defaultCrawlMaxDepth = 5
+	defaultCrawlMaxPages = 1000
+)
+
+// CrawlLimits bounds the link-following crawler
+type CrawlLimits struct {
+	MaxDepth int // Maximum number of links followed from the base URL (default: 5)
+	MaxPages int // Maximum number of pages discovered (default: 1000)
+}
+
+// assetExtensions are linked files that are never documentation pages
+var assetExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true, ".ico": true,
+	".css": true, ".js": true, ".json": true, ".xml": true, ".gz": true, ".zip": true, ".tar": true,
+	".pdf": true, ".woff": true, ".woff2": true, ".ttf": true, ".mp4": true, ".webm": true,
+}
+
+// crawlTarget is a page queued for crawling
+type crawlTarget struct {
+	url   *url.URL
+	depth int
+}
+
+// crawl discovers pages by following links breadth-first from the base URL. Only
+// links with the base URL's origin and under its path are followed, pages disallowed
+// by robots are not requested, and URLs are deduplicated after dropping fragments and
+// queries and resolving canonical links. Crawled pages are kept so fetchEntries does
+// not request them again.
+func (df *DocumentationFetcher) crawl(ctx context.Context, robots robotsRules) ([]SitemapEntry, error) {
+	root, err := url.Parse(df.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base URL: %w", err)
+	}
+	root = normalizeCrawlURL(root)
+
+	limits := df.crawlLimits
+	if limits.MaxDepth <= 0 {
+		limits.MaxDepth = defaultCrawlMaxDepth
+	}
+	if limits.MaxPages <= 0 {
+		limits.MaxPages = defaultCrawlMaxPages
+	}
+
+	df.logger.Info().
+		Str("base_url", root.String()).
+		Int("max_depth", limits.MaxDepth).
+		Int("max_pages", limits.MaxPages).
+		Msg("Crawling documentation site")
+
+	var mu sync.Mutex
+	var entries []SitemapEntry
+	seen := map[string]bool{crawlKey(root): true}
+	crawled := make(map[string]DocumentPage)
+	level := []crawlTarget{{url: root}}
+
+	for len(level) > 0 && ctx.Err() == nil {
+		var next []crawlTarget
+
+		runPool(ctx, df.client.MaxInFlight(), level, func(target crawlTarget) {
+			mu.Lock()
+			full := len(entries) >= limits.MaxPages
+			mu.Unlock()
+			if full || !robots.allowed(target.url.Path) {
+				return
+			}
+
+			content, validator, _, err := df.client.FetchConditional(ctx, target.url.String(), Validator{})
+			if err != nil {
+				df.logger.Warn().
+					Err(err).
+					Str("url", target.url.String()).
+					Msg("Failed to fetch page while crawling, skipping")
+				return
+			}
+			if !strings.HasPrefix(http.DetectContentType(content), "text/html") {
+				return
+			}
+
+			canonical, links := extractLinks(target.url, content)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			// A page whose canonical URL was already discovered is a duplicate
+			page := target.url
+			if canonical != nil && inCrawlScope(root, canonical) && crawlKey(canonical) != crawlKey(page) {
+				if seen[crawlKey(canonical)] {
+					return
+				}
+				seen[crawlKey(canonical)] = true
+				page = canonical
+			}
+			if len(entries) >= limits.MaxPages {
+				return
+			}
+			// Entries are relative to the base URL, like those of sitemaps
+			pagePath, ok := basePath(root, page)
+			if !ok {
+				return
+			}
+			entries = append(entries, SitemapEntry{Path: pagePath})
+			crawled[pagePath] = DocumentPage{Path: pagePath, Content: content, Validator: validator}
+
+			if target.depth >= limits.MaxDepth {
+				return
+			}
+			for _, link := range links {
+				if !inCrawlScope(root, link) || seen[crawlKey(link)] || assetExtensions[strings.ToLower(path.Ext(link.Path))] {
+					continue
+				}
+				seen[crawlKey(link)] = true
+				next = append(next, crawlTarget{url: link, depth: target.depth + 1})
+			}
+		})
+
+		level = next
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("failed to crawl %s: no pages found", root)
+	}
+
+	df.crawledMu.Lock()
+	df.crawled = crawled
+	df.crawledMu.Unlock()
+
+	df.logger.Info().
+		Int("count", len(entries)).
+		Msg("Crawled documentation site")
+
+	return entries, nil
+}
+
+// takeCrawled returns and forgets the content the crawler fetched for a page path
+func (df *DocumentationFetcher) takeCrawled(pagePath string) (DocumentPage, bool) {
+	df.crawledMu.Lock()
+	defer df.crawledMu.Unlock()
+
+	page, ok := df.crawled[pagePath]
+	if ok {
+		delete(df.crawled, pagePath)
+	}
+	return page, ok
+}
+
+// extractLinks returns the canonical URL and the normalized http(s) link targets of
+// an HTML page, resolved against its <base> element or URL
+func extractLinks(pageURL *url.URL, content []byte) (*url.URL, []*url.URL) {
+	base := pageURL
+	var canonical *url.URL
+	var links []*url.URL
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(content))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue
+		}
+
+		token := tokenizer.Token()
+		attrs := make(map[string]string, len(token.Attr))
+		for _, attr := range token.Attr {
+			attrs[strings.ToLower(attr.Key)] = attr.Val
+		}
+
+		switch token.Data {
+		case "base":
+			if resolved := resolveLink(pageURL, attrs["href"]); resolved != nil {
+				base = resolved
+			}
+		case "link":
+			if strings.EqualFold(strings.TrimSpace(attrs["rel"]), "canonical") {
+				canonical = resolveLink(base, attrs["href"])
+			}
+		case "a":
+			if link := resolveLink(base, attrs["href"]); link != nil {
+				links = append(links, link)
+			}
+		}
+	}
+
+	return canonical, links
+}
+
+// resolveLink resolves an href against base, returning nil for empty, unparsable and
+// non-http(s) references
+func resolveLink(base *url.URL, href string) *url.URL {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return nil
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return nil
+	}
+	resolved := base.ResolveReference(ref)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return nil
+	}
+	return normalizeCrawlURL(resolved)
+}
+
+// normalizeCrawlURL drops the fragment, query and user info of a URL, lowercases its
+// scheme and host, and cleans its path
+func normalizeCrawlURL(u *url.URL) *url.URL {
+	normalized := &url.URL{
+		Scheme: strings.ToLower(u.Scheme),
+		Host:   strings.ToLower(u.Host),
+		Path:   u.Path,
+	}
+	if normalized.Path == "" {
+		normalized.Path = "/"
+	}
+	trailingSlash := strings.HasSuffix(normalized.Path, "/")
+	normalized.Path = path.Clean(normalized.Path)
+	if trailingSlash && normalized.Path != "/" {
+		normalized.Path += "/"
+	}
+	return normalized
+}
+
+// crawlKey identifies a page for deduplication, treating paths with and without a
+// trailing slash as the same page
+func crawlKey(u *url.URL) string {
+	if u.Path == "/" {
+		return u.Host + "/"
+	}
+	return u.Host + strings.TrimSuffix(u.Path, "/")
+}
+
+// inCrawlScope reports whether u has the origin of root and lies under its path
+func inCrawlScope(root, u *url.URL) bool {
+	if u.Scheme != root.Scheme || u.Host != root.Host {
+		return false
+	}
+	prefix := strings.TrimSuffix(root.Path, "/")
+	return u.Path == prefix || strings.HasPrefix(u.Path, prefix+"/")
+}
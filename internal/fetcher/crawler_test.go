@@ -0,0 +1,229 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// newCrawlSite serves a small documentation site without sitemaps and counts the
+// requests made for each path
+func newCrawlSite(t *testing.T) (*httptest.Server, func(path string) int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	pages := map[string]string{
+		"/": `<html><head><title>Home</title></head><body>
+			<a href="/guide/">Guide</a>
+			<a href="/guide/#install">Install</a>
+			<a href="guide?ref=nav">Guide again</a>
+			<a href="/about">About</a>
+			<a href="/private/notes">Private</a>
+			<a href="https://example.com/elsewhere">External</a>
+			<a href="/logo.png">Logo</a>
+			<a href="mailto:docs@example.com">Mail</a>
+			<a href="/deep/1">Deep</a>
+		</body></html>`,
+		"/guide/":        `<html><body><a href="../">Home</a><a href="streams">Streams</a></body></html>`,
+		"/guide/streams": `<html><body><p>Streams</p></body></html>`,
+		"/about":         `<html><head><link rel="canonical" href="/guide/"></head><body>About</body></html>`,
+		"/private/notes": `<html><body>Private</body></html>`,
+		"/deep/1":        `<html><body><a href="/deep/2">Next</a></body></html>`,
+		"/deep/2":        `<html><body><a href="/deep/3">Next</a></body></html>`,
+		"/deep/3":        `<html><body>Bottom</body></html>`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+
+		if r.URL.Path == "/robots.txt" {
+			_, _ = fmt.Fprint(w, "User-agent: *\nDisallow: /private/\n")
+			return
+		}
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprint(w, page)
+	}))
+
+	count := func(path string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests[path]
+	}
+	return server, count
+}
+
+func discoveredPaths(entries []SitemapEntry) []string {
+	paths := make([]string, len(entries))
+	for i, entry := range entries {
+		paths[i] = entry.Path
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// TestDocumentationFetcherCrawlFallback verifies that a site without sitemaps is
+// crawled within its origin, honouring robots.txt, canonical links and depth limits
+func TestDocumentationFetcherCrawlFallback(t *testing.T) {
+	server, requests := newCrawlSite(t)
+	defer server.Close()
+
+	fetcher := NewDocumentationFetcher(NewHTTPClient(5*time.Second, 0, 5), server.URL, zerolog.Nop())
+	fetcher.crawlLimits = CrawlLimits{MaxDepth: 2}
+
+	pages, err := fetcher.FetchAllPages(context.Background())
+	if err != nil {
+		t.Fatalf("Expected successful crawl, got error: %v", err)
+	}
+
+	var paths []string
+	for _, page := range pages {
+		if len(page.Content) == 0 {
+			t.Errorf("Expected content for crawled page %s", page.Path)
+		}
+		paths = append(paths, page.Path)
+	}
+	sort.Strings(paths)
+	want := []string{"/", "/deep/1", "/deep/2", "/guide/", "/guide/streams"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("Unexpected crawled pages:\n got: %v\nwant: %v", paths, want)
+	}
+
+	for _, path := range []string{"/", "/guide/", "/guide/streams"} {
+		if n := requests(path); n != 1 {
+			t.Errorf("Expected %s to be requested once, got %d", path, n)
+		}
+	}
+	for _, path := range []string{"/private/notes", "/logo.png", "/deep/3"} {
+		if n := requests(path); n != 0 {
+			t.Errorf("Expected %s not to be requested, got %d", path, n)
+		}
+	}
+}
+
+func TestDocumentationFetcherCrawlLimitsAndModes(t *testing.T) {
+	server, _ := newCrawlSite(t)
+	defer server.Close()
+
+	fetcher := NewDocumentationFetcher(NewHTTPClient(5*time.Second, 0, 5), server.URL, zerolog.Nop())
+	fetcher.crawlLimits = CrawlLimits{MaxPages: 3}
+	entries, err := fetcher.DiscoverEntries(context.Background())
+	if err != nil {
+		t.Fatalf("Expected successful crawl, got error: %v", err)
+	}
+	if len(entries) != 3 || entries[0].Path != "/" {
+		t.Errorf("Expected the page limit to stop the crawl after the base page and 2 more, got %v", discoveredPaths(entries))
+	}
+
+	fetcher.discovery = DiscoverySitemap
+	if _, err := fetcher.DiscoverEntries(context.Background()); err == nil {
+		t.Error("Expected sitemap discovery to fail without a sitemap")
+	}
+}
+
+// TestDocumentationFetcherCrawlMode verifies that crawl mode ignores a sitemap and
+// stays under the base URL's path
+func TestDocumentationFetcherCrawlMode(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap.xml":
+			_, _ = fmt.Fprintf(w, `<urlset><url><loc>%s/docs/from-sitemap</loc></url></urlset>`, server.URL)
+		case "/docs":
+			_, _ = fmt.Fprint(w, `<html><body><a href="/docs/a">A</a><a href="/blog/post">Blog</a></body></html>`)
+		case "/docs/a":
+			_, _ = fmt.Fprint(w, `<html><body><a href="/docs">Up</a></body></html>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := NewDocumentationFetcher(NewHTTPClient(5*time.Second, 0, 5), server.URL+"/docs", zerolog.Nop())
+	fetcher.discovery = DiscoveryCrawl
+	entries, err := fetcher.DiscoverEntries(context.Background())
+	if err != nil {
+		t.Fatalf("Expected successful crawl, got error: %v", err)
+	}
+	if got, want := discoveredPaths(entries), []string{"/", "/a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestDocumentationFetcherCrawlBaseURLWithPath verifies that crawled pages are listed
+// relative to the base URL, so their document URLs are not prefixed twice
+func TestDocumentationFetcherCrawlBaseURLWithPath(t *testing.T) {
+	var mu sync.Mutex
+	requested := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/docs":
+			_, _ = fmt.Fprint(w, `<html><body><a href="/docs/guide/a">A</a></body></html>`)
+		case "/docs/guide/a":
+			_, _ = fmt.Fprint(w, `<html><body>Guide A</body></html>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := NewDocumentationFetcher(NewHTTPClient(5*time.Second, 0, 5), server.URL+"/docs", zerolog.Nop())
+	fetcher.discovery = DiscoveryCrawl
+	pages, err := fetcher.FetchAllPages(context.Background())
+	if err != nil {
+		t.Fatalf("Expected successful crawl, got error: %v", err)
+	}
+
+	urls := map[string]string{}
+	for _, page := range pages {
+		urls[fetcher.baseURL+page.Path] = string(page.Content)
+	}
+	if content, ok := urls[server.URL+"/docs/guide/a"]; !ok || content != `<html><body>Guide A</body></html>` {
+		t.Errorf("Expected the guide page at %s/docs/guide/a, got %v", server.URL, urls)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for path := range requested {
+		if strings.HasPrefix(path, "/docs/docs") {
+			t.Errorf("Expected no double-prefixed request, got %s", path)
+		}
+	}
+}
+
+func TestNormalizeCrawlURL(t *testing.T) {
+	tests := map[string]string{
+		"HTTPS://Docs.Example.com/guide/../intro?x=1#top": "https://docs.example.com/intro",
+		"https://docs.example.com":                        "https://docs.example.com/",
+		"https://docs.example.com/guide/./":               "https://docs.example.com/guide/",
+	}
+	for raw, want := range tests {
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", raw, err)
+		}
+		if got := normalizeCrawlURL(u).String(); got != want {
+			t.Errorf("normalizeCrawlURL(%s) = %s, want %s", raw, got, want)
+		}
+	}
+}
@@ -4,13 +4,12 @@ package fetcher
 
 import (
 	"context"
-	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"net/http"
-	"net/url"
-	"strings"
+	"sort"
 	"sync"
 	"time"
 
@@ -23,6 +22,26 @@ type HTTPClient struct {
 	client      *http.Client
 	maxRetries  int
 	rateLimiter *rate.Limiter
+	limits      Limits
+	inFlight    chan struct{} // Semaphore bounding requests in flight across all hosts
+
+	hostMu     *sync.Mutex
+	hostSlots  map[string]chan struct{} // Semaphores bounding requests in flight per host
+	hostResume map[string]time.Time     // When hosts that reported rate limiting accept requests again
+
+	maxRateLimitWait time.Duration // Longest wait for a rate limit to reset before failing
+
+	layer func(base http.RoundTripper) http.RoundTripper // Wraps every transport of the client, e.g. to record or replay requests
+
+	breakers *circuitBreakers // Per-host circuit breakers; nil when disabled
+}
+
+// Limits bounds the requests issued by an HTTPClient. Every attempt, including
+// retries, waits for a request-rate token, a global slot and a slot of its host.
+type Limits struct {
+	MaxInFlight       int     // Maximum requests in flight at once
+	RequestsPerSecond float64 // Sustained request rate across all hosts
+	MaxPerHost        int     // Maximum requests in flight per host; 0 or more than MaxInFlight means MaxInFlight
 }
 
 // NewHTTPClient creates a new HTTP client with the specified timeout, max retries, and max concurrent requests.
@@ -31,24 +50,151 @@ type HTTPClient struct {
 // Parameters:
 //   - timeout: HTTP request timeout duration
 //   - maxRetries: Maximum number of retry attempts (not including the initial request)
-//   - maxConcurrent: Maximum number of concurrent requests allowed
+//   - maxConcurrent: Maximum number of requests in flight, also used as the requests per second budget
 //
 // Returns a configured HTTPClient ready for use.
 func NewHTTPClient(timeout time.Duration, maxRetries int, maxConcurrent int) *HTTPClient {
+	return NewHTTPClientWithLimits(timeout, maxRetries, Limits{
+		MaxInFlight:       maxConcurrent,
+		RequestsPerSecond: float64(maxConcurrent),
+	})
+}
+
+// NewHTTPClientWithLimits creates a new HTTP client with the specified timeout, max
+// retries and request limits.
+//
+// Parameters:
+//   - timeout: HTTP request timeout duration
+//   - maxRetries: Maximum number of retry attempts (not including the initial request)
+//   - limits: In-flight, per-host and requests per second limits shared by all attempts
+//
+// Returns a configured HTTPClient ready for use.
+func NewHTTPClientWithLimits(timeout time.Duration, maxRetries int, limits Limits) *HTTPClient {
 	// Create HTTP client with timeout
 	httpClient := &http.Client{
 		Timeout: timeout,
 	}
 
-	// Create rate limiter for concurrent requests
-	// The limiter allows maxConcurrent tokens with a burst of maxConcurrent
-	rateLimiter := rate.NewLimiter(rate.Limit(maxConcurrent), maxConcurrent)
+	if limits.MaxInFlight <= 0 {
+		limits.MaxInFlight = 1
+	}
+	if limits.MaxPerHost <= 0 || limits.MaxPerHost > limits.MaxInFlight {
+		limits.MaxPerHost = limits.MaxInFlight
+	}
+	if limits.RequestsPerSecond <= 0 {
+		limits.RequestsPerSecond = float64(limits.MaxInFlight)
+	}
+
+	// The limiter refills at the requests per second budget and allows a burst of
+	// one second's worth of requests
+	burst := int(math.Ceil(limits.RequestsPerSecond))
+	rateLimiter := rate.NewLimiter(rate.Limit(limits.RequestsPerSecond), burst)
 
 	return &HTTPClient{
 		client:      httpClient,
 		maxRetries:  maxRetries,
 		rateLimiter: rateLimiter,
+		limits:      limits,
+		inFlight:    make(chan struct{}, limits.MaxInFlight),
+		hostMu:      &sync.Mutex{},
+		hostSlots:   make(map[string]chan struct{}),
+		hostResume:  make(map[string]time.Time),
+
+		maxRateLimitWait: defaultMaxRateLimitWait,
+	}
+}
+
+// WithTransport returns a client that sends requests through transport but shares
+// the timeout, retry policy and every request limit of c, including per-host slots
+// and rate limit pauses
+func (c *HTTPClient) WithTransport(transport http.RoundTripper) *HTTPClient {
+	derived := *c
+	derived.client = &http.Client{
+		Timeout:   c.client.Timeout,
+		Transport: c.wrap(transport),
+	}
+	return &derived
+}
+
+// WithRoundTripper returns a client whose requests go through the RoundTripper that
+// layer builds around its transport, e.g. to record or replay them. The layer also
+// wraps the transports of clients later derived with WithTransport, and the client
+// shares every request limit of c.
+func (c *HTTPClient) WithRoundTripper(layer func(base http.RoundTripper) http.RoundTripper) *HTTPClient {
+	base := c.client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	derived := *c
+	derived.client = &http.Client{
+		Timeout:   c.client.Timeout,
+		Transport: layer(base),
+		Jar:       c.client.Jar,
+	}
+	if previous := c.layer; previous != nil {
+		derived.layer = func(base http.RoundTripper) http.RoundTripper {
+			return layer(previous(base))
+		}
+	} else {
+		derived.layer = layer
+	}
+	return &derived
+}
+
+// wrap applies the RoundTripper layers of the client to transport
+func (c *HTTPClient) wrap(transport http.RoundTripper) http.RoundTripper {
+	if c.layer == nil {
+		return transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return c.layer(transport)
+}
+
+// MaxInFlight returns the maximum number of requests the client keeps in flight,
+// which callers use to size their worker pools
+func (c *HTTPClient) MaxInFlight() int {
+	return c.limits.MaxInFlight
+}
+
+// acquire waits until a request to host may be sent: it takes a per-host slot, a
+// global slot and a rate token, in that order, so requests waiting on a busy host
+// do not hold global capacity. The returned function releases both slots.
+func (c *HTTPClient) acquire(ctx context.Context, host string) (func(), error) {
+	c.hostMu.Lock()
+	hostSlot, ok := c.hostSlots[host]
+	if !ok {
+		hostSlot = make(chan struct{}, c.limits.MaxPerHost)
+		c.hostSlots[host] = hostSlot
+	}
+	c.hostMu.Unlock()
+
+	select {
+	case hostSlot <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case c.inFlight <- struct{}{}:
+	case <-ctx.Done():
+		<-hostSlot
+		return nil, ctx.Err()
 	}
+
+	release := func() {
+		<-c.inFlight
+		<-hostSlot
+	}
+
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		release()
+		return nil, fmt.Errorf("rate limiter wait failed: %w", err)
+	}
+
+	return release, nil
 }
 
 // Fetch retrieves content from the specified URL with retry logic and rate limiting.
@@ -61,13 +207,90 @@ func NewHTTPClient(timeout time.Duration, maxRetries int, maxConcurrent int) *HT
 //
 // Returns the response body as bytes and any error encountered.
 // Retries on 5xx errors and network errors, but not on 4xx client errors.
+// Rate limited responses (429, or 403 with rate limit headers) are retried once the
+// limit resets if that is soon enough; otherwise a *RateLimitError is returned.
 func (c *HTTPClient) Fetch(ctx context.Context, url string) ([]byte, error) {
-	// Wait for rate limiter token
-	if err := c.rateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter wait failed: %w", err)
+	return c.FetchWithHeaders(ctx, url, nil)
+}
+
+// FetchWithHeaders retrieves content from the specified URL like Fetch, adding the
+// given request headers (e.g., Authorization) to every attempt.
+func (c *HTTPClient) FetchWithHeaders(ctx context.Context, url string, header http.Header) ([]byte, error) {
+	resp, err := c.do(ctx, url, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.body, nil
+}
+
+// FetchStream retrieves the specified URL like FetchWithHeaders, but hands a successful
+// response body to consume as it arrives instead of buffering it. An error from
+// consume is retried like a failed body read, so consume must start over on each call.
+func (c *HTTPClient) FetchStream(ctx context.Context, url string, header http.Header, consume func(body io.Reader) error) error {
+	_, err := c.do(ctx, url, header, consume)
+	return err
+}
+
+// FetchConditional retrieves content from the specified URL like Fetch, but sends the
+// ETag and Last-Modified values of previous as If-None-Match and If-Modified-Since.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - url: The URL to fetch
+//   - previous: Validators recorded by the last fetch of the URL (may be zero)
+//
+// Returns the response body, the validators of the response and whether the server
+// answered 304 Not Modified, in which case the body is nil and the previous
+// validators are carried over where the response omits them.
+func (c *HTTPClient) FetchConditional(ctx context.Context, url string, previous Validator) ([]byte, Validator, bool, error) {
+	header := make(http.Header)
+	if previous.ETag != "" {
+		header.Set("If-None-Match", previous.ETag)
+	}
+	if previous.LastModified != "" {
+		header.Set("If-Modified-Since", previous.LastModified)
 	}
 
+	resp, err := c.do(ctx, url, header, nil)
+	if err != nil {
+		return nil, Validator{}, false, err
+	}
+
+	validator := Validator{
+		ETag:         resp.header.Get("ETag"),
+		LastModified: resp.header.Get("Last-Modified"),
+	}
+	if resp.notModified {
+		if validator.ETag == "" {
+			validator.ETag = previous.ETag
+		}
+		if validator.LastModified == "" {
+			validator.LastModified = previous.LastModified
+		}
+		return nil, validator, true, nil
+	}
+
+	return resp.body, validator, false, nil
+}
+
+// response is the outcome of a successful request made by HTTPClient.do
+type response struct {
+	body        []byte
+	header      http.Header
+	notModified bool
+}
+
+// do performs a GET request with the given extra headers, retrying with exponential
+// backoff on network and 5xx errors, or after the delay the server asks for with
+// Retry-After and rate limit headers. Every attempt goes through the client limits.
+// A 304 response is only accepted when the request was conditional. When consume is
+// set, a successful response body is streamed to it rather than returned.
+func (c *HTTPClient) do(ctx context.Context, rawURL string, header http.Header, consume func(body io.Reader) error) (*response, error) {
+	conditional := header.Get("If-None-Match") != "" || header.Get("If-Modified-Since") != ""
+
 	var lastErr error
+	var requestedDelay time.Duration // Delay the server asked for before the next attempt
+	var host string                  // Host of the request, set by the first attempt
 	initialDelay := 1 * time.Second
 	maxDelay := 60 * time.Second
 
@@ -75,6 +298,10 @@ func (c *HTTPClient) Fetch(ctx context.Context, url string) ([]byte, error) {
 	for attempt := 0; attempt <= c.maxRetries; attempt++ {
 		// If this is a retry, apply exponential backoff
 		if attempt > 0 {
+			if err := c.breakers.check(host); err != nil {
+				return nil, err
+			}
+
 			delay := time.Duration(math.Pow(2, float64(attempt-1))) * initialDelay
 
 			// Cap delay at maxDelay (60 seconds)
@@ -82,6 +309,12 @@ func (c *HTTPClient) Fetch(ctx context.Context, url string) ([]byte, error) {
 				delay = maxDelay
 			}
 
+			// A delay requested by the server replaces the backoff
+			if requestedDelay > 0 {
+				delay = requestedDelay
+				requestedDelay = 0
+			}
+
 			// Wait for backoff delay or context cancellation
 			select {
 			case <-time.After(delay):
@@ -92,25 +325,76 @@ func (c *HTTPClient) Fetch(ctx context.Context, url string) ([]byte, error) {
 		}
 
 		// Create request
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
 
 		// Set User-Agent header
 		req.Header.Set("User-Agent", "nats-docs-mcp-server/1.0")
+		for key, values := range header {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+
+		// A host whose circuit breaker is open fails fast, even between retries
+		host = req.URL.Host
+		probe, err := c.breakers.allow(host)
+		if err != nil {
+			return nil, err
+		}
+
+		// Wait until the host accepts requests again, then for the request limits
+		if err := c.waitForHost(ctx, rawURL, req.URL.Host); err != nil {
+			c.breakers.release(req.URL.Host, probe)
+			return nil, err
+		}
+		release, err := c.acquire(ctx, req.URL.Host)
+		if err != nil {
+			c.breakers.release(req.URL.Host, probe)
+			return nil, err
+		}
 
 		// Execute request
 		resp, err := c.client.Do(req)
 		if err != nil {
+			release()
+			// Requests missing from replayed fixtures fail the same way every time
+			if errors.Is(err, ErrNoFixture) {
+				c.breakers.release(req.URL.Host, probe)
+				return nil, fmt.Errorf("request failed: %w", err)
+			}
+			if ctx.Err() != nil {
+				c.breakers.release(req.URL.Host, probe)
+			} else {
+				c.breakers.record(req.URL.Host, probe, err)
+			}
 			lastErr = fmt.Errorf("request failed: %w", err)
 			// Retry on network errors
 			continue
 		}
 
+		// Stream a successful response body to the consumer
+		if consume != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			err := consume(resp.Body)
+			_ = resp.Body.Close()
+			release()
+			c.breakers.record(req.URL.Host, probe, nil)
+			c.recordRateLimit(req.URL.Host, resp.Header)
+			if err != nil {
+				lastErr = fmt.Errorf("failed to read response body: %w", err)
+				continue
+			}
+			return &response{header: resp.Header}, nil
+		}
+
 		// Read response body
 		body, err := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
+		release()
+		c.breakers.record(req.URL.Host, probe, breakerFailure(resp.StatusCode, err))
+		c.recordRateLimit(req.URL.Host, resp.Header)
 
 		if err != nil {
 			lastErr = fmt.Errorf("failed to read response body: %w", err)
@@ -120,7 +404,26 @@ func (c *HTTPClient) Fetch(ctx context.Context, url string) ([]byte, error) {
 		// Check status code
 		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 			// Success
-			return body, nil
+			return &response{body: body, header: resp.Header}, nil
+		}
+
+		// Unchanged since the validators sent with a conditional request
+		if resp.StatusCode == http.StatusNotModified && conditional {
+			return &response{header: resp.Header, notModified: true}, nil
+		}
+
+		// Rate limited - retry once the limit resets, unless that is too far away
+		if limit := rateLimitFrom(resp, time.Now()); limit != nil {
+			limit.URL = rawURL
+			c.pauseHost(req.URL.Host, limit.Reset)
+
+			wait := time.Until(limit.Reset)
+			if attempt == c.maxRetries || wait > c.maxRateLimitWait {
+				return nil, limit
+			}
+			lastErr = limit
+			requestedDelay = max(wait, time.Millisecond)
+			continue
 		}
 
 		// 4xx errors are client errors - don't retry
@@ -128,9 +431,12 @@ func (c *HTTPClient) Fetch(ctx context.Context, url string) ([]byte, error) {
 			return nil, fmt.Errorf("client error: HTTP %d", resp.StatusCode)
 		}
 
-		// 5xx errors are server errors - retry
+		// 5xx errors are server errors - retry, honouring Retry-After when present
 		if resp.StatusCode >= 500 {
 			lastErr = fmt.Errorf("server error: HTTP %d", resp.StatusCode)
+			if resume, ok := retryAfter(resp.Header, time.Now()); ok {
+				requestedDelay = min(max(time.Until(resume), time.Millisecond), maxDelay)
+			}
 			continue
 		}
 
@@ -148,6 +454,17 @@ type DocumentationFetcher struct {
 	client  *HTTPClient
 	baseURL string
 	logger  zerolog.Logger
+
+	include []string // Globs of page paths to fetch; every discovered page when empty
+	exclude []string // Globs of page paths to skip
+
+	discovery   string      // DiscoveryAuto (default), DiscoverySitemap or DiscoveryCrawl
+	crawlLimits CrawlLimits // Bounds of the crawler
+
+	minSuccessRatio float64 // Share of pages that must be fetched; failures below it match ErrTooManyFailures
+
+	crawledMu sync.Mutex
+	crawled   map[string]DocumentPage // Pages fetched by the last crawl, consumed by fetchEntry
 }
 
 // NewDocumentationFetcher creates a new documentation fetcher with the specified HTTP client and base URL.
@@ -206,22 +523,22 @@ func (df *DocumentationFetcher) FetchPage(ctx context.Context, path string) ([]b
 	return content, nil
 }
 
-// Sitemap XML structures for parsing sitemap-pages.xml
-type urlset struct {
-	XMLName xml.Name   `xml:"urlset"`
-	URLs    []urlEntry `xml:"url"`
-}
-
-type urlEntry struct {
-	Loc      string `xml:"loc"`
-	Priority string `xml:"priority"`
-	LastMod  string `xml:"lastmod"`
-}
-
 // DocumentPage represents a fetched documentation page
 type DocumentPage struct {
 	Path    string
 	Content []byte
+
+	// Validator records how the page can be revalidated on the next fetch
+	Validator Validator
+	// NotModified is set when the page is unchanged since the validators passed to
+	// FetchChangedPages; Content is nil and the previous parse should be reused
+	NotModified bool
+}
+
+// SitemapEntry is a documentation page listed in the sitemap
+type SitemapEntry struct {
+	Path    string // URL path relative to the base URL
+	LastMod string // The sitemap lastmod value, empty when not listed
 }
 
 // DiscoverPages fetches the sitemap and extracts all documentation page URLs.
@@ -232,76 +549,77 @@ type DocumentPage struct {
 //
 // Returns a slice of URL paths and any error encountered.
 func (df *DocumentationFetcher) DiscoverPages(ctx context.Context) ([]string, error) {
-	// Try multiple sitemap paths for compatibility with different documentation structures
-	// NATS uses /sitemap-pages.xml, Synadia uses /sitemap.xml
-	sitemapPaths := []string{"/sitemap-pages.xml", "/sitemap.xml"}
-
-	var content []byte
-	var err error
-	var sitemapPath string
-
-	// Try each sitemap path in order
-	for _, path := range sitemapPaths {
-		df.logger.Debug().
-			Str("path", path).
-			Msg("Fetching sitemap")
-
-		content, err = df.FetchPage(ctx, path)
-		if err == nil {
-			sitemapPath = path
-			break
-		}
-		// Log and continue to next path on failure
-		df.logger.Debug().
-			Str("path", path).
-			Err(err).
-			Msg("Failed to fetch sitemap, trying next path")
+	entries, err := df.DiscoverEntries(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if content == nil {
-		return nil, fmt.Errorf("failed to fetch sitemap: tried %v, last error: %w", sitemapPaths, err)
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
 	}
+	return paths, nil
+}
 
-	df.logger.Debug().
-		Str("path", sitemapPath).
-		Msg("Successfully fetched sitemap")
+// DiscoverEntries fetches the sitemaps and extracts all documentation pages along with
+// their lastmod values. Sitemaps listed in robots.txt are read when there are any,
+// otherwise the default sitemap paths are tried in order. Sitemap indexes are followed
+// recursively and gzipped sitemaps are decompressed. Depending on the discovery mode,
+// pages are instead found by crawling links from the base URL, either always or when
+// the site has no sitemap. Pages disallowed by robots.txt or rejected by the
+// fetcher's include and exclude patterns are left out.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//
+// Returns the sitemap entries and any error encountered.
+func (df *DocumentationFetcher) DiscoverEntries(ctx context.Context) ([]SitemapEntry, error) {
+	robots := df.fetchRobots(ctx)
 
-	// Parse the XML
-	var sitemap urlset
-	if err := xml.Unmarshal(content, &sitemap); err != nil {
-		df.logger.Error().
-			Err(err).
-			Msg("Failed to parse sitemap XML")
-		return nil, fmt.Errorf("failed to parse sitemap XML: %w", err)
-	}
+	df.crawledMu.Lock()
+	df.crawled = nil
+	df.crawledMu.Unlock()
 
-	// Extract paths from URLs
-	var paths []string
-	for _, entry := range sitemap.URLs {
-		// Parse the URL to extract the path
-		parsedURL, err := url.Parse(entry.Loc)
-		if err != nil {
-			df.logger.Warn().
+	var entries []SitemapEntry
+	var err error
+	switch {
+	case df.discovery == DiscoveryCrawl:
+		entries, err = df.crawl(ctx, robots)
+	case len(robots.sitemaps) > 0:
+		entries, err = df.readSitemaps(ctx, robots.sitemaps)
+	default:
+		entries, err = df.readDefaultSitemap(ctx)
+		if errors.Is(err, errNoSitemap) && df.discovery != DiscoverySitemap {
+			df.logger.Info().
 				Err(err).
-				Str("url", entry.Loc).
-				Msg("Failed to parse URL from sitemap, skipping")
-			continue
+				Msg("No sitemap found, crawling links instead")
+			entries, err = df.crawl(ctx, robots)
 		}
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		// Get the path (relative to base URL)
-		path := parsedURL.Path
-		if path == "" {
-			path = "/"
+	// Keep the first listing of each page that robots.txt and the path filters allow
+	seen := make(map[string]bool, len(entries))
+	filtered := entries[:0]
+	for _, entry := range entries {
+		if seen[entry.Path] {
+			continue
 		}
-
-		paths = append(paths, path)
+		seen[entry.Path] = true
+		if !robots.allowed(df.sitePath(entry.Path)) || !df.matchesPathFilters(entry.Path) {
+			continue
+		}
+		filtered = append(filtered, entry)
 	}
 
 	df.logger.Info().
-		Int("count", len(paths)).
+		Int("count", len(filtered)).
+		Int("skipped", len(seen)-len(filtered)).
 		Msg("Discovered documentation pages")
 
-	return paths, nil
+	return filtered, nil
 }
 
 // FetchAllPages discovers all documentation pages and fetches them concurrently.
@@ -311,74 +629,189 @@ func (df *DocumentationFetcher) DiscoverPages(ctx context.Context) ([]string, er
 //   - ctx: Context for cancellation and timeout control
 //
 // Returns a slice of DocumentPage structs and any error encountered.
-// If some pages fail to fetch, it returns the successfully fetched pages along with an error.
+// If some pages fail to fetch, it returns the successfully fetched pages along with a
+// *PartialFetchError.
 func (df *DocumentationFetcher) FetchAllPages(ctx context.Context) ([]DocumentPage, error) {
-	// Discover all pages
-	paths, err := df.DiscoverPages(ctx)
+	return df.FetchChangedPages(ctx, nil, nil)
+}
+
+// FetchChangedPages discovers all documentation pages and fetches those that changed
+// since the previous fetch. A page is skipped without a request when its sitemap
+// lastmod equals the recorded one, and is otherwise requested conditionally with its
+// recorded ETag and Last-Modified values. Unchanged pages are returned with
+// NotModified set and no content. Pages that failed last time are fetched first.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - previous: Validators of the last fetch keyed by page path (nil fetches everything)
+//   - retry: Paths of pages that failed to fetch last time
+//
+// Returns the pages and any error encountered.
+// If some pages fail to fetch, it returns the successfully fetched pages along with a
+// *PartialFetchError.
+func (df *DocumentationFetcher) FetchChangedPages(ctx context.Context, previous map[string]Validator, retry []string) ([]DocumentPage, error) {
+	return collect(func(out chan<- DocumentPage) error {
+		return df.StreamChangedPages(ctx, previous, retry, out)
+	})
+}
+
+// StreamChangedPages works like FetchChangedPages but sends each page to out as soon
+// as it is fetched instead of collecting them, so callers can process pages while
+// others are still downloading. Sends block while out is full, which bounds the pages
+// held in memory. It returns once every page was sent or ctx is cancelled, and never
+// closes out.
+//
+// Returns any error encountered; pages that failed to fetch are reported with a
+// *PartialFetchError.
+func (df *DocumentationFetcher) StreamChangedPages(ctx context.Context, previous map[string]Validator, retry []string, out chan<- DocumentPage) error {
+	entries, err := df.DiscoverEntries(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to discover pages: %w", err)
+	}
+
+	return df.streamEntries(ctx, retryFirst(entries, retry), previous, out)
+}
+
+// FetchMatchingPages discovers all documentation pages and concurrently fetches
+// those whose path satisfies match.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control
+//   - match: Reports whether a discovered page path should be fetched
+//
+// Returns the fetched pages; if some pages fail to fetch, the successfully fetched
+// pages are returned along with a *PartialFetchError.
+func (df *DocumentationFetcher) FetchMatchingPages(ctx context.Context, match func(path string) bool) ([]DocumentPage, error) {
+	entries, err := df.DiscoverEntries(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover pages: %w", err)
 	}
 
+	var matched []SitemapEntry
+	for _, entry := range entries {
+		if match(entry.Path) {
+			matched = append(matched, entry)
+		}
+	}
+
+	return collect(func(out chan<- DocumentPage) error {
+		return df.streamEntries(ctx, matched, nil, out)
+	})
+}
+
+// streamEntries fetches the given sitemap entries on a worker pool sized to the client's
+// in-flight limit, revalidating those with previous validators, and sends each fetched
+// page to out
+func (df *DocumentationFetcher) streamEntries(ctx context.Context, entries []SitemapEntry, previous map[string]Validator, out chan<- DocumentPage) error {
 	df.logger.Info().
-		Int("total_pages", len(paths)).
+		Int("total_pages", len(entries)).
+		Int("workers", df.client.MaxInFlight()).
 		Msg("Starting concurrent page fetching")
 
-	// Fetch all pages concurrently
-	var wg sync.WaitGroup
 	var mu sync.Mutex
-	var pages []DocumentPage
-	var fetchErrors []error
-
-	for _, path := range paths {
-		wg.Add(1)
-
-		// Capture path for goroutine
-		currentPath := path
+	var failures []PageFailure
+	done := make(map[string]bool, len(entries))
+	fetched, unchanged := 0, 0
+
+	runPool(ctx, df.client.MaxInFlight(), entries, func(entry SitemapEntry) {
+		// Fetch the page and hand it on; a page the consumer never received failed
+		page, err := df.fetchEntry(ctx, entry, previous[entry.Path])
+		if err == nil && !send(ctx, out, page) {
+			err = fmt.Errorf("page not delivered: %w", ctx.Err())
+		}
 
-		go func() {
-			defer wg.Done()
+		mu.Lock()
+		defer mu.Unlock()
+		done[entry.Path] = true
+		if err != nil {
+			failures = append(failures, PageFailure{Path: entry.Path, URL: df.baseURL + entry.Path, Error: err.Error()})
+			return
+		}
 
-			// Fetch the page
-			content, err := df.FetchPage(ctx, currentPath)
-			if err != nil {
-				mu.Lock()
-				fetchErrors = append(fetchErrors, fmt.Errorf("failed to fetch %s: %w", currentPath, err))
-				mu.Unlock()
-				return
+		fetched++
+		if page.NotModified {
+			unchanged++
+		}
+	})
+
+	// Pages never started because the context was cancelled count as failures
+	cause := ctx.Err()
+	if cause != nil {
+		for _, entry := range entries {
+			if !done[entry.Path] {
+				failures = append(failures, PageFailure{Path: entry.Path, URL: df.baseURL + entry.Path, Error: "not fetched: " + cause.Error()})
 			}
-
-			// Add to results
-			mu.Lock()
-			pages = append(pages, DocumentPage{
-				Path:    currentPath,
-				Content: content,
-			})
-			mu.Unlock()
-		}()
+		}
 	}
 
-	// Wait for all fetches to complete
-	wg.Wait()
-
 	df.logger.Info().
-		Int("successful", len(pages)).
-		Int("failed", len(fetchErrors)).
-		Int("total", len(paths)).
+		Int("successful", fetched).
+		Int("unchanged", unchanged).
+		Int("failed", len(failures)).
+		Int("total", len(entries)).
 		Msg("Completed page fetching")
 
-	// If there were any errors, return them along with successful pages
-	if len(fetchErrors) > 0 {
-		// Combine all errors into one
-		var errMsg strings.Builder
-		errMsg.WriteString(fmt.Sprintf("failed to fetch %d pages: ", len(fetchErrors)))
-		for i, err := range fetchErrors {
-			if i > 0 {
-				errMsg.WriteString("; ")
-			}
-			errMsg.WriteString(err.Error())
+	// If there were any failures, report them along with the successful pages
+	if len(failures) > 0 {
+		sort.Slice(failures, func(i, j int) bool { return failures[i].Path < failures[j].Path })
+		return &PartialFetchError{
+			Total:           len(entries),
+			Failures:        failures,
+			MinSuccessRatio: df.minSuccessRatio,
+			cause:           cause,
 		}
-		return pages, fmt.Errorf("%s", errMsg.String())
 	}
 
-	return pages, nil
+	return nil
+}
+
+// fetchEntry fetches a single sitemap entry, recording its validators. The request is
+// conditional when previous validators exist, and skipped entirely when the sitemap
+// lastmod is unchanged, so callers must only pass validators of pages whose previous
+// documents they still have. Pages the crawler already fetched are not requested again.
+func (df *DocumentationFetcher) fetchEntry(ctx context.Context, entry SitemapEntry, previous Validator) (DocumentPage, error) {
+	if page, ok := df.takeCrawled(entry.Path); ok {
+		return page, nil
+	}
+
+	if entry.LastMod != "" && entry.LastMod == previous.LastMod {
+		df.logger.Debug().
+			Str("path", entry.Path).
+			Str("lastmod", entry.LastMod).
+			Msg("Skipping documentation page unchanged since last fetch")
+		return DocumentPage{Path: entry.Path, Validator: previous, NotModified: true}, nil
+	}
+
+	url := df.baseURL + entry.Path
+
+	df.logger.Debug().
+		Str("url", url).
+		Str("path", entry.Path).
+		Bool("conditional", !previous.IsZero()).
+		Msg("Fetching documentation page")
+
+	content, validator, notModified, err := df.client.FetchConditional(ctx, url, previous)
+	if err != nil {
+		df.logger.Error().
+			Err(err).
+			Str("url", url).
+			Str("path", entry.Path).
+			Msg("Failed to fetch documentation page")
+		return DocumentPage{}, fmt.Errorf("failed to fetch page %s: %w", entry.Path, err)
+	}
+	validator.LastMod = entry.LastMod
+
+	df.logger.Info().
+		Str("url", url).
+		Str("path", entry.Path).
+		Bool("not_modified", notModified).
+		Int("content_size", len(content)).
+		Msg("Successfully fetched documentation page")
+
+	return DocumentPage{
+		Path:        entry.Path,
+		Content:     content,
+		Validator:   validator,
+		NotModified: notModified,
+	}, nil
 }
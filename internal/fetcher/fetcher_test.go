@@ -3,8 +3,11 @@ package fetcher
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -692,8 +695,8 @@ func TestDocumentationFetcherFetchAllPages(t *testing.T) {
 		}
 	}
 
-	// Verify fetch count (1 for sitemap + 3 for pages = 4)
-	expectedFetches := int32(4)
+	// Verify fetch count (1 for robots.txt + 1 for sitemap + 3 for pages = 5)
+	expectedFetches := int32(5)
 	if atomic.LoadInt32(&fetchCount) != expectedFetches {
 		t.Errorf("Expected %d fetches, got %d", expectedFetches, atomic.LoadInt32(&fetchCount))
 	}
@@ -733,8 +736,12 @@ func TestDocumentationFetcherFetchAllPagesWithErrors(t *testing.T) {
 	pages, err := fetcher.FetchAllPages(ctx)
 
 	// Should return an error since some pages failed
-	if err == nil {
-		t.Error("Expected error for partial failures, got nil")
+	var partial *PartialFetchError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected a PartialFetchError, got %v", err)
+	}
+	if partial.Total != 3 || len(partial.Failures) != 1 || partial.Failures[0].URL != testServer.URL+"/page2" {
+		t.Errorf("Unexpected partial failure report: %+v", partial)
 	}
 
 	// Should still return successfully fetched pages
@@ -742,3 +749,250 @@ func TestDocumentationFetcherFetchAllPagesWithErrors(t *testing.T) {
 		t.Errorf("Expected 2 successful pages, got %d", len(pages))
 	}
 }
+
+// TestDocumentationFetcherFetchMatchingPages verifies that only matching pages are fetched
+func TestDocumentationFetcherFetchMatchingPages(t *testing.T) {
+	var fetched sync.Map
+
+	var testServer *httptest.Server
+	testServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched.Store(r.URL.Path, true)
+
+		if r.URL.Path == "/sitemap-pages.xml" {
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>` + testServer.URL + `/running-a-nats-service/configuration</loc></url>
+	<url><loc>` + testServer.URL + `/nats-concepts/overview</loc></url>
+</urlset>`))
+			return
+		}
+		_, _ = w.Write([]byte("<html><body>Page content</body></html>"))
+	}))
+	defer testServer.Close()
+
+	fetcher := NewDocumentationFetcher(NewHTTPClient(5*time.Second, 1, 5), testServer.URL, zerolog.Nop())
+
+	pages, err := fetcher.FetchMatchingPages(context.Background(), func(path string) bool {
+		return strings.HasPrefix(path, "/running-a-nats-service/")
+	})
+	if err != nil {
+		t.Fatalf("Expected successful fetch, got error: %v", err)
+	}
+
+	if len(pages) != 1 || pages[0].Path != "/running-a-nats-service/configuration" {
+		t.Fatalf("Expected only the configuration page, got %+v", pages)
+	}
+	if _, ok := fetched.Load("/nats-concepts/overview"); ok {
+		t.Error("Non-matching page should not be fetched")
+	}
+}
+
+// TestHTTPClientFetchConditional verifies that validators are sent and a 304 is reported as not modified
+func TestHTTPClientFetchConditional(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		_, _ = w.Write([]byte("content"))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(5*time.Second, 0, 10)
+	ctx := context.Background()
+
+	body, validator, notModified, err := client.FetchConditional(ctx, server.URL, Validator{})
+	if err != nil {
+		t.Fatalf("FetchConditional failed: %v", err)
+	}
+	if notModified || string(body) != "content" {
+		t.Fatalf("Expected content, got notModified=%v body=%q", notModified, body)
+	}
+	if validator.ETag != `"v1"` || validator.LastModified != "Mon, 02 Jan 2006 15:04:05 GMT" {
+		t.Errorf("Unexpected validator: %+v", validator)
+	}
+
+	body, revalidated, notModified, err := client.FetchConditional(ctx, server.URL, validator)
+	if err != nil {
+		t.Fatalf("Conditional FetchConditional failed: %v", err)
+	}
+	if !notModified || body != nil {
+		t.Fatalf("Expected not modified, got notModified=%v body=%q", notModified, body)
+	}
+	if revalidated != validator {
+		t.Errorf("Expected validators to carry over, got %+v", revalidated)
+	}
+}
+
+// TestDocumentationFetcherFetchChangedPages verifies that unchanged pages are skipped or revalidated
+func TestDocumentationFetcherFetchChangedPages(t *testing.T) {
+	var requests sync.Map
+	var testServer *httptest.Server
+	testServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count, _ := requests.LoadOrStore(r.URL.Path, new(int32))
+		atomic.AddInt32(count.(*int32), 1)
+
+		switch r.URL.Path {
+		case "/sitemap-pages.xml":
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>` + testServer.URL + `/lastmod</loc><lastmod>2024-01-01</lastmod></url>
+	<url><loc>` + testServer.URL + `/etag</loc></url>
+	<url><loc>` + testServer.URL + `/changed</loc><lastmod>2024-02-01</lastmod></url>
+</urlset>`))
+		case "/etag":
+			if r.Header.Get("If-None-Match") == `"e1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"e1"`)
+			_, _ = w.Write([]byte("etag page"))
+		default:
+			_, _ = w.Write([]byte("page " + r.URL.Path))
+		}
+	}))
+	defer testServer.Close()
+
+	fetcher := NewDocumentationFetcher(NewHTTPClient(5*time.Second, 0, 10), testServer.URL, zerolog.Nop())
+
+	previous := map[string]Validator{
+		"/lastmod": {LastMod: "2024-01-01"},
+		"/etag":    {ETag: `"e1"`},
+		"/changed": {LastMod: "2024-01-15"},
+	}
+
+	pages, err := fetcher.FetchChangedPages(context.Background(), previous, nil)
+	if err != nil {
+		t.Fatalf("FetchChangedPages failed: %v", err)
+	}
+	if len(pages) != 3 {
+		t.Fatalf("Expected 3 pages, got %d", len(pages))
+	}
+
+	byPath := make(map[string]DocumentPage)
+	for _, page := range pages {
+		byPath[page.Path] = page
+	}
+
+	if page := byPath["/lastmod"]; !page.NotModified || page.Content != nil {
+		t.Errorf("Expected /lastmod to be skipped, got %+v", page)
+	}
+	if _, requested := requests.Load("/lastmod"); requested {
+		t.Error("Expected no request for a page with an unchanged lastmod")
+	}
+	if page := byPath["/etag"]; !page.NotModified || page.Validator.ETag != `"e1"` {
+		t.Errorf("Expected /etag to be revalidated as not modified, got %+v", page)
+	}
+	if page := byPath["/changed"]; page.NotModified || string(page.Content) != "page /changed" || page.Validator.LastMod != "2024-02-01" {
+		t.Errorf("Expected /changed to be fetched with its new lastmod, got %+v", page)
+	}
+}
+
+// TestHTTPClientLimitsApplyToRetries verifies that retried attempts respect the in-flight limit
+func TestHTTPClientLimitsApplyToRetries(t *testing.T) {
+	var inFlight, maxInFlight int32
+	var attempts sync.Map
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		// Fail the first attempt of every path so each request is retried
+		if _, retried := attempts.LoadOrStore(r.URL.Path, true); !retried {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClientWithLimits(5*time.Second, 1, Limits{MaxInFlight: 2, RequestsPerSecond: 1000})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := client.Fetch(context.Background(), fmt.Sprintf("%s/page-%d", server.URL, i)); err != nil {
+				t.Errorf("Fetch failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if max := atomic.LoadInt32(&maxInFlight); max > 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", max)
+	}
+}
+
+// TestHTTPClientMaxPerHost verifies that the per-host limit is enforced below the global limit
+func TestHTTPClientMaxPerHost(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClientWithLimits(5*time.Second, 0, Limits{MaxInFlight: 8, RequestsPerSecond: 1000, MaxPerHost: 1})
+	if client.MaxInFlight() != 8 {
+		t.Fatalf("Expected MaxInFlight 8, got %d", client.MaxInFlight())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = client.Fetch(context.Background(), server.URL)
+		}()
+	}
+	wg.Wait()
+
+	if max := atomic.LoadInt32(&maxInFlight); max != 1 {
+		t.Errorf("Expected 1 request in flight per host, got %d", max)
+	}
+}
+
+// TestRunPool verifies that the worker pool bounds concurrency and visits every item
+func TestRunPool(t *testing.T) {
+	var running, maxRunning, visited int32
+	items := make([]int, 20)
+
+	runPool(context.Background(), 3, items, func(int) {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		atomic.AddInt32(&visited, 1)
+	})
+
+	if visited != 20 {
+		t.Errorf("Expected 20 items visited, got %d", visited)
+	}
+	if maxRunning > 3 {
+		t.Errorf("Expected at most 3 workers, got %d", maxRunning)
+	}
+}
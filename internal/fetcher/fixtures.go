@@ -0,0 +1,244 @@
+package fetcher
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Fixture modes of FixtureConfig
+const (
+	FixtureModeOff    = "off"    // Requests go to the network
+	FixtureModeRecord = "record" // Requests go to the network and responses are saved
+	FixtureModeReplay = "replay" // Saved responses are served without any network access
+)
+
+// ErrNoFixture is returned in replay mode for a request that was never recorded.
+// It is not retried.
+var ErrNoFixture = errors.New("no recorded response")
+
+// FixtureConfig selects whether requests are recorded to, or replayed from, a
+// fixture directory
+type FixtureConfig struct {
+	Mode string // FixtureModeOff (default), FixtureModeRecord or FixtureModeReplay
+	Dir  string // Directory holding one JSON file per recorded request
+}
+
+// Layer returns the RoundTripper layer of the fixture mode for HTTPClient.WithRoundTripper,
+// or nil when fixtures are off.
+//
+// Returns an error when the mode is unknown or the directory is not set.
+func (fc FixtureConfig) Layer() (func(base http.RoundTripper) http.RoundTripper, error) {
+	if fc.Mode == "" || fc.Mode == FixtureModeOff {
+		return nil, nil
+	}
+	if fc.Dir == "" {
+		return nil, fmt.Errorf("fixture directory is required in %s mode", fc.Mode)
+	}
+	switch fc.Mode {
+	case FixtureModeRecord:
+		return func(base http.RoundTripper) http.RoundTripper {
+			return NewRecordingTransport(fc.Dir, base)
+		}, nil
+	case FixtureModeReplay:
+		return func(http.RoundTripper) http.RoundTripper {
+			return NewReplayTransport(fc.Dir)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown fixture mode %q", fc.Mode)
+	}
+}
+
+// fixture is a recorded request and its response, stored as JSON. Text bodies are
+// kept readable so fixtures can be inspected and edited by hand.
+type fixture struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 []byte      `json:"body_base64,omitempty"`
+	RecordedAt time.Time   `json:"recorded_at"`
+}
+
+// fixturePath returns the file of the fixture of a request: a directory per host
+// holding files named by a hash of the method and URL
+func fixturePath(dir string, req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + req.URL.String()))
+	host := strings.NewReplacer(":", "_", "/", "_").Replace(req.URL.Host)
+	return filepath.Join(dir, host, hex.EncodeToString(sum[:12])+".json")
+}
+
+// recordedHeaders are dropped from recorded responses: the body length changes when
+// a compressed body is stored decoded, and cookies may hold session secrets
+var recordedHeaders = []string{"Content-Length", "Content-Encoding", "Set-Cookie"}
+
+// RecordingTransport sends requests through its base transport and saves every
+// response to a fixture directory. Request headers are not saved, so credentials
+// never reach the fixtures.
+type RecordingTransport struct {
+	dir  string
+	base http.RoundTripper
+}
+
+// NewRecordingTransport creates a transport recording the responses of base, or of
+// the default transport when base is nil, to dir
+func NewRecordingTransport(dir string, base http.RoundTripper) *RecordingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RecordingTransport{dir: dir, base: base}
+}
+
+// RoundTrip sends req and records its response. Conditional headers are removed so
+// that full bodies are recorded; replay answers conditional requests from them.
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		req = req.Clone(req.Context())
+		req.Header.Del("If-None-Match")
+		req.Header.Del("If-Modified-Since")
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	f := fixture{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		Status:     resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		RecordedAt: time.Now().UTC(),
+	}
+	for _, name := range recordedHeaders {
+		f.Header.Del(name)
+	}
+	if utf8.Valid(body) {
+		f.Body = string(body)
+	} else {
+		f.BodyBase64 = body
+	}
+	if err := writeFixture(fixturePath(t.dir, req), f); err != nil {
+		return nil, fmt.Errorf("failed to record %s: %w", req.URL, err)
+	}
+
+	return resp, nil
+}
+
+// writeFixture writes f through a temporary file so concurrent recordings of the
+// same request never leave a partial file
+func writeFixture(file string, f fixture) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".fixture-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// ReplayTransport serves the responses saved by a RecordingTransport without any
+// network access
+type ReplayTransport struct {
+	dir string
+}
+
+// NewReplayTransport creates a transport serving the fixtures in dir
+func NewReplayTransport(dir string) *ReplayTransport {
+	return &ReplayTransport{dir: dir}
+}
+
+// RoundTrip returns the recorded response of req, or a 304 when req is conditional
+// and its validators match the recorded ETag or Last-Modified. A request that was
+// never recorded fails with ErrNoFixture.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	data, err := os.ReadFile(fixturePath(t.dir, req))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s %s", ErrNoFixture, req.Method, req.URL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid fixture for %s: %w", req.URL, err)
+	}
+
+	body := f.BodyBase64
+	if body == nil {
+		body = []byte(f.Body)
+	}
+	status := f.Status
+	if status >= 200 && status < 300 && notModified(req.Header, f.Header) {
+		status, body = http.StatusNotModified, nil
+	}
+
+	header := f.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// notModified reports whether the validators of a conditional request match the
+// recorded response, as a server would before answering 304
+func notModified(request, recorded http.Header) bool {
+	if etag := request.Get("If-None-Match"); etag != "" {
+		return etag == recorded.Get("ETag")
+	}
+	if since := request.Get("If-Modified-Since"); since != "" {
+		sinceTime, err := http.ParseTime(since)
+		if err != nil {
+			return false
+		}
+		modified, err := http.ParseTime(recorded.Get("Last-Modified"))
+		return err == nil && !modified.After(sinceTime)
+	}
+	return false
+}
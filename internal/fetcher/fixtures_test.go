@@ -0,0 +1,192 @@
+package fetcher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func fixtureClient(t *testing.T, mode, dir string) *HTTPClient {
+	t.Helper()
+	layer, err := FixtureConfig{Mode: mode, Dir: dir}.Layer()
+	if err != nil {
+		t.Fatalf("Layer failed: %v", err)
+	}
+	return NewHTTPClient(5*time.Second, 3, 50).WithRoundTripper(layer)
+}
+
+// TestFixturesRecordAndReplay verifies that recorded responses, including binary
+// bodies and errors, are replayed once the server is gone
+func TestFixturesRecordAndReplay(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write([]byte("<urlset></urlset>"))
+	_ = zw.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/page":
+			if r.Header.Get("If-None-Match") != "" {
+				t.Error("Expected conditional headers to be removed while recording")
+			}
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte("<h1>Streams</h1>"))
+		case "/sitemap.xml.gz":
+			_, _ = w.Write(gz.Bytes())
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	recorder := fixtureClient(t, FixtureModeRecord, dir)
+	if _, _, _, err := recorder.FetchConditional(ctx, server.URL+"/page", Validator{ETag: `"v0"`}); err != nil {
+		t.Fatalf("Recording failed: %v", err)
+	}
+	if _, err := recorder.Fetch(ctx, server.URL+"/sitemap.xml.gz"); err != nil {
+		t.Fatalf("Recording failed: %v", err)
+	}
+	if _, err := recorder.Fetch(ctx, server.URL+"/missing"); err == nil {
+		t.Fatal("Expected the 404 to be returned while recording")
+	}
+	server.Close()
+
+	replayer := fixtureClient(t, FixtureModeReplay, dir)
+	body, err := replayer.Fetch(ctx, server.URL+"/page")
+	if err != nil || string(body) != "<h1>Streams</h1>" {
+		t.Errorf("Unexpected replayed page: %q, %v", body, err)
+	}
+	body, err = replayer.Fetch(ctx, server.URL+"/sitemap.xml.gz")
+	if err != nil || !bytes.Equal(body, gz.Bytes()) {
+		t.Errorf("Expected the binary body to be replayed unchanged, got %v", err)
+	}
+	if _, err := replayer.Fetch(ctx, server.URL+"/missing"); err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("Expected the recorded 404, got: %v", err)
+	}
+
+	// Conditional requests are answered from the recorded validators
+	_, _, notModified, err := replayer.FetchConditional(ctx, server.URL+"/page", Validator{ETag: `"v1"`})
+	if err != nil || !notModified {
+		t.Errorf("Expected 304 for a matching ETag, got %v, %v", notModified, err)
+	}
+	body, _, notModified, err = replayer.FetchConditional(ctx, server.URL+"/page", Validator{ETag: `"v0"`})
+	if err != nil || notModified || len(body) == 0 {
+		t.Errorf("Expected the recorded body for a stale ETag, got %v, %v", notModified, err)
+	}
+
+	// A request that was never recorded fails at once instead of being retried
+	start := time.Now()
+	_, err = replayer.Fetch(ctx, server.URL+"/never-recorded")
+	if !errors.Is(err, ErrNoFixture) {
+		t.Errorf("Expected ErrNoFixture, got: %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected no retries for a missing fixture, took %v", time.Since(start))
+	}
+}
+
+// TestFixturesReplayDocumentationSite verifies that a whole discovery and fetch run
+// is reproduced from fixtures, through the transports of derived clients
+func TestFixturesReplayDocumentationSite(t *testing.T) {
+	server, _ := newCrawlSite(t)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	fetchAll := func(client *HTTPClient) map[string]string {
+		t.Helper()
+		client, err := client.WithAuth(server.URL, Auth{BearerToken: "s3cret"})
+		if err != nil {
+			t.Fatalf("WithAuth failed: %v", err)
+		}
+		df := NewDocumentationFetcher(client.WithTransport(http.DefaultTransport.(*http.Transport).Clone()), server.URL, zerolog.Nop())
+		pages, err := df.FetchAllPages(ctx)
+		if err != nil {
+			t.Fatalf("FetchAllPages failed: %v", err)
+		}
+		contents := make(map[string]string, len(pages))
+		for _, page := range pages {
+			contents[page.Path] = string(page.Content)
+		}
+		return contents
+	}
+
+	recorded := fetchAll(fixtureClient(t, FixtureModeRecord, dir))
+	server.Close()
+	replayed := fetchAll(fixtureClient(t, FixtureModeReplay, dir))
+
+	if len(recorded) == 0 || !reflect.DeepEqual(recorded, replayed) {
+		t.Errorf("Replayed pages %v differ from recorded pages %v", replayed, recorded)
+	}
+
+	// Request headers, and with them credentials, are never written to fixtures
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(data, []byte("s3cret")) {
+			t.Errorf("Fixture %s contains a credential", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk fixtures: %v", err)
+	}
+}
+
+func TestFixtureConfigLayer(t *testing.T) {
+	if layer, err := (FixtureConfig{}).Layer(); layer != nil || err != nil {
+		t.Errorf("Expected no layer when fixtures are off, got %v", err)
+	}
+	if _, err := (FixtureConfig{Mode: FixtureModeReplay}).Layer(); err == nil {
+		t.Error("Expected an error without a fixture directory")
+	}
+	if _, err := (FixtureConfig{Mode: "rewind", Dir: t.TempDir()}).Layer(); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
+
+// TestMultiSourceFetcherFixtures verifies that the fixtures option applies to every
+// source and that an invalid fixture configuration fails the fetcher
+func TestMultiSourceFetcherFixtures(t *testing.T) {
+	if _, err := NewMultiSourceFetcher(FetchConfig{}, FetchConfig{}, GitHubFetchConfig{}, zerolog.Nop(),
+		WithFixtures(FixtureConfig{Mode: FixtureModeReplay})); err == nil {
+		t.Error("Expected an error without a fixture directory")
+	}
+	if _, err := NewMultiSourceFetcher(FetchConfig{}, FetchConfig{}, GitHubFetchConfig{}, zerolog.Nop(),
+		WithFixtures(FixtureConfig{Mode: "rewind", Dir: t.TempDir()})); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+
+	// An empty replay directory fails every request, whichever source sends it
+	msf, err := NewMultiSourceFetcher(
+		FetchConfig{BaseURL: "https://docs.nats.example", FetchTimeout: 5 * time.Second, MaxConcurrent: 1},
+		FetchConfig{BaseURL: "https://docs.synadia.example", FetchTimeout: 5 * time.Second, MaxConcurrent: 1},
+		GitHubFetchConfig{},
+		zerolog.Nop(),
+		WithFixtures(FixtureConfig{Mode: FixtureModeReplay, Dir: t.TempDir()}),
+	)
+	if err != nil {
+		t.Fatalf("NewMultiSourceFetcher failed: %v", err)
+	}
+	for _, df := range []*DocumentationFetcher{msf.natsFetcher, msf.syadiaFetcher} {
+		if _, err := df.client.Fetch(context.Background(), df.baseURL+"/"); !errors.Is(err, ErrNoFixture) {
+			t.Errorf("Expected a missing fixture for %s, got %v", df.baseURL, err)
+		}
+	}
+}
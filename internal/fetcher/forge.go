@@ -0,0 +1,378 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Forge types supported by GitHubRepo.Forge
+const (
+	// ForgeGitHub is GitHub or GitHub Enterprise Server (REST API v3)
+	ForgeGitHub = "github"
+	// ForgeGitea is Gitea or Forgejo (REST API v1)
+	ForgeGitea = "gitea"
+	// ForgeGitLab is GitLab (REST API v4)
+	ForgeGitLab = "gitlab"
+)
+
+// IsForge reports whether forge names a supported forge type; empty means ForgeGitHub
+func IsForge(forge string) bool {
+	switch forge {
+	case "", ForgeGitHub, ForgeGitea, ForgeGitLab:
+		return true
+	}
+	return false
+}
+
+// maxTreePages limits how many pages of a paginated tree listing are requested
+const maxTreePages = 100
+
+// TreeEntry is a file listed in a repository tree
+type TreeEntry struct {
+	Path string // File path in repository
+	SHA  string // Git blob SHA
+	Size int    // Size in bytes, 0 when the forge does not report it
+}
+
+// ForgeClient lists and downloads repository files through the REST API of a Git
+// forge. Every method works on repo at repo.Branch.
+type ForgeClient interface {
+	// ListFiles returns every file in the repository tree
+	ListFiles(ctx context.Context, repo GitHubRepo) ([]TreeEntry, error)
+	// FetchFile returns the content of one file
+	FetchFile(ctx context.Context, repo GitHubRepo, path string) ([]byte, error)
+	// ListReleases returns one page of releases, newest first
+	ListReleases(ctx context.Context, repo GitHubRepo, page, perPage int) ([]GitHubRelease, error)
+	// DownloadArchive streams a gzipped tarball of the repository to consume
+	DownloadArchive(ctx context.Context, repo GitHubRepo, consume func(io.Reader) error) error
+}
+
+// forge returns the client for the forge hosting repo. Repositories on the default
+// API URL use the fetcher's token; any other host only receives repo.Token.
+func (gf *GitHubFetcher) forge(repo GitHubRepo) ForgeClient {
+	baseURL := strings.TrimSuffix(repo.APIURL, "/")
+	token := repo.Token
+	if baseURL == "" {
+		baseURL = gf.apiURL
+		if token == "" {
+			token = gf.token
+		}
+	}
+
+	api := gitHubAPI{client: gf.client, baseURL: baseURL, token: token, logger: gf.logger}
+	switch repo.Forge {
+	case ForgeGitea:
+		return giteaAPI{gitHubAPI: api}
+	case ForgeGitLab:
+		return gitLabAPI{client: gf.client, baseURL: baseURL, token: token}
+	default:
+		return api
+	}
+}
+
+// gitHubAPI is the ForgeClient of GitHub and GitHub Enterprise Server
+type gitHubAPI struct {
+	client  *HTTPClient
+	baseURL string
+	token   string
+	logger  zerolog.Logger
+}
+
+// headers returns the headers sent with every request, including the token when one
+// is configured
+func (api gitHubAPI) headers() http.Header {
+	header := make(http.Header)
+	header.Set("Accept", "application/vnd.github.v3+json")
+	if api.token != "" {
+		header.Set("Authorization", fmt.Sprintf("token %s", api.token))
+	}
+	return header
+}
+
+// ListFiles lists the repository with the recursive tree API
+func (api gitHubAPI) ListFiles(ctx context.Context, repo GitHubRepo) ([]TreeEntry, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/git/trees/%s?recursive=1", api.baseURL, repo.Owner, repo.Name, repo.Branch)
+
+	tree, err := api.fetchTree(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if tree.Truncated {
+		api.logger.Warn().
+			Str("repo", repo.ShortName).
+			Msg("GitHub tree response was truncated, some files may be missing")
+	}
+
+	return tree.files(), nil
+}
+
+// fetchTree fetches and decodes one tree API response
+func (api gitHubAPI) fetchTree(ctx context.Context, endpoint string) (*gitHubTreeResponse, error) {
+	content, err := api.client.FetchWithHeaders(ctx, endpoint, api.headers())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tree: %w", err)
+	}
+
+	var tree gitHubTreeResponse
+	if err := json.Unmarshal(content, &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse tree response: %w", err)
+	}
+	return &tree, nil
+}
+
+// FetchFile fetches a file with the contents API
+func (api gitHubAPI) FetchFile(ctx context.Context, repo GitHubRepo, path string) ([]byte, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/contents/%s?ref=%s", api.baseURL, repo.Owner, repo.Name, path, repo.Branch)
+
+	content, err := api.client.FetchWithHeaders(ctx, endpoint, api.headers())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch file: %w", err)
+	}
+
+	var fileResp gitHubFileResponse
+	if err := json.Unmarshal(content, &fileResp); err != nil {
+		return nil, fmt.Errorf("failed to parse file response: %w", err)
+	}
+
+	// For markdown files, the API returns base64-encoded content
+	// However, we need to check if it's actually base64 or raw text
+	decodedContent, err := decodeBase64IfNeeded(fileResp.Content)
+	if err != nil {
+		// If decoding fails, try to use as-is
+		decodedContent = []byte(fileResp.Content)
+	}
+
+	return decodedContent, nil
+}
+
+// ListReleases fetches one page of the releases API
+func (api gitHubAPI) ListReleases(ctx context.Context, repo GitHubRepo, page, perPage int) ([]GitHubRelease, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=%d&page=%d", api.baseURL, repo.Owner, repo.Name, perPage, page)
+	return api.fetchReleases(ctx, endpoint)
+}
+
+// fetchReleases fetches and decodes one releases API response
+func (api gitHubAPI) fetchReleases(ctx context.Context, endpoint string) ([]GitHubRelease, error) {
+	content, err := api.client.FetchWithHeaders(ctx, endpoint, api.headers())
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []GitHubRelease
+	if err := json.Unmarshal(content, &releases); err != nil {
+		return nil, fmt.Errorf("failed to parse releases response: %w", err)
+	}
+	return releases, nil
+}
+
+// DownloadArchive downloads the tarball endpoint, following its redirect to the
+// archive host
+func (api gitHubAPI) DownloadArchive(ctx context.Context, repo GitHubRepo, consume func(io.Reader) error) error {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/tarball/%s", api.baseURL, repo.Owner, repo.Name, repo.Branch)
+	return api.client.FetchStream(ctx, endpoint, api.headers(), consume)
+}
+
+// giteaAPI is the ForgeClient of Gitea and Forgejo, whose contents and releases
+// endpoints match GitHub's but whose trees are paginated
+type giteaAPI struct {
+	gitHubAPI
+}
+
+// ListFiles lists the repository with the recursive tree API, one page at a time
+func (api giteaAPI) ListFiles(ctx context.Context, repo GitHubRepo) ([]TreeEntry, error) {
+	var files []TreeEntry
+	listed := 0
+	for page := 1; page <= maxTreePages; page++ {
+		endpoint := fmt.Sprintf("%s/repos/%s/%s/git/trees/%s?recursive=true&per_page=1000&page=%d",
+			api.baseURL, repo.Owner, repo.Name, url.PathEscape(repo.Branch), page)
+
+		tree, err := api.fetchTree(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, tree.files()...)
+		listed += len(tree.Tree)
+		if len(tree.Tree) == 0 || !tree.Truncated || listed >= tree.TotalCount {
+			break
+		}
+	}
+	return files, nil
+}
+
+// ListReleases fetches one page of the releases API, which Gitea sizes with limit
+func (api giteaAPI) ListReleases(ctx context.Context, repo GitHubRepo, page, perPage int) ([]GitHubRelease, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/releases?limit=%d&page=%d", api.baseURL, repo.Owner, repo.Name, perPage, page)
+	return api.fetchReleases(ctx, endpoint)
+}
+
+// DownloadArchive downloads the archive endpoint
+func (api giteaAPI) DownloadArchive(ctx context.Context, repo GitHubRepo, consume func(io.Reader) error) error {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/archive/%s.tar.gz", api.baseURL, repo.Owner, repo.Name, url.PathEscape(repo.Branch))
+	return api.client.FetchStream(ctx, endpoint, api.headers(), consume)
+}
+
+// gitLabAPI is the ForgeClient of GitLab. Projects are addressed by their URL-encoded
+// "owner/name" path, where owner may be a nested group.
+type gitLabAPI struct {
+	client  *HTTPClient
+	baseURL string
+	token   string
+}
+
+// gitLabTreeEntry represents an entry in the GitLab repository tree response
+type gitLabTreeEntry struct {
+	ID   string `json:"id"` // Git blob SHA
+	Type string `json:"type"`
+	Path string `json:"path"`
+}
+
+// gitLabRelease represents an entry in the GitLab releases response
+type gitLabRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ReleasedAt  time.Time `json:"released_at"`
+	Upcoming    bool      `json:"upcoming_release"`
+	Links       struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+// headers returns the headers sent with every request, including the token when one
+// is configured
+func (api gitLabAPI) headers() http.Header {
+	header := make(http.Header)
+	header.Set("Accept", "application/json")
+	if api.token != "" {
+		header.Set("Authorization", "Bearer "+api.token)
+	}
+	return header
+}
+
+// projectURL returns the API URL of the project
+func (api gitLabAPI) projectURL(repo GitHubRepo) string {
+	return fmt.Sprintf("%s/projects/%s", api.baseURL, url.PathEscape(repo.Owner+"/"+repo.Name))
+}
+
+// ListFiles lists the repository tree, 100 entries per page
+func (api gitLabAPI) ListFiles(ctx context.Context, repo GitHubRepo) ([]TreeEntry, error) {
+	var files []TreeEntry
+	for page := 1; page <= maxTreePages; page++ {
+		endpoint := fmt.Sprintf("%s/repository/tree?recursive=true&ref=%s&per_page=100&page=%d",
+			api.projectURL(repo), url.QueryEscape(repo.Branch), page)
+
+		content, err := api.client.FetchWithHeaders(ctx, endpoint, api.headers())
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tree: %w", err)
+		}
+
+		var entries []gitLabTreeEntry
+		if err := json.Unmarshal(content, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse tree response: %w", err)
+		}
+
+		for _, entry := range entries {
+			if entry.Type == "blob" {
+				files = append(files, TreeEntry{Path: entry.Path, SHA: entry.ID})
+			}
+		}
+		if len(entries) < 100 {
+			break
+		}
+	}
+	return files, nil
+}
+
+// FetchFile fetches the raw content of a file
+func (api gitLabAPI) FetchFile(ctx context.Context, repo GitHubRepo, path string) ([]byte, error) {
+	endpoint := fmt.Sprintf("%s/repository/files/%s/raw?ref=%s", api.projectURL(repo), url.PathEscape(path), url.QueryEscape(repo.Branch))
+
+	content, err := api.client.FetchWithHeaders(ctx, endpoint, api.headers())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch file: %w", err)
+	}
+	return content, nil
+}
+
+// ListReleases fetches one page of releases, converted to their GitHub form
+func (api gitLabAPI) ListReleases(ctx context.Context, repo GitHubRepo, page, perPage int) ([]GitHubRelease, error) {
+	endpoint := fmt.Sprintf("%s/releases?per_page=%d&page=%d", api.projectURL(repo), perPage, page)
+
+	content, err := api.client.FetchWithHeaders(ctx, endpoint, api.headers())
+	if err != nil {
+		return nil, err
+	}
+
+	var batch []gitLabRelease
+	if err := json.Unmarshal(content, &batch); err != nil {
+		return nil, fmt.Errorf("failed to parse releases response: %w", err)
+	}
+
+	releases := make([]GitHubRelease, 0, len(batch))
+	for _, release := range batch {
+		releases = append(releases, GitHubRelease{
+			TagName:     release.TagName,
+			Name:        release.Name,
+			Body:        release.Description,
+			HTMLURL:     release.Links.Self,
+			PublishedAt: release.ReleasedAt,
+			Upcoming:    release.Upcoming,
+		})
+	}
+	return releases, nil
+}
+
+// DownloadArchive downloads the repository archive endpoint
+func (api gitLabAPI) DownloadArchive(ctx context.Context, repo GitHubRepo, consume func(io.Reader) error) error {
+	endpoint := fmt.Sprintf("%s/repository/archive.tar.gz?sha=%s", api.projectURL(repo), url.QueryEscape(repo.Branch))
+	return api.client.FetchStream(ctx, endpoint, api.headers(), consume)
+}
+
+// FileURL returns the web URL of a file in the repository at repo.Branch, expanding
+// WebURL or the default template of the forge. An empty path yields the URL prefix
+// of every file in the repository.
+func (repo GitHubRepo) FileURL(path string) string {
+	template := repo.WebURL
+	if template == "" {
+		template = defaultWebURL(repo.Forge, repo.APIURL)
+	}
+	return strings.NewReplacer(
+		"{owner}", repo.Owner,
+		"{repo}", repo.Name,
+		"{ref}", repo.Branch,
+		"{path}", path,
+	).Replace(template)
+}
+
+// defaultWebURL returns the file URL template of a forge whose API is served at
+// apiURL. Self-hosted forges serve their web UI at the API URL without its "/api/..."
+// suffix (e.g., https://ghe.example.com/api/v3 and https://git.example.com/api/v1).
+func defaultWebURL(forge, apiURL string) string {
+	root := "https://github.com"
+	if apiURL = strings.TrimSuffix(apiURL, "/"); apiURL != "" && apiURL != DefaultGitHubAPIURL {
+		root = apiURL
+		if i := strings.Index(apiURL, "/api/"); i >= 0 {
+			root = apiURL[:i]
+		}
+		root = strings.TrimSuffix(root, "/api")
+	}
+
+	switch forge {
+	case ForgeGitea:
+		return root + "/{owner}/{repo}/src/branch/{ref}/{path}"
+	case ForgeGitLab:
+		return root + "/{owner}/{repo}/-/blob/{ref}/{path}"
+	default:
+		return root + "/{owner}/{repo}/blob/{ref}/{path}"
+	}
+}
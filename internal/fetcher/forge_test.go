@@ -0,0 +1,220 @@
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// newForgeTestFetcher returns a fetcher whose default API URL is unused by the tests
+func newForgeTestFetcher() *GitHubFetcher {
+	gf := NewGitHubFetcher(NewHTTPClient(5*time.Second, 0, 5), "github-token", nil, zerolog.Nop())
+	gf.apiURL = "http://127.0.0.1:0"
+	return gf
+}
+
+// TestGiteaClient verifies that Gitea trees are listed page by page and files are
+// fetched with the repository's own token
+func TestGiteaClient(t *testing.T) {
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/v1/repos/ext/jetstream-ext/git/trees/main":
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			entries := []gitHubTreeEntry{{Path: "README.md", Type: "blob", SHA: "a"}, {Path: "docs", Type: "tree", SHA: "b"}}
+			if page == 2 {
+				entries = []gitHubTreeEntry{{Path: "docs/usage.md", Type: "blob", SHA: "c"}}
+			}
+			_ = json.NewEncoder(w).Encode(gitHubTreeResponse{Tree: entries, Truncated: page == 1, TotalCount: 3})
+		case "/api/v1/repos/ext/jetstream-ext/contents/docs/usage.md":
+			_ = json.NewEncoder(w).Encode(gitHubFileResponse{Content: "IyBVc2FnZQo="})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	repo := GitHubRepo{Owner: "ext", Name: "jetstream-ext", Branch: "main", ShortName: "jetstream-ext",
+		Forge: ForgeGitea, APIURL: server.URL + "/api/v1/", Token: "gitea-token"}
+	gf := newForgeTestFetcher()
+
+	files, err := gf.discoverDocuments(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("discoverDocuments failed: %v", err)
+	}
+	if len(files) != 2 || files[0].Path != "README.md" || files[1].Path != "docs/usage.md" {
+		t.Errorf("Expected README.md and docs/usage.md from both pages, got %+v", files)
+	}
+
+	content, err := gf.fetchFileContent(context.Background(), repo, "docs/usage.md")
+	if err != nil {
+		t.Fatalf("fetchFileContent failed: %v", err)
+	}
+	if string(content) != "# Usage\n" {
+		t.Errorf("Expected decoded content, got %q", content)
+	}
+
+	for _, auth := range authorizations {
+		if auth != "token gitea-token" {
+			t.Errorf("Expected the Gitea token, got %q", auth)
+		}
+	}
+}
+
+// TestGitLabClient verifies that GitLab trees are listed page by page and raw files
+// are fetched from the URL-encoded project path
+func TestGitLabClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gitlab-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/nats%2Fextensions%2Fkv-ext/repository/tree":
+			var entries []gitLabTreeEntry
+			if r.URL.Query().Get("page") == "1" {
+				for i := 0; i < 100; i++ {
+					entries = append(entries, gitLabTreeEntry{ID: fmt.Sprint(i), Type: "blob", Path: fmt.Sprintf("src/file%d.go", i)})
+				}
+			} else {
+				entries = []gitLabTreeEntry{{ID: "d", Type: "tree", Path: "docs"}, {ID: "e", Type: "blob", Path: "docs/kv.md"}}
+			}
+			_ = json.NewEncoder(w).Encode(entries)
+		case "/api/v4/projects/nats%2Fextensions%2Fkv-ext/repository/files/docs%2Fkv.md/raw":
+			if r.URL.Query().Get("ref") != "v1.0" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte("# KV\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	repo := GitHubRepo{Owner: "nats/extensions", Name: "kv-ext", Branch: "v1.0", ShortName: "kv-ext",
+		Forge: ForgeGitLab, APIURL: server.URL + "/api/v4", Token: "gitlab-token"}
+	gf := newForgeTestFetcher()
+
+	files, err := gf.discoverDocuments(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("discoverDocuments failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "docs/kv.md" || files[0].SHA != "e" {
+		t.Fatalf("Expected docs/kv.md from the second page, got %+v", files)
+	}
+
+	content, err := gf.fetchFileContent(context.Background(), repo, "docs/kv.md")
+	if err != nil {
+		t.Fatalf("fetchFileContent failed: %v", err)
+	}
+	if string(content) != "# KV\n" {
+		t.Errorf("Expected raw content, got %q", content)
+	}
+}
+
+// TestGitLabReleases verifies that GitLab releases are converted to their GitHub form
+// and that upcoming releases are skipped rather than reported as pre-releases
+func TestGitLabReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/nats%2Fkv-ext/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+  {"tag_name": "v1.1.0", "description": "Scheduled", "released_at": "2099-01-01T00:00:00Z", "upcoming_release": true},
+  {"tag_name": "v1.0.0", "name": "First", "description": "- Buckets", "released_at": "2024-05-01T00:00:00Z",
+   "upcoming_release": false, "_links": {"self": "https://gitlab.example.com/nats/kv-ext/-/releases/v1.0.0"}}
+]`))
+	}))
+	defer server.Close()
+
+	repo := GitHubRepo{Owner: "nats", Name: "kv-ext", Branch: "main", ShortName: "kv-ext", Forge: ForgeGitLab, APIURL: server.URL + "/api/v4"}
+	releases, err := newForgeTestFetcher().FetchReleases(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("FetchReleases failed: %v", err)
+	}
+	if len(releases) != 1 {
+		t.Fatalf("Expected the upcoming release to be skipped, got %+v", releases)
+	}
+	release := releases[0]
+	if release.TagName != "v1.0.0" || release.Prerelease || release.Body != "- Buckets" ||
+		release.HTMLURL != "https://gitlab.example.com/nats/kv-ext/-/releases/v1.0.0" {
+		t.Errorf("Unexpected release: %+v", release)
+	}
+}
+
+// TestForgeTokenScope verifies that the fetcher token is not sent to other API hosts
+func TestForgeTokenScope(t *testing.T) {
+	gf := newForgeTestFetcher()
+
+	tests := []struct {
+		name string
+		repo GitHubRepo
+		want string
+	}{
+		{"default API", GitHubRepo{}, "github-token"},
+		{"enterprise without token", GitHubRepo{APIURL: "https://ghe.example.com/api/v3"}, ""},
+		{"enterprise with token", GitHubRepo{APIURL: "https://ghe.example.com/api/v3", Token: "ghe-token"}, "ghe-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gf.forge(tt.repo).(gitHubAPI).token; got != tt.want {
+				t.Errorf("Expected token %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestGitHubRepoFileURL verifies web URLs of files on each forge
+func TestGitHubRepoFileURL(t *testing.T) {
+	tests := []struct {
+		name string
+		repo GitHubRepo
+		want string
+	}{
+		{
+			name: "github.com",
+			repo: GitHubRepo{Owner: "nats-io", Name: "nats-server", Branch: "main"},
+			want: "https://github.com/nats-io/nats-server/blob/main/README.md",
+		},
+		{
+			name: "GitHub Enterprise",
+			repo: GitHubRepo{Owner: "ext", Name: "auth", Branch: "main", APIURL: "https://ghe.example.com/api/v3/"},
+			want: "https://ghe.example.com/ext/auth/blob/main/README.md",
+		},
+		{
+			name: "Gitea",
+			repo: GitHubRepo{Owner: "ext", Name: "auth", Branch: "main", Forge: ForgeGitea, APIURL: "https://git.example.com/api/v1"},
+			want: "https://git.example.com/ext/auth/src/branch/main/README.md",
+		},
+		{
+			name: "GitLab",
+			repo: GitHubRepo{Owner: "group/sub", Name: "auth", Branch: "v1", Forge: ForgeGitLab, APIURL: "https://gitlab.example.com/api/v4"},
+			want: "https://gitlab.example.com/group/sub/auth/-/blob/v1/README.md",
+		},
+		{
+			name: "template",
+			repo: GitHubRepo{Owner: "ext", Name: "auth", Branch: "v1", Forge: ForgeGitea, APIURL: "https://git.example.com/api/v1",
+				WebURL: "https://git.example.com/{owner}/{repo}/src/tag/{ref}/{path}"},
+			want: "https://git.example.com/ext/auth/src/tag/v1/README.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.repo.FileURL("README.md"); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
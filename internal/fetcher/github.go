@@ -5,21 +5,32 @@ package fetcher
 import (
 	"context"
 	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 )
 
-// GitHubRepo represents a GitHub repository configuration
+// GitHubRepo represents a repository configuration. Repositories are on github.com
+// unless Forge and APIURL point at GitHub Enterprise or another Git forge.
 type GitHubRepo struct {
-	Owner  string // Repository owner (e.g., "nats-io")
-	Name   string // Repository name (e.g., "nats-server")
-	Branch string // Branch to fetch from (e.g., "main")
+	Owner     string // Repository owner (e.g., "nats-io")
+	Name      string // Repository name (e.g., "nats-server")
+	Branch    string // Branch to fetch from (e.g., "main")
 	ShortName string // Short name for document IDs (e.g., "nats-server")
+	Forge     string // ForgeGitHub (default), ForgeGitea or ForgeGitLab
+	APIURL    string // Base URL of the forge REST API (default: the fetcher's, https://api.github.com)
+	WebURL    string // File URL template with {owner}, {repo}, {ref} and {path} (default: the forge's)
+	Token     string // Token for APIURL; the fetcher's token is only sent to the default API URL
+
+	// Documentation files are filtered by IsDocument
+	DocRoot string   // Directory holding the documentation; the whole repository when empty
+	Include []string // Globs of files to index, relative to DocRoot; every documentation file when empty
+	Exclude []string // Globs of files to skip, relative to DocRoot
 }
 
 // GitHubFile represents a file fetched from GitHub
@@ -27,10 +38,46 @@ type GitHubFile struct {
 	Path    string // File path in repository (e.g., "docs/README.md")
 	Content []byte // File content
 	Repo    string // Short repository name
+	Ref     string // Branch or tag the file was fetched from; empty for local files
 	SHA     string // Git SHA of the file
+
+	// NotModified is set when the blob SHA equals the one passed to FetchChangedFiles;
+	// Content is nil and the previous parse should be reused
+	NotModified bool
+}
+
+// gitHubFileJob is a changed file waiting to be fetched from its repository
+type gitHubFileJob struct {
+	repo GitHubRepo
+	file GitHubFile
+}
+
+// Key identifies the file across fetches as "repo@ref/path"
+func (f GitHubFile) Key() string {
+	return f.Repo + "@" + f.Ref + "/" + f.Path
+}
+
+// Key identifies the repository at its ref as "owner/name@ref"
+func (repo GitHubRepo) Key() string {
+	return repo.Owner + "/" + repo.Name + "@" + repo.Branch
+}
+
+// GitHubRelease represents a release returned by the GitHub releases API
+type GitHubRelease struct {
+	TagName     string    `json:"tag_name"`     // Release tag (e.g., "v2.11.0")
+	Name        string    `json:"name"`         // Release title
+	Body        string    `json:"body"`         // Release notes (markdown)
+	HTMLURL     string    `json:"html_url"`     // Release page URL
+	PublishedAt time.Time `json:"published_at"` // When the release was published
+	Prerelease  bool      `json:"prerelease"`   // Whether the release is a pre-release
+	Draft       bool      `json:"draft"`        // Whether the release is an unpublished draft
+	Upcoming    bool      `json:"upcoming"`     // Whether the release date is still in the future (GitLab)
 }
 
-// GitHubTreeEntry represents an entry in the GitHub tree API response
+// maxReleasePages limits how many pages of 100 releases are requested per repository
+const maxReleasePages = 10
+
+// gitHubTreeEntry represents an entry in the GitHub tree API response
 type gitHubTreeEntry struct {
 	Path string `json:"path"`
 	Type string `json:"type"`
@@ -39,12 +86,26 @@ type gitHubTreeEntry struct {
 	Size int    `json:"size,omitempty"`
 }
 
-// GitHubTreeResponse represents the GitHub tree API response
+// gitHubTreeResponse represents the GitHub tree API response. Gitea pages the tree
+// and reports the number of entries in TotalCount.
 type gitHubTreeResponse struct {
-	SHA      string             `json:"sha"`
-	URL      string             `json:"url"`
-	Tree     []gitHubTreeEntry  `json:"tree"`
-	Truncated bool              `json:"truncated"`
+	SHA        string            `json:"sha"`
+	URL        string            `json:"url"`
+	Tree       []gitHubTreeEntry `json:"tree"`
+	Truncated  bool              `json:"truncated"`
+	TotalCount int               `json:"total_count,omitempty"`
+}
+
+// files returns the blobs of the tree
+func (tree *gitHubTreeResponse) files() []TreeEntry {
+	var files []TreeEntry
+	for _, entry := range tree.Tree {
+		// Only include files (not directories)
+		if entry.Type == "blob" {
+			files = append(files, TreeEntry{Path: entry.Path, SHA: entry.SHA, Size: entry.Size})
+		}
+	}
+	return files
 }
 
 // GitHubFileResponse represents the GitHub contents API response
@@ -58,12 +119,29 @@ type gitHubFileResponse struct {
 	URL     string `json:"url"`
 }
 
+// DefaultGitHubAPIURL is the base URL of the public GitHub REST API
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// GitHub fetch strategies
+const (
+	// GitHubStrategyAPI lists files with the tree API and fetches each one with the
+	// contents API
+	GitHubStrategyAPI = "api"
+	// GitHubStrategyArchive downloads one tarball per repository and ref and
+	// extracts the matching files while it streams
+	GitHubStrategyArchive = "archive"
+)
+
 // GitHubFetcher provides functionality for fetching documentation from GitHub repositories
 type GitHubFetcher struct {
 	client       *HTTPClient
 	token        string
 	repositories []GitHubRepo
 	logger       zerolog.Logger
+	apiURL       string // Base URL of the REST API for repositories without an APIURL
+	strategy     string // GitHubStrategyAPI or GitHubStrategyArchive
+
+	minSuccessRatio float64 // Share of changed files that must be fetched for partial results to be used
 }
 
 // NewGitHubFetcher creates a new GitHub fetcher with the specified configuration
@@ -73,227 +151,342 @@ func NewGitHubFetcher(client *HTTPClient, token string, repos []GitHubRepo, logg
 		token:        token,
 		repositories: repos,
 		logger:       logger,
+		apiURL:       DefaultGitHubAPIURL,
+		strategy:     GitHubStrategyAPI,
 	}
 }
 
-// FetchAllFiles discovers and fetches all markdown files from configured repositories
+// FetchAllFiles discovers and fetches all documentation files from configured repositories
 func (gf *GitHubFetcher) FetchAllFiles(ctx context.Context) ([]GitHubFile, error) {
-	gf.logger.Info().Msg("Starting GitHub documentation fetch")
-
-	var allFiles []GitHubFile
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	var fetchErrors []error
-	var rateLimited bool
+	return gf.FetchChangedFiles(ctx, nil)
+}
 
-	for _, repo := range gf.repositories {
-		wg.Add(1)
+// FetchChangedFiles discovers all documentation files from configured repositories and
+// fetches those whose blob SHA differs from the one recorded in previous, keyed by
+// GitHubFile.Key. Unchanged files are returned with NotModified set and no content.
+func (gf *GitHubFetcher) FetchChangedFiles(ctx context.Context, previous map[string]Validator) ([]GitHubFile, error) {
+	return collect(func(out chan<- GitHubFile) error {
+		return gf.StreamChangedFiles(ctx, previous, out)
+	})
+}
 
-		// Capture repo for goroutine
-		currentRepo := repo
+// StreamChangedFiles works like FetchChangedFiles but sends each file to out as soon as
+// it is fetched instead of collecting them. Sends block while out is full, which bounds
+// the files held in memory. It returns once every file was sent or ctx is cancelled,
+// and never closes out.
+//
+// Files whose content failed to fetch are reported with a *PartialFetchError, with
+// their GitHubFile.Key as the failure path. Repositories whose archive or file list
+// failed to fetch are reported as a whole, with their GitHubRepo.Key as the failure
+// path.
+func (gf *GitHubFetcher) StreamChangedFiles(ctx context.Context, previous map[string]Validator, out chan<- GitHubFile) error {
+	gf.logger.Info().Msg("Starting GitHub documentation fetch")
 
-		go func() {
-			defer wg.Done()
+	sent, discovered, failedRepos := 0, 0, 0
+	var mu sync.Mutex
+	var failures []PageFailure
+	var rateLimit *RateLimitError // Latest-resetting rate limit hit, if any
+
+	// failRepo reports a repository that failed as a whole; it counts as one page
+	failRepo := func(repo GitHubRepo, err error) {
+		mu.Lock()
+		rateLimit = laterRateLimit(rateLimit, err)
+		failures = append(failures, PageFailure{Path: repo.Key(), URL: repo.FileURL(""), Error: err.Error()})
+		discovered++
+		failedRepos++
+		mu.Unlock()
+		gf.logger.Warn().
+			Str("repo", repo.Key()).
+			Err(err).
+			Msg("Failed to fetch repository")
+	}
 
-			gf.logger.Info().
-				Str("owner", currentRepo.Owner).
-				Str("name", currentRepo.Name).
-				Str("branch", currentRepo.Branch).
-				Msg("Fetching repository")
+	// emit hands a file on, counting it unless ctx was cancelled first
+	emit := func(file GitHubFile) {
+		if send(ctx, out, file) {
+			mu.Lock()
+			sent++
+			mu.Unlock()
+		}
+	}
 
-			// Discover markdown files
-			files, err := gf.discoverMarkdownFiles(ctx, currentRepo)
-			if err != nil {
-				if isRateLimitError(err) {
-					mu.Lock()
-					rateLimited = true
-					mu.Unlock()
-				}
+	// Discover the documentation files of every repository, then fetch the changed ones on a
+	// single worker pool so the client limits apply across repositories
+	var pending []gitHubFileJob
+	runPool(ctx, gf.client.MaxInFlight(), gf.repositories, func(repo GitHubRepo) {
+		gf.logger.Info().
+			Str("owner", repo.Owner).
+			Str("name", repo.Name).
+			Str("branch", repo.Branch).
+			Str("strategy", gf.strategy).
+			Msg("Fetching repository")
+
+		// Archives carry every file's content, so only parsing can be skipped. Files
+		// are handed on while the archive streams.
+		if gf.strategy == GitHubStrategyArchive {
+			err := gf.streamArchive(ctx, repo, repo.IsDocument, func(file GitHubFile) {
 				mu.Lock()
-				fetchErrors = append(fetchErrors, fmt.Errorf("failed to discover files in %s/%s: %w", currentRepo.Owner, currentRepo.Name, err))
+				discovered++
 				mu.Unlock()
-				return
+				if prev, ok := previous[file.Key()]; ok && prev.SHA == file.SHA {
+					file.Content = nil
+					file.NotModified = true
+				}
+				emit(file)
+			})
+			if err != nil {
+				failRepo(repo, fmt.Errorf("failed to fetch archive: %w", err))
 			}
+			return
+		}
 
-			gf.logger.Info().
-				Str("repo", currentRepo.ShortName).
-				Int("files", len(files)).
-				Msg("Discovered markdown files")
-
-			// Fetch content for each file
-			for _, file := range files {
-				content, err := gf.fetchFileContent(ctx, currentRepo, file.Path)
-				if err != nil {
-					if isRateLimitError(err) {
-						mu.Lock()
-						rateLimited = true
-						mu.Unlock()
-					}
-					gf.logger.Warn().
-						Str("repo", currentRepo.ShortName).
-						Str("path", file.Path).
-						Err(err).
-						Msg("Failed to fetch file content")
-					continue
-				}
+		// Discover documentation files
+		files, err := gf.discoverDocuments(ctx, repo)
+		if err != nil {
+			failRepo(repo, fmt.Errorf("failed to discover files: %w", err))
+			return
+		}
 
-				mu.Lock()
-				allFiles = append(allFiles, GitHubFile{
-					Path:    file.Path,
-					Content: content,
-					Repo:    currentRepo.ShortName,
-					SHA:     file.SHA,
-				})
-				mu.Unlock()
+		gf.logger.Info().
+			Str("repo", repo.ShortName).
+			Int("files", len(files)).
+			Msg("Discovered documentation files")
+		mu.Lock()
+		discovered += len(files)
+		mu.Unlock()
+
+		for _, file := range files {
+			discovered := GitHubFile{
+				Path: file.Path,
+				Repo: repo.ShortName,
+				Ref:  repo.Branch,
+				SHA:  file.SHA,
 			}
-		}()
-	}
+			if prev, ok := previous[discovered.Key()]; ok && prev.SHA != "" && prev.SHA == file.SHA {
+				discovered.NotModified = true
+				emit(discovered)
+				continue
+			}
+			mu.Lock()
+			pending = append(pending, gitHubFileJob{repo: repo, file: discovered})
+			mu.Unlock()
+		}
+	})
+
+	// Fetch content for each changed file
+	runPool(ctx, gf.client.MaxInFlight(), pending, func(job gitHubFileJob) {
+		file := job.file
+		content, err := gf.fetchFileContent(ctx, job.repo, file.Path)
+		if err != nil {
+			mu.Lock()
+			rateLimit = laterRateLimit(rateLimit, err)
+			failures = append(failures, PageFailure{Path: file.Key(), URL: job.repo.FileURL(file.Path), Error: err.Error()})
+			mu.Unlock()
+			gf.logger.Warn().
+				Str("repo", file.Repo).
+				Str("path", file.Path).
+				Err(err).
+				Msg("Failed to fetch file content")
+			return
+		}
 
-	wg.Wait()
+		file.Content = content
+		emit(file)
+	})
 
 	gf.logger.Info().
-		Int("total_files", len(allFiles)).
-		Int("errors", len(fetchErrors)).
-		Bool("rate_limited", rateLimited).
+		Int("total_files", sent).
+		Int("failed_files", len(failures)-failedRepos).
+		Int("failed_repositories", failedRepos).
+		Bool("rate_limited", rateLimit != nil).
 		Msg("Completed GitHub documentation fetch")
 
-	// If there were errors, return them along with successfully fetched files
-	if len(fetchErrors) > 0 && len(allFiles) == 0 {
-		// All repos failed
-		var errMsg strings.Builder
-		errMsg.WriteString(fmt.Sprintf("failed to fetch from %d repositories: ", len(fetchErrors)))
-		for i, err := range fetchErrors {
-			if i > 0 {
-				errMsg.WriteString("; ")
-			}
-			errMsg.WriteString(err.Error())
+	// Report the files and repositories that failed along with the fetched ones
+	var partial *PartialFetchError
+	if len(failures) > 0 {
+		sort.Slice(failures, func(i, j int) bool { return failures[i].Path < failures[j].Path })
+		partial = &PartialFetchError{
+			Total:           discovered,
+			Failures:        failures,
+			MinSuccessRatio: gf.minSuccessRatio,
 		}
+	}
+
+	if failedRepos > 0 && sent == 0 {
+		// All repos failed
+		err := fmt.Errorf("failed to fetch from %d repositories: %w", failedRepos, partial)
 		// Add rate limit hint if we detected rate limiting
-		if rateLimited {
-			errMsg.WriteString(". Rate limited by GitHub API. To increase limits, provide a GitHub Personal Access Token via NATS_DOCS_GITHUB_TOKEN")
+		if rateLimit != nil {
+			return fmt.Errorf("%w. To increase limits, provide a GitHub Personal Access Token via NATS_DOCS_GITHUB_TOKEN: %w", err, rateLimit)
 		}
-		return nil, fmt.Errorf("%s", errMsg.String())
+		return err
 	}
 
 	// Return partial results if some repos failed
-	if len(fetchErrors) > 0 {
-		if rateLimited {
+	if failedRepos > 0 {
+		if rateLimit != nil {
 			gf.logger.Warn().
-				Int("error_count", len(fetchErrors)).
+				Int("error_count", failedRepos).
+				Time("reset", rateLimit.Reset).
 				Msg("GitHub API rate limit reached. Provide a Personal Access Token via NATS_DOCS_GITHUB_TOKEN to increase limits")
 		} else {
 			gf.logger.Warn().
-				Int("error_count", len(fetchErrors)).
+				Int("error_count", failedRepos).
 				Msg("Some repositories failed to fetch, continuing with partial results")
 		}
 	}
 
-	return allFiles, nil
-}
+	// Files not yet fetched when ctx was cancelled are missing
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("GitHub documentation fetch stopped: %w", err)
+	}
 
-// isRateLimitError checks if an error indicates GitHub API rate limiting
-func isRateLimitError(err error) bool {
-	if err == nil {
-		return false
+	if partial != nil {
+		return partial
 	}
-	errStr := err.Error()
-	// Check for rate limit indicators
-	return strings.Contains(errStr, "403") ||
-		strings.Contains(errStr, "rate limit") ||
-		strings.Contains(errStr, "API rate limit exceeded")
+	return nil
 }
 
-// discoverMarkdownFiles discovers all markdown files in a repository
-func (gf *GitHubFetcher) discoverMarkdownFiles(ctx context.Context, repo GitHubRepo) ([]gitHubTreeEntry, error) {
-	// Use GitHub Tree API to get all files recursively
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/trees/%s?recursive=1", repo.Owner, repo.Name, repo.Branch)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// laterRateLimit returns whichever of current and the rate limit wrapped by err
+// resets later; current is returned unchanged when err is not a rate limit
+func laterRateLimit(current *RateLimitError, err error) *RateLimitError {
+	var limit *RateLimitError
+	if !errors.As(err, &limit) {
+		return current
+	}
+	if current == nil || limit.Reset.After(current.Reset) {
+		return limit
 	}
+	return current
+}
 
-	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", gf.token))
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "nats-docs-mcp-server/1.0")
+// FetchRepositoryFiles fetches every file in a single repository whose path is
+// accepted by match. It is used by structured sources (e.g. JSON Schemas) that
+// need files other than the documentation fetched by FetchAllFiles.
+// Files that fail to fetch are logged and skipped.
+func (gf *GitHubFetcher) FetchRepositoryFiles(ctx context.Context, repo GitHubRepo, match func(path string) bool) ([]GitHubFile, error) {
+	if gf.strategy == GitHubStrategyArchive {
+		var files []GitHubFile
+		err := gf.streamArchive(ctx, repo, match, func(file GitHubFile) {
+			files = append(files, file)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s/%s: %w", repo.Owner, repo.Name, err)
+		}
+		return files, nil
+	}
 
-	// Use HTTP client with rate limiting
-	content, err := gf.client.Fetch(ctx, url)
+	entries, err := gf.discoverFiles(ctx, repo, match)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch tree: %w", err)
+		return nil, fmt.Errorf("failed to discover files in %s/%s: %w", repo.Owner, repo.Name, err)
 	}
 
-	// Parse response
-	var tree gitHubTreeResponse
-	if err := json.Unmarshal(content, &tree); err != nil {
-		return nil, fmt.Errorf("failed to parse tree response: %w", err)
-	}
+	var mu sync.Mutex
+	files := make([]GitHubFile, 0, len(entries))
+	runPool(ctx, gf.client.MaxInFlight(), entries, func(entry TreeEntry) {
+		content, err := gf.fetchFileContent(ctx, repo, entry.Path)
+		if err != nil {
+			gf.logger.Warn().
+				Str("repo", repo.ShortName).
+				Str("path", entry.Path).
+				Err(err).
+				Msg("Failed to fetch file content")
+			return
+		}
 
-	if tree.Truncated {
-		gf.logger.Warn().
-			Str("repo", repo.ShortName).
-			Msg("GitHub tree response was truncated, some files may be missing")
+		mu.Lock()
+		files = append(files, GitHubFile{
+			Path:    entry.Path,
+			Content: content,
+			Repo:    repo.ShortName,
+			Ref:     repo.Branch,
+			SHA:     entry.SHA,
+		})
+		mu.Unlock()
+	})
+
+	// Keep the tree order regardless of which worker finished first
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	if len(files) == 0 && len(entries) > 0 {
+		return nil, fmt.Errorf("failed to fetch any of %d files from %s/%s", len(entries), repo.Owner, repo.Name)
 	}
 
-	// Filter for markdown files
-	var markdownFiles []gitHubTreeEntry
-	for _, entry := range tree.Tree {
-		// Only include files (not directories)
-		if entry.Type != "blob" {
-			continue
-		}
-		// Only include markdown files
-		if !strings.HasSuffix(strings.ToLower(entry.Path), ".md") {
-			continue
+	return files, nil
+}
+
+// FetchReleases fetches the published releases of a repository, newest first.
+// Drafts and upcoming releases are skipped. At most maxReleasePages pages of releases
+// are requested.
+func (gf *GitHubFetcher) FetchReleases(ctx context.Context, repo GitHubRepo) ([]GitHubRelease, error) {
+	forge := gf.forge(repo)
+
+	var releases []GitHubRelease
+	for page := 1; page <= maxReleasePages; page++ {
+		batch, err := forge.ListReleases(ctx, repo, page, 100)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch releases of %s/%s: %w", repo.Owner, repo.Name, err)
 		}
-		// Skip vendor and node_modules directories
-		if strings.Contains(entry.Path, "/vendor/") || strings.Contains(entry.Path, "/node_modules/") {
-			continue
+
+		for _, release := range batch {
+			if !release.Draft && !release.Upcoming {
+				releases = append(releases, release)
+			}
 		}
 
-		markdownFiles = append(markdownFiles, entry)
+		if len(batch) < 100 {
+			break
+		}
 	}
 
-	return markdownFiles, nil
+	gf.logger.Info().
+		Str("repo", repo.ShortName).
+		Int("releases", len(releases)).
+		Msg("Fetched repository releases")
+
+	return releases, nil
 }
 
-// fetchFileContent fetches the content of a file from GitHub
-func (gf *GitHubFetcher) fetchFileContent(ctx context.Context, repo GitHubRepo, path string) ([]byte, error) {
-	// Use GitHub Contents API to fetch file
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s?ref=%s", repo.Owner, repo.Name, path, repo.Branch)
+// discoverDocuments discovers the documentation files of a repository accepted by
+// GitHubRepo.IsDocument
+func (gf *GitHubFetcher) discoverDocuments(ctx context.Context, repo GitHubRepo) ([]TreeEntry, error) {
+	return gf.discoverFiles(ctx, repo, repo.IsDocument)
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// discoverFiles lists all files in a repository whose path is accepted by match
+func (gf *GitHubFetcher) discoverFiles(ctx context.Context, repo GitHubRepo, match func(path string) bool) ([]TreeEntry, error) {
+	files, err := gf.forge(repo).ListFiles(ctx, repo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", gf.token))
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "nats-docs-mcp-server/1.0")
+	// Filter for matching files
+	var matchedFiles []TreeEntry
+	for _, file := range files {
+		// Only include files accepted by the caller
+		if match != nil && !match(file.Path) {
+			continue
+		}
+		// Skip vendor and node_modules directories
+		if isVendoredPath(file.Path) {
+			continue
+		}
 
-	// Use HTTP client with rate limiting
-	content, err := gf.client.Fetch(ctx, url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch file: %w", err)
+		matchedFiles = append(matchedFiles, file)
 	}
 
-	// Parse response
-	var fileResp gitHubFileResponse
-	if err := json.Unmarshal(content, &fileResp); err != nil {
-		return nil, fmt.Errorf("failed to parse file response: %w", err)
-	}
+	return matchedFiles, nil
+}
 
-	// For markdown files, the API returns base64-encoded content
-	// However, we need to check if it's actually base64 or raw text
-	decodedContent, err := decodeBase64IfNeeded(fileResp.Content)
-	if err != nil {
-		// If decoding fails, try to use as-is
-		decodedContent = []byte(fileResp.Content)
-	}
+// fetchFileContent fetches the content of a file from the repository's forge
+func (gf *GitHubFetcher) fetchFileContent(ctx context.Context, repo GitHubRepo, path string) ([]byte, error) {
+	return gf.forge(repo).FetchFile(ctx, repo, path)
+}
 
-	return decodedContent, nil
+// isVendoredPath reports whether a repository path is inside a vendor or node_modules directory
+func isVendoredPath(path string) bool {
+	path = "/" + path
+	return strings.Contains(path, "/vendor/") || strings.Contains(path, "/node_modules/")
 }
 
 // decodeBase64IfNeeded attempts to decode base64 content, falls back to raw content
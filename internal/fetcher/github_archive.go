@@ -0,0 +1,104 @@
+package fetcher
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxArchiveFileSize is the largest archive entry extracted; bigger files (binary
+// assets, generated data) are skipped without being buffered
+const maxArchiveFileSize = 8 << 20
+
+// streamArchive downloads the tarball of a repository at its ref and calls emit with
+// each file accepted by match as soon as it is extracted, so only the file being
+// extracted is held in memory and emit can block until the file is consumed. Each
+// file carries its git blob SHA, computed from the content, so it compares equal to
+// the SHA reported by the tree API. A retried download skips the files emitted before
+// the failed attempt broke off.
+func (gf *GitHubFetcher) streamArchive(ctx context.Context, repo GitHubRepo, match func(path string) bool, emit func(file GitHubFile)) error {
+	emitted := make(map[string]bool)
+	err := gf.forge(repo).DownloadArchive(ctx, repo, func(body io.Reader) error {
+		return extractArchive(body, match, func(path string, content []byte) {
+			if emitted[path] {
+				return
+			}
+			emitted[path] = true
+			emit(GitHubFile{
+				Path:    path,
+				Content: content,
+				Repo:    repo.ShortName,
+				Ref:     repo.Branch,
+				SHA:     gitBlobSHA(content),
+			})
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to download archive: %w", err)
+	}
+
+	gf.logger.Info().
+		Str("repo", repo.ShortName).
+		Str("ref", repo.Branch).
+		Int("files", len(emitted)).
+		Msg("Extracted repository archive")
+
+	return nil
+}
+
+// extractArchive reads a gzipped tarball as produced by the forge archive endpoints and
+// calls emit for every regular file accepted by match. The top-level directory the
+// archive wraps the repository in is stripped from paths, and vendored paths are skipped.
+func extractArchive(r io.Reader, match func(path string) bool, emit func(path string, content []byte)) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// Strip the "<owner>-<repo>-<sha>/" root directory
+		_, path, found := strings.Cut(header.Name, "/")
+		if !found || path == "" {
+			continue
+		}
+		if isVendoredPath(path) || (match != nil && !match(path)) {
+			continue
+		}
+		if header.Size > maxArchiveFileSize {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("failed to read %s from archive: %w", path, err)
+		}
+		emit(path, content)
+	}
+}
+
+// gitBlobSHA returns the SHA-1 git assigns to a blob with the given content
+func gitBlobSHA(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
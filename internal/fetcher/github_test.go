@@ -0,0 +1,284 @@
+package fetcher
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher/githubtest"
+	"github.com/rs/zerolog"
+)
+
+var stubFiles = map[string]string{
+	"README.md":                     "# NATS Server\n",
+	"doc/jetstream.md":              "# JetStream\n",
+	"server/server.go":              "package server\n",
+	"vendor/example.com/lib/doc.md": "# Vendored\n",
+	"node_modules/pkg/README.md":    "# Module\n",
+}
+
+// newStubGitHubFetcher returns a fetcher for nats-io/nats-server@main served by a
+// stand-in GitHub server
+func newStubGitHubFetcher(t *testing.T, token, strategy string) (*GitHubFetcher, *githubtest.Server) {
+	t.Helper()
+
+	stub := githubtest.NewServer()
+	t.Cleanup(stub.Close)
+	stub.Token = token
+	stub.AddRepo("nats-io", "nats-server", "main", stubFiles)
+
+	repos := []GitHubRepo{{Owner: "nats-io", Name: "nats-server", Branch: "main", ShortName: "nats-server"}}
+	gf := NewGitHubFetcher(NewHTTPClient(5*time.Second, 0, 5), token, repos, zerolog.Nop())
+	gf.apiURL = stub.URL
+	gf.strategy = strategy
+	return gf, stub
+}
+
+// TestGitHubFetcherSendsToken verifies that the token is sent with tree, contents and
+// releases requests, and that no Authorization header is sent without one
+func TestGitHubFetcherSendsToken(t *testing.T) {
+	for _, token := range []string{"secret", ""} {
+		gf, stub := newStubGitHubFetcher(t, token, GitHubStrategyAPI)
+
+		files, err := gf.FetchAllFiles(context.Background())
+		if err != nil {
+			t.Fatalf("FetchAllFiles with token %q failed: %v", token, err)
+		}
+		if len(files) != 2 {
+			t.Errorf("Expected 2 markdown files, got %d", len(files))
+		}
+		if _, err := gf.FetchReleases(context.Background(), gf.repositories[0]); err != nil {
+			t.Fatalf("FetchReleases with token %q failed: %v", token, err)
+		}
+
+		want := ""
+		if token != "" {
+			want = "token " + token
+		}
+		requests := stub.Requests()
+		if len(requests) < 4 {
+			t.Fatalf("Expected tree, contents and releases requests, got %d requests", len(requests))
+		}
+		for _, req := range requests {
+			if req.Authorization != want {
+				t.Errorf("Expected Authorization %q on %s, got %q", want, req.Path, req.Authorization)
+			}
+		}
+	}
+}
+
+// TestGitHubFetcherArchiveStrategy verifies that the archive strategy downloads one
+// tarball and returns the matching files with the SHAs the tree API reports
+func TestGitHubFetcherArchiveStrategy(t *testing.T) {
+	gf, stub := newStubGitHubFetcher(t, "secret", GitHubStrategyArchive)
+
+	files, err := gf.FetchAllFiles(context.Background())
+	if err != nil {
+		t.Fatalf("FetchAllFiles failed: %v", err)
+	}
+
+	got := make(map[string]GitHubFile)
+	for _, file := range files {
+		got[file.Path] = file
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected README.md and doc/jetstream.md, got %v", files)
+	}
+	for _, path := range []string{"README.md", "doc/jetstream.md"} {
+		file, ok := got[path]
+		if !ok {
+			t.Errorf("Expected %s to be extracted", path)
+			continue
+		}
+		if string(file.Content) != stubFiles[path] {
+			t.Errorf("Expected content %q for %s, got %q", stubFiles[path], path, file.Content)
+		}
+		if file.SHA != githubtest.BlobSHA(stubFiles[path]) {
+			t.Errorf("Expected blob SHA %s for %s, got %s", githubtest.BlobSHA(stubFiles[path]), path, file.SHA)
+		}
+		if file.Repo != "nats-server" || file.Ref != "main" {
+			t.Errorf("Expected nats-server@main for %s, got %s@%s", path, file.Repo, file.Ref)
+		}
+	}
+
+	// One tarball request followed by its redirect
+	for _, req := range stub.Requests() {
+		if strings.Contains(req.Path, "/git/trees/") || strings.Contains(req.Path, "/contents/") {
+			t.Errorf("Expected no tree or contents requests, got %s", req.Path)
+		}
+	}
+	if requests := stub.Requests(); len(requests) != 2 {
+		t.Errorf("Expected the tarball request and its redirect, got %v", requests)
+	}
+}
+
+// TestGitHubFetcherArchiveUnchangedFiles verifies that archive files whose SHA matches
+// the previous validator are reported as not modified
+func TestGitHubFetcherArchiveUnchangedFiles(t *testing.T) {
+	gf, _ := newStubGitHubFetcher(t, "", GitHubStrategyArchive)
+
+	previous := map[string]Validator{
+		"nats-server@main/README.md":        {SHA: githubtest.BlobSHA(stubFiles["README.md"])},
+		"nats-server@main/doc/jetstream.md": {SHA: "stale"},
+	}
+	files, err := gf.FetchChangedFiles(context.Background(), previous)
+	if err != nil {
+		t.Fatalf("FetchChangedFiles failed: %v", err)
+	}
+
+	for _, file := range files {
+		switch file.Path {
+		case "README.md":
+			if !file.NotModified || file.Content != nil {
+				t.Errorf("Expected README.md to be not modified without content, got %+v", file)
+			}
+		case "doc/jetstream.md":
+			if file.NotModified || string(file.Content) != stubFiles[file.Path] {
+				t.Errorf("Expected doc/jetstream.md to be fetched, got %+v", file)
+			}
+		}
+	}
+}
+
+// TestGitHubFetcherReportsFailedFiles verifies that files whose content fails to fetch
+// are reported as failures along with the fetched files
+func TestGitHubFetcherReportsFailedFiles(t *testing.T) {
+	gf, stub := newStubGitHubFetcher(t, "", GitHubStrategyAPI)
+	stub.FailContents("nats-io", "nats-server", "doc/jetstream.md", http.StatusServiceUnavailable)
+
+	gf.minSuccessRatio = 0.5
+	files, err := gf.FetchAllFiles(context.Background())
+	var partial *PartialFetchError
+	if !errors.As(err, &partial) || !partial.Tolerable() {
+		t.Fatalf("Expected a tolerable partial fetch error, got %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "README.md" {
+		t.Errorf("Expected README.md to be fetched, got %+v", files)
+	}
+	want := []PageFailure{{Path: "nats-server@main/doc/jetstream.md", URL: "https://github.com/nats-io/nats-server/blob/main/doc/jetstream.md"}}
+	if partial.Total != 2 || len(partial.Failures) != 1 || partial.Failures[0].Path != want[0].Path || partial.Failures[0].URL != want[0].URL {
+		t.Errorf("Expected %+v of 2 files to fail, got %+v of %d", want, partial.Failures, partial.Total)
+	}
+
+	// Below the minimum success ratio the partial results are not usable
+	gf.minSuccessRatio = 0.9
+	if _, err := gf.FetchAllFiles(context.Background()); !errors.Is(err, ErrTooManyFailures) {
+		t.Errorf("Expected ErrTooManyFailures, got %v", err)
+	}
+}
+
+// TestGitHubFetcherReportsFailedRepositories verifies that a repository whose tree or
+// archive fails to fetch is reported as a failure keyed by the repository and ref
+func TestGitHubFetcherReportsFailedRepositories(t *testing.T) {
+	for _, strategy := range []string{GitHubStrategyAPI, GitHubStrategyArchive} {
+		gf, stub := newStubGitHubFetcher(t, "", strategy)
+		stub.AddRepo("nats-io", "nats.go", "main", stubFiles)
+		stub.FailRepo("nats-io", "nats.go", "main", http.StatusInternalServerError)
+		gf.repositories = append(gf.repositories, GitHubRepo{Owner: "nats-io", Name: "nats.go", Branch: "main", ShortName: "nats.go"})
+
+		gf.minSuccessRatio = 0.5
+		files, err := gf.FetchAllFiles(context.Background())
+		var partial *PartialFetchError
+		if !errors.As(err, &partial) || !partial.Tolerable() {
+			t.Fatalf("%s: expected a tolerable partial fetch error, got %v", strategy, err)
+		}
+		if len(files) != 2 {
+			t.Errorf("%s: expected the files of the other repository, got %+v", strategy, files)
+		}
+		want := PageFailure{Path: "nats-io/nats.go@main", URL: "https://github.com/nats-io/nats.go/blob/main/"}
+		if partial.Total != 3 || len(partial.Failures) != 1 || partial.Failures[0].Path != want.Path || partial.Failures[0].URL != want.URL {
+			t.Errorf("%s: expected %+v to fail, got %+v of %d", strategy, want, partial.Failures, partial.Total)
+		}
+
+		// A fetch where every repository failed still reports them
+		gf.repositories = gf.repositories[1:]
+		_, err = gf.FetchAllFiles(context.Background())
+		if !errors.As(err, &partial) || partial.Tolerable() || len(partial.Failures) != 1 || partial.Failures[0].Path != want.Path {
+			t.Errorf("%s: expected the failed repository to be reported, got %v", strategy, err)
+		}
+	}
+}
+
+// TestGitHubFetcherStreamsArchiveFiles verifies that archive files are handed on while
+// the archive is still downloading rather than once it is complete
+func TestGitHubFetcherStreamsArchiveFiles(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gz := gzip.NewWriter(w)
+		tw := tar.NewWriter(gz)
+		for i, path := range []string{"docs/first.md", "docs/second.md"} {
+			if i > 0 {
+				// The rest of the archive waits until the first file was received
+				<-release
+			}
+			_ = tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "repo-sha/" + path, Mode: 0644, Size: 7})
+			_, _ = tw.Write([]byte("# Title"))
+			_ = tw.Flush()
+			_ = gz.Flush()
+			w.(http.Flusher).Flush()
+		}
+		_ = tw.Close()
+		_ = gz.Close()
+	}))
+	defer server.Close()
+	defer close(release)
+
+	repos := []GitHubRepo{{Owner: "nats-io", Name: "nats-server", Branch: "main", ShortName: "nats-server"}}
+	gf := NewGitHubFetcher(NewHTTPClient(5*time.Second, 0, 5), "", repos, zerolog.Nop())
+	gf.apiURL = server.URL
+	gf.strategy = GitHubStrategyArchive
+
+	out := make(chan GitHubFile)
+	done := make(chan error, 1)
+	go func() {
+		done <- gf.StreamChangedFiles(context.Background(), nil, out)
+	}()
+
+	select {
+	case file := <-out:
+		if file.Path != "docs/first.md" {
+			t.Errorf("Expected docs/first.md first, got %s", file.Path)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected the first file before the archive was complete")
+	}
+	release <- struct{}{}
+	if file := <-out; file.Path != "docs/second.md" {
+		t.Errorf("Expected docs/second.md second, got %s", file.Path)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("StreamChangedFiles failed: %v", err)
+	}
+}
+
+// TestExtractArchiveSkipsNonRegularEntries verifies that directories and links are
+// skipped and paths are relative to the repository root
+func TestExtractArchiveSkipsNonRegularEntries(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	_ = tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "repo-sha/docs/", Mode: 0755})
+	_ = tw.WriteHeader(&tar.Header{Typeflag: tar.TypeSymlink, Name: "repo-sha/docs/link.md", Linkname: "../README.md"})
+	_ = tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "repo-sha/docs/guide.md", Mode: 0644, Size: 7})
+	_, _ = tw.Write([]byte("# Guide"))
+	_ = tw.Close()
+	_ = gz.Close()
+
+	var paths []string
+	err := extractArchive(&buf, isDocumentPath, func(path string, content []byte) {
+		paths = append(paths, path)
+	})
+	if err != nil {
+		t.Fatalf("extractArchive failed: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "docs/guide.md" {
+		t.Errorf("Expected only docs/guide.md, got %v", paths)
+	}
+}
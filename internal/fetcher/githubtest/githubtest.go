@@ -0,0 +1,277 @@
+// Package githubtest provides a local stand-in for the GitHub REST API, serving the
+// tree, contents, tarball and releases endpoints used by the fetcher from in-memory
+// repositories.
+package githubtest
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Request records a request received by the server
+type Request struct {
+	Path          string // URL path
+	Authorization string // Authorization header, empty when none was sent
+}
+
+// Server is a stand-in GitHub API server. Its URL is used as the API base URL.
+type Server struct {
+	*httptest.Server
+
+	// Token, when set, is required as "token <Token>" or "Bearer <Token>"; requests
+	// without it are answered 401
+	Token string
+
+	mu       sync.Mutex
+	repos    map[string]map[string]string // "owner/name@ref" -> path -> content
+	releases map[string][]byte            // "owner/name" -> releases JSON
+	failing  map[string]int               // "owner/name/path" -> status its contents requests fail with
+	broken   map[string]int               // "owner/name@ref" -> status its tree and tarball requests fail with
+	requests []Request
+}
+
+// NewServer starts a stand-in GitHub API server with no repositories
+func NewServer() *Server {
+	s := &Server{
+		repos:    make(map[string]map[string]string),
+		releases: make(map[string][]byte),
+		failing:  make(map[string]int),
+		broken:   make(map[string]int),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// AddRepo adds a repository ref whose files are given as path -> content
+func (s *Server) AddRepo(owner, name, ref string, files map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repos[owner+"/"+name+"@"+ref] = files
+}
+
+// SetReleases sets the releases listed for a repository; releases is encoded as JSON
+func (s *Server) SetReleases(owner, name string, releases any) {
+	data, err := json.Marshal(releases)
+	if err != nil {
+		panic(fmt.Sprintf("githubtest: failed to encode releases: %v", err))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releases[owner+"/"+name] = data
+}
+
+// FailContents makes contents requests for a file of a repository fail with status
+// at every ref, or succeed again when status is 0
+func (s *Server) FailContents(owner, name, path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing[owner+"/"+name+"/"+path] = status
+}
+
+// FailRepo makes tree and tarball requests for a repository ref fail with status, or
+// succeed again when status is 0
+func (s *Server) FailRepo(owner, name, ref string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.broken[owner+"/"+name+"@"+ref] = status
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// BlobSHA returns the git blob SHA of content, as reported by the tree endpoint
+func BlobSHA(content string) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write([]byte(content))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Path: r.URL.Path, Authorization: r.Header.Get("Authorization")})
+	s.mu.Unlock()
+
+	if strings.HasPrefix(r.URL.Path, "/codeload/") {
+		// Archive downloads are redirected here and authorized by the redirect itself
+		s.serveTarball(w, strings.TrimPrefix(r.URL.Path, "/codeload/"))
+		return
+	}
+
+	if s.Token != "" {
+		auth := r.Header.Get("Authorization")
+		if auth != "token "+s.Token && auth != "Bearer "+s.Token {
+			http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+			return
+		}
+	}
+
+	// /repos/{owner}/{name}/{endpoint}/{rest}
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/repos/"), "/", 4)
+	if !strings.HasPrefix(r.URL.Path, "/repos/") || len(parts) < 3 {
+		http.NotFound(w, r)
+		return
+	}
+	repo := parts[0] + "/" + parts[1]
+	rest := ""
+	if len(parts) == 4 {
+		rest = parts[3]
+	}
+
+	switch parts[2] {
+	case "git", "tarball":
+		s.mu.Lock()
+		status := s.broken[repo+"@"+strings.TrimPrefix(rest, "trees/")]
+		s.mu.Unlock()
+		if status != 0 {
+			http.Error(w, fmt.Sprintf(`{"message":%q}`, http.StatusText(status)), status)
+			return
+		}
+	}
+
+	switch parts[2] {
+	case "git":
+		s.serveTree(w, r, repo, strings.TrimPrefix(rest, "trees/"))
+	case "contents":
+		s.serveContents(w, r, repo, rest, r.URL.Query().Get("ref"))
+	case "tarball":
+		http.Redirect(w, r, "/codeload/"+repo+"@"+rest, http.StatusFound)
+	case "releases":
+		s.serveReleases(w, r, repo)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// files returns the files of a repository ref
+func (s *Server) files(repoRef string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files, ok := s.repos[repoRef]
+	return files, ok
+}
+
+func (s *Server) serveTree(w http.ResponseWriter, r *http.Request, repo, ref string) {
+	files, ok := s.files(repo + "@" + ref)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	type entry struct {
+		Path string `json:"path"`
+		Type string `json:"type"`
+		SHA  string `json:"sha"`
+		Size int    `json:"size"`
+	}
+	tree := struct {
+		SHA       string  `json:"sha"`
+		Tree      []entry `json:"tree"`
+		Truncated bool    `json:"truncated"`
+	}{SHA: ref}
+	for _, path := range sortedPaths(files) {
+		tree.Tree = append(tree.Tree, entry{Path: path, Type: "blob", SHA: BlobSHA(files[path]), Size: len(files[path])})
+	}
+
+	writeJSON(w, tree)
+}
+
+func (s *Server) serveContents(w http.ResponseWriter, r *http.Request, repo, path, ref string) {
+	s.mu.Lock()
+	status := s.failing[repo+"/"+path]
+	s.mu.Unlock()
+	if status != 0 {
+		http.Error(w, fmt.Sprintf(`{"message":%q}`, http.StatusText(status)), status)
+		return
+	}
+
+	files, ok := s.files(repo + "@" + ref)
+	content, found := files[path]
+	if !ok || !found {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"name":    path[strings.LastIndex(path, "/")+1:],
+		"path":    path,
+		"sha":     BlobSHA(content),
+		"size":    len(content),
+		"type":    "file",
+		"content": base64.StdEncoding.EncodeToString([]byte(content)),
+	})
+}
+
+func (s *Server) serveTarball(w http.ResponseWriter, repoRef string) {
+	files, ok := s.files(repoRef)
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	// Archives wrap the repository in a "<owner>-<name>-<sha>/" directory and start
+	// with a pax global header, like GitHub's
+	repo, _, _ := strings.Cut(repoRef, "@")
+	root := strings.ReplaceAll(repo, "/", "-") + "-0123abc/"
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	_ = tw.WriteHeader(&tar.Header{Typeflag: tar.TypeXGlobalHeader, Name: "pax_global_header", PAXRecords: map[string]string{"comment": "0123abc"}})
+	_ = tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: root, Mode: 0755})
+	for _, path := range sortedPaths(files) {
+		content := files[path]
+		_ = tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: root + path, Mode: 0644, Size: int64(len(content))})
+		_, _ = tw.Write([]byte(content))
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+
+	w.Header().Set("Content-Type", "application/x-gzip")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (s *Server) serveReleases(w http.ResponseWriter, r *http.Request, repo string) {
+	s.mu.Lock()
+	data, ok := s.releases[repo]
+	s.mu.Unlock()
+	if !ok {
+		data = []byte("[]")
+	}
+	// Every release fits on the first page
+	if page := r.URL.Query().Get("page"); page != "" && page != "1" {
+		data = []byte("[]")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func sortedPaths(files map[string]string) []string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,96 @@
+package fetcher
+
+import (
+	"path"
+	"strings"
+)
+
+// documentExtensions are the file extensions indexed as documentation, matching the
+// formats the parser package reads
+var documentExtensions = []string{".md", ".mdx", ".rst", ".adoc", ".txt"}
+
+// isDocumentPath reports whether a repository path is a documentation file or a file
+// of Go testable examples (example_test.go or example_<name>_test.go)
+func isDocumentPath(filePath string) bool {
+	name := path.Base(filePath)
+	if name == "example_test.go" || (strings.HasPrefix(name, "example_") && strings.HasSuffix(name, "_test.go")) {
+		return true
+	}
+
+	ext := strings.ToLower(path.Ext(filePath))
+	for _, documentExt := range documentExtensions {
+		if ext == documentExt {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDocument reports whether a documentation file at filePath is indexed for the
+// repository: it must be under DocRoot, have a documentation extension or be a Go
+// example file, and match an Include glob (when any are set) and no Exclude glob.
+// Globs are relative to DocRoot.
+func (repo GitHubRepo) IsDocument(filePath string) bool {
+	if !isDocumentPath(filePath) {
+		return false
+	}
+
+	relative := filePath
+	if root := strings.Trim(repo.DocRoot, "/"); root != "" {
+		if !strings.HasPrefix(filePath, root+"/") {
+			return false
+		}
+		relative = strings.TrimPrefix(filePath, root+"/")
+	}
+
+	if len(repo.Include) > 0 && !matchAnyGlob(repo.Include, relative) {
+		return false
+	}
+	return !matchAnyGlob(repo.Exclude, relative)
+}
+
+// matchAnyGlob reports whether name matches any of patterns
+func matchAnyGlob(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if MatchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchGlob reports whether a slash-separated path matches a glob pattern. Segments
+// use path.Match syntax, and a "**" segment matches any number of directories. As in
+// .gitignore, a pattern without a slash matches at any depth, and a pattern matching a
+// directory matches every file below it.
+func MatchGlob(pattern, name string) bool {
+	pattern = strings.Trim(pattern, "/")
+	if !strings.Contains(pattern, "/") && pattern != "**" {
+		pattern = "**/" + pattern
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchSegments matches path segments against pattern segments
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Match the rest of the pattern at every remaining depth
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	// Any remaining segments are below a matched directory
+	return true
+}
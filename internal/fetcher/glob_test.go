@@ -0,0 +1,68 @@
+package fetcher
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.md", "README.md", true},
+		{"*.md", "doc/adr/ADR-1.md", true},
+		{"CHANGELOG*", "doc/CHANGELOG.md", true},
+		{"doc/*.md", "doc/intro.md", true},
+		{"doc/*.md", "doc/adr/ADR-1.md", false},
+		{"doc/**/*.md", "doc/intro.md", true},
+		{"doc/**/*.md", "doc/adr/ADR-1.md", true},
+		{"**/testdata/**", "server/testdata/certs/README.md", true},
+		{"**/testdata/**", "server/doc.md", false},
+		{"test/**", "test/configs/README.md", true},
+		{"test/**", "server/test/README.md", false},
+		{"testdata", "server/testdata/certs/README.md", true},
+		{"doc/adr", "doc/adr/ADR-1.md", true},
+		{"doc/adr", "doc/adr.md", false},
+		{"[", "README.md", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGitHubRepoIsDocument(t *testing.T) {
+	repo := GitHubRepo{
+		DocRoot: "/doc/",
+		Exclude: []string{"**/testdata/**", "CHANGELOG*"},
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"doc/intro.md", true},
+		{"doc/adr/design.rst", true},
+		{"doc/ops/guide.adoc", true},
+		{"doc/notes.txt", true},
+		{"doc/site/page.mdx", true},
+		{"README.md", false},
+		{"doc/main.go", false},
+		{"doc/example_test.go", true},
+		{"doc/example_jetstream_test.go", true},
+		{"doc/main_test.go", false},
+		{"doc/testdata/sample.md", false},
+		{"doc/CHANGELOG.md", false},
+	}
+	for _, tt := range tests {
+		if got := repo.IsDocument(tt.path); got != tt.want {
+			t.Errorf("IsDocument(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	repo = GitHubRepo{Include: []string{"docs/**"}}
+	if !repo.IsDocument("docs/a/b.md") || repo.IsDocument("README.md") {
+		t.Error("Expected include globs to restrict documents")
+	}
+}
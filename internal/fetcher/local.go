@@ -0,0 +1,81 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadLocalFiles walks a local directory (for example a repository checkout) and
+// returns every regular file accepted by match. Paths are relative to root and use
+// forward slashes so local checkouts can be processed exactly like GitHub contents.
+// Hidden directories, vendor and node_modules are skipped.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - root: Directory to walk
+//   - match: Predicate on the relative file path; nil accepts every file
+//
+// Returns the matching files with Repo set to the base name of root.
+func ReadLocalFiles(ctx context.Context, root string, match func(path string) bool) ([]GitHubFile, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	repoName := filepath.Base(filepath.Clean(root))
+
+	var files []GitHubFile
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if match != nil && !match(rel) {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+
+		files = append(files, GitHubFile{
+			Path:    rel,
+			Content: content,
+			Repo:    repoName,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local files from %s: %w", root, err)
+	}
+
+	return files, nil
+}
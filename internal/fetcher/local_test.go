@@ -0,0 +1,94 @@
+package fetcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+}
+
+func TestReadLocalFiles(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "jsm.go")
+	writeTestFile(t, root, "schemas/a.json", `{"a":1}`)
+	writeTestFile(t, root, "schemas/nested/b.json", `{"b":2}`)
+	writeTestFile(t, root, "README.md", "# readme")
+	writeTestFile(t, root, "vendor/c.json", `{}`)
+	writeTestFile(t, root, ".git/d.json", `{}`)
+
+	files, err := ReadLocalFiles(context.Background(), root, func(path string) bool {
+		return strings.HasSuffix(path, ".json")
+	})
+	if err != nil {
+		t.Fatalf("ReadLocalFiles failed: %v", err)
+	}
+
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path)
+		if f.Repo != "jsm.go" {
+			t.Errorf("expected repo jsm.go, got %s", f.Repo)
+		}
+	}
+	sort.Strings(paths)
+
+	want := []string{"schemas/a.json", "schemas/nested/b.json"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, paths)
+	}
+}
+
+func TestReadLocalFilesNilMatch(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "one.txt", "1")
+	writeTestFile(t, root, "two.md", "2")
+
+	files, err := ReadLocalFiles(context.Background(), root, nil)
+	if err != nil {
+		t.Fatalf("ReadLocalFiles failed: %v", err)
+	}
+	if len(files) != 2 {
+		t.Errorf("expected 2 files, got %d", len(files))
+	}
+}
+
+func TestReadLocalFilesMissingDirectory(t *testing.T) {
+	_, err := ReadLocalFiles(context.Background(), filepath.Join(t.TempDir(), "missing"), nil)
+	if err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
+
+func TestReadLocalFilesNotDirectory(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "file.txt", "x")
+
+	_, err := ReadLocalFiles(context.Background(), filepath.Join(root, "file.txt"), nil)
+	if err == nil {
+		t.Error("expected error when root is a file")
+	}
+}
+
+func TestReadLocalFilesCancelled(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "file.txt", "x")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ReadLocalFiles(ctx, root, nil); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
@@ -2,6 +2,7 @@ package fetcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,19 +11,32 @@ import (
 
 // FetchConfig holds configuration for fetching a documentation source
 type FetchConfig struct {
-	BaseURL      string        // Base URL for documentation (e.g., "https://docs.nats.io")
-	MaxRetries   int           // Maximum number of retry attempts
-	FetchTimeout time.Duration // Timeout per HTTP request
-	MaxConcurrent int          // Maximum concurrent fetches
+	BaseURL           string          // Base URL for documentation (e.g., "https://docs.nats.io")
+	MaxRetries        int             // Maximum number of retry attempts
+	FetchTimeout      time.Duration   // Timeout per HTTP request
+	MaxConcurrent     int             // Maximum requests in flight at once
+	RequestsPerSecond float64         // Maximum requests per second; 0 means MaxConcurrent
+	MaxPerHost        int             // Maximum requests in flight per host; 0 means MaxConcurrent
+	IncludePaths      []string        // Globs of discovered page paths to fetch; every page when empty
+	ExcludePaths      []string        // Globs of discovered page paths to skip
+	Discovery         string          // DiscoveryAuto (default), DiscoverySitemap or DiscoveryCrawl
+	Crawl             CrawlLimits     // Bounds of the crawler
+	MinSuccessRatio   float64         // Share of pages that must be fetched for partial results to be used
+	Transport         TransportConfig // Proxy and TLS settings; the default transport when zero
+	Auth              Auth            // Credentials sent to the BaseURL host; none when zero
 }
 
 // GitHubFetchConfig holds configuration for fetching from GitHub repositories
 type GitHubFetchConfig struct {
-	Token         string        // GitHub Personal Access Token
-	Repositories  []GitHubRepo  // List of repositories to fetch from
-	MaxRetries    int           // Maximum number of retry attempts
-	FetchTimeout  time.Duration // Timeout per HTTP request
-	MaxConcurrent int           // Maximum concurrent fetches
+	Token         string          // GitHub Personal Access Token
+	Repositories  []GitHubRepo    // List of repositories to fetch from
+	MaxRetries    int             // Maximum number of retry attempts
+	FetchTimeout  time.Duration   // Timeout per HTTP request
+	MaxConcurrent int             // Maximum concurrent fetches
+	Strategy      string          // GitHubStrategyAPI (default) or GitHubStrategyArchive
+	Transport     TransportConfig // Proxy and TLS settings; the default transport when zero
+
+	MinSuccessRatio float64 // Share of changed files that must be fetched for partial results to be used
 }
 
 // FetchResult holds the result of fetching a documentation source
@@ -35,6 +49,8 @@ type FetchResult struct {
 // MultiSourceFetcher fetches documentation from multiple sources (NATS, Synadia, and GitHub)
 // It uses a shared HTTPClient with consistent retry and rate limiting behavior
 type MultiSourceFetcher struct {
+	httpClient    *HTTPClient
+	githubClient  *HTTPClient // httpClient, or one with the GitHub transport sharing its limits
 	natsConfig    FetchConfig
 	syadiaConfig   FetchConfig
 	githubConfig  GitHubFetchConfig
@@ -44,67 +60,246 @@ type MultiSourceFetcher struct {
 	logger        zerolog.Logger
 }
 
-// NewMultiSourceFetcher creates a fetcher for multiple documentation sources
+// MultiSourceOption configures a setting of a MultiSourceFetcher shared by every source
+type MultiSourceOption func(*multiSourceOptions)
+
+// multiSourceOptions holds the settings applied by MultiSourceOptions
+type multiSourceOptions struct {
+	fixtures FixtureConfig
+	breaker  *BreakerConfig
+}
+
+// WithFixtures records or replays the requests of every source
+func WithFixtures(config FixtureConfig) MultiSourceOption {
+	return func(o *multiSourceOptions) {
+		o.fixtures = config
+	}
+}
+
+// WithBreaker fails fast on hosts that keep failing, whichever source requests them;
+// circuit breakers are disabled without it
+func WithBreaker(config BreakerConfig) MultiSourceOption {
+	return func(o *multiSourceOptions) {
+		o.breaker = &config
+	}
+}
+
+// NewMultiSourceFetcher creates a fetcher for multiple documentation sources; it fails
+// when a source's transport or credentials, or the fixtures, cannot be set up
 func NewMultiSourceFetcher(
 	natsConfig FetchConfig,
 	syadiaConfig FetchConfig,
 	githubConfig GitHubFetchConfig,
 	logger zerolog.Logger,
-) *MultiSourceFetcher {
+	opts ...MultiSourceOption,
+) (*MultiSourceFetcher, error) {
+	var options multiSourceOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+
 	// Create HTTP client with reasonable defaults
-	// Use NATS config as primary for client settings
-	httpClient := NewHTTPClient(
+	// Use NATS config as primary for client settings; the limits are shared by every
+	// source so they bound the total load, including retries
+	httpClient := NewHTTPClientWithLimits(
 		natsConfig.FetchTimeout,
 		natsConfig.MaxRetries,
-		natsConfig.MaxConcurrent,
+		Limits{
+			MaxInFlight:       natsConfig.MaxConcurrent,
+			RequestsPerSecond: natsConfig.RequestsPerSecond,
+			MaxPerHost:        natsConfig.MaxPerHost,
+		},
 	)
 
-	natsFetcher := NewDocumentationFetcher(httpClient, natsConfig.BaseURL, logger)
-	syadiaFetcher := NewDocumentationFetcher(httpClient, syadiaConfig.BaseURL, logger)
+	// Fail fast on hosts that keep failing, whichever source requests them
+	if breaker := options.breaker; breaker != nil {
+		config := *breaker
+		config.OnStateChange = func(host string, from, to BreakerState) {
+			event := logger.Info()
+			if to == BreakerOpen {
+				event = logger.Warn()
+			}
+			event.
+				Str("host", host).
+				Str("from", string(from)).
+				Str("to", string(to)).
+				Msg("Circuit breaker state changed")
+		}
+		httpClient = httpClient.WithCircuitBreaker(config)
+	}
 
-	var githubFetcher *GitHubFetcher
-	// Create GitHub fetcher if repositories are configured (token is optional - will use unauthenticated requests)
-	// Unauthenticated requests have lower rate limits but should work for reasonable use
-	if len(githubConfig.Repositories) > 0 {
-		githubFetcher = NewGitHubFetcher(httpClient, githubConfig.Token, githubConfig.Repositories, logger)
+	// Record or replay the requests of every source
+	layer, err := options.fixtures.Layer()
+	if err != nil {
+		return nil, fmt.Errorf("invalid fixture configuration: %w", err)
+	}
+	if layer != nil {
+		httpClient = httpClient.WithRoundTripper(layer)
+		logger.Info().
+			Str("mode", options.fixtures.Mode).
+			Str("dir", options.fixtures.Dir).
+			Msg("HTTP fixtures enabled")
 	}
 
-	return &MultiSourceFetcher{
+	// Sources with their own transport get a client that still shares the limits
+	natsTransportClient, err := sourceClient(httpClient, "NATS", natsConfig.Transport)
+	if err != nil {
+		return nil, err
+	}
+	syadiaTransportClient, err := sourceClient(httpClient, "Synadia", syadiaConfig.Transport)
+	if err != nil {
+		return nil, err
+	}
+	githubClient, err := sourceClient(httpClient, "GitHub", githubConfig.Transport)
+	if err != nil {
+		return nil, err
+	}
+	natsClient, err := authenticatedClient(natsTransportClient, "NATS", natsConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+	syadiaClient, err := authenticatedClient(syadiaTransportClient, "Synadia", syadiaConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+	natsFetcher := newSourceFetcher(natsClient, natsConfig, logger)
+	syadiaFetcher := newSourceFetcher(syadiaClient, syadiaConfig, logger)
+
+	msf := &MultiSourceFetcher{
+		httpClient:    httpClient,
+		githubClient:  githubClient,
 		natsConfig:    natsConfig,
 		syadiaConfig:   syadiaConfig,
 		githubConfig:  githubConfig,
 		natsFetcher:   natsFetcher,
 		syadiaFetcher:  syadiaFetcher,
-		githubFetcher: githubFetcher,
 		logger:        logger,
 	}
+
+	// Create GitHub fetcher if repositories are configured (token is optional - will use unauthenticated requests)
+	// Unauthenticated requests have lower rate limits but should work for reasonable use
+	if len(githubConfig.Repositories) > 0 {
+		msf.githubFetcher = msf.newGitHubFetcher(githubConfig.Repositories)
+	}
+
+	return msf, nil
+}
+
+// sourceClient returns client, or when the source configures its own transport, a
+// client using that transport that shares the limits of client
+func sourceClient(client *HTTPClient, source string, tc TransportConfig) (*HTTPClient, error) {
+	if tc.IsZero() {
+		return client, nil
+	}
+	transport, err := NewTransport(tc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s transport configuration: %w", source, err)
+	}
+	return client.WithTransport(transport), nil
+}
+
+// authenticatedClient returns client, or when the source configures credentials, a
+// client sending them to the source's host that shares the limits of client
+func authenticatedClient(client *HTTPClient, source string, config FetchConfig, logger zerolog.Logger) (*HTTPClient, error) {
+	if config.Auth.IsZero() {
+		return client, nil
+	}
+	authenticated, err := client.WithAuth(config.BaseURL, config.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s authentication configuration: %w", source, err)
+	}
+	logger.Info().
+		Str("source", source).
+		Object("auth", config.Auth).
+		Msg("Fetching with source credentials")
+	return authenticated, nil
+}
+
+// newSourceFetcher creates a documentation fetcher for a source that shares the HTTP
+// client and applies the source's path filters and discovery mode
+func newSourceFetcher(client *HTTPClient, config FetchConfig, logger zerolog.Logger) *DocumentationFetcher {
+	df := NewDocumentationFetcher(client, config.BaseURL, logger)
+	df.include = config.IncludePaths
+	df.exclude = config.ExcludePaths
+	df.discovery = config.Discovery
+	df.crawlLimits = config.Crawl
+	df.minSuccessRatio = config.MinSuccessRatio
+	return df
+}
+
+// newGitHubFetcher creates a GitHub fetcher for repos that shares the GitHub HTTP
+// client, token and fetch strategy
+func (msf *MultiSourceFetcher) newGitHubFetcher(repos []GitHubRepo) *GitHubFetcher {
+	gf := NewGitHubFetcher(msf.githubClient, msf.githubConfig.Token, repos, msf.logger)
+	if msf.githubConfig.Strategy != "" {
+		gf.strategy = msf.githubConfig.Strategy
+	}
+	gf.minSuccessRatio = msf.githubConfig.MinSuccessRatio
+	return gf
 }
 
 // FetchNATS retrieves all NATS documentation pages
 // Returns pages and any error encountered. Non-fatal errors are returned with partial results.
 func (msf *MultiSourceFetcher) FetchNATS(ctx context.Context) ([]DocumentPage, error) {
+	return msf.FetchNATSChanged(ctx, nil, nil)
+}
+
+// FetchNATSChanged retrieves the NATS documentation pages changed since the fetch that
+// recorded previous (keyed by page path); unchanged pages are returned with NotModified set.
+// The pages at the retry paths, which failed last time, are fetched first.
+// Returns pages and any error encountered. Non-fatal errors are returned with partial
+// results; a *PartialFetchError matches ErrTooManyFailures when fewer pages than the
+// source's minimum success ratio were fetched.
+func (msf *MultiSourceFetcher) FetchNATSChanged(ctx context.Context, previous map[string]Validator, retry []string) ([]DocumentPage, error) {
+	return collect(func(out chan<- DocumentPage) error {
+		return msf.StreamNATSChanged(ctx, previous, retry, out)
+	})
+}
+
+// StreamNATSChanged works like FetchNATSChanged but sends each page to out as soon as
+// it is fetched; see DocumentationFetcher.StreamChangedPages.
+func (msf *MultiSourceFetcher) StreamNATSChanged(ctx context.Context, previous map[string]Validator, retry []string, out chan<- DocumentPage) error {
 	msf.logger.Info().
 		Str("source", "NATS").
 		Str("base_url", msf.natsConfig.BaseURL).
+		Int("known_pages", len(previous)).
+		Int("retried_pages", len(retry)).
 		Msg("Starting NATS documentation fetch")
 
-	pages, err := msf.natsFetcher.FetchAllPages(ctx)
+	err := msf.natsFetcher.StreamChangedPages(ctx, previous, retry, out)
 
+	if tolerable(err) {
+		msf.logger.Warn().
+			Err(err).
+			Str("source", "NATS").
+			Msg("Some NATS documentation pages failed to fetch (continuing with partial results)")
+		return err
+	}
 	if err != nil {
 		msf.logger.Error().
 			Err(err).
 			Str("source", "NATS").
-			Int("pages_fetched", len(pages)).
 			Msg("Error during NATS documentation fetch (partial results)")
-		return pages, err
+		return err
 	}
 
 	msf.logger.Info().
 		Str("source", "NATS").
-		Int("pages_fetched", len(pages)).
 		Msg("Successfully completed NATS documentation fetch")
 
-	return pages, nil
+	return nil
+}
+
+// FetchNATSPages retrieves the NATS documentation pages whose path satisfies match.
+// Returns pages and any error encountered. Non-fatal errors are returned with partial results.
+func (msf *MultiSourceFetcher) FetchNATSPages(ctx context.Context, match func(path string) bool) ([]DocumentPage, error) {
+	msf.logger.Info().
+		Str("source", "NATS").
+		Str("base_url", msf.natsConfig.BaseURL).
+		Msg("Fetching matching NATS documentation pages")
+
+	return msf.natsFetcher.FetchMatchingPages(ctx, match)
 }
 
 // FetchSynadia retrieves all Synadia documentation pages
@@ -112,28 +307,58 @@ func (msf *MultiSourceFetcher) FetchNATS(ctx context.Context) ([]DocumentPage, e
 // If syncp fetching fails completely, this is treated as graceful degradation - the server
 // continues with NATS documentation only.
 func (msf *MultiSourceFetcher) FetchSynadia(ctx context.Context) ([]DocumentPage, error) {
+	return msf.FetchSynadiaChanged(ctx, nil, nil)
+}
+
+// FetchSynadiaChanged retrieves the Synadia documentation pages changed since the fetch
+// that recorded previous (keyed by page path); unchanged pages are returned with
+// NotModified set. The pages at the retry paths are fetched first. Errors are handled
+// like FetchNATSChanged.
+func (msf *MultiSourceFetcher) FetchSynadiaChanged(ctx context.Context, previous map[string]Validator, retry []string) ([]DocumentPage, error) {
+	return collect(func(out chan<- DocumentPage) error {
+		return msf.StreamSynadiaChanged(ctx, previous, retry, out)
+	})
+}
+
+// StreamSynadiaChanged works like FetchSynadiaChanged but sends each page to out as soon as
+// it is fetched; see DocumentationFetcher.StreamChangedPages.
+func (msf *MultiSourceFetcher) StreamSynadiaChanged(ctx context.Context, previous map[string]Validator, retry []string, out chan<- DocumentPage) error {
 	msf.logger.Info().
 		Str("source", "Synadia").
 		Str("base_url", msf.syadiaConfig.BaseURL).
+		Int("known_pages", len(previous)).
+		Int("retried_pages", len(retry)).
 		Msg("Starting Synadia documentation fetch")
 
-	pages, err := msf.syadiaFetcher.FetchAllPages(ctx)
+	err := msf.syadiaFetcher.StreamChangedPages(ctx, previous, retry, out)
 
+	if tolerable(err) {
+		msf.logger.Warn().
+			Err(err).
+			Str("source", "Synadia").
+			Msg("Some Synadia documentation pages failed to fetch (continuing with partial results)")
+		return err
+	}
 	if err != nil {
 		msf.logger.Error().
 			Err(err).
 			Str("source", "Synadia").
-			Int("pages_fetched", len(pages)).
 			Msg("Error during Synadia documentation fetch (graceful degradation to NATS-only mode)")
-		return pages, err
+		return err
 	}
 
 	msf.logger.Info().
 		Str("source", "Synadia").
-		Int("pages_fetched", len(pages)).
 		Msg("Successfully completed Synadia documentation fetch")
 
-	return pages, nil
+	return nil
+}
+
+// tolerable reports whether err only records pages that failed while enough others
+// were fetched for the partial results to be used
+func tolerable(err error) bool {
+	var partial *PartialFetchError
+	return errors.As(err, &partial) && partial.Tolerable()
 }
 
 // FetchBoth retrieves documentation from both NATS and Synadia sources concurrently
@@ -171,31 +396,88 @@ func (msf *MultiSourceFetcher) FetchBoth(ctx context.Context) (natsResult, syncp
 	return natsResult, syncpResult
 }
 
-// FetchGitHub retrieves all markdown files from configured GitHub repositories
+// FetchGitHub retrieves all documentation files from configured GitHub repositories
 // Returns files and any error encountered. Non-fatal errors are returned with partial results.
 func (msf *MultiSourceFetcher) FetchGitHub(ctx context.Context) ([]GitHubFile, error) {
+	return msf.FetchGitHubChanged(ctx, nil)
+}
+
+// FetchGitHubChanged retrieves the documentation files whose blob SHA changed since the fetch
+// that recorded previous (keyed by GitHubFile.Key); unchanged files are returned with
+// NotModified set. Errors are handled like FetchGitHub; files whose content failed to
+// fetch are reported with a *PartialFetchError that matches ErrTooManyFailures when
+// fewer files than the minimum success ratio were fetched.
+func (msf *MultiSourceFetcher) FetchGitHubChanged(ctx context.Context, previous map[string]Validator) ([]GitHubFile, error) {
+	return collect(func(out chan<- GitHubFile) error {
+		return msf.StreamGitHubChanged(ctx, previous, out)
+	})
+}
+
+// StreamGitHubChanged works like FetchGitHubChanged but sends each file to out as soon
+// as it is fetched; see GitHubFetcher.StreamChangedFiles.
+func (msf *MultiSourceFetcher) StreamGitHubChanged(ctx context.Context, previous map[string]Validator, out chan<- GitHubFile) error {
 	if msf.githubFetcher == nil {
-		return nil, fmt.Errorf("GitHub fetcher not configured")
+		return fmt.Errorf("GitHub fetcher not configured")
 	}
 
 	msf.logger.Info().
+		Int("known_files", len(previous)).
 		Msg("Starting GitHub documentation fetch")
 
-	files, err := msf.githubFetcher.FetchAllFiles(ctx)
+	err := msf.githubFetcher.StreamChangedFiles(ctx, previous, out)
 
+	if tolerable(err) {
+		msf.logger.Warn().
+			Err(err).
+			Msg("Some GitHub documentation files failed to fetch (continuing with partial results)")
+		return err
+	}
 	if err != nil {
 		msf.logger.Error().
 			Err(err).
-			Int("files_fetched", len(files)).
 			Msg("Error during GitHub documentation fetch (graceful degradation)")
-		return files, err
+		return err
 	}
 
 	msf.logger.Info().
-		Int("files_fetched", len(files)).
 		Msg("Successfully completed GitHub documentation fetch")
 
-	return files, nil
+	return nil
+}
+
+// FetchGitHubFiles retrieves files accepted by match from a single GitHub repository.
+// Unlike FetchGitHub it does not require GitHub documentation to be configured, so
+// structured sources can pull from repositories outside the documentation list.
+// The configured GitHub token, retry and rate limiting settings are shared.
+func (msf *MultiSourceFetcher) FetchGitHubFiles(ctx context.Context, repo GitHubRepo, match func(path string) bool) ([]GitHubFile, error) {
+	if repo.ShortName == "" {
+		repo.ShortName = repo.Name
+	}
+
+	msf.logger.Info().
+		Str("owner", repo.Owner).
+		Str("name", repo.Name).
+		Str("branch", repo.Branch).
+		Msg("Fetching repository files")
+
+	gf := msf.newGitHubFetcher([]GitHubRepo{repo})
+	return gf.FetchRepositoryFiles(ctx, repo, match)
+}
+
+// FetchGitHubReleases retrieves the published releases of a single GitHub repository.
+// Like FetchGitHubFiles it does not require GitHub documentation to be configured.
+func (msf *MultiSourceFetcher) FetchGitHubReleases(ctx context.Context, repo GitHubRepo) ([]GitHubRelease, error) {
+	if repo.ShortName == "" {
+		repo.ShortName = repo.Name
+	}
+
+	msf.logger.Info().
+		Str("owner", repo.Owner).
+		Str("name", repo.Name).
+		Msg("Fetching repository releases")
+
+	gf := msf.newGitHubFetcher([]GitHubRepo{repo})
+	return gf.FetchReleases(ctx, repo)
 }
 
 // FetchAllWithFallback fetches documentation from both sources with fallback behavior
@@ -207,8 +489,8 @@ func (msf *MultiSourceFetcher) FetchAllWithFallback(ctx context.Context) (natsPa
 	natsPages = natsResult.Pages
 	syncpPages = syncpResult.Pages
 
-	// NATS must succeed (primary documentation source)
-	if natsResult.Error != nil {
+	// NATS must succeed (primary documentation source), at least for enough pages
+	if natsResult.Error != nil && !tolerable(natsResult.Error) {
 		return natsPages, syncpPages, fmt.Errorf("NATS documentation fetch failed: %w", natsResult.Error)
 	}
 
@@ -223,3 +505,9 @@ func (msf *MultiSourceFetcher) FetchAllWithFallback(ctx context.Context) (natsPa
 
 	return natsPages, syncpPages, nil
 }
+
+// BreakerStatus returns the circuit breaker state of every host requested by any
+// source, or nil when circuit breakers are disabled
+func (msf *MultiSourceFetcher) BreakerStatus() []HostStatus {
+	return msf.httpClient.BreakerStatus()
+}
@@ -37,10 +37,9 @@ func TestNewMultiSourceFetcher(t *testing.T) {
 		MaxConcurrent: 5,
 	}
 
-	fetcher := NewMultiSourceFetcher(natsConfig, SynadiaConfig, githubConfig, logger)
-
-	if fetcher == nil {
-		t.Fatal("NewMultiSourceFetcher returned nil")
+	fetcher, err := NewMultiSourceFetcher(natsConfig, SynadiaConfig, githubConfig, logger)
+	if err != nil {
+		t.Fatalf("NewMultiSourceFetcher failed: %v", err)
 	}
 
 	if fetcher.natsFetcher == nil {
@@ -81,10 +80,9 @@ func TestFetchNATS_WithMockServer(t *testing.T) {
 		MaxConcurrent: 1,
 	}
 
-	fetcher := NewMultiSourceFetcher(natsConfig, SynadiaConfig, githubConfig, logger)
-
-	if fetcher == nil {
-		t.Fatal("fetcher creation failed")
+	fetcher, err := NewMultiSourceFetcher(natsConfig, SynadiaConfig, githubConfig, logger)
+	if err != nil {
+		t.Fatalf("fetcher creation failed: %v", err)
 	}
 
 	// Verify fetchers were created with correct base URLs
@@ -207,7 +205,10 @@ func TestProperty_RetryLogicConsistency(t *testing.T) {
 					MaxConcurrent: 5,
 				}
 
-				fetcher := NewMultiSourceFetcher(natsConfig, SynadiaConfig, githubConfig, logger)
+				fetcher, err := NewMultiSourceFetcher(natsConfig, SynadiaConfig, githubConfig, logger)
+				if err != nil {
+					return false
+				}
 
 				// Both fetchers should share the same HTTP client
 				// and thus the same retry logic
@@ -260,7 +261,10 @@ func TestProperty_RetryLogicConsistency(t *testing.T) {
 					MaxConcurrent: 5,
 				}
 
-				fetcher := NewMultiSourceFetcher(natsConfig, SynadiaConfig, githubConfig, logger)
+				fetcher, err := NewMultiSourceFetcher(natsConfig, SynadiaConfig, githubConfig, logger)
+				if err != nil {
+					return false
+				}
 
 				// Both fetchers should have HTTP clients with same timeout
 				natsTimeout := fetcher.natsFetcher.client.client.Timeout
@@ -307,7 +311,10 @@ func TestProperty_FetcherIndependence(t *testing.T) {
 					MaxConcurrent: 3,
 				}
 
-				fetcher := NewMultiSourceFetcher(natsConfig, SynadiaConfig, githubConfig, logger)
+				fetcher, err := NewMultiSourceFetcher(natsConfig, SynadiaConfig, githubConfig, logger)
+				if err != nil {
+					return false
+				}
 
 				// Base URLs should be different
 				if fetcher.natsFetcher.baseURL == fetcher.syadiaFetcher.baseURL {
@@ -365,7 +372,10 @@ func TestProperty_BackwardCompatibility_Fetcher(t *testing.T) {
 					MaxConcurrent: 5,
 				}
 
-				fetcher := NewMultiSourceFetcher(natsConfig, SynadiaConfig, githubConfig, logger)
+				fetcher, err := NewMultiSourceFetcher(natsConfig, SynadiaConfig, githubConfig, logger)
+				if err != nil {
+					return false
+				}
 
 				// NATS fetcher should be usable independently
 				if fetcher.natsFetcher == nil {
@@ -0,0 +1,92 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTooManyFailures is matched by errors.Is for a PartialFetchError whose share of
+// successfully fetched pages is below the source's minimum success ratio
+var ErrTooManyFailures = errors.New("too many pages failed to fetch")
+
+// PageFailure records a documentation page that could not be fetched. It is kept in
+// the cache so the next fetch of the source retries the page first.
+type PageFailure struct {
+	Path  string `json:"path"`
+	URL   string `json:"url"`
+	Error string `json:"error"`
+}
+
+// PartialFetchError reports the pages of a source that failed to fetch while the
+// others were fetched. The fetched pages are returned along with it.
+type PartialFetchError struct {
+	Total           int           // Pages that were to be fetched
+	Failures        []PageFailure // Pages that failed, sorted by path
+	MinSuccessRatio float64       // Share of pages that must succeed for the fetch to be usable
+
+	cause error // Context error when pages were never started
+}
+
+// Error implements the error interface
+func (e *PartialFetchError) Error() string {
+	var msg strings.Builder
+	msg.WriteString(fmt.Sprintf("failed to fetch %d of %d pages: ", len(e.Failures), e.Total))
+	for i, failure := range e.Failures {
+		if i > 0 {
+			msg.WriteString("; ")
+		}
+		msg.WriteString(failure.Path + ": " + failure.Error)
+	}
+	return msg.String()
+}
+
+// SuccessRatio returns the share of pages that were fetched
+func (e *PartialFetchError) SuccessRatio() float64 {
+	if e.Total == 0 {
+		return 1
+	}
+	return float64(e.Total-len(e.Failures)) / float64(e.Total)
+}
+
+// Tolerable reports whether enough pages were fetched for the partial results to be
+// used: the success ratio reaches the minimum and the fetch was not cancelled
+func (e *PartialFetchError) Tolerable() bool {
+	return e.cause == nil && e.SuccessRatio() >= e.MinSuccessRatio
+}
+
+// Is makes errors.Is(err, ErrTooManyFailures) true when the success ratio is below
+// the minimum
+func (e *PartialFetchError) Is(target error) bool {
+	return target == ErrTooManyFailures && e.SuccessRatio() < e.MinSuccessRatio
+}
+
+// Unwrap returns the context error that stopped the fetch, if any
+func (e *PartialFetchError) Unwrap() error {
+	return e.cause
+}
+
+// retryFirst returns entries with those whose path is in retry moved to the front,
+// keeping the order of both groups
+func retryFirst(entries []SitemapEntry, retry []string) []SitemapEntry {
+	if len(retry) == 0 {
+		return entries
+	}
+	failed := make(map[string]bool, len(retry))
+	for _, path := range retry {
+		failed[path] = true
+	}
+
+	ordered := make([]SitemapEntry, 0, len(entries))
+	for _, entry := range entries {
+		if failed[entry.Path] {
+			ordered = append(ordered, entry)
+		}
+	}
+	for _, entry := range entries {
+		if !failed[entry.Path] {
+			ordered = append(ordered, entry)
+		}
+	}
+	return ordered
+}
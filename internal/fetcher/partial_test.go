@@ -0,0 +1,122 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestPartialFetchErrorSuccessRatio(t *testing.T) {
+	err := &PartialFetchError{
+		Total:           10,
+		Failures:        []PageFailure{{Path: "/a", Error: "HTTP 404"}},
+		MinSuccessRatio: 0.9,
+	}
+	if ratio := err.SuccessRatio(); ratio != 0.9 {
+		t.Errorf("expected success ratio 0.9, got %g", ratio)
+	}
+	if errors.Is(err, ErrTooManyFailures) || !err.Tolerable() {
+		t.Error("a success ratio at the minimum should be tolerated")
+	}
+
+	err.Failures = append(err.Failures, PageFailure{Path: "/b", Error: "HTTP 500"})
+	if !errors.Is(fmt.Errorf("wrapped: %w", err), ErrTooManyFailures) {
+		t.Error("a success ratio below the minimum should match ErrTooManyFailures")
+	}
+
+	cancelled := &PartialFetchError{Total: 1, Failures: []PageFailure{{Path: "/a"}}, cause: context.Canceled}
+	if !errors.Is(cancelled, context.Canceled) {
+		t.Error("expected the context error to be unwrapped")
+	}
+	if cancelled.Tolerable() {
+		t.Error("a cancelled fetch should not be tolerable")
+	}
+}
+
+func TestRetryFirst(t *testing.T) {
+	entries := []SitemapEntry{{Path: "/a"}, {Path: "/b"}, {Path: "/c"}, {Path: "/d"}}
+
+	got := retryFirst(entries, []string{"/d", "/b", "/gone"})
+	want := []SitemapEntry{{Path: "/b"}, {Path: "/d"}, {Path: "/a"}, {Path: "/c"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("retryFirst() = %v, want %v", got, want)
+	}
+	if got := retryFirst(entries, nil); !reflect.DeepEqual(got, entries) {
+		t.Errorf("retryFirst() without retries reordered entries: %v", got)
+	}
+}
+
+// TestFetchNATSChangedToleratesPartialFailures verifies that a few failed pages are
+// reported without failing the source and that failed pages are fetched first
+func TestFetchNATSChangedToleratesPartialFailures(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sitemap-pages.xml" {
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+			for i := 1; i <= 10; i++ {
+				fmt.Fprintf(w, "<url><loc>%s/page%d</loc></url>", server.URL, i)
+			}
+			fmt.Fprint(w, "</urlset>")
+			return
+		}
+		if !strings.HasPrefix(r.URL.Path, "/page") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		order = append(order, r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/page3" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, "<html><body>Page content</body></html>")
+	}))
+	defer server.Close()
+
+	fetch := func(minSuccessRatio float64) ([]DocumentPage, error) {
+		config := FetchConfig{
+			BaseURL:           server.URL,
+			FetchTimeout:      5 * time.Second,
+			MaxConcurrent:     1,
+			RequestsPerSecond: 1000,
+			MinSuccessRatio:   minSuccessRatio,
+		}
+		msf, err := NewMultiSourceFetcher(config, FetchConfig{}, GitHubFetchConfig{}, zerolog.Nop())
+		if err != nil {
+			t.Fatalf("NewMultiSourceFetcher failed: %v", err)
+		}
+		return msf.FetchNATSChanged(context.Background(), nil, []string{"/page7"})
+	}
+
+	pages, err := fetch(0.9)
+	if len(pages) != 9 {
+		t.Errorf("expected 9 fetched pages, got %d", len(pages))
+	}
+	var partial *PartialFetchError
+	if !errors.As(err, &partial) || errors.Is(err, ErrTooManyFailures) {
+		t.Fatalf("expected a tolerated partial failure, got %v", err)
+	}
+	if len(partial.Failures) != 1 || partial.Failures[0].Path != "/page3" {
+		t.Errorf("unexpected failures: %+v", partial.Failures)
+	}
+	if len(order) == 0 || order[0] != "/page7" {
+		t.Errorf("expected the retried page to be fetched first, got order %v", order)
+	}
+
+	if _, err := fetch(0.95); !errors.Is(err, ErrTooManyFailures) {
+		t.Errorf("expected ErrTooManyFailures below the minimum success ratio, got %v", err)
+	}
+}
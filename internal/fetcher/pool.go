@@ -0,0 +1,71 @@
+package fetcher
+
+import (
+	"context"
+	"sync"
+)
+
+// runPool calls fn for every item on at most workers goroutines and waits for them
+// to finish. Items not yet started when ctx is cancelled are skipped.
+func runPool[T any](ctx context.Context, workers int, items []T, fn func(item T)) {
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	queue := make(chan T)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range queue {
+				fn(item)
+			}
+		}()
+	}
+
+feed:
+	for _, item := range items {
+		select {
+		case queue <- item:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+
+	wg.Wait()
+}
+
+// collect runs stream with a channel it drains concurrently and returns every item
+// stream sent before returning, along with its error. It adapts the streaming fetch
+// methods to callers that want all results at once.
+func collect[T any](stream func(out chan<- T) error) ([]T, error) {
+	out := make(chan T)
+	done := make(chan struct{})
+	var items []T
+	go func() {
+		defer close(done)
+		for item := range out {
+			items = append(items, item)
+		}
+	}()
+
+	err := stream(out)
+	close(out)
+	<-done
+	return items, err
+}
+
+// send delivers item to out unless ctx is cancelled first, reporting whether it was sent
+func send[T any](ctx context.Context, out chan<- T, item T) bool {
+	select {
+	case out <- item:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
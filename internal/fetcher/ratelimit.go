@@ -0,0 +1,145 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRateLimited is matched by errors.Is for every RateLimitError
+var ErrRateLimited = errors.New("rate limited")
+
+const (
+	// defaultMaxRateLimitWait is the longest an HTTPClient waits for a rate limit to
+	// reset before giving up with a RateLimitError
+	defaultMaxRateLimitWait = time.Minute
+	// defaultRateLimitWait is assumed when a rate limited response says nothing about
+	// when to retry, as GitHub recommends for its secondary rate limits
+	defaultRateLimitWait = time.Minute
+)
+
+// RateLimitError reports that a host refused requests because of rate limiting.
+// Reset is when the host accepts requests again.
+type RateLimitError struct {
+	URL        string    // Requested URL
+	StatusCode int       // HTTP status of the refusal; 0 when the request was not sent
+	Reset      time.Time // When requests are accepted again
+}
+
+// Error implements the error interface
+func (e *RateLimitError) Error() string {
+	var msg strings.Builder
+	msg.WriteString("rate limited")
+	if e.StatusCode != 0 {
+		msg.WriteString(fmt.Sprintf(": HTTP %d", e.StatusCode))
+	}
+	if e.URL != "" {
+		msg.WriteString(" for " + e.URL)
+	}
+	if !e.Reset.IsZero() {
+		msg.WriteString(" until " + e.Reset.Format(time.RFC3339))
+	}
+	return msg.String()
+}
+
+// Is makes errors.Is(err, ErrRateLimited) true for every RateLimitError
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// rateLimitFrom returns the rate limit reported by a response, or nil when the
+// response is not a rate limit refusal. A 429 always is; a 403 is when it carries
+// Retry-After or an exhausted X-RateLimit-Remaining, as GitHub's primary and
+// secondary rate limits do.
+func rateLimitFrom(resp *http.Response, now time.Time) *RateLimitError {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+	case http.StatusForbidden:
+		if resp.Header.Get("Retry-After") == "" && resp.Header.Get("X-RateLimit-Remaining") != "0" {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	reset, ok := retryAfter(resp.Header, now)
+	if !ok {
+		reset, ok = rateLimitReset(resp.Header)
+	}
+	if !ok {
+		reset = now.Add(defaultRateLimitWait)
+	}
+
+	return &RateLimitError{StatusCode: resp.StatusCode, Reset: reset}
+}
+
+// retryAfter parses the Retry-After header, given either in seconds or as an HTTP date
+func retryAfter(header http.Header, now time.Time) (time.Time, bool) {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return time.Time{}, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return now.Add(time.Duration(seconds) * time.Second), true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date, true
+	}
+	return time.Time{}, false
+}
+
+// rateLimitReset parses the X-RateLimit-Reset header (Unix seconds)
+func rateLimitReset(header http.Header) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(header.Get("X-RateLimit-Reset")), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+// recordRateLimit remembers when a host's quota resets once a response reports it
+// exhausted, so later requests to the host wait instead of being refused
+func (c *HTTPClient) recordRateLimit(host string, header http.Header) {
+	if header.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+	if reset, ok := rateLimitReset(header); ok {
+		c.pauseHost(host, reset)
+	}
+}
+
+// pauseHost holds requests to host until the given time
+func (c *HTTPClient) pauseHost(host string, until time.Time) {
+	c.hostMu.Lock()
+	defer c.hostMu.Unlock()
+	if until.After(c.hostResume[host]) {
+		c.hostResume[host] = until
+	}
+}
+
+// waitForHost blocks until requests to host may resume. It returns a RateLimitError
+// without waiting when the host resumes later than the client is willing to wait.
+func (c *HTTPClient) waitForHost(ctx context.Context, rawURL, host string) error {
+	c.hostMu.Lock()
+	resume := c.hostResume[host]
+	c.hostMu.Unlock()
+
+	wait := time.Until(resume)
+	if wait <= 0 {
+		return nil
+	}
+	if wait > c.maxRateLimitWait {
+		return &RateLimitError{URL: rawURL, Reset: resume}
+	}
+
+	select {
+	case <-time.After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
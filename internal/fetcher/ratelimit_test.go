@@ -0,0 +1,142 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestHTTPClientRetriesAfterRateLimit verifies that a 429 is retried after Retry-After
+func TestHTTPClientRetriesAfterRateLimit(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(5*time.Second, 2, 10)
+	body, err := client.Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Expected fetch to succeed after the rate limit, got: %v", err)
+	}
+	if string(body) != "ok" || attempts != 2 {
+		t.Errorf("Expected 2 attempts and body ok, got %d attempts and %q", attempts, body)
+	}
+}
+
+// TestHTTPClientRateLimitBeyondMaxWait verifies that a distant reset returns a typed error
+// and holds later requests to the same host without sending them
+func TestHTTPClientRateLimitBeyondMaxWait(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(5*time.Second, 3, 10)
+
+	_, err := client.Fetch(context.Background(), server.URL)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected ErrRateLimited, got: %v", err)
+	}
+	var limit *RateLimitError
+	if !errors.As(err, &limit) || !limit.Reset.Equal(reset) || limit.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected reset %v with HTTP 403, got %+v", reset, limit)
+	}
+
+	_, err = client.Fetch(context.Background(), server.URL+"/other")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected the host to stay rate limited, got: %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 request to reach the server, got %d", attempts)
+	}
+}
+
+// TestHTTPClientForbiddenIsNotRateLimit verifies that a plain 403 stays a client error
+func TestHTTPClientForbiddenIsNotRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPClient(5*time.Second, 3, 10).Fetch(context.Background(), server.URL)
+	if err == nil || errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected a client error that is not a rate limit, got: %v", err)
+	}
+}
+
+// TestRateLimitFrom verifies parsing of Retry-After and rate limit headers
+func TestRateLimitFrom(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		status int
+		header map[string]string
+		want   time.Time // zero when not rate limited
+	}{
+		{name: "retry after seconds", status: 429, header: map[string]string{"Retry-After": "30"}, want: now.Add(30 * time.Second)},
+		{name: "retry after date", status: 429, header: map[string]string{"Retry-After": "Mon, 01 Jan 2024 12:05:00 GMT"}, want: now.Add(5 * time.Minute)},
+		{name: "reset header", status: 403, header: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": fmt.Sprint(now.Add(time.Hour).Unix())}, want: now.Add(time.Hour)},
+		{name: "secondary limit", status: 403, header: map[string]string{"Retry-After": "60"}, want: now.Add(time.Minute)},
+		{name: "no headers", status: 429, want: now.Add(defaultRateLimitWait)},
+		{name: "plain forbidden", status: 403},
+		{name: "server error", status: 503, header: map[string]string{"Retry-After": "5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: make(http.Header)}
+			for key, value := range tt.header {
+				resp.Header.Set(key, value)
+			}
+
+			limit := rateLimitFrom(resp, now)
+			if tt.want.IsZero() {
+				if limit != nil {
+					t.Errorf("Expected no rate limit, got %+v", limit)
+				}
+				return
+			}
+			if limit == nil || !limit.Reset.Equal(tt.want) {
+				t.Errorf("Expected reset %v, got %+v", tt.want, limit)
+			}
+		})
+	}
+}
+
+// TestLaterRateLimit verifies that the latest reset is kept
+func TestLaterRateLimit(t *testing.T) {
+	early := &RateLimitError{Reset: time.Unix(100, 0)}
+	late := &RateLimitError{Reset: time.Unix(200, 0)}
+
+	if got := laterRateLimit(nil, fmt.Errorf("wrapped: %w", early)); got != early {
+		t.Errorf("Expected wrapped rate limit, got %+v", got)
+	}
+	if got := laterRateLimit(early, late); got != late {
+		t.Errorf("Expected later reset, got %+v", got)
+	}
+	if got := laterRateLimit(late, early); got != late {
+		t.Errorf("Expected later reset to be kept, got %+v", got)
+	}
+	if got := laterRateLimit(late, errors.New("client error: HTTP 404")); got != late {
+		t.Errorf("Expected other errors to be ignored, got %+v", got)
+	}
+}
@@ -0,0 +1,168 @@
+package fetcher
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// robotsAgent is the product token matched against robots.txt User-agent lines
+const robotsAgent = "nats-docs-mcp-server"
+
+// robotsRules is what a site's robots.txt says to this client
+type robotsRules struct {
+	sitemaps []string     // Sitemap URLs, which apply to every agent
+	rules    []robotsRule // Allow and Disallow rules of the groups that apply
+}
+
+// robotsRule is a single Allow or Disallow line
+type robotsRule struct {
+	pattern string // Path prefix, with * wildcards and an optional trailing $ anchor
+	allow   bool
+}
+
+// robotsGroup is a run of User-agent lines and the rules that follow them
+type robotsGroup struct {
+	agents []string
+	rules  []robotsRule
+	closed bool // A rule line was seen, so the next User-agent line starts a new group
+}
+
+// fetchRobots fetches and parses the robots.txt at the root of the base URL's host.
+// A missing or unreadable robots.txt allows everything and lists no sitemaps.
+func (df *DocumentationFetcher) fetchRobots(ctx context.Context) robotsRules {
+	robotsURL, err := resolveURL(df.baseURL, "/robots.txt")
+	if err != nil {
+		return robotsRules{}
+	}
+
+	content, err := df.client.Fetch(ctx, robotsURL)
+	if err != nil {
+		df.logger.Debug().
+			Err(err).
+			Str("url", robotsURL).
+			Msg("No robots.txt, allowing all pages")
+		return robotsRules{}
+	}
+
+	robots := parseRobots(string(content))
+	for i, sitemap := range robots.sitemaps {
+		if resolved, err := resolveURL(robotsURL, sitemap); err == nil {
+			robots.sitemaps[i] = resolved
+		}
+	}
+
+	df.logger.Debug().
+		Str("url", robotsURL).
+		Int("sitemaps", len(robots.sitemaps)).
+		Int("rules", len(robots.rules)).
+		Msg("Parsed robots.txt")
+
+	return robots
+}
+
+// parseRobots parses robots.txt content. The rules of groups naming robotsAgent apply;
+// without such a group the rules of the "*" groups do.
+func parseRobots(content string) robotsRules {
+	var robots robotsRules
+	var groups []*robotsGroup
+	var current *robotsGroup
+
+	for _, line := range strings.Split(content, "\n") {
+		if comment := strings.IndexByte(line, '#'); comment >= 0 {
+			line = line[:comment]
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if current == nil || current.closed {
+				current = &robotsGroup{}
+				groups = append(groups, current)
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			if current == nil {
+				continue
+			}
+			current.closed = true
+			// An empty Disallow allows everything, and an empty Allow means nothing
+			if value != "" {
+				current.rules = append(current.rules, robotsRule{pattern: value, allow: key == "allow"})
+			}
+		case "sitemap":
+			if value != "" {
+				robots.sitemaps = append(robots.sitemaps, value)
+			}
+		}
+	}
+
+	var specific, wildcard []robotsRule
+	for _, group := range groups {
+		for _, agent := range group.agents {
+			if agent == robotsAgent {
+				specific = append(specific, group.rules...)
+				break
+			}
+			if agent == "*" {
+				wildcard = append(wildcard, group.rules...)
+				break
+			}
+		}
+	}
+	robots.rules = wildcard
+	if specific != nil {
+		robots.rules = specific
+	}
+	return robots
+}
+
+// allowed reports whether robots.txt allows fetching path. The longest matching rule
+// decides, Allow winning ties; a path no rule matches is allowed.
+func (r robotsRules) allowed(path string) bool {
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+
+	allowed := true
+	longest := -1
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > longest || (len(rule.pattern) == longest && rule.allow) {
+			allowed = rule.allow
+			longest = len(rule.pattern)
+		}
+	}
+	return allowed
+}
+
+// robotsMatch matches a path against a robots.txt rule pattern: a path prefix in which
+// * matches any characters and a trailing $ anchors the end of the path
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		// The last part of an anchored pattern must end the path
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+		index := strings.Index(rest, part)
+		if index < 0 {
+			return false
+		}
+		rest = rest[index+len(part):]
+	}
+	return !anchored || rest == ""
+}
@@ -0,0 +1,280 @@
+package fetcher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// defaultSitemapPaths are tried in order when robots.txt lists no sitemaps.
+// NATS uses /sitemap-pages.xml, Synadia uses /sitemap.xml.
+var defaultSitemapPaths = []string{"/sitemap-pages.xml", "/sitemap.xml", "/sitemap.xml.gz"}
+
+// errNoSitemap is returned when none of the default sitemap paths can be fetched
+var errNoSitemap = errors.New("no sitemap found")
+
+// maxSitemapDepth bounds how deeply sitemap indexes may nest
+const maxSitemapDepth = 4
+
+// maxSitemapSize is the largest uncompressed sitemap accepted, the limit set by the
+// sitemap protocol
+const maxSitemapSize = 50 << 20
+
+// sitemapDocument is either a <urlset> listing pages or a <sitemapindex> listing
+// further sitemaps
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []urlEntry     `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type urlEntry struct {
+	Loc      string `xml:"loc"`
+	Priority string `xml:"priority"`
+	LastMod  string `xml:"lastmod"`
+}
+
+type sitemapEntry struct {
+	Loc string `xml:"loc"`
+}
+
+// readDefaultSitemap reads the first of the default sitemap paths that can be fetched
+func (df *DocumentationFetcher) readDefaultSitemap(ctx context.Context) ([]SitemapEntry, error) {
+	var err error
+	for _, path := range defaultSitemapPaths {
+		var content []byte
+		content, err = df.fetchSitemap(ctx, df.baseURL+path)
+		if err != nil {
+			// Log and continue to next path on failure
+			df.logger.Debug().
+				Str("path", path).
+				Err(err).
+				Msg("Failed to fetch sitemap, trying next path")
+			continue
+		}
+
+		df.logger.Debug().
+			Str("path", path).
+			Msg("Successfully fetched sitemap")
+
+		var entries []SitemapEntry
+		if err := df.walkSitemap(ctx, df.baseURL+path, content, 0, make(map[string]bool), &entries); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}
+
+	return nil, fmt.Errorf("%w: tried %v, last error: %w", errNoSitemap, defaultSitemapPaths, err)
+}
+
+// readSitemaps reads every sitemap listed in robots.txt. Sitemaps that cannot be read
+// are skipped; an error is only returned when none can.
+func (df *DocumentationFetcher) readSitemaps(ctx context.Context, sitemapURLs []string) ([]SitemapEntry, error) {
+	var entries []SitemapEntry
+	var lastErr error
+	read := 0
+	visited := make(map[string]bool)
+	for _, sitemapURL := range sitemapURLs {
+		content, err := df.fetchSitemap(ctx, sitemapURL)
+		if err == nil {
+			err = df.walkSitemap(ctx, sitemapURL, content, 0, visited, &entries)
+		}
+		if err != nil {
+			df.logger.Warn().
+				Err(err).
+				Str("url", sitemapURL).
+				Msg("Failed to read sitemap listed in robots.txt, skipping")
+			lastErr = err
+			continue
+		}
+		read++
+	}
+
+	if read == 0 {
+		return nil, fmt.Errorf("failed to read any of the %d sitemaps listed in robots.txt: %w", len(sitemapURLs), lastErr)
+	}
+	return entries, nil
+}
+
+// walkSitemap appends the pages of a fetched sitemap to entries, following the nested
+// sitemaps of a sitemap index up to maxSitemapDepth. Nested sitemaps that cannot be
+// read are skipped.
+func (df *DocumentationFetcher) walkSitemap(ctx context.Context, sitemapURL string, content []byte, depth int, visited map[string]bool, entries *[]SitemapEntry) error {
+	visited[sitemapURL] = true
+
+	var sitemap sitemapDocument
+	if err := xml.Unmarshal(content, &sitemap); err != nil {
+		df.logger.Error().
+			Err(err).
+			Str("url", sitemapURL).
+			Msg("Failed to parse sitemap XML")
+		return fmt.Errorf("failed to parse sitemap XML: %w", err)
+	}
+
+	switch sitemap.XMLName.Local {
+	case "urlset":
+		base, err := url.Parse(df.baseURL)
+		if err != nil {
+			return fmt.Errorf("failed to parse base URL: %w", err)
+		}
+		for _, entry := range sitemap.URLs {
+			// Parse the URL to extract the path
+			resolved, err := resolveURL(sitemapURL, entry.Loc)
+			var parsedURL *url.URL
+			if err == nil {
+				parsedURL, err = url.Parse(resolved)
+			}
+			if err != nil {
+				df.logger.Warn().
+					Err(err).
+					Str("url", entry.Loc).
+					Msg("Failed to parse URL from sitemap, skipping")
+				continue
+			}
+
+			// Pages are fetched by joining their path onto the base URL, so pages on
+			// another origin (e.g. a CDN or a docs subdomain) are not the site's pages
+			path, ok := basePath(base, parsedURL)
+			if !ok {
+				df.logger.Debug().
+					Str("url", entry.Loc).
+					Str("base_url", df.baseURL).
+					Msg("Skipping sitemap URL outside the base URL")
+				continue
+			}
+
+			*entries = append(*entries, SitemapEntry{
+				Path:    path,
+				LastMod: strings.TrimSpace(entry.LastMod),
+			})
+		}
+		return nil
+
+	case "sitemapindex":
+		if depth >= maxSitemapDepth {
+			df.logger.Warn().
+				Str("url", sitemapURL).
+				Int("depth", depth).
+				Msg("Sitemap index nested too deeply, skipping its sitemaps")
+			return nil
+		}
+		for _, nested := range sitemap.Sitemaps {
+			nestedURL, err := resolveURL(sitemapURL, nested.Loc)
+			if err != nil || visited[nestedURL] {
+				continue
+			}
+			nestedContent, err := df.fetchSitemap(ctx, nestedURL)
+			if err == nil {
+				err = df.walkSitemap(ctx, nestedURL, nestedContent, depth+1, visited, entries)
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				df.logger.Warn().
+					Err(err).
+					Str("url", nestedURL).
+					Msg("Failed to read nested sitemap, skipping")
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("failed to parse sitemap XML: unexpected root element <%s>", sitemap.XMLName.Local)
+	}
+}
+
+// fetchSitemap fetches a sitemap by absolute URL, decompressing gzipped sitemaps.
+// Compression is detected from the content, so .xml.gz files are read whether or
+// not the server declares their encoding.
+func (df *DocumentationFetcher) fetchSitemap(ctx context.Context, sitemapURL string) ([]byte, error) {
+	df.logger.Debug().
+		Str("url", sitemapURL).
+		Msg("Fetching sitemap")
+
+	content, err := df.client.Fetch(ctx, sitemapURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sitemap %s: %w", sitemapURL, err)
+	}
+	if !isGzip(content) {
+		return content, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress sitemap %s: %w", sitemapURL, err)
+	}
+	defer reader.Close()
+
+	content, err = io.ReadAll(io.LimitReader(reader, maxSitemapSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress sitemap %s: %w", sitemapURL, err)
+	}
+	if len(content) > maxSitemapSize {
+		return nil, fmt.Errorf("sitemap %s exceeds %d bytes", sitemapURL, maxSitemapSize)
+	}
+	return content, nil
+}
+
+// isGzip reports whether content starts with the gzip magic number
+func isGzip(content []byte) bool {
+	return len(content) >= 2 && content[0] == 0x1f && content[1] == 0x8b
+}
+
+// resolveURL resolves a possibly relative reference against base
+func resolveURL(base, ref string) (string, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	refURL, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil {
+		return "", err
+	}
+	return baseURL.ResolveReference(refURL).String(), nil
+}
+
+// basePath returns the path of u relative to the path of base, e.g. "/a" for
+// "https://host/docs/a" under "https://host/docs", so joining it onto the base URL
+// yields u again. It fails for URLs with another scheme or host, or outside the base
+// URL's path.
+func basePath(base, u *url.URL) (string, bool) {
+	if !strings.EqualFold(u.Scheme, base.Scheme) || !strings.EqualFold(u.Host, base.Host) {
+		return "", false
+	}
+	prefix := strings.TrimSuffix(base.Path, "/")
+	switch {
+	case u.Path == prefix || u.Path == prefix+"/":
+		return "/", true
+	case strings.HasPrefix(u.Path, prefix+"/"):
+		return strings.TrimPrefix(u.Path, prefix), true
+	default:
+		return "", false
+	}
+}
+
+// sitePath returns the path from the site root of a page path relative to the base
+// URL, as robots.txt rules are written
+func (df *DocumentationFetcher) sitePath(pagePath string) string {
+	base, err := url.Parse(df.baseURL)
+	if err != nil {
+		return pagePath
+	}
+	return strings.TrimSuffix(base.Path, "/") + pagePath
+}
+
+// matchesPathFilters reports whether a page path passes the fetcher's include and
+// exclude patterns. Patterns are MatchGlob globs relative to the base URL.
+func (df *DocumentationFetcher) matchesPathFilters(path string) bool {
+	relative := strings.TrimPrefix(path, "/")
+	if len(df.include) > 0 && !matchAnyGlob(df.include, relative) {
+		return false
+	}
+	return !matchAnyGlob(df.exclude, relative)
+}
@@ -0,0 +1,214 @@
+package fetcher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// gzipped compresses content for serving .xml.gz sitemaps
+func gzipped(t *testing.T, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write([]byte(content)); err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// TestDocumentationFetcherDiscoverSitemapIndex verifies that robots.txt sitemaps,
+// nested and gzipped sitemap indexes, Disallow rules and path filters are applied
+func TestDocumentationFetcherDiscoverSitemapIndex(t *testing.T) {
+	var server *httptest.Server
+	files := map[string]string{}
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap-guides.xml.gz":
+			_, _ = w.Write(gzipped(t, files[r.URL.Path]))
+		default:
+			content, ok := files[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(content))
+		}
+	}))
+	defer server.Close()
+
+	files["/robots.txt"] = `# Docs site
+User-agent: *
+Disallow: /internal/
+Allow: /internal/public$
+
+User-agent: other-bot
+Disallow: /
+
+Sitemap: ` + server.URL + `/sitemap-index.xml
+`
+	files["/sitemap-index.xml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap><loc>` + server.URL + `/sitemap-pages.xml</loc></sitemap>
+	<sitemap><loc>/sitemap-nested.xml</loc></sitemap>
+	<sitemap><loc>` + server.URL + `/sitemap-missing.xml</loc></sitemap>
+</sitemapindex>`
+	files["/sitemap-pages.xml"] = `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>` + server.URL + `/</loc><lastmod>2024-01-01</lastmod></url>
+	<url><loc>` + server.URL + `/nats-concepts/overview</loc></url>
+	<url><loc>` + server.URL + `/internal/secret</loc></url>
+	<url><loc>` + server.URL + `/internal/public</loc></url>
+	<url><loc>` + server.URL + `/legacy/old-page</loc></url>
+</urlset>`
+	files["/sitemap-nested.xml"] = `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap><loc>` + server.URL + `/sitemap-guides.xml.gz</loc></sitemap>
+	<sitemap><loc>` + server.URL + `/sitemap-index.xml</loc></sitemap>
+</sitemapindex>`
+	files["/sitemap-guides.xml.gz"] = `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>` + server.URL + `/running-a-nats-service/introduction</loc></url>
+	<url><loc>` + server.URL + `/nats-concepts/overview</loc></url>
+</urlset>`
+
+	fetcher := NewDocumentationFetcher(NewHTTPClient(5*time.Second, 0, 5), server.URL, zerolog.Nop())
+	fetcher.exclude = []string{"legacy/**"}
+
+	entries, err := fetcher.DiscoverEntries(context.Background())
+	if err != nil {
+		t.Fatalf("Expected successful discovery, got error: %v", err)
+	}
+
+	want := []SitemapEntry{
+		{Path: "/", LastMod: "2024-01-01"},
+		{Path: "/nats-concepts/overview"},
+		{Path: "/internal/public"},
+		{Path: "/running-a-nats-service/introduction"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Unexpected entries:\n got: %+v\nwant: %+v", entries, want)
+	}
+
+	fetcher.exclude = nil
+	fetcher.include = []string{"nats-concepts/**"}
+	paths, err := fetcher.DiscoverPages(context.Background())
+	if err != nil {
+		t.Fatalf("Expected successful discovery, got error: %v", err)
+	}
+	if !reflect.DeepEqual(paths, []string{"/nats-concepts/overview"}) {
+		t.Errorf("Expected include patterns to restrict pages, got %v", paths)
+	}
+}
+
+// TestDocumentationFetcherDiscoverGzipDefaultSitemap verifies the gzipped default
+// sitemap is read when neither robots.txt nor the plain sitemaps exist
+func TestDocumentationFetcherDiscoverGzipDefaultSitemap(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sitemap.xml.gz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(gzipped(t, `<urlset><url><loc>`+server.URL+`/page</loc></url></urlset>`))
+	}))
+	defer server.Close()
+
+	fetcher := NewDocumentationFetcher(NewHTTPClient(5*time.Second, 0, 5), server.URL, zerolog.Nop())
+	paths, err := fetcher.DiscoverPages(context.Background())
+	if err != nil {
+		t.Fatalf("Expected successful discovery, got error: %v", err)
+	}
+	if !reflect.DeepEqual(paths, []string{"/page"}) {
+		t.Errorf("Expected the gzipped sitemap's page, got %v", paths)
+	}
+}
+
+// TestDocumentationFetcherDiscoverSkipsOtherOrigins verifies that sitemap URLs on
+// another scheme, host or outside the base URL's path are skipped, and that pages
+// are listed relative to a base URL with a path
+func TestDocumentationFetcherDiscoverSkipsOtherOrigins(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/docs/streams" {
+			_, _ = w.Write([]byte("<html><body>Streams</body></html>"))
+			return
+		}
+		if r.URL.Path != "/docs/sitemap-pages.xml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>` + server.URL + `/docs/streams</loc><lastmod>2024-01-01</lastmod></url>
+	<url><loc>https://cdn.example.com/docs/logo</loc></url>
+	<url><loc>https://` + server.Listener.Addr().String() + `/docs/tls</loc></url>
+	<url><loc>` + server.URL + `/blog/announcement</loc></url>
+	<url><loc>/docs/consumers</loc></url>
+</urlset>`))
+	}))
+	defer server.Close()
+
+	fetcher := NewDocumentationFetcher(NewHTTPClient(5*time.Second, 0, 5), server.URL+"/docs", zerolog.Nop())
+	entries, err := fetcher.DiscoverEntries(context.Background())
+	if err != nil {
+		t.Fatalf("Expected successful discovery, got error: %v", err)
+	}
+
+	want := []SitemapEntry{{Path: "/streams", LastMod: "2024-01-01"}, {Path: "/consumers"}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("Unexpected entries:\n got: %+v\nwant: %+v", entries, want)
+	}
+
+	// Entries are joined back onto the base URL when fetched
+	page, err := fetcher.fetchEntry(context.Background(), entries[0], Validator{})
+	if err != nil || string(page.Content) != "<html><body>Streams</body></html>" {
+		t.Errorf("Expected the page to be fetched from the base URL, got %q (err: %v)", page.Content, err)
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	robots := parseRobots(`User-agent: *
+Disallow: /private
+
+User-agent: Googlebot
+User-agent: nats-docs-mcp-server
+Disallow: /drafts/
+Disallow: /*.pdf$
+Allow: /drafts/published
+Disallow:
+
+Sitemap: https://docs.example.com/sitemap.xml
+`)
+
+	if want := []string{"https://docs.example.com/sitemap.xml"}; !reflect.DeepEqual(robots.sitemaps, want) {
+		t.Errorf("Expected sitemaps %v, got %v", want, robots.sitemaps)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/private/page", true}, // the "*" group does not apply when a specific one exists
+		{"/drafts/wip", false},
+		{"/drafts/published/page", true},
+		{"/guides/manual.pdf", false},
+		{"/guides/manual.pdf.html", true},
+		{"/", true},
+	}
+	for _, tt := range tests {
+		if got := robots.allowed(tt.path); got != tt.want {
+			t.Errorf("allowed(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	if parseRobots("User-agent: *\nDisallow: /private\n").allowed("/private/page") {
+		t.Error("Expected the \"*\" group to apply without a specific group")
+	}
+}
@@ -0,0 +1,112 @@
+package fetcher
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// TransportConfig configures how a documentation source connects to its hosts, e.g.
+// through a corporate proxy that intercepts TLS with a private CA
+type TransportConfig struct {
+	ProxyURL      string   // Proxy for http and https requests; the HTTP_PROXY/HTTPS_PROXY environment when empty
+	NoProxy       []string // Hosts, domains (.example.com), IPs and CIDRs reached without the proxy
+	CAFiles       []string // PEM files of CAs trusted in addition to the system roots
+	ClientCert    string   // PEM certificate file presented for mutual TLS
+	ClientKey     string   // PEM private key file of ClientCert
+	TLSMinVersion string   // Minimum TLS version: 1.0, 1.1, 1.2 or 1.3 (default: Go's default)
+}
+
+// IsZero reports whether no transport setting is configured, in which case the
+// default transport is used
+func (tc TransportConfig) IsZero() bool {
+	return tc.ProxyURL == "" && len(tc.NoProxy) == 0 && len(tc.CAFiles) == 0 &&
+		tc.ClientCert == "" && tc.ClientKey == "" && tc.TLSMinVersion == ""
+}
+
+// tlsVersions maps TransportConfig.TLSMinVersion values to TLS versions
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// NewTransport builds an HTTP transport from the default one with the proxy, CA
+// bundle, client certificate and minimum TLS version of tc applied.
+//
+// Returns an error when the proxy URL is invalid, a CA file holds no certificates,
+// the client key pair cannot be loaded or the TLS version is unknown.
+func NewTransport(tc TransportConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if tc.ProxyURL != "" || len(tc.NoProxy) > 0 {
+		proxy := httpproxy.FromEnvironment()
+		if tc.ProxyURL != "" {
+			proxyURL, err := url.Parse(tc.ProxyURL)
+			if err != nil || proxyURL.Host == "" {
+				return nil, fmt.Errorf("invalid proxy URL %q", tc.ProxyURL)
+			}
+			switch proxyURL.Scheme {
+			case "http", "https", "socks5":
+			default:
+				return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+			}
+			proxy.HTTPProxy = tc.ProxyURL
+			proxy.HTTPSProxy = tc.ProxyURL
+		}
+		if len(tc.NoProxy) > 0 {
+			proxy.NoProxy = strings.Join(tc.NoProxy, ",")
+		}
+		proxyFunc := proxy.ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	tlsConfig := &tls.Config{}
+	if len(tc.CAFiles) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		for _, file := range tc.CAFiles {
+			pem, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no PEM certificates found in CA file %s", file)
+			}
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if tc.ClientCert != "" || tc.ClientKey != "" {
+		if tc.ClientCert == "" || tc.ClientKey == "" {
+			return nil, fmt.Errorf("client certificate and key must be configured together")
+		}
+		cert, err := tls.LoadX509KeyPair(tc.ClientCert, tc.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if tc.TLSMinVersion != "" {
+		version, ok := tlsVersions[tc.TLSMinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS version %q, expected 1.0, 1.1, 1.2 or 1.3", tc.TLSMinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
@@ -0,0 +1,228 @@
+package fetcher
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// writeClientCert creates a self-signed client certificate and key in dir and
+// returns their file names and the parsed certificate
+func writeClientCert(t *testing.T, dir string) (string, string, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "docs-fetcher"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile, cert
+}
+
+func writePEM(t *testing.T, file, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", file, err)
+	}
+}
+
+// TestNewTransportMutualTLS verifies that a private CA and a client certificate let
+// the client reach a server requiring both
+func TestNewTransportMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCert := writeClientCert(t, dir)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("internal docs"))
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(dir, "ca.pem")
+	writePEM(t, caFile, "CERTIFICATE", server.Certificate().Raw)
+
+	base := NewHTTPClient(5*time.Second, 0, 5)
+	ctx := context.Background()
+
+	// The server's CA is private, so the default transport rejects it
+	if _, err := base.Fetch(ctx, server.URL); err == nil {
+		t.Error("Expected the default transport to reject the private CA")
+	}
+
+	transport, err := NewTransport(TransportConfig{CAFiles: []string{caFile}})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	if _, err := base.WithTransport(transport).Fetch(ctx, server.URL); err == nil {
+		t.Error("Expected the server to require a client certificate")
+	}
+
+	transport, err = NewTransport(TransportConfig{
+		CAFiles:       []string{caFile},
+		ClientCert:    certFile,
+		ClientKey:     keyFile,
+		TLSMinVersion: "1.2",
+	})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	client := base.WithTransport(transport)
+	body, err := client.Fetch(ctx, server.URL)
+	if err != nil {
+		t.Fatalf("Expected mutual TLS fetch to succeed, got: %v", err)
+	}
+	if string(body) != "internal docs" {
+		t.Errorf("Unexpected body: %q", body)
+	}
+
+	// Derived clients share the request limits of the client they come from
+	if client.inFlight != base.inFlight || client.rateLimiter != base.rateLimiter || client.hostMu != base.hostMu {
+		t.Error("Expected WithTransport to share request limits")
+	}
+}
+
+func TestNewTransportMinVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	writePEM(t, caFile, "CERTIFICATE", server.Certificate().Raw)
+
+	transport, err := NewTransport(TransportConfig{CAFiles: []string{caFile}, TLSMinVersion: "1.3"})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	if _, err := NewHTTPClient(5*time.Second, 0, 5).WithTransport(transport).Fetch(context.Background(), server.URL); err == nil {
+		t.Error("Expected a TLS 1.2 server to be rejected with a 1.3 minimum")
+	}
+}
+
+func TestNewTransportProxy(t *testing.T) {
+	transport, err := NewTransport(TransportConfig{
+		ProxyURL: "http://proxy.corp.example:3128",
+		NoProxy:  []string{".internal.example", "10.0.0.0/8"},
+	})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"https://docs.nats.io/nats-concepts/overview": "http://proxy.corp.example:3128",
+		"https://wiki.internal.example/page":          "",
+		"http://10.1.2.3/docs":                        "",
+	}
+	for target, want := range tests {
+		req, err := http.NewRequest(http.MethodGet, target, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		proxy, err := transport.Proxy(req)
+		if err != nil {
+			t.Fatalf("Proxy(%s) failed: %v", target, err)
+		}
+		got := ""
+		if proxy != nil {
+			got = proxy.String()
+		}
+		if got != want {
+			t.Errorf("Proxy(%s) = %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestNewTransportErrors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeClientCert(t, dir)
+	notPEM := filepath.Join(dir, "not-a-ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	invalid := map[string]TransportConfig{
+		"proxy scheme":     {ProxyURL: "ftp://proxy.example"},
+		"proxy host":       {ProxyURL: "http://"},
+		"missing CA":       {CAFiles: []string{filepath.Join(dir, "missing.pem")}},
+		"CA without certs": {CAFiles: []string{notPEM}},
+		"cert without key": {ClientCert: certFile},
+		"mismatched pair":  {ClientCert: keyFile, ClientKey: certFile},
+		"TLS version":      {TLSMinVersion: "1.4"},
+	}
+	for name, tc := range invalid {
+		if _, err := NewTransport(tc); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if !(TransportConfig{}).IsZero() || (TransportConfig{TLSMinVersion: "1.2"}).IsZero() {
+		t.Error("IsZero reported the wrong result")
+	}
+
+	// A source whose transport cannot be created fails the fetcher instead of
+	// falling back to the default transport
+	broken := TransportConfig{CAFiles: []string{notPEM}}
+	sources := map[string]func() (*MultiSourceFetcher, error){
+		"NATS": func() (*MultiSourceFetcher, error) {
+			return NewMultiSourceFetcher(FetchConfig{Transport: broken}, FetchConfig{}, GitHubFetchConfig{}, zerolog.Nop())
+		},
+		"Synadia": func() (*MultiSourceFetcher, error) {
+			return NewMultiSourceFetcher(FetchConfig{}, FetchConfig{Transport: broken}, GitHubFetchConfig{}, zerolog.Nop())
+		},
+		"GitHub": func() (*MultiSourceFetcher, error) {
+			return NewMultiSourceFetcher(FetchConfig{}, FetchConfig{}, GitHubFetchConfig{Transport: broken}, zerolog.Nop())
+		},
+	}
+	for source, create := range sources {
+		if msf, err := create(); err == nil || msf != nil {
+			t.Errorf("%s: expected the fetcher to fail, got %v", source, err)
+		} else if !strings.Contains(err.Error(), source) {
+			t.Errorf("%s: expected the error to name the source, got %v", source, err)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package fetcher
+
+// Validator records what is needed to tell whether a previously fetched page or file
+// changed: the HTTP ETag and Last-Modified response headers, the sitemap lastmod of a
+// documentation page, and the blob SHA of a GitHub file.
+type Validator struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	LastMod      string `json:"lastmod,omitempty"`
+	SHA          string `json:"sha,omitempty"`
+}
+
+// IsZero reports whether no validator was recorded
+func (v Validator) IsZero() bool {
+	return v == Validator{}
+}
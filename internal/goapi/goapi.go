@@ -0,0 +1,335 @@
+// Package goapi builds a Go API reference from local Go source trees (for example a
+// checked-out or vendored nats.go) using go/parser and go/doc. Every exported type,
+// function and method becomes a document with its signature, doc comment, examples
+// and a pkg.go.dev URL.
+package goapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// Metadata keys stored on index.Document for Go API documents
+const (
+	MetaImportPath = "go_import_path" // Import path of the package (e.g., "github.com/nats-io/nats.go")
+	MetaPackage    = "go_package"     // Package name (e.g., "nats")
+	MetaSymbol     = "go_symbol"      // Symbol name (e.g., "Conn.Publish")
+	MetaKind       = "go_kind"        // "type", "func" or "method"
+)
+
+// DocIDPrefix is the prefix of all Go API document IDs
+const DocIDPrefix = "go-api/"
+
+// pkgGoDevURL is the base URL of the Go package documentation site
+const pkgGoDevURL = "https://pkg.go.dev/"
+
+// Symbol is an exported Go API element
+type Symbol struct {
+	ImportPath string    // Import path of the declaring package
+	Package    string    // Package name
+	Name       string    // Symbol name; methods are qualified by receiver (e.g., "Conn.Publish")
+	Kind       string    // "type", "func" or "method"
+	Signature  string    // Declaration without body
+	Doc        string    // Doc comment
+	Examples   []Example // Examples from _test.go files
+}
+
+// Example is a runnable example attached to a symbol
+type Example struct {
+	Name   string // Example suffix (e.g., "" or "withTimeout")
+	Code   string // Example body
+	Output string // Expected output, if any
+}
+
+// LoadModule parses every package below root and returns its exported symbols.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - root: Local directory of the module or vendored package tree
+//   - importPath: Import path of root; when empty it is read from root/go.mod
+//
+// Returns the exported symbols sorted by import path and name.
+func LoadModule(ctx context.Context, root string, importPath string) ([]*Symbol, error) {
+	if importPath == "" {
+		modulePath, err := readModulePath(filepath.Join(root, "go.mod"))
+		if err != nil {
+			return nil, fmt.Errorf("import path not configured and %w", err)
+		}
+		importPath = modulePath
+	}
+
+	var symbols []*Symbol
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		name := d.Name()
+		if p != root && skipDir(name) {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		pkgImportPath := importPath
+		if rel != "." {
+			pkgImportPath = path.Join(importPath, filepath.ToSlash(rel))
+		}
+
+		pkgSymbols, err := loadPackage(p, pkgImportPath)
+		if err != nil {
+			return fmt.Errorf("failed to load package %s: %w", pkgImportPath, err)
+		}
+		symbols = append(symbols, pkgSymbols...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].ImportPath != symbols[j].ImportPath {
+			return symbols[i].ImportPath < symbols[j].ImportPath
+		}
+		return symbols[i].Name < symbols[j].Name
+	})
+
+	return symbols, nil
+}
+
+// skipDir reports whether a directory holds no public API (tests data, internal
+// packages, vendored code or hidden directories)
+func skipDir(name string) bool {
+	return name == "testdata" || name == "internal" || name == "vendor" ||
+		strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// readModulePath reads the module path from a go.mod file
+func readModulePath(goModPath string) (string, error) {
+	f, err := os.Open(goModPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "module") {
+			modulePath := strings.TrimSpace(strings.TrimPrefix(line, "module"))
+			modulePath = strings.Trim(modulePath, "\"`")
+			if modulePath != "" {
+				return modulePath, nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	return "", fmt.Errorf("no module directive in %s", goModPath)
+}
+
+// loadPackage parses the Go files of a single directory and extracts its exported
+// symbols. Directories without a non-main package yield no symbols.
+func loadPackage(dir string, importPath string) ([]*Symbol, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		// Respect build constraints so platform variants don't duplicate symbols
+		if match, err := build.Default.MatchFile(dir, name); err != nil || !match {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	pkg, err := doc.NewFromFiles(fset, files, importPath)
+	if err != nil {
+		return nil, err
+	}
+	if pkg.Name == "main" || strings.HasSuffix(pkg.Name, "_test") {
+		return nil, nil
+	}
+
+	var symbols []*Symbol
+	newSymbol := func(name, kind string, decl ast.Node, docText string, examples []*doc.Example) {
+		symbols = append(symbols, &Symbol{
+			ImportPath: importPath,
+			Package:    pkg.Name,
+			Name:       name,
+			Kind:       kind,
+			Signature:  formatDecl(fset, decl),
+			Doc:        strings.TrimSpace(docText),
+			Examples:   convertExamples(fset, examples),
+		})
+	}
+
+	for _, fn := range pkg.Funcs {
+		newSymbol(fn.Name, "func", fn.Decl, fn.Doc, fn.Examples)
+	}
+	for _, typ := range pkg.Types {
+		newSymbol(typ.Name, "type", typ.Decl, typ.Doc, typ.Examples)
+		// Constructors and factories are associated with the type they return
+		for _, fn := range typ.Funcs {
+			newSymbol(fn.Name, "func", fn.Decl, fn.Doc, fn.Examples)
+		}
+		for _, method := range typ.Methods {
+			newSymbol(typ.Name+"."+method.Name, "method", method.Decl, method.Doc, method.Examples)
+		}
+	}
+
+	return symbols, nil
+}
+
+// formatDecl prints a declaration without its doc comment or function body
+func formatDecl(fset *token.FileSet, decl ast.Node) string {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		copied := *d
+		copied.Doc = nil
+		copied.Body = nil
+		decl = &copied
+	case *ast.GenDecl:
+		copied := *d
+		copied.Doc = nil
+		decl = &copied
+	}
+
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&buf, fset, decl); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// convertExamples renders doc examples as source code
+func convertExamples(fset *token.FileSet, examples []*doc.Example) []Example {
+	result := make([]Example, 0, len(examples))
+	for _, ex := range examples {
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, ex.Code); err != nil {
+			continue
+		}
+		result = append(result, Example{
+			Name:   ex.Suffix,
+			Code:   unwrapBlock(buf.String()),
+			Output: strings.TrimSpace(ex.Output),
+		})
+	}
+	return result
+}
+
+// unwrapBlock strips the braces of a printed block statement and removes one level
+// of indentation from its body
+func unwrapBlock(code string) string {
+	code = strings.TrimSpace(code)
+	if !strings.HasPrefix(code, "{") || !strings.HasSuffix(code, "}") {
+		return code
+	}
+	code = strings.Trim(code[1:len(code)-1], "\n")
+
+	lines := strings.Split(code, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "\t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// URL returns the pkg.go.dev URL of the symbol
+func (s *Symbol) URL() string {
+	return pkgGoDevURL + s.ImportPath + "#" + s.Name
+}
+
+// Document converts the symbol into a searchable document with signature,
+// documentation and example sections.
+func (s *Symbol) Document() *index.Document {
+	qualified := s.Package + "." + s.Name
+
+	sections := []index.Section{
+		{Heading: "Signature", Content: "```go\n" + s.Signature + "\n```", Level: 2},
+	}
+	if s.Doc != "" {
+		sections = append(sections, index.Section{Heading: "Documentation", Content: s.Doc, Level: 2})
+	}
+	for _, ex := range s.Examples {
+		heading := "Example"
+		if ex.Name != "" {
+			heading += " (" + ex.Name + ")"
+		}
+		content := "```go\n" + ex.Code + "\n```"
+		if ex.Output != "" {
+			content += "\n\nOutput:\n" + ex.Output
+		}
+		sections = append(sections, index.Section{
+			Heading:    heading,
+			Content:    content,
+			Level:      2,
+			CodeBlocks: []index.CodeBlock{{Language: "go", Content: ex.Code}},
+		})
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("%s %s (%s)\n", s.Kind, qualified, s.ImportPath))
+	content.WriteString(s.Signature)
+	content.WriteString("\n")
+	content.WriteString(s.Doc)
+
+	return &index.Document{
+		ID:          DocIDPrefix + s.ImportPath + "#" + s.Name,
+		Title:       qualified,
+		URL:         s.URL(),
+		Content:     content.String(),
+		Sections:    sections,
+		LastUpdated: time.Now(),
+		Metadata: map[string]string{
+			MetaImportPath: s.ImportPath,
+			MetaPackage:    s.Package,
+			MetaSymbol:     s.Name,
+			MetaKind:       s.Kind,
+		},
+	}
+}
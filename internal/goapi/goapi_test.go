@@ -0,0 +1,196 @@
+package goapi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testGoMod = "module example.com/natsgo\n\ngo 1.22\n"
+
+const testSource = `// Package nats is a test client.
+package nats
+
+// Conn represents a connection to a server.
+type Conn struct {
+	url string
+}
+
+// Connect creates a connection to the given URL.
+func Connect(url string) (*Conn, error) {
+	return &Conn{url: url}, nil
+}
+
+// Publish publishes data to the given subject.
+func (nc *Conn) Publish(subj string, data []byte) error {
+	return nil
+}
+
+// unexported helpers are not part of the API
+func helper() {}
+`
+
+const testExample = `package nats_test
+
+import (
+	"fmt"
+
+	"example.com/natsgo"
+)
+
+func ExampleConn_Publish() {
+	nc, _ := nats.Connect("nats://localhost:4222")
+	nc.Publish("foo", []byte("hello"))
+	fmt.Println("published")
+	// Output: published
+}
+`
+
+// writeTestModule writes a small module with a root package, a subpackage, an
+// internal package and a main package
+func writeTestModule(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":               testGoMod,
+		"nats.go":              testSource,
+		"example_test.go":      testExample,
+		"micro/service.go":     "package micro\n\n// Service is a micro service.\ntype Service interface{ Stop() error }\n",
+		"internal/x/x.go":      "package x\n\n// Hidden is internal.\nfunc Hidden() {}\n",
+		"examples/pub/main.go": "package main\n\nfunc Main() {}\n\nfunc main() {}\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return root
+}
+
+func findSymbol(symbols []*Symbol, importPath, name string) *Symbol {
+	for _, s := range symbols {
+		if s.ImportPath == importPath && s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func TestLoadModule(t *testing.T) {
+	root := writeTestModule(t)
+
+	symbols, err := LoadModule(context.Background(), root, "")
+	if err != nil {
+		t.Fatalf("LoadModule failed: %v", err)
+	}
+
+	if len(symbols) != 4 {
+		for _, s := range symbols {
+			t.Logf("symbol: %s %s", s.ImportPath, s.Name)
+		}
+		t.Fatalf("expected 4 symbols, got %d", len(symbols))
+	}
+
+	conn := findSymbol(symbols, "example.com/natsgo", "Conn")
+	if conn == nil || conn.Kind != "type" || conn.Package != "nats" {
+		t.Fatalf("unexpected Conn symbol: %+v", conn)
+	}
+	if conn.Doc != "Conn represents a connection to a server." {
+		t.Errorf("unexpected doc: %q", conn.Doc)
+	}
+
+	connect := findSymbol(symbols, "example.com/natsgo", "Connect")
+	if connect == nil || connect.Kind != "func" {
+		t.Fatalf("unexpected Connect symbol: %+v", connect)
+	}
+	if connect.Signature != "func Connect(url string) (*Conn, error)" {
+		t.Errorf("unexpected signature: %q", connect.Signature)
+	}
+
+	publish := findSymbol(symbols, "example.com/natsgo", "Conn.Publish")
+	if publish == nil || publish.Kind != "method" {
+		t.Fatalf("unexpected Conn.Publish symbol: %+v", publish)
+	}
+	if len(publish.Examples) != 1 {
+		t.Fatalf("expected 1 example, got %d", len(publish.Examples))
+	}
+	if !strings.HasPrefix(publish.Examples[0].Code, "nc, _ := nats.Connect(") || publish.Examples[0].Output != "published" {
+		t.Errorf("unexpected example: %+v", publish.Examples[0])
+	}
+
+	if findSymbol(symbols, "example.com/natsgo/micro", "Service") == nil {
+		t.Error("expected subpackage symbol micro.Service")
+	}
+}
+
+func TestLoadModuleExplicitImportPath(t *testing.T) {
+	root := writeTestModule(t)
+	if err := os.Remove(filepath.Join(root, "go.mod")); err != nil {
+		t.Fatalf("failed to remove go.mod: %v", err)
+	}
+
+	if _, err := LoadModule(context.Background(), root, ""); err == nil {
+		t.Error("expected error without go.mod or import path")
+	}
+
+	symbols, err := LoadModule(context.Background(), root, "github.com/nats-io/nats.go")
+	if err != nil {
+		t.Fatalf("LoadModule failed: %v", err)
+	}
+	if findSymbol(symbols, "github.com/nats-io/nats.go", "Connect") == nil {
+		t.Error("expected symbols under the configured import path")
+	}
+}
+
+func TestLoadModuleCancelled(t *testing.T) {
+	root := writeTestModule(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := LoadModule(ctx, root, ""); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
+
+func TestSymbolDocument(t *testing.T) {
+	s := &Symbol{
+		ImportPath: "github.com/nats-io/nats.go",
+		Package:    "nats",
+		Name:       "Conn.Publish",
+		Kind:       "method",
+		Signature:  "func (nc *Conn) Publish(subj string, data []byte) error",
+		Doc:        "Publish publishes the data argument to the given subject.",
+		Examples:   []Example{{Name: "headers", Code: "nc.Publish(\"foo\", nil)", Output: "ok"}},
+	}
+
+	doc := s.Document()
+	if doc.ID != "go-api/github.com/nats-io/nats.go#Conn.Publish" {
+		t.Errorf("unexpected ID: %s", doc.ID)
+	}
+	if doc.URL != "https://pkg.go.dev/github.com/nats-io/nats.go#Conn.Publish" {
+		t.Errorf("unexpected URL: %s", doc.URL)
+	}
+	if doc.Title != "nats.Conn.Publish" {
+		t.Errorf("unexpected title: %s", doc.Title)
+	}
+	if doc.Metadata[MetaKind] != "method" || doc.Metadata[MetaSymbol] != "Conn.Publish" {
+		t.Errorf("unexpected metadata: %v", doc.Metadata)
+	}
+
+	if len(doc.Sections) != 3 {
+		t.Fatalf("expected 3 sections, got %d", len(doc.Sections))
+	}
+	if doc.Sections[2].Heading != "Example (headers)" || !strings.Contains(doc.Sections[2].Content, "Output:\nok") {
+		t.Errorf("unexpected example section: %+v", doc.Sections[2])
+	}
+	if !doc.HasCodeLanguage("go") || doc.Sections[2].CodeBlocks[0].Content != "nc.Publish(\"foo\", nil)" {
+		t.Errorf("expected the example as a Go code block: %+v", doc.Sections[2].CodeBlocks)
+	}
+}
@@ -20,13 +20,92 @@ type Document struct {
 	Content     string    `json:"content"`       // Full text content
 	Sections    []Section `json:"sections"`      // Subsections within the document
 	LastUpdated time.Time `json:"last_updated"` // When the document was last fetched/updated
+
+	// Metadata holds source-specific structured attributes (e.g., an API subject
+	// or schema type) that lookup tools use to resolve documents without searching.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
+// MetaVersion is the metadata key holding the version (branch or tag) a document
+// was indexed from. Documents without it are unversioned.
+const MetaVersion = "version"
+
+// MetaRepository is the metadata key holding the display name of the repository a
+// GitHub document was indexed from
+const MetaRepository = "repository"
+
+// SearchFilter reports whether a document may appear in search results
+type SearchFilter func(doc *Document) bool
+
 // Section represents a subsection within a document with its own heading and content.
 type Section struct {
-	Heading string `json:"heading"` // Section heading text
-	Content string `json:"content"` // Section content
-	Level   int    `json:"level"`   // Heading level (1-6 for h1-h6)
+	Heading    string      `json:"heading"`               // Section heading text
+	Content    string      `json:"content"`               // Section content, with code blocks as Markdown fences and tables as Markdown tables
+	Level      int         `json:"level"`                 // Heading level (1-6 for h1-h6)
+	CodeBlocks []CodeBlock `json:"code_blocks,omitempty"` // Code blocks of the section, in order
+	Tables     []Table     `json:"tables,omitempty"`      // Tables of the section, in order
+}
+
+// CodeBlock is a block of code within a section
+type CodeBlock struct {
+	Language string `json:"language,omitempty"` // Normalised language (e.g. "go"); empty when unknown
+	Content  string `json:"content"`            // Code
+	Caption  string `json:"caption,omitempty"`  // Title of the block, e.g. a file name
+}
+
+// Table is a table within a section. Its first column is the key column, e.g. the
+// option name of a configuration table.
+type Table struct {
+	Caption string     `json:"caption,omitempty"` // Caption of the table
+	Header  []string   `json:"header"`            // Column headings
+	Rows    [][]string `json:"rows,omitempty"`    // Body rows, each with one cell per column
+}
+
+// TableRow is a row of a section table together with the headings it belongs to
+type TableRow struct {
+	Heading string   // Heading of the section holding the table
+	Header  []string // Column headings of the table
+	Cells   []string // Cells of the row
+}
+
+// FindTableRows returns the table rows whose key column matches query or one of its
+// words, ignoring case and the backticks or quotes around the key
+func (d *Document) FindTableRows(query string) []TableRow {
+	keys := map[string]bool{tableKey(query): true}
+	for _, word := range strings.Fields(query) {
+		keys[tableKey(word)] = true
+	}
+	delete(keys, "")
+
+	var rows []TableRow
+	for _, section := range d.Sections {
+		for _, table := range section.Tables {
+			for _, row := range table.Rows {
+				if len(row) > 0 && keys[tableKey(row[0])] {
+					rows = append(rows, TableRow{Heading: section.Heading, Header: table.Header, Cells: row})
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// tableKey normalises a key cell or query word for FindTableRows
+func tableKey(text string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(text), "`'\"*"))
+}
+
+// HasCodeLanguage reports whether a section of the document has a code block in
+// language, a normalised language name such as "go"
+func (d *Document) HasCodeLanguage(language string) bool {
+	for _, section := range d.Sections {
+		for _, block := range section.CodeBlocks {
+			if block.Language == language {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // DocumentStore provides thread-safe storage for documents with concurrent read access.
@@ -349,6 +428,12 @@ func (di *DocumentationIndex) Get(id string) (*Document, error) {
 // Search performs a full-text search and returns ranked results.
 // The query is tokenized and matched against indexed documents using TF-IDF scoring.
 func (di *DocumentationIndex) Search(query string, limit int) ([]SearchResult, error) {
+	return di.SearchFiltered(query, limit, nil)
+}
+
+// SearchFiltered performs a full-text search like Search, considering only documents
+// accepted by filter. The limit applies after filtering. A nil filter accepts all documents.
+func (di *DocumentationIndex) SearchFiltered(query string, limit int, filter SearchFilter) ([]SearchResult, error) {
 	if query == "" {
 		return nil, fmt.Errorf("search query cannot be empty")
 	}
@@ -369,6 +454,10 @@ func (di *DocumentationIndex) Search(query string, limit int) ([]SearchResult, e
 	queryTerms := tokenize(query)
 
 	for _, doc := range docs {
+		if filter != nil && !filter(doc) {
+			continue
+		}
+
 		// Check if document contains any query terms
 		hasMatch := false
 		for _, term := range queryTerms {
@@ -419,6 +508,15 @@ func (di *DocumentationIndex) Count() int {
 	return di.store.Count()
 }
 
+// Clear removes every document from the index.
+func (di *DocumentationIndex) Clear() {
+	di.mu.Lock()
+	defer di.mu.Unlock()
+
+	di.store = NewDocumentStore()
+	di.searchIndex = NewSearchIndex()
+}
+
 // ExportDocuments returns all documents in the index for caching purposes.
 // This exports the raw documents without the search index structure.
 func (di *DocumentationIndex) ExportDocuments() []*Document {
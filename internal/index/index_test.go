@@ -483,6 +483,53 @@ func TestDocumentationIndexSearchLimit(t *testing.T) {
 	}
 }
 
+func TestDocumentationIndexSearchFiltered(t *testing.T) {
+	idx := NewDocumentationIndex()
+
+	for i := 0; i < 10; i++ {
+		version := "main"
+		if i%2 == 1 {
+			version = "v2.10.0"
+		}
+		doc := &Document{
+			ID:       fmt.Sprintf("doc%d", i),
+			Title:    fmt.Sprintf("Document %d", i),
+			Content:  "NATS messaging system",
+			Metadata: map[string]string{MetaVersion: version},
+		}
+		if err := idx.Index(doc); err != nil {
+			t.Fatalf("Failed to index document: %v", err)
+		}
+	}
+
+	results, err := idx.SearchFiltered("NATS", 3, func(doc *Document) bool {
+		return doc.Metadata[MetaVersion] == "v2.10.0"
+	})
+	if err != nil {
+		t.Fatalf("SearchFiltered failed: %v", err)
+	}
+
+	// The limit applies after filtering
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	for _, result := range results {
+		doc, _ := idx.Get(result.DocumentID)
+		if doc.Metadata[MetaVersion] != "v2.10.0" {
+			t.Errorf("Filtered search returned %s at version %s", result.DocumentID, doc.Metadata[MetaVersion])
+		}
+	}
+
+	// A nil filter behaves like Search
+	all, err := idx.SearchFiltered("NATS", 0, nil)
+	if err != nil {
+		t.Fatalf("SearchFiltered failed: %v", err)
+	}
+	if len(all) != 10 {
+		t.Errorf("Expected 10 results without a filter, got %d", len(all))
+	}
+}
+
 func TestDocumentationIndexCaseInsensitiveSearch(t *testing.T) {
 	idx := NewDocumentationIndex()
 
@@ -732,3 +779,24 @@ func TestDocumentationIndexNoMatchingDocuments(t *testing.T) {
 		t.Errorf("Expected no results for non-matching query, got %d", len(results))
 	}
 }
+
+func TestDocumentFindTableRows(t *testing.T) {
+	doc := &Document{
+		ID: "limits",
+		Sections: []Section{{
+			Heading: "Limits",
+			Tables: []Table{{
+				Header: []string{"Property", "Default"},
+				Rows:   [][]string{{"`max_payload`", "1MB"}, {"max_pending", "64MB"}},
+			}},
+		}},
+	}
+
+	rows := doc.FindTableRows("MAX_PAYLOAD default")
+	if len(rows) != 1 || rows[0].Heading != "Limits" || rows[0].Cells[1] != "1MB" || rows[0].Header[1] != "Default" {
+		t.Errorf("expected the max_payload row, got %+v", rows)
+	}
+	if rows := doc.FindTableRows("1MB"); len(rows) != 0 {
+		t.Errorf("expected only the key column to match, got %+v", rows)
+	}
+}
@@ -137,6 +137,28 @@ func (m *Manager) Stats() IndexStats {
 	}
 }
 
+// ResetNATS clears the NATS index in place, e.g. to drop the batches of a fetch that
+// failed. Unlike Reset, holders of the index returned by GetNATSIndex see the change.
+func (m *Manager) ResetNATS() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.natsIndex.Clear()
+}
+
+// ResetSynadia clears the syncp index in place
+func (m *Manager) ResetSynadia() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.syadiaIndex.Clear()
+}
+
+// ResetGitHub clears the GitHub index in place
+func (m *Manager) ResetGitHub() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.githubIndex.Clear()
+}
+
 // Reset clears all indices (useful for testing and refresh)
 func (m *Manager) Reset() {
 	m.mu.Lock()
@@ -412,3 +412,26 @@ func TestProperty_ParserConsistency_Placeholder(t *testing.T) {
 	// Parser consistency will be validated when both NATS and Synadia
 	// documents are parsed with the same logic
 }
+
+func TestResetSource_ClearsInPlace(t *testing.T) {
+	manager := NewManager()
+	natsIndex := manager.GetNATSIndex()
+
+	manager.IndexNATS([]*Document{{ID: "nats-001", Title: "NATS", Content: "content"}})
+	manager.IndexGitHub([]*Document{{ID: "github-001", Title: "GitHub", Content: "content"}})
+
+	manager.ResetNATS()
+
+	if natsIndex.Count() != 0 {
+		t.Error("NATS index held before the reset should be empty")
+	}
+	if results, _ := natsIndex.Search("content", 10); len(results) != 0 {
+		t.Errorf("NATS index should not find cleared documents, got %d", len(results))
+	}
+	if manager.GetNATSIndex() != natsIndex {
+		t.Error("ResetNATS should keep the same index instance")
+	}
+	if manager.GetGitHubIndex().Count() != 1 {
+		t.Error("ResetNATS should not clear the GitHub index")
+	}
+}
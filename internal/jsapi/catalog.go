@@ -0,0 +1,158 @@
+package jsapi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// Catalog resolves JetStream API subjects and schema type names to schema documents.
+// It is built from index documents so it can be restored from the documentation cache,
+// and is immutable once created so it is safe for concurrent use.
+type Catalog struct {
+	docs      []*index.Document
+	bySubject map[string][]*index.Document
+}
+
+// NewCatalog creates a catalog from schema documents. Documents without JetStream
+// API metadata are ignored.
+func NewCatalog(docs []*index.Document) *Catalog {
+	c := &Catalog{
+		bySubject: make(map[string][]*index.Document),
+	}
+
+	for _, doc := range docs {
+		if doc == nil || doc.Metadata[MetaName] == "" {
+			continue
+		}
+		c.docs = append(c.docs, doc)
+		if subject := doc.Metadata[MetaSubject]; subject != "" {
+			c.bySubject[subject] = append(c.bySubject[subject], doc)
+		}
+	}
+
+	sort.Slice(c.docs, func(i, j int) bool {
+		if c.docs[i].Metadata[MetaName] != c.docs[j].Metadata[MetaName] {
+			return c.docs[i].Metadata[MetaName] < c.docs[j].Metadata[MetaName]
+		}
+		return c.docs[i].ID < c.docs[j].ID
+	})
+	for subject := range c.bySubject {
+		sortByKind(c.bySubject[subject])
+	}
+
+	return c
+}
+
+// Count returns the number of schemas in the catalog
+func (c *Catalog) Count() int {
+	return len(c.docs)
+}
+
+// Lookup resolves a JetStream API subject or schema type name to matching schemas.
+//
+// Subjects (e.g., "$JS.API.CONSUMER.CREATE.ORDERS.pull") resolve to the schemas of the
+// longest known API subject that prefixes them, so concrete stream and consumer names
+// are accepted. Type names match the schema name or title case-insensitively and
+// ignoring punctuation, so "consumer_create_request",
+// "io.nats.jetstream.api.v1.consumer_create_request" and "JSApiConsumerCreateRequest"
+// are equivalent. If no exact name matches, schemas whose name contains the query
+// are returned.
+func (c *Catalog) Lookup(query string) []*index.Document {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	upper := strings.ToUpper(query)
+	if strings.HasPrefix(upper, "JS.API") {
+		upper = "$" + upper
+	}
+	if strings.HasPrefix(upper, "$JS.API") {
+		if docs := c.lookupSubject(upper); len(docs) > 0 {
+			return docs
+		}
+	}
+
+	return c.lookupName(query)
+}
+
+// lookupSubject finds the schemas of the longest known subject prefixing subject
+func (c *Catalog) lookupSubject(subject string) []*index.Document {
+	tokens := strings.Split(subject, ".")
+
+	best := ""
+	bestLen := 0
+	for known := range c.bySubject {
+		knownTokens := strings.Split(known, ".")
+		if len(knownTokens) > len(tokens) || len(knownTokens) <= bestLen {
+			continue
+		}
+		matched := true
+		for i, token := range knownTokens {
+			if token != "*" && token != tokens[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			best = known
+			bestLen = len(knownTokens)
+		}
+	}
+
+	// Require more than the bare "$JS.API" prefix to match
+	if bestLen <= 2 {
+		return nil
+	}
+	return append([]*index.Document(nil), c.bySubject[best]...)
+}
+
+// lookupName finds schemas by name or title
+func (c *Catalog) lookupName(query string) []*index.Document {
+	key := normalizeName(query)
+	if key == "" {
+		return nil
+	}
+
+	var exact, partial []*index.Document
+	for _, doc := range c.docs {
+		name := normalizeName(doc.Metadata[MetaName])
+		title := normalizeName(doc.Metadata[MetaType])
+		switch {
+		case key == name || key == title:
+			exact = append(exact, doc)
+		case strings.Contains(name, key):
+			partial = append(partial, doc)
+		}
+	}
+
+	if len(exact) > 0 {
+		return exact
+	}
+	return partial
+}
+
+// normalizeName lowercases a type name and strips punctuation and the common
+// "io.nats.jetstream.api.v1." and "JSApi" prefixes
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	normalized := b.String()
+	normalized = strings.TrimPrefix(normalized, "ionatsjetstreamapiv1")
+	normalized = strings.TrimPrefix(normalized, "jsapi")
+	return normalized
+}
+
+// sortByKind orders documents requests first, then responses, then definitions
+func sortByKind(docs []*index.Document) {
+	rank := map[string]int{"request": 0, "response": 1, "definition": 2}
+	sort.SliceStable(docs, func(i, j int) bool {
+		return rank[docs[i].Metadata[MetaKind]] < rank[docs[j].Metadata[MetaKind]]
+	})
+}
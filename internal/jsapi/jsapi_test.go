@@ -0,0 +1,291 @@
+package jsapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+const consumerCreateRequestSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "https://nats.io/schemas/jetstream/api/v1/consumer_create_request.json",
+  "description": "A request to the JetStream $JS.API.CONSUMER.CREATE API",
+  "title": "io.nats.jetstream.api.v1.consumer_create_request",
+  "type": "object",
+  "required": ["stream_name", "config"],
+  "properties": {
+    "stream_name": {
+      "type": "string",
+      "description": "The name of the stream to create the consumer in"
+    },
+    "config": {
+      "type": "object",
+      "description": "The consumer configuration",
+      "properties": {
+        "ack_policy": {
+          "type": "string",
+          "description": "How messages should be acknowledged",
+          "enum": ["none", "all", "explicit"],
+          "default": "explicit"
+        },
+        "max_deliver": {
+          "type": "integer",
+          "default": -1
+        }
+      }
+    }
+  }
+}`
+
+const consumerCreateResponseSchema = `{
+  "$id": "https://nats.io/schemas/jetstream/api/v1/consumer_create_response.json",
+  "description": "A response from the JetStream $JS.API.CONSUMER.CREATE API",
+  "title": "io.nats.jetstream.api.v1.consumer_create_response",
+  "type": "object",
+  "oneOf": [
+    {"$ref": "definitions.json#/definitions/error_response"},
+    {"type": "object", "properties": {"name": {"type": "string"}, "config": {"$ref": "definitions.json#/definitions/consumer_configuration"}}}
+  ],
+  "properties": {
+    "type": {"type": "string", "const": "io.nats.jetstream.api.v1.consumer_create_response"}
+  }
+}`
+
+const definitionsSchema = `{
+  "definitions": {
+    "consumer_configuration": {
+      "type": "object",
+      "properties": {
+        "durable_name": {"type": "string", "description": "A unique name for a durable consumer"}
+      }
+    },
+    "error_response": {
+      "type": "object",
+      "properties": {"error": {"type": "object"}}
+    }
+  }
+}`
+
+func mustParse(t *testing.T, content string, path string) []*Schema {
+	t.Helper()
+	schemas, err := ParseSchemas([]byte(content), path)
+	if err != nil {
+		t.Fatalf("ParseSchemas(%s) failed: %v", path, err)
+	}
+	return schemas
+}
+
+func findProperty(props []Property, name string) *Property {
+	for i := range props {
+		if props[i].Name == name {
+			return &props[i]
+		}
+	}
+	return nil
+}
+
+func TestParseSchemas_Request(t *testing.T) {
+	schemas := mustParse(t, consumerCreateRequestSchema, "schemas/jetstream/api/v1/consumer_create_request.json")
+	if len(schemas) != 1 {
+		t.Fatalf("expected 1 schema, got %d", len(schemas))
+	}
+
+	s := schemas[0]
+	if s.Name != "consumer_create_request" {
+		t.Errorf("expected name consumer_create_request, got %s", s.Name)
+	}
+	if s.Subject != "$JS.API.CONSUMER.CREATE" {
+		t.Errorf("expected subject $JS.API.CONSUMER.CREATE, got %s", s.Subject)
+	}
+	if s.Kind != "request" {
+		t.Errorf("expected kind request, got %s", s.Kind)
+	}
+
+	stream := findProperty(s.Properties, "stream_name")
+	if stream == nil || !stream.Required || stream.Type != "string" {
+		t.Errorf("unexpected stream_name property: %+v", stream)
+	}
+
+	ack := findProperty(s.Properties, "config.ack_policy")
+	if ack == nil {
+		t.Fatal("expected nested property config.ack_policy")
+	}
+	if ack.Default != "explicit" {
+		t.Errorf("expected default explicit, got %q", ack.Default)
+	}
+	if strings.Join(ack.Enum, ",") != "none,all,explicit" {
+		t.Errorf("unexpected enum: %v", ack.Enum)
+	}
+
+	maxDeliver := findProperty(s.Properties, "config.max_deliver")
+	if maxDeliver == nil || maxDeliver.Default != "-1" {
+		t.Errorf("expected config.max_deliver default -1, got %+v", maxDeliver)
+	}
+}
+
+func TestParseSchemas_ResponseWithComposition(t *testing.T) {
+	schemas := mustParse(t, consumerCreateResponseSchema, "consumer_create_response.json")
+	if len(schemas) != 1 {
+		t.Fatalf("expected 1 schema, got %d", len(schemas))
+	}
+
+	s := schemas[0]
+	if s.Kind != "response" {
+		t.Errorf("expected kind response, got %s", s.Kind)
+	}
+	if findProperty(s.Properties, "name") == nil {
+		t.Error("expected property from oneOf branch")
+	}
+	config := findProperty(s.Properties, "config")
+	if config == nil || config.Type != "consumer_configuration" {
+		t.Errorf("expected $ref type consumer_configuration, got %+v", config)
+	}
+	typ := findProperty(s.Properties, "type")
+	if typ == nil || len(typ.Enum) != 1 {
+		t.Errorf("expected const to be reported as enum, got %+v", typ)
+	}
+}
+
+func TestParseSchemas_Definitions(t *testing.T) {
+	schemas := mustParse(t, definitionsSchema, "definitions.json")
+	if len(schemas) != 2 {
+		t.Fatalf("expected 2 definition schemas, got %d", len(schemas))
+	}
+	if schemas[0].Name != "consumer_configuration" || schemas[0].Kind != "definition" {
+		t.Errorf("unexpected first definition: %s (%s)", schemas[0].Name, schemas[0].Kind)
+	}
+	if schemas[0].ID != "definitions/consumer_configuration" {
+		t.Errorf("expected the definition ID under its file, got %s", schemas[0].ID)
+	}
+}
+
+func TestParseSchemas_InvalidJSON(t *testing.T) {
+	if _, err := ParseSchemas([]byte("{not json"), "bad.json"); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestParseSchemas_DeclaredSubject(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		want    string
+	}{
+		"consumer_getnext_request.json": {`{"description": "A request to the JetStream $JS.API.CONSUMER.MSG.NEXT API", "properties": {"batch": {"type": "integer"}}}`, "$JS.API.CONSUMER.MSG.NEXT"},
+		"stream_info_request.json":      {`{"$comment": "Sent to $JS.API.STREAM.INFO.*", "properties": {"subjects_filter": {"type": "string"}}}`, "$JS.API.STREAM.INFO"},
+		"stream_msg_get_request.json":   {`{"description": "Retrieves a message", "properties": {"seq": {"type": "integer"}}}`, ""},
+	}
+	for path, tt := range tests {
+		if got := mustParse(t, tt.content, path)[0].Subject; got != tt.want {
+			t.Errorf("subject of %s = %q, want %q", path, got, tt.want)
+		}
+	}
+}
+
+func TestSchemaDocument(t *testing.T) {
+	s := mustParse(t, consumerCreateRequestSchema, "consumer_create_request.json")[0]
+	doc := s.Document("https://github.com/nats-io/jsm.go/blob/main/consumer_create_request.json")
+
+	if doc.ID != "jetstream-api/consumer_create_request" {
+		t.Errorf("unexpected ID: %s", doc.ID)
+	}
+	if doc.Metadata[MetaSubject] != "$JS.API.CONSUMER.CREATE" {
+		t.Errorf("unexpected subject metadata: %v", doc.Metadata)
+	}
+	// Overview plus one section per property
+	if len(doc.Sections) != 1+len(s.Properties) {
+		t.Errorf("expected %d sections, got %d", 1+len(s.Properties), len(doc.Sections))
+	}
+	for _, section := range doc.Sections {
+		if section.Heading == "config.ack_policy" {
+			if !strings.Contains(section.Content, "Default: explicit") || !strings.Contains(section.Content, "Enum: none, all, explicit") {
+				t.Errorf("unexpected ack_policy section: %s", section.Content)
+			}
+		}
+	}
+}
+
+func buildCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	var docs []*index.Document
+	for path, content := range map[string]string{
+		"consumer_create_request.json":  consumerCreateRequestSchema,
+		"consumer_create_response.json": consumerCreateResponseSchema,
+		"definitions.json":              definitionsSchema,
+	} {
+		for _, s := range mustParse(t, content, path) {
+			docs = append(docs, s.Document(path))
+		}
+	}
+	// Documents from other sources are ignored
+	docs = append(docs, &index.Document{ID: "other", Title: "Other"})
+	return NewCatalog(docs)
+}
+
+func TestCatalogLookup(t *testing.T) {
+	catalog := buildCatalog(t)
+
+	if catalog.Count() != 4 {
+		t.Fatalf("expected 4 schemas in catalog, got %d", catalog.Count())
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"exact subject", "$JS.API.CONSUMER.CREATE", []string{"consumer_create_request", "consumer_create_response"}},
+		{"concrete subject", "$JS.API.CONSUMER.CREATE.ORDERS.processor", []string{"consumer_create_request", "consumer_create_response"}},
+		{"subject without dollar", "js.api.consumer.create", []string{"consumer_create_request", "consumer_create_response"}},
+		{"schema name", "consumer_create_request", []string{"consumer_create_request"}},
+		{"type title", "io.nats.jetstream.api.v1.consumer_create_response", []string{"consumer_create_response"}},
+		{"go type name", "JSApiConsumerCreateRequest", []string{"consumer_create_request"}},
+		{"definition", "ConsumerConfiguration", []string{"consumer_configuration"}},
+		{"partial name", "consumer_create", []string{"consumer_create_request", "consumer_create_response"}},
+		{"unknown subject", "$JS.API.NOPE", nil},
+		{"empty", "  ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := catalog.Lookup(tt.query)
+			var got []string
+			for _, doc := range docs {
+				got = append(got, doc.Metadata[MetaName])
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Lookup(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogKeepsSchemasWithTheSameName(t *testing.T) {
+	var docs []*index.Document
+	for _, path := range []string{"jetstream/api/v1/definitions.json", "micro/v1/definitions.json"} {
+		for _, s := range mustParse(t, definitionsSchema, path) {
+			docs = append(docs, s.Document(path))
+		}
+	}
+
+	ids := make(map[string]bool)
+	for _, doc := range docs {
+		ids[doc.ID] = true
+	}
+	if len(ids) != 4 || !ids["jetstream-api/jetstream/api/v1/definitions/error_response"] || !ids["jetstream-api/micro/v1/definitions/error_response"] {
+		t.Fatalf("expected schema IDs keyed by file path, got %v", ids)
+	}
+
+	schemaIndex := index.NewDocumentationIndex()
+	for _, doc := range docs {
+		if err := schemaIndex.Index(doc); err != nil {
+			t.Fatalf("failed to index %s: %v", doc.ID, err)
+		}
+	}
+	if schemaIndex.Count() != 4 {
+		t.Errorf("expected schemas sharing a name to be indexed separately, got %d", schemaIndex.Count())
+	}
+	if got := NewCatalog(docs).Lookup("error_response"); len(got) != 2 {
+		t.Errorf("expected both error_response schemas, got %d", len(got))
+	}
+}
@@ -0,0 +1,403 @@
+// Package jsapi ingests the JetStream API JSON Schemas published by the nats-io
+// repositories (one schema per $JS.API request and response) and turns them into
+// structured documents that can be resolved by API subject or schema type name.
+package jsapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// Metadata keys stored on index.Document for schema documents
+const (
+	MetaName    = "jetstream_api_name"    // Schema name (e.g., "consumer_create_request")
+	MetaType    = "jetstream_api_type"    // Schema type/title (e.g., "io.nats.jetstream.api.v1.consumer_create_request")
+	MetaSubject = "jetstream_api_subject" // API subject (e.g., "$JS.API.CONSUMER.CREATE")
+	MetaKind    = "jetstream_api_kind"    // "request", "response" or "definition"
+)
+
+// DocIDPrefix is the prefix of all JetStream API schema document IDs
+const DocIDPrefix = "jetstream-api/"
+
+// maxPropertyDepth limits how deep nested object properties are flattened
+const maxPropertyDepth = 3
+
+// subjectPattern matches JetStream API subjects mentioned in schema descriptions
+var subjectPattern = regexp.MustCompile(`\$JS\.API(?:\.[A-Z0-9_*>]+)*`)
+
+// Schema is a parsed JetStream API JSON Schema
+type Schema struct {
+	ID          string     // Unique ID derived from the file path, e.g. "v1/definitions/consumer_configuration"
+	Name        string     // Schema name derived from the file or definition name
+	Title       string     // Schema title, typically the API type name
+	Description string     // Schema description
+	Subject     string     // $JS.API subject the schema declares in its description, if any
+	Kind        string     // "request", "response" or "definition"
+	Path        string     // Path of the file the schema was read from
+	Properties  []Property // Flattened properties, nested objects use dotted names
+}
+
+// Property describes a single schema property
+type Property struct {
+	Name        string   // Property name, dotted for nested properties (e.g., "config.ack_policy")
+	Type        string   // JSON type or referenced definition name
+	Description string   // Property description
+	Default     string   // JSON-encoded default value, empty if none
+	Enum        []string // Allowed values, empty if unrestricted
+	Required    bool     // Whether the property is required
+}
+
+// schemaNode mirrors the subset of JSON Schema used by the JetStream API schemas
+type schemaNode struct {
+	ID          string                 `json:"$id"`
+	Ref         string                 `json:"$ref"`
+	Comment     string                 `json:"$comment"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Type        json.RawMessage        `json:"type"`
+	Properties  map[string]*schemaNode `json:"properties"`
+	Required    []string               `json:"required"`
+	Default     json.RawMessage        `json:"default"`
+	Enum        []json.RawMessage      `json:"enum"`
+	Const       json.RawMessage        `json:"const"`
+	Items       *schemaNode            `json:"items"`
+	AllOf       []*schemaNode          `json:"allOf"`
+	AnyOf       []*schemaNode          `json:"anyOf"`
+	OneOf       []*schemaNode          `json:"oneOf"`
+	Definitions map[string]*schemaNode `json:"definitions"`
+}
+
+// IsSchemaPath reports whether a repository path looks like a JSON Schema file
+func IsSchemaPath(p string) bool {
+	return strings.HasSuffix(strings.ToLower(p), ".json")
+}
+
+// ParseSchemas parses a JSON Schema file. A file describing a request or response
+// yields one schema; a definitions file yields one schema per definition.
+//
+// Parameters:
+//   - content: Raw JSON Schema content
+//   - filePath: Path of the file, used to derive the schema name
+//
+// Returns the parsed schemas or an error if the content is not valid JSON.
+func ParseSchemas(content []byte, filePath string) ([]*Schema, error) {
+	var root schemaNode
+	if err := json.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON schema %s: %w", filePath, err)
+	}
+
+	id := strings.TrimSuffix(filePath, path.Ext(filePath))
+	name := path.Base(id)
+
+	var schemas []*Schema
+	if len(root.Properties) > 0 || hasInlineProperties(&root) || len(root.Definitions) == 0 {
+		schemas = append(schemas, newSchema(id, name, filePath, &root))
+	}
+
+	// Definitions are schemas in their own right (e.g., consumer_configuration)
+	defNames := make([]string, 0, len(root.Definitions))
+	for defName := range root.Definitions {
+		defNames = append(defNames, defName)
+	}
+	sort.Strings(defNames)
+	for _, defName := range defNames {
+		def := root.Definitions[defName]
+		if def == nil {
+			continue
+		}
+		schema := newSchema(id+"/"+defName, defName, filePath, def)
+		schema.Kind = "definition"
+		schemas = append(schemas, schema)
+	}
+
+	return schemas, nil
+}
+
+// newSchema builds a Schema from a parsed node
+func newSchema(id, name string, filePath string, node *schemaNode) *Schema {
+	schema := &Schema{
+		ID:          id,
+		Name:        name,
+		Title:       node.Title,
+		Description: strings.TrimSpace(node.Description),
+		Path:        filePath,
+		Kind:        schemaKind(name),
+	}
+	if schema.Title == "" {
+		schema.Title = name
+	}
+
+	// Subjects are only taken from the schema: names do not map onto subjects
+	// reliably, e.g. consumer_getnext_request is served on $JS.API.CONSUMER.MSG.NEXT
+	schema.Subject = subjectFromText(node.Description + " " + node.Comment)
+
+	var props []Property
+	collectProperties(node, "", 0, &props)
+
+	// Composed branches may repeat a property; keep the first occurrence
+	seen := make(map[string]bool, len(props))
+	for _, prop := range props {
+		if seen[prop.Name] {
+			continue
+		}
+		seen[prop.Name] = true
+		schema.Properties = append(schema.Properties, prop)
+	}
+
+	return schema
+}
+
+// schemaKind derives the kind of schema from its name
+func schemaKind(name string) string {
+	switch {
+	case strings.HasSuffix(name, "_request"):
+		return "request"
+	case strings.HasSuffix(name, "_response"):
+		return "response"
+	default:
+		return "definition"
+	}
+}
+
+// subjectFromText extracts the first $JS.API subject mentioned in text
+func subjectFromText(text string) string {
+	subject := subjectPattern.FindString(text)
+	subject = strings.TrimRight(subject, ".")
+	// Drop trailing wildcard tokens so the subject can be used as a prefix
+	for strings.HasSuffix(subject, ".*") || strings.HasSuffix(subject, ".>") {
+		subject = subject[:len(subject)-2]
+	}
+	return subject
+}
+
+// hasInlineProperties reports whether any composed sub-schema declares properties
+func hasInlineProperties(node *schemaNode) bool {
+	for _, group := range [][]*schemaNode{node.AllOf, node.AnyOf, node.OneOf} {
+		for _, sub := range group {
+			if sub != nil && (len(sub.Properties) > 0 || hasInlineProperties(sub)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collectProperties flattens the properties of node (including inline allOf/anyOf/oneOf
+// branches) into out, prefixing nested property names with their parent name.
+func collectProperties(node *schemaNode, prefix string, depth int, out *[]Property) {
+	if node == nil || depth > maxPropertyDepth {
+		return
+	}
+
+	required := make(map[string]bool, len(node.Required))
+	for _, name := range node.Required {
+		required[name] = true
+	}
+
+	names := make([]string, 0, len(node.Properties))
+	for name := range node.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop := node.Properties[name]
+		if prop == nil {
+			continue
+		}
+
+		*out = append(*out, Property{
+			Name:        prefix + name,
+			Type:        typeString(prop),
+			Description: strings.TrimSpace(prop.Description),
+			Default:     rawString(prop.Default),
+			Enum:        enumStrings(prop),
+			Required:    required[name],
+		})
+
+		// Flatten inline nested objects
+		collectProperties(prop, prefix+name+".", depth+1, out)
+		if prop.Items != nil && len(prop.Items.Properties) > 0 {
+			collectProperties(prop.Items, prefix+name+"[].", depth+1, out)
+		}
+	}
+
+	for _, group := range [][]*schemaNode{node.AllOf, node.AnyOf, node.OneOf} {
+		for _, sub := range group {
+			collectProperties(sub, prefix, depth, out)
+		}
+	}
+}
+
+// typeString renders the type of a property in a compact human-readable form
+func typeString(node *schemaNode) string {
+	if node.Ref != "" {
+		return refName(node.Ref)
+	}
+
+	if len(node.Type) > 0 {
+		var single string
+		if err := json.Unmarshal(node.Type, &single); err == nil {
+			if single == "array" && node.Items != nil {
+				return "array of " + typeString(node.Items)
+			}
+			return single
+		}
+		var multiple []string
+		if err := json.Unmarshal(node.Type, &multiple); err == nil {
+			return strings.Join(multiple, " | ")
+		}
+	}
+
+	for _, group := range [][]*schemaNode{node.AllOf, node.OneOf, node.AnyOf} {
+		if len(group) == 0 {
+			continue
+		}
+		types := make([]string, 0, len(group))
+		for _, sub := range group {
+			if sub == nil {
+				continue
+			}
+			if t := typeString(sub); t != "" {
+				types = append(types, t)
+			}
+		}
+		if len(types) > 0 {
+			return strings.Join(types, " | ")
+		}
+	}
+
+	if len(node.Properties) > 0 {
+		return "object"
+	}
+	if len(node.Enum) > 0 || len(node.Const) > 0 {
+		return "enum"
+	}
+	return ""
+}
+
+// refName returns the definition name referenced by a $ref
+// e.g. "definitions.json#/definitions/consumer_configuration" -> "consumer_configuration"
+func refName(ref string) string {
+	if idx := strings.LastIndex(ref, "/"); idx >= 0 {
+		return ref[idx+1:]
+	}
+	return strings.TrimSuffix(ref, ".json")
+}
+
+// enumStrings returns the allowed values of a property (enum or const)
+func enumStrings(node *schemaNode) []string {
+	values := make([]string, 0, len(node.Enum)+1)
+	for _, v := range node.Enum {
+		values = append(values, rawString(v))
+	}
+	if len(node.Const) > 0 {
+		values = append(values, rawString(node.Const))
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// rawString renders a raw JSON value, unquoting plain strings
+func rawString(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// Document converts the schema into a searchable document with an overview
+// section followed by one section per property.
+//
+// Parameters:
+//   - sourceURL: URL (or file location) of the schema file
+//
+// Returns the document ready for indexing.
+func (s *Schema) Document(sourceURL string) *index.Document {
+	var overview strings.Builder
+	if s.Description != "" {
+		overview.WriteString(s.Description)
+		overview.WriteString("\n\n")
+	}
+	overview.WriteString(fmt.Sprintf("Type: %s\n", s.Title))
+	overview.WriteString(fmt.Sprintf("Kind: %s\n", s.Kind))
+	if s.Subject != "" {
+		overview.WriteString(fmt.Sprintf("Subject: %s\n", s.Subject))
+	}
+
+	sections := []index.Section{
+		{Heading: s.Name, Content: strings.TrimSpace(overview.String()), Level: 1},
+	}
+
+	var content strings.Builder
+	content.WriteString(overview.String())
+	content.WriteString("\n")
+
+	for _, prop := range s.Properties {
+		body := prop.Describe()
+		sections = append(sections, index.Section{
+			Heading: prop.Name,
+			Content: body,
+			Level:   2,
+		})
+		content.WriteString(prop.Name)
+		content.WriteString(" ")
+		content.WriteString(body)
+		content.WriteString("\n")
+	}
+
+	metadata := map[string]string{
+		MetaName: s.Name,
+		MetaType: s.Title,
+		MetaKind: s.Kind,
+	}
+	if s.Subject != "" {
+		metadata[MetaSubject] = s.Subject
+	}
+
+	return &index.Document{
+		ID:          DocIDPrefix + s.ID,
+		Title:       s.Title,
+		URL:         sourceURL,
+		Content:     content.String(),
+		Sections:    sections,
+		LastUpdated: time.Now(),
+		Metadata:    metadata,
+	}
+}
+
+// Describe renders the property's type, requirement, default, allowed values
+// and description as section content
+func (p Property) Describe() string {
+	var b strings.Builder
+	if p.Type != "" {
+		b.WriteString(fmt.Sprintf("Type: %s\n", p.Type))
+	}
+	if p.Required {
+		b.WriteString("Required: yes\n")
+	}
+	if p.Default != "" {
+		b.WriteString(fmt.Sprintf("Default: %s\n", p.Default))
+	}
+	if len(p.Enum) > 0 {
+		b.WriteString(fmt.Sprintf("Enum: %s\n", strings.Join(p.Enum, ", ")))
+	}
+	if p.Description != "" {
+		b.WriteString("\n")
+		b.WriteString(p.Description)
+	}
+	return strings.TrimSpace(b.String())
+}
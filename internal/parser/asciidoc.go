@@ -0,0 +1,213 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// adocHeading matches a section title, e.g. "== Streams" (or "## Streams")
+	adocHeading = regexp.MustCompile(`^(={1,6}|#{1,6})\s+(.+?)\s*$`)
+	// adocAttribute matches an attribute entry, e.g. ":toc: left"
+	adocAttribute = regexp.MustCompile(`^:!?[\w-]+!?:`)
+	// adocBlockMacro matches block macros such as include::, image:: and toc::
+	adocBlockMacro = regexp.MustCompile(`^[\w-]+::\S*\[.*\]$`)
+	// adocListItem matches an unordered, ordered or checklist item
+	adocListItem = regexp.MustCompile(`^(\*{1,5}|-|\.{1,5}|\d+\.)\s+(\[[ x*]\]\s+)?`)
+	// adocLink matches URL and link: macros, e.g. https://nats.io[NATS]
+	adocLink = regexp.MustCompile(`((?:link:|mailto:|https?://)[^\s\[\]]*)\[([^\]]*)\]`)
+	// adocXref matches cross references, e.g. <<streams,Streams>>
+	adocXref = regexp.MustCompile(`<<([^,>]+)(?:,\s*([^>]+))?>>`)
+	// adocUnconstrained matches unconstrained bold, italic and monospace phrases
+	adocUnconstrained = regexp.MustCompile("\\*\\*(.+?)\\*\\*|__(.+?)__|``(.+?)``")
+	// adocConstrained matches constrained phrases, which start and end at word
+	// boundaries so identifiers such as max_ack_pending are left alone
+	adocConstrained = []*regexp.Regexp{
+		regexp.MustCompile(`(^|\W)\*([^*\s](?:[^*]*[^*\s])?)\*($|\W)`),
+		regexp.MustCompile(`(^|\W)_([^_\s](?:[^_]*[^_\s])?)_($|\W)`),
+		regexp.MustCompile("(^|\\W)`([^`\\s](?:[^`]*[^`\\s])?)`($|\\W)"),
+	}
+)
+
+// adocVerbatimDelimiters are the delimiters of blocks whose lines are kept as is
+var adocVerbatimDelimiters = map[string]bool{"----": true, "....": true, "```": true}
+
+// adocSkippedDelimiters are the delimiters of comment and passthrough blocks
+var adocSkippedDelimiters = map[string]bool{"////": true, "++++": true}
+
+// ParseAsciiDoc parses AsciiDoc content. "= Title" is the document title and "=="
+// to "======" are section levels (Markdown-style "#" headings are also accepted).
+// Listing and literal blocks are kept verbatim, table cells as text, and comments,
+// attribute entries and block macros are dropped.
+func ParseAsciiDoc(content []byte, filepath string) (*Document, error) {
+	lines := splitLines(content)
+
+	var title string
+	var b sectionBuilder
+	var p paragraphBuilder
+
+	delimiter := ""    // Delimiter of the open verbatim, skipped or table block
+	var block []string // Lines of the open verbatim block
+	inHeader := false  // Author and revision lines follow the document title
+
+	// Language and title of the next verbatim block, from "[source,go]" and ".Title"
+	// lines, and of the open one
+	var language, caption string
+	var blockLanguage, blockCaption string
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// Inside a delimited block until its closing delimiter
+		if delimiter != "" {
+			if adocDelimiter(trimmed) == delimiter {
+				if adocVerbatimDelimiters[delimiter] {
+					b.addCode(newCodeBlock(blockLanguage, dedent(block), blockCaption))
+				}
+				delimiter, block = "", nil
+				continue
+			}
+			switch {
+			case adocVerbatimDelimiters[delimiter]:
+				block = append(block, line)
+			case delimiter == "|===":
+				if cells := adocTableCells(trimmed); cells != "" {
+					b.add(cells)
+				}
+			}
+			continue
+		}
+
+		if inHeader {
+			if trimmed == "" {
+				inHeader = false
+			}
+			continue
+		}
+
+		if adocVerbatimDelimiters[adocDelimiter(trimmed)] || adocSkippedDelimiters[trimmed] || trimmed == "|===" {
+			p.flush(&b)
+			delimiter = adocDelimiter(trimmed)
+			blockLanguage, blockCaption = language, caption
+			if delimiter == "```" && len(trimmed) > 3 {
+				blockLanguage = trimmed[3:]
+			}
+			language, caption = "", ""
+			continue
+		}
+
+		if match := adocHeading.FindStringSubmatch(trimmed); match != nil && line == trimmed {
+			p.flush(&b)
+			heading := cleanAsciiDocInline(match[2])
+			if title == "" && len(match[1]) == 1 {
+				title = heading
+				inHeader = true
+			}
+			b.start(heading, len(match[1]))
+			language, caption = "", ""
+			continue
+		}
+
+		switch {
+		case trimmed == "" || trimmed == "+":
+			p.flush(&b)
+		case strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]"):
+			// Block attributes; "[source,go]" names the language of a listing block
+			if attrs := strings.Split(strings.Trim(trimmed, "[]"), ","); len(attrs) > 1 && strings.TrimSpace(attrs[0]) == "source" {
+				language = strings.TrimSpace(attrs[1])
+			}
+		case strings.HasPrefix(trimmed, "//"),
+			adocAttribute.MatchString(trimmed),
+			adocBlockMacro.MatchString(trimmed):
+			// Comments, attribute entries and block macros
+		case isAdocOpenDelimiter(trimmed):
+			// Example, sidebar, quote and open blocks hold regular content
+			p.flush(&b)
+		case strings.HasPrefix(trimmed, ".") && len(trimmed) > 1 && trimmed[1] != '.' && trimmed[1] != ' ':
+			// Block titles label the following block
+			p.flush(&b)
+			caption = cleanAsciiDocInline(trimmed[1:])
+			b.add(caption)
+		case adocListItem.MatchString(trimmed):
+			p.flush(&b)
+			p.line("• " + cleanAsciiDocInline(adocListItem.ReplaceAllString(trimmed, "")))
+		default:
+			p.line(cleanAsciiDocInline(trimmed))
+			language, caption = "", ""
+		}
+	}
+	p.flush(&b)
+
+	if title == "" {
+		title = titleFromPath(filepath)
+	}
+
+	return &Document{
+		Title:    title,
+		Sections: b.finish(),
+	}, nil
+}
+
+// adocDelimiter normalises a listing or literal delimiter line, which may be longer
+// than four characters, and returns "" for other lines. Fenced code blocks may carry
+// a language after the backticks.
+func adocDelimiter(line string) string {
+	switch {
+	case strings.HasPrefix(line, "```"):
+		return "```"
+	case len(line) >= 4 && strings.Trim(line, "-") == "":
+		return "----"
+	case len(line) >= 4 && strings.Trim(line, ".") == "":
+		return "...."
+	}
+	return line
+}
+
+// isAdocOpenDelimiter reports whether line delimits an example, sidebar, quote or
+// open block
+func isAdocOpenDelimiter(line string) bool {
+	if line == "--" {
+		return true
+	}
+	for _, c := range []string{"=", "*", "_"} {
+		if len(line) >= 4 && strings.Trim(line, c) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// adocTableCells returns the text of a table row, with cells separated by " | "
+func adocTableCells(line string) string {
+	var cells []string
+	for _, cell := range strings.Split(line, "|") {
+		if cell = strings.TrimSpace(cell); cell != "" {
+			cells = append(cells, cleanAsciiDocInline(cell))
+		}
+	}
+	return strings.Join(cells, " | ")
+}
+
+// cleanAsciiDocInline removes inline markup, keeping the text of links, cross
+// references and formatted phrases
+func cleanAsciiDocInline(text string) string {
+	text = adocXref.ReplaceAllStringFunc(text, func(m string) string {
+		match := adocXref.FindStringSubmatch(m)
+		if match[2] != "" {
+			return match[2]
+		}
+		return match[1]
+	})
+	text = adocLink.ReplaceAllStringFunc(text, func(m string) string {
+		match := adocLink.FindStringSubmatch(m)
+		if match[2] != "" {
+			return match[2]
+		}
+		return strings.TrimPrefix(match[1], "link:")
+	})
+	text = adocUnconstrained.ReplaceAllString(text, "$1$2$3")
+	for _, re := range adocConstrained {
+		text = re.ReplaceAllString(text, "$1$2$3")
+	}
+	return text
+}
@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// CodeBlock is a block of code within a section. Its text also appears in the
+// section's content as a Markdown fenced block, so it stays searchable.
+type CodeBlock struct {
+	Language string // Normalised language (e.g. "go", "javascript"); empty when unknown
+	Content  string // Code, without a trailing newline
+	Caption  string // Title of the block, e.g. a file name; empty when none
+}
+
+// Fenced renders the code block as a Markdown fenced block, with a fence longer than
+// any run of backticks in the code. The caption becomes a title in the info string,
+// e.g. ```go title="main.go".
+func (c CodeBlock) Fenced() string {
+	fence := "```"
+	for strings.Contains(c.Content, fence) {
+		fence += "`"
+	}
+	info := c.Language
+	if c.Caption != "" {
+		info += ` title="` + strings.ReplaceAll(c.Caption, `"`, "'") + `"`
+	}
+	return fence + strings.TrimSpace(info) + "\n" + c.Content + "\n" + fence
+}
+
+// languageAliases maps alternative language names to the normalised one
+var languageAliases = map[string]string{
+	"golang":  "go",
+	"js":      "javascript",
+	"node":    "javascript",
+	"nodejs":  "javascript",
+	"ts":      "typescript",
+	"py":      "python",
+	"python3": "python",
+	"rs":      "rust",
+	"yml":     "yaml",
+	"sh":      "shell",
+}
+
+// NormalizeLanguage returns the normalised name of a code block language, e.g. "go"
+// for "Golang" or "language-go"
+func NormalizeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	for _, prefix := range []string{"language-", "lang-"} {
+		language = strings.TrimPrefix(language, prefix)
+	}
+	if alias, ok := languageAliases[language]; ok {
+		return alias
+	}
+	return language
+}
+
+// newCodeBlock returns a code block with its language normalised and its content
+// trimmed of surrounding blank lines
+func newCodeBlock(language, content, caption string) CodeBlock {
+	content = strings.TrimRight(content, " \t\n")
+	for strings.HasPrefix(content, "\n") {
+		content = content[1:]
+	}
+	return CodeBlock{
+		Language: NormalizeLanguage(language),
+		Content:  content,
+		Caption:  strings.TrimSpace(caption),
+	}
+}
+
+// htmlCodeBlock returns the code block of a <pre> element. The language comes from a
+// language-* or lang-* class or a data-lang(uage) attribute of the <pre> or its
+// <code>, and the caption from a title attribute or the <figcaption> of an enclosing
+// <figure>.
+func htmlCodeBlock(pre *html.Node) CodeBlock {
+	nodes := []*html.Node{pre}
+	for c := pre.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "code" {
+			nodes = append(nodes, c)
+		}
+	}
+
+	var language, caption string
+	for _, n := range nodes {
+		if language == "" {
+			language = htmlCodeLanguage(n)
+		}
+		if caption == "" {
+			caption = attr(n, "title")
+			if caption == "" {
+				caption = attr(n, "data-title")
+			}
+		}
+	}
+	if caption == "" {
+		for p := pre.Parent; p != nil && p.Type == html.ElementNode; p = p.Parent {
+			if p.Data != "figure" {
+				continue
+			}
+			if figcaption := findFirst(p, figcaptionSelector); figcaption != nil {
+				caption = extractText(figcaption)
+			}
+			break
+		}
+	}
+	return newCodeBlock(language, extractText(pre), caption)
+}
+
+// figcaptionSelector matches the caption of a <figure>
+var figcaptionSelector = selector{{{compound: compound{tag: "figcaption"}}}}
+
+// htmlCodeLanguage returns the language named by the classes or data attributes of n
+func htmlCodeLanguage(n *html.Node) string {
+	for _, key := range []string{"data-language", "data-lang"} {
+		if language := attr(n, key); language != "" {
+			return language
+		}
+	}
+	for _, class := range strings.Fields(attr(n, "class")) {
+		if strings.HasPrefix(class, "language-") || strings.HasPrefix(class, "lang-") {
+			return class
+		}
+	}
+	return ""
+}
+
+// fenceTitle matches a title in the info string of a fenced code block, e.g.
+// ```go title="main.go"
+var fenceTitle = regexp.MustCompile(`\btitle=(?:"([^"]*)"|'([^']*)'|(\S+))`)
+
+// fenceInfo splits the info string of a fenced code block into its language and
+// caption
+func fenceInfo(info string) (string, string) {
+	language := ""
+	if fields := strings.Fields(info); len(fields) > 0 && !strings.Contains(fields[0], "=") {
+		language = strings.Trim(fields[0], "{}.")
+	}
+	caption := ""
+	if match := fenceTitle.FindStringSubmatch(info); match != nil {
+		caption = match[1] + match[2] + match[3]
+	}
+	return language, caption
+}
@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHTML_CodeBlocks(t *testing.T) {
+	page := `<html><body>
+<h2>Publish</h2>
+<p>Call <code>nc.Publish</code> to send a message.</p>
+<div class="code-tabs">
+	<figure>
+		<figcaption>main.go</figcaption>
+		<pre><code class="language-golang">nc.Publish("updates", []byte("hello"))
+</code></pre>
+	</figure>
+	<pre data-lang="shell"><code>nats pub updates hello</code></pre>
+</div>
+<ul><li>Or in JavaScript: <pre class="lang-js">nc.publish("updates")</pre></li></ul>
+<table><tr><td><code>updates</code></td></tr></table>
+</body></html>`
+
+	doc, err := ParseHTML(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("ParseHTML failed: %v", err)
+	}
+	if len(doc.Sections) != 1 {
+		t.Fatalf("expected 1 section, got %d", len(doc.Sections))
+	}
+	section := doc.Sections[0]
+
+	want := []CodeBlock{
+		{Language: "go", Content: `nc.Publish("updates", []byte("hello"))`, Caption: "main.go"},
+		{Language: "shell", Content: "nats pub updates hello"},
+		{Language: "javascript", Content: `nc.publish("updates")`},
+	}
+	if !reflect.DeepEqual(section.CodeBlocks, want) {
+		t.Errorf("code blocks = %+v, want %+v", section.CodeBlocks, want)
+	}
+	if !strings.Contains(section.Content, "```go title=\"main.go\"\nnc.Publish(\"updates\", []byte(\"hello\"))\n```") {
+		t.Errorf("expected the code as a fenced block in the content, got %q", section.Content)
+	}
+	// Inline code is part of the text, not a block of its own
+	if !strings.Contains(section.Content, "Call nc.Publish to send a message.") {
+		t.Errorf("expected inline code to stay in its paragraph, got %q", section.Content)
+	}
+	if strings.Contains(section.Content, "updates\n\n```") || strings.Contains(section.Content, "```\nupdates\n```") {
+		t.Errorf("inline code should not become a block, got %q", section.Content)
+	}
+}
+
+func TestParseMarkdown_CodeBlocks(t *testing.T) {
+	md := "# Guide\n\n" +
+		"## Connect\n\n" +
+		"```go title=\"main.go\"\nnc, _ := nats.Connect(nats.DefaultURL)\n```\n\n" +
+		"    indented block\n\n" +
+		"- Then check it:\n\n  ```bash\n  nats server check connection\n  ```\n"
+
+	doc, err := ParseMarkdown([]byte(md), "guide.md")
+	if err != nil {
+		t.Fatalf("ParseMarkdown failed: %v", err)
+	}
+	var connect *Section
+	for i := range doc.Sections {
+		if doc.Sections[i].Heading == "Connect" {
+			connect = &doc.Sections[i]
+		}
+	}
+	if connect == nil {
+		t.Fatalf("Connect section not found in %+v", doc.Sections)
+	}
+
+	want := []CodeBlock{
+		{Language: "go", Content: "nc, _ := nats.Connect(nats.DefaultURL)", Caption: "main.go"},
+		{Content: "indented block"},
+		{Language: "bash", Content: "nats server check connection"},
+	}
+	if !reflect.DeepEqual(connect.CodeBlocks, want) {
+		t.Errorf("code blocks = %+v, want %+v", connect.CodeBlocks, want)
+	}
+	if !strings.Contains(connect.Content, "```go title=\"main.go\"\nnc, _ := nats.Connect(nats.DefaultURL)\n```") {
+		t.Errorf("expected a fenced Go block in the content, got %q", connect.Content)
+	}
+}
+
+func TestParseRSTAndAsciiDoc_CodeBlocks(t *testing.T) {
+	rst := "Streams\n=======\n\n.. code-block:: python\n   :caption: stream.py\n   :linenos:\n\n   await js.add_stream(name=\"ORDERS\")\n"
+	doc, err := ParseRST([]byte(rst), "streams.rst")
+	if err != nil {
+		t.Fatalf("ParseRST failed: %v", err)
+	}
+	want := []CodeBlock{{Language: "python", Content: `await js.add_stream(name="ORDERS")`, Caption: "stream.py"}}
+	if len(doc.Sections) != 1 || !reflect.DeepEqual(doc.Sections[0].CodeBlocks, want) {
+		t.Errorf("RST code blocks = %+v, want %+v", doc.Sections, want)
+	}
+
+	adoc := "= Streams\n\n== Create\n\n.Create a stream\n[source,rust]\n----\njs.create_stream(\"ORDERS\").await?;\n----\n\n----\nplain listing\n----\n"
+	doc, err = ParseAsciiDoc([]byte(adoc), "streams.adoc")
+	if err != nil {
+		t.Fatalf("ParseAsciiDoc failed: %v", err)
+	}
+	want = []CodeBlock{
+		{Language: "rust", Content: `js.create_stream("ORDERS").await?;`, Caption: "Create a stream"},
+		{Content: "plain listing"},
+	}
+	if got := doc.Sections[len(doc.Sections)-1].CodeBlocks; !reflect.DeepEqual(got, want) {
+		t.Errorf("AsciiDoc code blocks = %+v, want %+v", got, want)
+	}
+}
+
+func TestCodeBlockFenced(t *testing.T) {
+	block := CodeBlock{Language: "markdown", Content: "```go\nx := 1\n```"}
+	if got := block.Fenced(); got != "````markdown\n```go\nx := 1\n```\n````" {
+		t.Errorf("expected a longer fence around backticks, got %q", got)
+	}
+	if got := NormalizeLanguage(" language-Golang "); got != "go" {
+		t.Errorf("NormalizeLanguage() = %q, want go", got)
+	}
+}
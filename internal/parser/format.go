@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// documentParsers maps the extensions of supported documentation files to their parser
+var documentParsers = map[string]func(content []byte, filepath string) (*Document, error){
+	".md":   ParseMarkdown,
+	".mdx":  ParseMDX,
+	".rst":  ParseRST,
+	".adoc": ParseAsciiDoc,
+	".txt":  ParseText,
+}
+
+// IsDocumentPath reports whether ParseFile supports the format of the file at filepath
+func IsDocumentPath(filepath string) bool {
+	_, ok := documentParsers[strings.ToLower(path.Ext(filepath))]
+	return ok || IsGoExamplePath(filepath)
+}
+
+// ParseFile parses a documentation file with the parser for its extension:
+// Markdown (.md), MDX (.mdx), reStructuredText (.rst), AsciiDoc (.adoc) or plain text (.txt).
+// Go example files (example_test.go) are parsed with ParseGoExamples.
+func ParseFile(content []byte, filepath string) (*Document, error) {
+	if IsGoExamplePath(filepath) {
+		return ParseGoExamples(content, filepath)
+	}
+	parse, ok := documentParsers[strings.ToLower(path.Ext(filepath))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported document format: %s", filepath)
+	}
+	return parse(content, filepath)
+}
+
+// titleFromPath derives a title from a file name, e.g. "jetstream_model.rst" becomes
+// "Jetstream Model"
+func titleFromPath(filepath string) string {
+	if filepath == "" {
+		return "Untitled"
+	}
+	filename := path.Base(filepath)
+	filename = strings.TrimSuffix(filename, path.Ext(filename))
+	// Convert underscores/hyphens to spaces and capitalize
+	filename = strings.ReplaceAll(filename, "_", " ")
+	filename = strings.ReplaceAll(filename, "-", " ")
+	return strings.Title(filename)
+}
+
+// sectionBuilder collects the sections of a document whose headings are recognised
+// line by line. Like ParseMarkdown, a document without headings gets a single
+// "Content" section.
+type sectionBuilder struct {
+	sections []Section
+	heading  string
+	level    int
+	blocks   []string    // Paragraphs, list items and code blocks of the current section
+	codes    []CodeBlock // Code blocks of the current section
+}
+
+// start begins a new section, closing the current one
+func (b *sectionBuilder) start(heading string, level int) {
+	b.flush()
+	b.heading = heading
+	b.level = level
+}
+
+// add appends a block of text to the current section
+func (b *sectionBuilder) add(block string) {
+	if block = strings.TrimRight(block, " \t\n"); strings.TrimSpace(block) != "" {
+		b.blocks = append(b.blocks, block)
+	}
+}
+
+// addCode appends a code block to the current section, as a fenced block in its text
+func (b *sectionBuilder) addCode(block CodeBlock) {
+	if block.Content == "" {
+		return
+	}
+	b.add(block.Fenced())
+	b.codes = append(b.codes, block)
+}
+
+// flush closes the current section. Text before the first heading is dropped, as
+// ParseMarkdown does.
+func (b *sectionBuilder) flush() {
+	if b.heading != "" {
+		b.sections = append(b.sections, Section{
+			Heading:    b.heading,
+			Content:    strings.TrimSpace(strings.Join(b.blocks, "\n")),
+			Level:      b.level,
+			CodeBlocks: b.codes,
+		})
+	}
+	b.blocks = nil
+	b.codes = nil
+}
+
+// finish closes the last section and returns all sections
+func (b *sectionBuilder) finish() []Section {
+	if b.heading == "" && len(b.sections) == 0 && len(b.blocks) > 0 {
+		b.heading = "Content"
+		b.level = 1
+	}
+	b.flush()
+	return b.sections
+}
+
+// paragraphBuilder joins wrapped lines into paragraphs
+type paragraphBuilder struct {
+	lines []string
+}
+
+// line adds a line of the current paragraph
+func (p *paragraphBuilder) line(text string) {
+	if text = strings.TrimSpace(text); text != "" {
+		p.lines = append(p.lines, text)
+	}
+}
+
+// flush adds the current paragraph to b
+func (p *paragraphBuilder) flush(b *sectionBuilder) {
+	if len(p.lines) > 0 {
+		b.add(strings.Join(p.lines, " "))
+		p.lines = nil
+	}
+}
+
+// indentation returns the number of leading spaces of line, counting tabs as 4
+func indentation(line string) int {
+	n := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			n++
+		case '\t':
+			n += 4
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// dedent removes the common indentation of lines and trailing blank lines
+func dedent(lines []string) string {
+	common := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if n := indentation(line); common < 0 || n < common {
+			common = n
+		}
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var buf strings.Builder
+	for _, line := range lines {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		if len(line) >= common && common > 0 {
+			line = line[common:]
+		}
+		buf.WriteString(strings.TrimRight(line, " "))
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// splitLines splits content into lines, normalising line endings
+func splitLines(content []byte) []string {
+	return strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+}
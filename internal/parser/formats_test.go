@@ -0,0 +1,246 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseFile_Dispatch tests that ParseFile selects a parser by extension
+func TestParseFile_Dispatch(t *testing.T) {
+	for _, path := range []string{"a.md", "a.mdx", "a.rst", "a.adoc", "A.TXT"} {
+		if !IsDocumentPath(path) {
+			t.Errorf("Expected %s to be a document path", path)
+		}
+		if _, err := ParseFile([]byte("Hello\n"), path); err != nil {
+			t.Errorf("ParseFile(%s) failed: %v", path, err)
+		}
+	}
+
+	if IsDocumentPath("main.go") {
+		t.Error("Expected main.go not to be a document path")
+	}
+	if _, err := ParseFile([]byte("package main"), "main.go"); err == nil {
+		t.Error("Expected an error for an unsupported format")
+	}
+}
+
+// TestParseMDX tests that imports, exports, comments and component tags are removed
+func TestParseMDX(t *testing.T) {
+	mdx := `---
+title: Consumers
+---
+import Tabs from '@theme/Tabs';
+import {
+  TabItem,
+} from '@theme/TabItem';
+export const meta = {};
+
+# Consumers
+
+{/* Shown on every page */}
+<Tabs>
+  <TabItem value="go" label="Go">
+    Consumers track **delivery** of messages.
+  </TabItem>
+</Tabs>
+
+` + "```jsx\n<Tabs>example</Tabs>\n```\n"
+
+	doc, err := ParseMDX([]byte(mdx), "docs/consumers.mdx")
+	if err != nil {
+		t.Fatalf("ParseMDX failed: %v", err)
+	}
+	if doc.Title != "Consumers" || len(doc.Sections) != 1 {
+		t.Fatalf("Expected one Consumers section, got %q with %d sections", doc.Title, len(doc.Sections))
+	}
+
+	content := doc.Sections[0].Content
+	if !strings.Contains(content, "Consumers track delivery of messages.") {
+		t.Errorf("Expected component content to be kept, got %q", content)
+	}
+	if strings.Contains(content, "import") || strings.Contains(content, "TabItem") || strings.Contains(content, "Shown on") {
+		t.Errorf("Expected JSX to be removed, got %q", content)
+	}
+	if !strings.Contains(content, "<Tabs>example</Tabs>") {
+		t.Errorf("Expected code blocks to be kept verbatim, got %q", content)
+	}
+}
+
+// TestParseRST tests section levels, literal blocks, directives and inline markup
+func TestParseRST(t *testing.T) {
+	rst := `.. _jetstream:
+
+==========
+JetStream
+==========
+
+JetStream is the **persistence** layer, see :ref:` + "`Streams <streams>`" + `.
+
+Streams
+=======
+
+A stream stores messages. Read the ` + "`docs <https://docs.nats.io>`_" + `
+for details.
+
+- file storage
+- memory storage
+
+Example::
+
+    nats stream add ORDERS
+
+.. code-block:: go
+
+    js.AddStream(cfg)
+
+.. note:: Streams are replicated.
+
+.. image:: stream.png
+
+Limits
+------
+
+.. This comment is not indexed.
+
+Limits bound a stream.
+`
+
+	doc, err := ParseRST([]byte(rst), "doc/jetstream.rst")
+	if err != nil {
+		t.Fatalf("ParseRST failed: %v", err)
+	}
+	if doc.Title != "JetStream" {
+		t.Errorf("Expected title JetStream, got %q", doc.Title)
+	}
+
+	want := []struct {
+		heading string
+		level   int
+	}{{"JetStream", 1}, {"Streams", 2}, {"Limits", 3}}
+	if len(doc.Sections) != len(want) {
+		t.Fatalf("Expected %d sections, got %+v", len(want), doc.Sections)
+	}
+	for i, w := range want {
+		if doc.Sections[i].Heading != w.heading || doc.Sections[i].Level != w.level {
+			t.Errorf("Expected section %d to be %s at level %d, got %s at %d",
+				i, w.heading, w.level, doc.Sections[i].Heading, doc.Sections[i].Level)
+		}
+	}
+
+	if got := doc.Sections[0].Content; got != "JetStream is the persistence layer, see Streams." {
+		t.Errorf("Unexpected inline markup handling: %q", got)
+	}
+
+	streams := doc.Sections[1].Content
+	for _, want := range []string{
+		"Read the docs for details.",
+		"• file storage",
+		"Example:",
+		"nats stream add ORDERS",
+		"js.AddStream(cfg)",
+		"Streams are replicated.",
+	} {
+		if !strings.Contains(streams, want) {
+			t.Errorf("Expected Streams section to contain %q, got %q", want, streams)
+		}
+	}
+	if strings.Contains(streams, "stream.png") || strings.Contains(doc.Sections[2].Content, "comment") {
+		t.Errorf("Expected images and comments to be dropped")
+	}
+}
+
+// TestParseAsciiDoc tests the header, section levels, blocks, tables and inline markup
+func TestParseAsciiDoc(t *testing.T) {
+	adoc := `= NATS Server Operations
+Jane Doe <jane@example.com>
+:toc: left
+
+Operating *nats-server* with max_ack_pending set, see <<monitoring,Monitoring>>.
+
+// An editorial comment
+
+[[monitoring]]
+== Monitoring
+
+Enable the https://docs.nats.io/monitoring[monitoring endpoint].
+
+.Start the server
+[source,shell]
+----
+nats-server -m 8222
+----
+
+[cols="1,2"]
+|===
+| Endpoint | Description
+| /varz | General information
+|===
+
+=== Options
+
+* ` + "`-m`" + ` sets the port
+* _Use_ TLS
+
+////
+A comment block
+////
+`
+
+	doc, err := ParseAsciiDoc([]byte(adoc), "ops.adoc")
+	if err != nil {
+		t.Fatalf("ParseAsciiDoc failed: %v", err)
+	}
+	if doc.Title != "NATS Server Operations" {
+		t.Errorf("Expected the document title, got %q", doc.Title)
+	}
+	if len(doc.Sections) != 3 || doc.Sections[1].Level != 2 || doc.Sections[2].Level != 3 {
+		t.Fatalf("Expected three sections at levels 1-3, got %+v", doc.Sections)
+	}
+
+	if got := doc.Sections[0].Content; got != "Operating nats-server with max_ack_pending set, see Monitoring." {
+		t.Errorf("Unexpected preamble: %q", got)
+	}
+
+	monitoring := doc.Sections[1].Content
+	for _, want := range []string{
+		"Enable the monitoring endpoint.",
+		"Start the server",
+		"nats-server -m 8222",
+		"/varz | General information",
+	} {
+		if !strings.Contains(monitoring, want) {
+			t.Errorf("Expected Monitoring section to contain %q, got %q", want, monitoring)
+		}
+	}
+	if strings.Contains(monitoring, "source,shell") || strings.Contains(monitoring, "cols=") {
+		t.Errorf("Expected block attributes to be dropped, got %q", monitoring)
+	}
+
+	options := doc.Sections[2].Content
+	if !strings.Contains(options, "• -m sets the port") || !strings.Contains(options, "• Use TLS") {
+		t.Errorf("Expected list items without markup, got %q", options)
+	}
+	if strings.Contains(options, "comment block") || strings.Contains(doc.Sections[0].Content, "editorial") {
+		t.Error("Expected comments to be dropped")
+	}
+}
+
+// TestParseText tests titles of plain text files
+func TestParseText(t *testing.T) {
+	doc, err := ParseText([]byte("NATS Release Process\n====================\n\nTag the release.\nPush the tag.\n\nAnnounce it.\n"), "RELEASING.txt")
+	if err != nil {
+		t.Fatalf("ParseText failed: %v", err)
+	}
+	if doc.Title != "NATS Release Process" {
+		t.Errorf("Expected the first line as title, got %q", doc.Title)
+	}
+	if len(doc.Sections) != 1 || doc.Sections[0].Content != "Tag the release. Push the tag.\nAnnounce it." {
+		t.Errorf("Unexpected sections: %+v", doc.Sections)
+	}
+
+	long := strings.Repeat("word ", 30)
+	doc, _ = ParseText([]byte(long), "docs/release_notes.txt")
+	if doc.Title != "Release Notes" {
+		t.Errorf("Expected a title from the file name, got %q", doc.Title)
+	}
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// IsGoExamplePath reports whether the file at filepath holds Go testable examples:
+// example_test.go, or example_<name>_test.go as some packages split them
+func IsGoExamplePath(filepath string) bool {
+	name := path.Base(filepath)
+	return name == "example_test.go" ||
+		(strings.HasPrefix(name, "example_") && strings.HasSuffix(name, "_test.go"))
+}
+
+// ParseGoExamples parses a Go test file into a section per testable example (func
+// ExampleXxx). A section holds the example's doc comment, its code as a Go code
+// block and its expected output.
+func ParseGoExamples(content []byte, filepath string) (*Document, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filepath, content, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Go file: %w", err)
+	}
+
+	pkg := strings.TrimSuffix(file.Name.Name, "_test")
+	result := &Document{Title: pkg + " examples"}
+	for _, ex := range doc.Examples(file) {
+		code, err := exampleCode(fset, ex.Code)
+		if err != nil {
+			continue
+		}
+		block := newCodeBlock("go", code, "Example"+ex.Name)
+
+		var blocks []string
+		if text := strings.TrimSpace(ex.Doc); text != "" {
+			blocks = append(blocks, text)
+		}
+		blocks = append(blocks, block.Fenced())
+		if output := strings.TrimSpace(ex.Output); output != "" {
+			blocks = append(blocks, "Output:\n"+output)
+		}
+
+		result.Sections = append(result.Sections, Section{
+			Heading:    exampleHeading(ex),
+			Content:    strings.Join(blocks, "\n\n"),
+			Level:      2,
+			CodeBlocks: []CodeBlock{block},
+		})
+	}
+	if len(result.Sections) == 0 {
+		return nil, fmt.Errorf("no examples found in %s", filepath)
+	}
+	return result, nil
+}
+
+// exampleHeading names an example after the symbol it documents, e.g. "Example:
+// Conn.Publish (json)" for ExampleConn_Publish_json. As in go doc, a last part
+// starting with a lower-case letter is a suffix distinguishing examples of a symbol.
+func exampleHeading(ex *doc.Example) string {
+	symbol, suffix := ex.Name, ""
+	if i := strings.LastIndex(symbol, "_"); i >= 0 && i+1 < len(symbol) && unicode.IsLower(rune(symbol[i+1])) {
+		symbol, suffix = symbol[:i], symbol[i+1:]
+	}
+	heading := "Example"
+	if symbol != "" {
+		heading += ": " + strings.ReplaceAll(symbol, "_", ".")
+	}
+	if suffix != "" {
+		heading += " (" + suffix + ")"
+	}
+	return heading
+}
+
+// exampleCode prints the body of an example function without its braces and with one
+// level of indentation removed
+func exampleCode(fset *token.FileSet, code ast.Node) (string, error) {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, code); err != nil {
+		return "", err
+	}
+	text := strings.TrimSpace(buf.String())
+	if !strings.HasPrefix(text, "{") || !strings.HasSuffix(text, "}") {
+		return text, nil
+	}
+
+	lines := strings.Split(strings.Trim(text[1:len(text)-1], "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "\t")
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const exampleFile = `package nats_test
+
+import (
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Shows how to fetch a batch of messages with a pull consumer.
+func ExampleJetStreamContext_PullSubscribe() {
+	nc, _ := nats.Connect(nats.DefaultURL)
+	js, _ := nc.JetStream()
+
+	sub, _ := js.PullSubscribe("ORDERS.*", "worker")
+	msgs, _ := sub.Fetch(10)
+	fmt.Println(len(msgs))
+	// Output: 10
+}
+
+func ExampleConn_Publish_headers() {
+	nc, _ := nats.Connect(nats.DefaultURL)
+	nc.Publish("updates", nil)
+}
+
+func helper() {}
+`
+
+func TestParseGoExamples(t *testing.T) {
+	if !IsGoExamplePath("nats.go/example_test.go") || !IsGoExamplePath("jetstream/example_pull_test.go") {
+		t.Error("expected example files to be recognised")
+	}
+	if IsGoExamplePath("nats_test.go") || IsGoExamplePath("example.go") {
+		t.Error("expected other Go files not to be example files")
+	}
+
+	doc, err := ParseFile([]byte(exampleFile), "example_test.go")
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	if doc.Title != "nats examples" {
+		t.Errorf("unexpected title: %q", doc.Title)
+	}
+
+	var headings []string
+	for _, section := range doc.Sections {
+		headings = append(headings, section.Heading)
+	}
+	want := []string{"Example: Conn.Publish (headers)", "Example: JetStreamContext.PullSubscribe"}
+	if !reflect.DeepEqual(headings, want) {
+		t.Fatalf("headings = %q, want %q", headings, want)
+	}
+
+	pull := doc.Sections[1]
+	if len(pull.CodeBlocks) != 1 || pull.CodeBlocks[0].Language != "go" || pull.CodeBlocks[0].Caption != "ExampleJetStreamContext_PullSubscribe" {
+		t.Fatalf("expected one Go code block, got %+v", pull.CodeBlocks)
+	}
+	code := pull.CodeBlocks[0].Content
+	if !strings.HasPrefix(code, "nc, _ := nats.Connect(nats.DefaultURL)") || strings.Contains(code, "// Output:") {
+		t.Errorf("expected the unindented function body, got %q", code)
+	}
+	for _, text := range []string{"Shows how to fetch a batch of messages", "```go", "Output:\n10"} {
+		if !strings.Contains(pull.Content, text) {
+			t.Errorf("expected %q in the section content, got %q", text, pull.Content)
+		}
+	}
+
+	if _, err := ParseFile([]byte("package nats_test\n\nfunc helper() {}\n"), "example_test.go"); err == nil {
+		t.Error("expected an error for a file without examples")
+	}
+}
@@ -9,13 +9,16 @@ import (
 
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
 	"github.com/yuin/goldmark/text"
 )
 
-// ParseMarkdown parses markdown content and extracts title and sections
+// ParseMarkdown parses markdown content and extracts title and sections. GitHub
+// Flavored Markdown tables are kept as structured Tables.
 func ParseMarkdown(content []byte, filepath string) (*Document, error) {
-	md := goldmark.New()
-	source := text.NewReader(content)
+	md := goldmark.New(goldmark.WithExtensions(extension.Table))
+	source := text.NewReader(maskFrontmatter(content))
 	doc := md.Parser().Parse(source)
 
 	// Extract title from frontmatter or first heading
@@ -52,21 +55,28 @@ func extractTitleFromMarkdown(doc ast.Node, filepath string, content []byte) str
 	}
 
 	// Fall back to filename
-	if filepath != "" {
-		parts := strings.Split(filepath, "/")
-		filename := parts[len(parts)-1]
-		// Remove .md extension
-		if strings.HasSuffix(filename, ".md") {
-			filename = filename[:len(filename)-3]
-		}
-		// Convert underscores/hyphens to spaces and capitalize
-		filename = strings.ReplaceAll(filename, "_", " ")
-		filename = strings.ReplaceAll(filename, "-", " ")
-		filename = strings.Title(filename)
-		return filename
+	return titleFromPath(filepath)
+}
+
+// maskFrontmatter blanks out YAML frontmatter so it is not parsed as a setext heading.
+// Newlines are kept, so node segments index the original content.
+func maskFrontmatter(content []byte) []byte {
+	if !bytes.HasPrefix(content, []byte("---")) {
+		return content
+	}
+	end := bytes.Index(content[3:], []byte("\n---"))
+	if end < 0 {
+		return content
 	}
+	end += 3 + len("\n---")
 
-	return "Untitled"
+	masked := append([]byte(nil), content...)
+	for i := 0; i < end; i++ {
+		if masked[i] != '\n' {
+			masked[i] = ' '
+		}
+	}
+	return masked
 }
 
 // extractFrontmatterTitle extracts title from YAML frontmatter
@@ -139,6 +149,8 @@ func extractMarkdownSections(doc ast.Node, source []byte) []Section {
 	var currentContent strings.Builder
 	var currentHeading string
 	var currentLevel int
+	var currentCode []CodeBlock
+	var currentTables []Table
 
 	walker := doc.FirstChild()
 	for walker != nil {
@@ -146,11 +158,15 @@ func extractMarkdownSections(doc ast.Node, source []byte) []Section {
 			// Save previous section if it exists
 			if currentHeading != "" {
 				sections = append(sections, Section{
-					Heading: currentHeading,
-					Content: strings.TrimSpace(currentContent.String()),
-					Level:   currentLevel,
+					Heading:    currentHeading,
+					Content:    strings.TrimSpace(currentContent.String()),
+					Level:      currentLevel,
+					CodeBlocks: currentCode,
+					Tables:     currentTables,
 				})
 				currentContent.Reset()
+				currentCode = nil
+				currentTables = nil
 			}
 
 			// Start new section
@@ -164,6 +180,8 @@ func extractMarkdownSections(doc ast.Node, source []byte) []Section {
 				currentContent.WriteString(content)
 				currentContent.WriteString("\n")
 			}
+			currentCode = append(currentCode, markdownCodeBlocks(walker, source)...)
+			currentTables = append(currentTables, markdownTables(walker, source)...)
 		}
 
 		walker = walker.NextSibling()
@@ -172,9 +190,11 @@ func extractMarkdownSections(doc ast.Node, source []byte) []Section {
 	// Don't forget the last section
 	if currentHeading != "" {
 		sections = append(sections, Section{
-			Heading: currentHeading,
-			Content: strings.TrimSpace(currentContent.String()),
-			Level:   currentLevel,
+			Heading:    currentHeading,
+			Content:    strings.TrimSpace(currentContent.String()),
+			Level:      currentLevel,
+			CodeBlocks: currentCode,
+			Tables:     currentTables,
 		})
 	}
 
@@ -183,9 +203,11 @@ func extractMarkdownSections(doc ast.Node, source []byte) []Section {
 		allContent := extractContentFromNode(doc, source)
 		if allContent != "" {
 			sections = append(sections, Section{
-				Heading: "Content",
-				Content: allContent,
-				Level:   1,
+				Heading:    "Content",
+				Content:    allContent,
+				Level:      1,
+				CodeBlocks: markdownCodeBlocks(doc, source),
+				Tables:     markdownTables(doc, source),
 			})
 		}
 	}
@@ -198,14 +220,11 @@ func extractContentFromNode(node ast.Node, source []byte) string {
 	switch n := node.(type) {
 	case *ast.Paragraph:
 		return extractTextFromNode(n, source)
-	case *ast.FencedCodeBlock:
-		var buf bytes.Buffer
-		lines := n.Lines()
-		for i := 0; i < lines.Len(); i++ {
-			line := lines.At(i)
-			buf.Write(line.Value(source))
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		if block, ok := markdownCodeBlock(n, source); ok {
+			return block.Fenced()
 		}
-		return buf.String()
+		return ""
 	case *ast.ListItem:
 		var buf bytes.Buffer
 		walker := n.FirstChild()
@@ -243,6 +262,11 @@ func extractContentFromNode(node ast.Node, source []byte) string {
 			walker = walker.NextSibling()
 		}
 		return buf.String()
+	case *east.Table:
+		if table, ok := markdownTable(n, source); ok {
+			return table.Markdown()
+		}
+		return ""
 	case *ast.HTMLBlock:
 		// Skip HTML blocks
 		return ""
@@ -272,6 +296,49 @@ func extractContentFromNode(node ast.Node, source []byte) string {
 	}
 }
 
+// markdownCodeBlock returns the code block of a fenced or indented code block node.
+// The language and caption of a fenced block come from its info string, e.g.
+// ```go title="main.go"
+func markdownCodeBlock(node ast.Node, source []byte) (CodeBlock, bool) {
+	var info string
+	switch n := node.(type) {
+	case *ast.FencedCodeBlock:
+		if n.Info != nil {
+			info = string(n.Info.Segment.Value(source))
+		}
+	case *ast.CodeBlock:
+	default:
+		return CodeBlock{}, false
+	}
+
+	var buf bytes.Buffer
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		buf.Write(line.Value(source))
+	}
+	language, caption := fenceInfo(info)
+	block := newCodeBlock(language, buf.String(), caption)
+	return block, block.Content != ""
+}
+
+// markdownCodeBlocks returns the code blocks of node and its descendants, e.g. in
+// list items
+func markdownCodeBlocks(node ast.Node, source []byte) []CodeBlock {
+	var blocks []CodeBlock
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if block, ok := markdownCodeBlock(n, source); ok {
+			blocks = append(blocks, block)
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return blocks
+}
+
 // TitleCase converts a string to title case
 // This is a simple implementation - a more robust one would use unicode.ToTitle
 func titleCase(s string) string {
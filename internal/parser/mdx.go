@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// mdxStatement matches the ES module statements MDX files start with
+	mdxStatement = regexp.MustCompile(`^(import|export)\s`)
+	// mdxComponent matches opening, closing and self-closing JSX component tags
+	mdxComponent = regexp.MustCompile(`</?[A-Z][\w.]*(\s[^<>]*)?/?>`)
+	// mdxComment matches JSX comments
+	mdxComment = regexp.MustCompile(`\{/\*.*?\*/\}`)
+)
+
+// ParseMDX parses MDX content. Import and export statements, JSX comments and
+// component tags are removed, keeping the markdown inside components, and the
+// result is parsed as markdown.
+func ParseMDX(content []byte, filepath string) (*Document, error) {
+	return ParseMarkdown(stripMDX(content), filepath)
+}
+
+// stripMDX removes the JSX parts of MDX content outside fenced code blocks
+func stripMDX(content []byte) []byte {
+	var buf strings.Builder
+	inFence := false
+	inStatement := false
+	depth := 0 // Number of open components
+	for _, line := range splitLines(content) {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if inFence {
+			buf.WriteString(line)
+			buf.WriteString("\n")
+			continue
+		}
+
+		// Multi-line statements end with the line closing their braces
+		if inStatement {
+			inStatement = !strings.Contains(line, "}")
+			continue
+		}
+		if mdxStatement.MatchString(line) {
+			inStatement = strings.Count(line, "{") > strings.Count(line, "}")
+			continue
+		}
+
+		line = mdxComment.ReplaceAllString(line, "")
+		for _, tag := range mdxComponent.FindAllString(line, -1) {
+			switch {
+			case strings.HasPrefix(tag, "</"):
+				depth--
+			case !strings.HasSuffix(tag, "/>"):
+				depth++
+			}
+		}
+		stripped := mdxComponent.ReplaceAllString(line, "")
+		if depth > 0 || stripped != line {
+			// Content nested in components is indented; keep it out of code blocks
+			stripped = strings.TrimLeft(stripped, " \t")
+		}
+		buf.WriteString(stripped)
+		buf.WriteString("\n")
+	}
+	return []byte(buf.String())
+}
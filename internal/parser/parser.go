@@ -1,5 +1,5 @@
 // Package parser provides HTML parsing functionality for extracting structured
-// content from NATS documentation pages, including titles, headings, and code blocks.
+// content from NATS documentation pages, including titles, headings, code blocks and tables.
 package parser
 
 import (
@@ -17,29 +17,17 @@ type Document struct {
 
 // Section represents a subsection within a document
 type Section struct {
-	Heading string
-	Content string
-	Level   int
+	Heading    string
+	Content    string
+	Level      int
+	CodeBlocks []CodeBlock // Code blocks of the section, in order
+	Tables     []Table     // Tables of the section, in order
 }
 
-// ParseHTML parses an HTML document and extracts structured content
+// ParseHTML parses an HTML document and extracts structured content from the whole
+// page. The title falls back to og:title and then the first h1.
 func ParseHTML(r io.Reader) (*Document, error) {
-	doc, err := html.Parse(r)
-	if err != nil {
-		return nil, err
-	}
-
-	result := &Document{
-		Sections: make([]Section, 0),
-	}
-
-	// Extract title
-	result.Title = extractTitle(doc)
-
-	// Extract sections based on headings
-	extractSections(doc, result)
-
-	return result, nil
+	return ParseHTMLWithProfile(r, Profile{})
 }
 
 // extractTitle finds and returns the document title
@@ -59,10 +47,14 @@ func extractTitle(n *html.Node) string {
 
 // extractSections walks the HTML tree and extracts sections based on headings.
 // Each section starts with a heading (h1-h6) and includes all content until the next heading.
-// Content includes paragraphs, code blocks, lists, and other elements.
+// Content includes paragraphs, code blocks, lists, and other elements. Code blocks
+// (<pre>) are also kept as structured CodeBlocks, while inline <code> is text.
+// Tables are kept as structured Tables and rendered as Markdown tables in the content.
 func extractSections(n *html.Node, doc *Document) {
 	var currentSection *Section
 	var contentBuilder strings.Builder
+	var codeBlocks []CodeBlock
+	var tables []Table
 
 	var walk func(*html.Node)
 	walk = func(node *html.Node) {
@@ -72,6 +64,8 @@ func extractSections(n *html.Node, doc *Document) {
 				// Save previous section if exists
 				if currentSection != nil {
 					currentSection.Content = strings.TrimSpace(contentBuilder.String())
+					currentSection.CodeBlocks = codeBlocks
+					currentSection.Tables = tables
 					doc.Sections = append(doc.Sections, *currentSection)
 				}
 
@@ -81,26 +75,50 @@ func extractSections(n *html.Node, doc *Document) {
 					Level:   level,
 				}
 				contentBuilder.Reset()
+				codeBlocks = nil
+				tables = nil
 				return // Don't process children of heading
 			}
 
-			// Handle code blocks - preserve formatting
-			if node.Data == "pre" || node.Data == "code" {
-				contentBuilder.WriteString(extractText(node))
-				contentBuilder.WriteString("\n")
+			// Handle code blocks - preserve formatting and language
+			if node.Data == "pre" {
+				if block := htmlCodeBlock(node); block.Content != "" {
+					codeBlocks = append(codeBlocks, block)
+					contentBuilder.WriteString(block.Fenced())
+					contentBuilder.WriteString("\n")
+				}
 				return // Don't process children separately
 			}
 
+			// Handle tables - keep rows and columns
+			if node.Data == "table" {
+				if table, ok := htmlTable(node); ok {
+					tables = append(tables, table)
+					contentBuilder.WriteString(table.Markdown())
+					contentBuilder.WriteString("\n\n")
+				}
+				return
+			}
+
+			// Inline code outside a paragraph is text, not a block
+			if node.Data == "code" {
+				contentBuilder.WriteString(extractText(node))
+				contentBuilder.WriteString(" ")
+				return
+			}
+
 			// Handle list items
 			if node.Data == "li" {
 				contentBuilder.WriteString("• ")
 				contentBuilder.WriteString(extractText(node))
 				contentBuilder.WriteString("\n")
+				codeBlocks = append(codeBlocks, nestedCodeBlocks(node)...)
 				return
 			}
 
-			// Handle paragraphs and other block elements
-			if node.Data == "p" || node.Data == "div" {
+			// Handle paragraphs and other block elements. A div wrapping headings, code
+			// blocks or tables is a layout container, so its children are walked instead.
+			if node.Data == "p" || (node.Data == "div" && !containsBlock(node)) {
 				text := extractText(node)
 				if text != "" {
 					contentBuilder.WriteString(text)
@@ -121,10 +139,39 @@ func extractSections(n *html.Node, doc *Document) {
 	// Save last section if exists
 	if currentSection != nil {
 		currentSection.Content = strings.TrimSpace(contentBuilder.String())
+		currentSection.CodeBlocks = codeBlocks
+		currentSection.Tables = tables
 		doc.Sections = append(doc.Sections, *currentSection)
 	}
 }
 
+// containsBlock reports whether an h1-h6, <pre> or <table> element is nested anywhere
+// under n
+func containsBlock(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && (getHeadingLevel(c.Data) > 0 || c.Data == "pre" || c.Data == "table" || containsBlock(c)) {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedCodeBlocks returns the code blocks of the <pre> elements under n, e.g. in a
+// list item whose text is flattened
+func nestedCodeBlocks(n *html.Node) []CodeBlock {
+	var blocks []CodeBlock
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "pre" {
+			if block := htmlCodeBlock(c); block.Content != "" {
+				blocks = append(blocks, block)
+			}
+			continue
+		}
+		blocks = append(blocks, nestedCodeBlocks(c)...)
+	}
+	return blocks
+}
+
 // getHeadingLevel returns the heading level (1-6) or 0 if not a heading
 func getHeadingLevel(tag string) int {
 	switch tag {
@@ -314,8 +314,9 @@ func TestParseHTML_NoTitle(t *testing.T) {
 		t.Fatalf("ParseHTML failed: %v", err)
 	}
 
-	if doc.Title != "" {
-		t.Errorf("Expected empty title, got '%s'", doc.Title)
+	// The first h1 stands in for the missing title
+	if doc.Title != "Content Only" {
+		t.Errorf("Expected title 'Content Only', got '%s'", doc.Title)
 	}
 
 	if len(doc.Sections) != 1 {
@@ -0,0 +1,230 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Profile tells ParseHTMLWithProfile which part of a documentation site's pages is
+// content. Site chrome such as navigation, sidebars, "Last updated" footers, cookie
+// banners and "Previous/Next" links would otherwise be indexed with every page.
+// Selectors use the CSS-like syntax described on selector.
+type Profile struct {
+	Name          string   // Name of the profile, for logs and configuration
+	Content       []string // Selectors of the content root, tried in order; the whole page when none matches
+	Remove        []string // Selectors of elements dropped from the page, e.g. "nav, aside"
+	TitleSuffixes []string // Site names stripped from the end of page titles, e.g. " | NATS Docs"
+}
+
+// withCommonRemove returns the selectors of elements that are never documentation
+// content followed by extra
+func withCommonRemove(extra ...string) []string {
+	return append([]string{
+		"script", "style", "noscript", "template", "svg", "button",
+		"nav", "aside", "body > header", "body > footer", "main footer", "article footer",
+		"[role=navigation]", "[role=banner]", "[role=contentinfo]", "[aria-hidden=true]",
+		"[id*=cookie]", "[class*=cookie]", "[id*=consent]", "[class*=consent]",
+		"a[rel=prev]", "a[rel=next]", "[aria-label=Pagination]", "[class*=pagination]",
+		"div:starts-with(Last updated)", "p:starts-with(Last updated)",
+		"[class*=breadcrumb]", "[aria-label=Breadcrumb]",
+	}, extra...)
+}
+
+// Built-in profiles of the documentation sites indexed by default
+var (
+	// NATSDocsProfile extracts docs.nats.io, a GitBook site
+	NATSDocsProfile = Profile{
+		Name:          "nats-docs",
+		Content:       []string{"main", "[role=main]", "article"},
+		Remove:        withCommonRemove("[class*=page-footer]", "[class*=last-updated]"),
+		TitleSuffixes: []string{" | NATS Docs", " - NATS Docs", " | NATS Documentation"},
+	}
+
+	// SynadiaDocsProfile extracts docs.synadia.com
+	SynadiaDocsProfile = Profile{
+		Name:          "synadia-docs",
+		Content:       []string{"main article", "article", "main", "[role=main]"},
+		Remove:        withCommonRemove("[class*=doc-footer]", "[class*=table-of-contents]"),
+		TitleSuffixes: []string{" | Synadia Docs", " - Synadia Docs", " | Synadia Documentation", " | Synadia"},
+	}
+)
+
+// builtinProfiles maps the names of built-in profiles to them. "none" indexes whole
+// pages.
+var builtinProfiles = map[string]Profile{
+	NATSDocsProfile.Name:    NATSDocsProfile,
+	SynadiaDocsProfile.Name: SynadiaDocsProfile,
+	"none":                  {Name: "none"},
+}
+
+// profileHosts maps documentation hosts to their built-in profile
+var profileHosts = map[string]string{
+	"docs.nats.io":     NATSDocsProfile.Name,
+	"docs.synadia.com": SynadiaDocsProfile.Name,
+}
+
+// BuiltinProfile returns the built-in profile called name
+func BuiltinProfile(name string) (Profile, bool) {
+	profile, ok := builtinProfiles[name]
+	return profile, ok
+}
+
+// BuiltinProfileNames returns the names of the built-in profiles, sorted
+func BuiltinProfileNames() []string {
+	names := make([]string, 0, len(builtinProfiles))
+	for name := range builtinProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProfileForURL returns the built-in profile of the site at baseURL, or the "none"
+// profile when the site has none
+func ProfileForURL(baseURL string) Profile {
+	if u, err := url.Parse(baseURL); err == nil {
+		if name, ok := profileHosts[strings.ToLower(u.Hostname())]; ok {
+			return builtinProfiles[name]
+		}
+	}
+	return builtinProfiles["none"]
+}
+
+// Validate reports the first selector of the profile that cannot be parsed
+func (p Profile) Validate() error {
+	_, err := p.compile()
+	return err
+}
+
+// compiledProfile holds the parsed selectors of a profile
+type compiledProfile struct {
+	content []selector
+	remove  []selector
+}
+
+func (p Profile) compile() (compiledProfile, error) {
+	var c compiledProfile
+	for _, text := range p.Content {
+		s, err := parseSelector(text)
+		if err != nil {
+			return c, fmt.Errorf("content: %w", err)
+		}
+		c.content = append(c.content, s)
+	}
+	for _, text := range p.Remove {
+		s, err := parseSelector(text)
+		if err != nil {
+			return c, fmt.Errorf("remove: %w", err)
+		}
+		c.remove = append(c.remove, s)
+	}
+	return c, nil
+}
+
+// ParseHTMLWithProfile parses an HTML document like ParseHTML, extracting sections
+// from the profile's content root only, without the elements it removes, and
+// stripping its site names from the title
+func ParseHTMLWithProfile(r io.Reader, profile Profile) (*Document, error) {
+	compiled, err := profile.compile()
+	if err != nil {
+		return nil, fmt.Errorf("invalid extraction profile %q: %w", profile.Name, err)
+	}
+
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Document{
+		Sections: make([]Section, 0),
+	}
+
+	// The title comes from the head, before site chrome is removed
+	result.Title = stripTitleSuffix(extractTitle(doc), profile.TitleSuffixes)
+	if result.Title == "" {
+		result.Title = stripTitleSuffix(metaContent(doc, "og:title"), profile.TitleSuffixes)
+	}
+
+	for _, remove := range compiled.remove {
+		removeMatching(doc, remove)
+	}
+	root := doc
+	for _, content := range compiled.content {
+		if n := findFirst(doc, content); n != nil {
+			root = n
+			break
+		}
+	}
+
+	if result.Title == "" {
+		if h1 := findFirst(root, h1Selector); h1 != nil {
+			result.Title = strings.TrimSpace(extractText(h1))
+		}
+	}
+
+	extractSections(root, result)
+
+	return result, nil
+}
+
+// h1Selector matches the headings a missing title falls back to
+var h1Selector = selector{{{compound: compound{tag: "h1"}}}}
+
+// stripTitleSuffix removes the first matching site name from the end of title. A
+// title that is only the site name is treated as missing.
+func stripTitleSuffix(title string, suffixes []string) string {
+	title = strings.TrimSpace(title)
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(title, suffix) {
+			return strings.TrimSpace(strings.TrimSuffix(title, suffix))
+		}
+		if title == strings.TrimSpace(strings.TrimLeft(suffix, " |-–—:")) {
+			return ""
+		}
+	}
+	return title
+}
+
+// metaContent returns the content of the <meta> element whose property or name is key
+func metaContent(n *html.Node, key string) string {
+	if n.Type == html.ElementNode && n.Data == "meta" && (attr(n, "property") == key || attr(n, "name") == key) {
+		return attr(n, "content")
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if content := metaContent(c, key); content != "" {
+			return content
+		}
+	}
+	return ""
+}
+
+// findFirst returns the first element under n, in document order, matching s
+func findFirst(n *html.Node, s selector) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if s.matches(c) {
+			return c
+		}
+		if found := findFirst(c, s); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// removeMatching detaches every element under n matching s from the tree
+func removeMatching(n *html.Node, s selector) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if s.matches(c) {
+			n.RemoveChild(c)
+		} else {
+			removeMatching(c, s)
+		}
+		c = next
+	}
+}
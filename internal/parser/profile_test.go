@@ -0,0 +1,174 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestSelectorMatches(t *testing.T) {
+	page := `<html><body>
+<div id="layout" class="page wide">
+	<nav class="sidebar"><a href="/a" rel="prev">Previous</a></nav>
+	<main role="main"><section data-kind="cookie-banner"><p>Last updated 3 months ago</p></section></main>
+</div>
+</body></html>`
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("failed to parse page: %v", err)
+	}
+
+	tests := []struct {
+		selector string
+		want     string // Tag of the first match; empty when nothing matches
+	}{
+		{"main", "main"},
+		{"#layout", "div"},
+		{"div.page.wide", "div"},
+		{"div.page.narrow", ""},
+		{"[role=main]", "main"},
+		{"[data-kind^=cookie]", "section"},
+		{"[data-kind$=banner]", "section"},
+		{"[data-kind*=kie-ban]", "section"},
+		{"[class~=wide]", "div"},
+		{"[class~=wid]", ""},
+		{"nav a[rel=prev]", "a"},
+		{"div > nav", "nav"},
+		{"body > nav", ""},
+		{"p:starts-with(Last updated)", "p"},
+		{`p:starts-with("Updated")`, ""},
+		{"article, main", "main"},
+		{"* > section", "section"},
+	}
+	for _, tt := range tests {
+		s, err := parseSelector(tt.selector)
+		if err != nil {
+			t.Errorf("parseSelector(%q) failed: %v", tt.selector, err)
+			continue
+		}
+		got := ""
+		if n := findFirst(doc, s); n != nil {
+			got = n.Data
+		}
+		if got != tt.want {
+			t.Errorf("selector %q matched %q, want %q", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestParseSelectorErrors(t *testing.T) {
+	for _, text := range []string{"", "div,", "> div", "div >", "div..x", "#", "[", "[=x]", "a:hover", "p:starts-with()", "div!"} {
+		if _, err := parseSelector(text); err == nil {
+			t.Errorf("expected parseSelector(%q) to fail", text)
+		}
+	}
+}
+
+const gitBookPage = `<!DOCTYPE html>
+<html>
+<head><title>JetStream | NATS Docs</title><meta property="og:title" content="JetStream"></head>
+<body>
+	<header><a href="/">NATS Docs</a><input placeholder="Search"></header>
+	<div class="layout">
+		<aside><nav><ul><li>Welcome</li><li>JetStream</li></ul></nav></aside>
+		<main>
+			<h1>JetStream</h1>
+			<p>JetStream is the NATS persistence layer.</p>
+			<h2>Streams</h2>
+			<p>Streams store messages.</p>
+			<div class="pagination"><a href="/prev">Previous Welcome</a><a href="/next">Next Consumers</a></div>
+			<div>Last updated 2 months ago</div>
+		</main>
+	</div>
+	<div id="cookie-banner"><p>We use cookies.</p></div>
+	<footer><p>Copyright Synadia</p></footer>
+</body>
+</html>`
+
+func TestParseHTMLWithProfile_NATSDocs(t *testing.T) {
+	doc, err := ParseHTMLWithProfile(strings.NewReader(gitBookPage), NATSDocsProfile)
+	if err != nil {
+		t.Fatalf("ParseHTMLWithProfile failed: %v", err)
+	}
+
+	if doc.Title != "JetStream" {
+		t.Errorf("expected the site name stripped from the title, got %q", doc.Title)
+	}
+	if len(doc.Sections) != 2 {
+		t.Fatalf("expected 2 sections, got %d: %+v", len(doc.Sections), doc.Sections)
+	}
+	if doc.Sections[0].Content != "JetStream is the NATS persistence layer." {
+		t.Errorf("unexpected first section content: %q", doc.Sections[0].Content)
+	}
+	all := doc.Sections[0].Content + doc.Sections[1].Content
+	for _, chrome := range []string{"Welcome", "Search", "Previous", "Last updated", "cookies", "Copyright"} {
+		if strings.Contains(all, chrome) {
+			t.Errorf("expected %q to be removed, got %q", chrome, all)
+		}
+	}
+
+	// Without a profile the whole page is indexed
+	doc, err = ParseHTML(strings.NewReader(gitBookPage))
+	if err != nil {
+		t.Fatalf("ParseHTML failed: %v", err)
+	}
+	if doc.Title != "JetStream | NATS Docs" {
+		t.Errorf("expected the title unchanged without a profile, got %q", doc.Title)
+	}
+	if !strings.Contains(doc.Sections[len(doc.Sections)-1].Content, "Copyright") {
+		t.Error("expected the footer to be indexed without a profile")
+	}
+}
+
+func TestParseHTMLWithProfile_TitleFallbacks(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		want string
+	}{
+		{
+			name: "og:title when the title is only the site name",
+			page: `<html><head><title>NATS Docs</title><meta property="og:title" content="Consumers | NATS Docs"></head><body><main><h1>Heading</h1></main></body></html>`,
+			want: "Consumers",
+		},
+		{
+			name: "first h1 of the content root",
+			page: `<html><head></head><body><h1>Site</h1><main><h1>Subjects</h1><p>Text</p></main></body></html>`,
+			want: "Subjects",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ParseHTMLWithProfile(strings.NewReader(tt.page), NATSDocsProfile)
+			if err != nil {
+				t.Fatalf("ParseHTMLWithProfile failed: %v", err)
+			}
+			if doc.Title != tt.want {
+				t.Errorf("expected title %q, got %q", tt.want, doc.Title)
+			}
+		})
+	}
+}
+
+func TestProfileForURL(t *testing.T) {
+	if got := ProfileForURL("https://docs.nats.io").Name; got != "nats-docs" {
+		t.Errorf("expected the nats-docs profile for docs.nats.io, got %q", got)
+	}
+	if got := ProfileForURL("https://DOCS.SYNADIA.COM/control-plane").Name; got != "synadia-docs" {
+		t.Errorf("expected the synadia-docs profile for docs.synadia.com, got %q", got)
+	}
+	if got := ProfileForURL("http://localhost:8080").Name; got != "none" {
+		t.Errorf("expected no profile for an unknown host, got %q", got)
+	}
+
+	for _, name := range BuiltinProfileNames() {
+		profile, _ := BuiltinProfile(name)
+		if err := profile.Validate(); err != nil {
+			t.Errorf("built-in profile %q is invalid: %v", name, err)
+		}
+	}
+	if err := (Profile{Remove: []string{"div >"}}).Validate(); err == nil {
+		t.Error("expected an invalid selector to fail validation")
+	}
+}
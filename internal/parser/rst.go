@@ -0,0 +1,228 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// rstDirective matches an explicit markup directive, e.g. ".. code-block:: go"
+	rstDirective = regexp.MustCompile(`^\.\.\s+([\w:-]+)::\s*(.*)$`)
+	// rstBullet matches a bullet or enumerated list item
+	rstBullet = regexp.MustCompile(`^([-*+•]|\d+[.)]|#\.)\s+`)
+	// rstRole matches interpreted text with a role, e.g. :ref:`Streams <streams>`
+	rstRole = regexp.MustCompile(":[\\w:-]+:`([^`]*)`")
+	// rstReference matches hyperlink references, e.g. `NATS <https://nats.io>`_
+	rstReference = regexp.MustCompile("`([^`]*)`__?")
+	// rstLinkTarget matches the embedded target of a reference or role
+	rstLinkTarget = regexp.MustCompile(`\s*<[^<>]*>$`)
+	// rstOption matches a directive option, e.g. ":caption: main.go"
+	rstOption = regexp.MustCompile(`^\s*:([\w-]+):\s*(.*)$`)
+	// rstStrong matches strong and emphasised text
+	rstStrong = regexp.MustCompile(`\*\*([^*]+)\*\*|\*([^*\s][^*]*)\*`)
+)
+
+// rstCodeDirectives are directives whose body is code
+var rstCodeDirectives = map[string]bool{"code-block": true, "code": true, "sourcecode": true}
+
+// rstTextDirectives are directives whose argument and body are prose
+var rstTextDirectives = map[string]bool{
+	"note": true, "warning": true, "tip": true, "important": true, "caution": true,
+	"attention": true, "hint": true, "danger": true, "error": true, "admonition": true,
+	"seealso": true, "versionadded": true, "versionchanged": true, "deprecated": true,
+	"topic": true, "sidebar": true, "rubric": true,
+}
+
+// ParseRST parses reStructuredText content. Section titles are recognised by their
+// underline and optional overline; as in docutils, levels follow the order in which
+// adornment styles first appear. Code blocks and admonitions are kept, while
+// comments, images, targets and substitution definitions are dropped.
+func ParseRST(content []byte, filepath string) (*Document, error) {
+	lines := splitLines(content)
+
+	var styles []string // Adornment styles in order of first appearance
+	var title string
+	var b sectionBuilder
+	var p paragraphBuilder
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if heading, style, consumed, ok := rstTitle(lines, i); ok {
+			p.flush(&b)
+			level := indexOf(styles, style) + 1
+			if level == 0 {
+				styles = append(styles, style)
+				level = len(styles)
+			}
+			if title == "" {
+				title = heading
+			}
+			b.start(cleanRSTInline(heading), level)
+			i += consumed - 1
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			p.flush(&b)
+
+		case strings.HasPrefix(trimmed, ".. ") || trimmed == "..":
+			p.flush(&b)
+			body, next := rstIndentedBlock(lines, i+1, indentation(line))
+			i = next - 1
+			match := rstDirective.FindStringSubmatch(trimmed)
+			switch {
+			case match != nil && rstCodeDirectives[match[1]]:
+				b.addCode(rstCodeBlock(match[2], body))
+			case match != nil && rstTextDirectives[match[1]]:
+				if match[2] != "" {
+					b.add(cleanRSTInline(match[2]))
+				}
+				rstParagraphs(body, &b)
+			}
+			// Comments, targets, images and other directives are skipped
+
+		case rstBullet.MatchString(trimmed):
+			p.flush(&b)
+			p.line("• " + cleanRSTInline(rstBullet.ReplaceAllString(trimmed, "")))
+
+		case isRSTAdornment(trimmed):
+			// Transitions separate paragraphs
+			p.flush(&b)
+
+		default:
+			// A paragraph ending in "::" introduces an indented literal block
+			if strings.HasSuffix(trimmed, "::") {
+				if text := strings.TrimSuffix(trimmed, ":"); text != ":" {
+					p.line(cleanRSTInline(text))
+				}
+				p.flush(&b)
+				body, next := rstIndentedBlock(lines, i+1, indentation(line))
+				b.addCode(newCodeBlock("", dedent(body), ""))
+				i = next - 1
+				continue
+			}
+			p.line(cleanRSTInline(trimmed))
+		}
+	}
+	p.flush(&b)
+
+	if title == "" {
+		title = titleFromPath(filepath)
+	}
+
+	return &Document{
+		Title:    title,
+		Sections: b.finish(),
+	}, nil
+}
+
+// rstCodeBlock returns the code block of a code directive with the language argument
+// and body. Option lines at the start of the body are dropped, except :caption:.
+func rstCodeBlock(language string, body []string) CodeBlock {
+	caption := ""
+	for len(body) > 0 {
+		option := rstOption.FindStringSubmatch(body[0])
+		if option == nil {
+			break
+		}
+		if option[1] == "caption" {
+			caption = option[2]
+		}
+		body = body[1:]
+	}
+	return newCodeBlock(language, dedent(body), caption)
+}
+
+// rstTitle recognises a section title at lines[i], returning its text, adornment
+// style and the number of lines it spans
+func rstTitle(lines []string, i int) (string, string, int, bool) {
+	line := strings.TrimRight(lines[i], " \t")
+
+	// Overlined title: adornment, text, matching adornment
+	if isRSTAdornment(line) && i+2 < len(lines) {
+		text := strings.TrimSpace(lines[i+1])
+		under := strings.TrimRight(lines[i+2], " \t")
+		if text != "" && !isRSTAdornment(text) && under == line {
+			return text, "over" + line[:1], 3, true
+		}
+	}
+
+	// Underlined title: unindented text preceded by a blank line and followed by an
+	// adornment at least as long as the text
+	if line == "" || indentation(line) > 0 || isRSTAdornment(line) || i+1 >= len(lines) {
+		return "", "", 0, false
+	}
+	if i > 0 && strings.TrimSpace(lines[i-1]) != "" {
+		return "", "", 0, false
+	}
+	under := strings.TrimRight(lines[i+1], " \t")
+	if !isRSTAdornment(under) || len([]rune(under)) < len([]rune(line)) {
+		return "", "", 0, false
+	}
+	return strings.TrimSpace(line), under[:1], 2, true
+}
+
+// isRSTAdornment reports whether line is a row of one repeated punctuation character
+func isRSTAdornment(line string) bool {
+	if len(line) < 3 || !strings.ContainsRune("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", rune(line[0])) {
+		return false
+	}
+	return strings.Trim(line, line[:1]) == ""
+}
+
+// rstIndentedBlock returns the lines from start that are indented deeper than
+// indent (with the blank lines between them) and the index of the first line after
+// the block
+func rstIndentedBlock(lines []string, start, indent int) ([]string, int) {
+	end := start
+	for i := start; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		if indentation(lines[i]) <= indent {
+			break
+		}
+		end = i + 1
+	}
+	return lines[start:end], end
+}
+
+// rstParagraphs adds the paragraphs of an indented directive body to b
+func rstParagraphs(lines []string, b *sectionBuilder) {
+	var p paragraphBuilder
+	for _, line := range lines {
+		// Directive options such as ":class: tip"
+		if trimmed := strings.TrimSpace(line); trimmed == "" {
+			p.flush(b)
+		} else if !strings.HasPrefix(trimmed, ":") {
+			p.line(cleanRSTInline(trimmed))
+		}
+	}
+	p.flush(b)
+}
+
+// cleanRSTInline removes inline markup, keeping the text of roles, references,
+// literals and emphasis
+func cleanRSTInline(text string) string {
+	text = strings.ReplaceAll(text, "``", "")
+	text = rstRole.ReplaceAllStringFunc(text, func(m string) string {
+		return rstLinkTarget.ReplaceAllString(rstRole.FindStringSubmatch(m)[1], "")
+	})
+	text = rstReference.ReplaceAllStringFunc(text, func(m string) string {
+		return rstLinkTarget.ReplaceAllString(rstReference.FindStringSubmatch(m)[1], "")
+	})
+	return rstStrong.ReplaceAllString(text, "$1$2")
+}
+
+// indexOf returns the index of value in values, or -1
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,313 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// selector is a parsed CSS-like selector: a comma-separated group of alternatives.
+// It supports type (div), universal (*), ID (#main), class (.nav) and attribute
+// selectors ([role], [role=main], with the ^=, $=, *= and ~= operators), the
+// descendant and child (>) combinators, and :starts-with(text), which matches
+// elements whose trimmed text starts with text (e.g. a:starts-with(Previous)).
+type selector []complexSelector
+
+// complexSelector is a chain of compound selectors, from the outermost ancestor to
+// the matched element
+type complexSelector []selectorStep
+
+// selectorStep is one compound selector of a chain
+type selectorStep struct {
+	compound
+	child bool // The element must be a child of the previous step's, not any descendant
+}
+
+// compound matches a single element
+type compound struct {
+	tag        string // Element name; empty matches any
+	id         string
+	classes    []string
+	attrs      []attrMatch
+	textPrefix string // Required start of the element's text, from :starts-with
+}
+
+// attrMatch is an attribute selector
+type attrMatch struct {
+	name  string
+	op    string // "", "=", "^=", "$=", "*=" or "~="
+	value string
+}
+
+// parseSelector parses a selector group such as "nav, aside, div.cookie-banner"
+func parseSelector(text string) (selector, error) {
+	var group selector
+	for _, part := range splitSelectorGroup(text) {
+		complex, err := parseComplexSelector(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", text, err)
+		}
+		group = append(group, complex)
+	}
+	return group, nil
+}
+
+// splitSelectorGroup splits text at the commas outside brackets, parentheses and quotes
+func splitSelectorGroup(text string) []string {
+	var parts []string
+	depth, quote, start := 0, byte(0), 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, text[start:])
+}
+
+// parseComplexSelector parses compound selectors joined by combinators
+func parseComplexSelector(text string) (complexSelector, error) {
+	var steps complexSelector
+	child := false
+	for i := 0; i < len(text); {
+		switch text[i] {
+		case ' ', '\t', '\n':
+			i++
+		case '>':
+			if len(steps) == 0 || child {
+				return nil, errors.New("misplaced '>'")
+			}
+			child = true
+			i++
+		default:
+			c, n, err := parseCompound(text[i:])
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, selectorStep{compound: c, child: child})
+			child = false
+			i += n
+		}
+	}
+	if len(steps) == 0 {
+		return nil, errors.New("empty selector")
+	}
+	if child {
+		return nil, errors.New("selector ends with '>'")
+	}
+	return steps, nil
+}
+
+// parseCompound parses the compound selector at the start of text and returns it with
+// the number of bytes consumed
+func parseCompound(text string) (compound, int, error) {
+	var c compound
+	i := 0
+	name := func() string {
+		start := i
+		for i < len(text) && isSelectorNameChar(text[i]) {
+			i++
+		}
+		return text[start:i]
+	}
+
+	if text[0] == '*' {
+		i++
+	} else {
+		c.tag = strings.ToLower(name())
+	}
+	for i < len(text) {
+		switch text[i] {
+		case '#':
+			i++
+			if c.id = name(); c.id == "" {
+				return c, 0, errors.New("missing ID after '#'")
+			}
+		case '.':
+			i++
+			class := name()
+			if class == "" {
+				return c, 0, errors.New("missing class after '.'")
+			}
+			c.classes = append(c.classes, class)
+		case '[':
+			end := strings.IndexByte(text[i:], ']')
+			if end < 0 {
+				return c, 0, errors.New("unclosed '['")
+			}
+			attr, err := parseAttrMatch(text[i+1 : i+end])
+			if err != nil {
+				return c, 0, err
+			}
+			c.attrs = append(c.attrs, attr)
+			i += end + 1
+		case ':':
+			const pseudo = ":starts-with("
+			end := strings.IndexByte(text[i:], ')')
+			if !strings.HasPrefix(text[i:], pseudo) || end < 0 {
+				return c, 0, fmt.Errorf("unsupported pseudo-class at %q", text[i:])
+			}
+			if c.textPrefix = unquote(text[i+len(pseudo) : i+end]); c.textPrefix == "" {
+				return c, 0, errors.New("empty :starts-with text")
+			}
+			i += end + 1
+		case ' ', '\t', '\n', '>':
+			return c, i, nil
+		default:
+			return c, 0, fmt.Errorf("unexpected %q", text[i])
+		}
+	}
+	if i == 0 {
+		return c, 0, errors.New("empty selector")
+	}
+	return c, i, nil
+}
+
+// parseAttrMatch parses the body of an attribute selector, e.g. `class*="cookie"`
+func parseAttrMatch(body string) (attrMatch, error) {
+	eq := strings.IndexByte(body, '=')
+	if eq < 0 {
+		name := strings.TrimSpace(body)
+		if name == "" {
+			return attrMatch{}, errors.New("empty attribute selector")
+		}
+		return attrMatch{name: strings.ToLower(name)}, nil
+	}
+
+	nameEnd, op := eq, "="
+	if eq > 0 && strings.IndexByte("^$*~", body[eq-1]) >= 0 {
+		nameEnd, op = eq-1, body[eq-1:eq+1]
+	}
+	name := strings.TrimSpace(body[:nameEnd])
+	if name == "" {
+		return attrMatch{}, errors.New("missing attribute name")
+	}
+	return attrMatch{name: strings.ToLower(name), op: op, value: unquote(body[eq+1:])}, nil
+}
+
+// unquote trims whitespace and one pair of surrounding quotes
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func isSelectorNameChar(c byte) bool {
+	return c == '-' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// matches reports whether any alternative of the group matches n
+func (s selector) matches(n *html.Node) bool {
+	for _, complex := range s {
+		if complex.matchesAt(len(complex)-1, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAt reports whether step i of the chain matches n and the steps before it
+// match n's ancestors
+func (c complexSelector) matchesAt(i int, n *html.Node) bool {
+	if !c[i].compound.matches(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	if c[i].child {
+		return n.Parent != nil && c.matchesAt(i-1, n.Parent)
+	}
+	for p := n.Parent; p != nil; p = p.Parent {
+		if c.matchesAt(i-1, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether the element n matches every part of the compound selector
+func (c compound) matches(n *html.Node) bool {
+	if n.Type != html.ElementNode || (c.tag != "" && c.tag != n.Data) {
+		return false
+	}
+	if c.id != "" && attr(n, "id") != c.id {
+		return false
+	}
+	if len(c.classes) > 0 {
+		classes := strings.Fields(attr(n, "class"))
+		for _, class := range c.classes {
+			if !containsString(classes, class) {
+				return false
+			}
+		}
+	}
+	for _, a := range c.attrs {
+		if !a.matches(n) {
+			return false
+		}
+	}
+	if c.textPrefix != "" && !strings.HasPrefix(strings.TrimSpace(extractText(n)), c.textPrefix) {
+		return false
+	}
+	return true
+}
+
+// matches reports whether n has the attribute with a matching value
+func (a attrMatch) matches(n *html.Node) bool {
+	for _, at := range n.Attr {
+		if at.Namespace != "" || at.Key != a.name {
+			continue
+		}
+		switch a.op {
+		case "":
+			return true
+		case "=":
+			return at.Val == a.value
+		case "^=":
+			return a.value != "" && strings.HasPrefix(at.Val, a.value)
+		case "$=":
+			return a.value != "" && strings.HasSuffix(at.Val, a.value)
+		case "*=":
+			return a.value != "" && strings.Contains(at.Val, a.value)
+		case "~=":
+			return containsString(strings.Fields(at.Val), a.value)
+		}
+	}
+	return false
+}
+
+// attr returns the value of the attribute key of n, or "" when it has none
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"golang.org/x/net/html"
+)
+
+// Table is a table within a section. Like code blocks, it also appears in the
+// section's content, rendered as a Markdown table, so its cells stay searchable.
+type Table struct {
+	Caption string     // Caption of the table; empty when none
+	Header  []string   // Column headings
+	Rows    [][]string // Body rows, each with one cell per column
+}
+
+// Markdown renders the table as a GitHub Flavored Markdown table, preceded by its
+// caption. Pipes in cells are escaped and rows are padded to the widest row.
+func (t Table) Markdown() string {
+	columns := len(t.Header)
+	for _, row := range t.Rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	if t.Caption != "" {
+		b.WriteString(t.Caption)
+		b.WriteString("\n\n")
+	}
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for i := 0; i < columns; i++ {
+			cell := ""
+			if i < len(cells) {
+				cell = strings.ReplaceAll(cells[i], "|", `\|`)
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+	writeRow(t.Header)
+	b.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+	for _, row := range t.Rows {
+		writeRow(row)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// newTable returns a table of rows whose first row is the header. Cells are
+// collapsed to a single line, and a table without cells is not a table.
+func newTable(caption string, rows [][]string) (Table, bool) {
+	var table Table
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		empty := true
+		for i, cell := range row {
+			cells[i] = strings.Join(strings.Fields(cell), " ")
+			empty = empty && cells[i] == ""
+		}
+		if len(cells) == 0 || (empty && table.Header != nil) {
+			continue
+		}
+		if table.Header == nil {
+			table.Header = cells
+			continue
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+	table.Caption = strings.Join(strings.Fields(caption), " ")
+	return table, table.Header != nil
+}
+
+// htmlTable returns the table of a <table> element. The header is the row of its
+// <thead>, or its first row when there is none. Rows of nested tables are not part
+// of the table.
+func htmlTable(n *html.Node) (Table, bool) {
+	var caption string
+	var header []string
+	var rows [][]string
+
+	var walk func(*html.Node, bool)
+	walk = func(node *html.Node, inHead bool) {
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.Data {
+			case "caption":
+				caption = extractText(c)
+			case "thead":
+				walk(c, true)
+			case "tbody", "tfoot":
+				walk(c, false)
+			case "tr":
+				var cells []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+						cells = append(cells, extractText(cell))
+					}
+				}
+				if inHead && header == nil {
+					header = cells
+				} else {
+					rows = append(rows, cells)
+				}
+			}
+		}
+	}
+	walk(n, false)
+
+	if header != nil {
+		rows = append([][]string{header}, rows...)
+	}
+	return newTable(caption, rows)
+}
+
+// markdownTable returns the table of a GFM table node
+func markdownTable(node ast.Node, source []byte) (Table, bool) {
+	if _, ok := node.(*east.Table); !ok {
+		return Table{}, false
+	}
+	var rows [][]string
+	for row := node.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells []string
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			cells = append(cells, inlineText(cell, source))
+		}
+		rows = append(rows, cells)
+	}
+	return newTable("", rows)
+}
+
+// markdownTables returns the tables of node and its descendants, e.g. in list items
+func markdownTables(node ast.Node, source []byte) []Table {
+	var tables []Table
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if table, ok := markdownTable(n, source); ok {
+			tables = append(tables, table)
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return tables
+}
+
+// inlineText returns the text of the inline nodes under node, including code spans,
+// links and emphasis
+func inlineText(node ast.Node, source []byte) string {
+	var buf strings.Builder
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Text:
+			buf.Write(n.Segment.Value(source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				buf.WriteString(" ")
+			}
+		case *ast.String:
+			buf.Write(n.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return buf.String()
+}
@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHTML_Tables(t *testing.T) {
+	page := `<html><body>
+<h2>Limits</h2>
+<p>Server limits are set in the configuration file.</p>
+<div class="table-wrapper">
+	<table>
+		<caption>Server limits</caption>
+		<thead><tr><th>Property</th><th>Description</th><th>Default</th></tr></thead>
+		<tbody>
+			<tr><td><code>max_payload</code></td><td>Maximum number of bytes
+				in a message payload</td><td>1MB</td></tr>
+			<tr><td><code>max_pending</code></td><td>Maximum buffered | pending bytes</td><td>64MB</td></tr>
+		</tbody>
+	</table>
+</div>
+<h2>Endpoints</h2>
+<table><tr><td>Endpoint</td><td>Path</td></tr><tr><td>Varz</td><td>/varz</td></tr></table>
+</body></html>`
+
+	doc, err := ParseHTML(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("ParseHTML failed: %v", err)
+	}
+	if len(doc.Sections) != 2 {
+		t.Fatalf("expected 2 sections, got %d: %+v", len(doc.Sections), doc.Sections)
+	}
+
+	want := []Table{{
+		Caption: "Server limits",
+		Header:  []string{"Property", "Description", "Default"},
+		Rows: [][]string{
+			{"max_payload", "Maximum number of bytes in a message payload", "1MB"},
+			{"max_pending", "Maximum buffered | pending bytes", "64MB"},
+		},
+	}}
+	limits := doc.Sections[0]
+	if !reflect.DeepEqual(limits.Tables, want) {
+		t.Errorf("tables = %+v, want %+v", limits.Tables, want)
+	}
+	for _, text := range []string{
+		"Server limits are set in the configuration file.",
+		"| Property | Description | Default |\n| --- | --- | --- |\n| max_payload | Maximum number of bytes in a message payload | 1MB |",
+		`| max_pending | Maximum buffered \| pending bytes | 64MB |`,
+	} {
+		if !strings.Contains(limits.Content, text) {
+			t.Errorf("expected %q in the content, got %q", text, limits.Content)
+		}
+	}
+
+	// Without a <thead> the first row is the header
+	want = []Table{{Header: []string{"Endpoint", "Path"}, Rows: [][]string{{"Varz", "/varz"}}}}
+	if !reflect.DeepEqual(doc.Sections[1].Tables, want) {
+		t.Errorf("tables = %+v, want %+v", doc.Sections[1].Tables, want)
+	}
+}
+
+func TestParseMarkdown_Tables(t *testing.T) {
+	md := "# Monitoring\n\n" +
+		"## Endpoints\n\n" +
+		"The server exposes these endpoints:\n\n" +
+		"| Endpoint | Path | Description |\n" +
+		"|:---------|:----:|------------:|\n" +
+		"| General | `/varz` | General *server* information |\n" +
+		"| Connections | `/connz` | Connection [details](connz.md) |\n"
+
+	doc, err := ParseMarkdown([]byte(md), "monitoring.md")
+	if err != nil {
+		t.Fatalf("ParseMarkdown failed: %v", err)
+	}
+	var endpoints *Section
+	for i := range doc.Sections {
+		if doc.Sections[i].Heading == "Endpoints" {
+			endpoints = &doc.Sections[i]
+		}
+	}
+	if endpoints == nil {
+		t.Fatalf("Endpoints section not found in %+v", doc.Sections)
+	}
+
+	want := []Table{{
+		Header: []string{"Endpoint", "Path", "Description"},
+		Rows: [][]string{
+			{"General", "/varz", "General server information"},
+			{"Connections", "/connz", "Connection details"},
+		},
+	}}
+	if !reflect.DeepEqual(endpoints.Tables, want) {
+		t.Errorf("tables = %+v, want %+v", endpoints.Tables, want)
+	}
+	if !strings.Contains(endpoints.Content, "| Connections | /connz | Connection details |") {
+		t.Errorf("expected the table rendered as Markdown, got %q", endpoints.Content)
+	}
+	if strings.Contains(endpoints.Content, ":----") {
+		t.Errorf("expected the table to be re-rendered, got %q", endpoints.Content)
+	}
+}
+
+func TestTableMarkdown(t *testing.T) {
+	table := Table{Header: []string{"Name"}, Rows: [][]string{{"a", "extra"}}}
+	if got := table.Markdown(); got != "| Name |  |\n| --- | --- |\n| a | extra |" {
+		t.Errorf("expected rows padded to the widest row, got %q", got)
+	}
+	if got := (Table{}).Markdown(); got != "" {
+		t.Errorf("expected an empty table to render nothing, got %q", got)
+	}
+}
@@ -0,0 +1,50 @@
+package parser
+
+import "strings"
+
+// maxTextTitleLength is the longest first line of a plain text file used as its title
+const maxTextTitleLength = 80
+
+// ParseText parses a plain text file. A short first line (optionally underlined with
+// "=" or "-") is the title; otherwise the title comes from the file name. The text is
+// kept as a single section of paragraphs.
+func ParseText(content []byte, filepath string) (*Document, error) {
+	lines := splitLines(content)
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	title := titleFromPath(filepath)
+	if len(lines) > 0 {
+		first := strings.TrimSpace(lines[0])
+		if len(first) <= maxTextTitleLength {
+			title = first
+			lines = lines[1:]
+			if len(lines) > 0 && isTextUnderline(lines[0]) {
+				lines = lines[1:]
+			}
+		}
+	}
+
+	var b sectionBuilder
+	var p paragraphBuilder
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			p.flush(&b)
+			continue
+		}
+		p.line(line)
+	}
+	p.flush(&b)
+
+	return &Document{
+		Title:    title,
+		Sections: b.finish(),
+	}, nil
+}
+
+// isTextUnderline reports whether line is a row of "=" or "-" underlining a title
+func isTextUnderline(line string) bool {
+	line = strings.TrimSpace(line)
+	return len(line) >= 3 && (strings.Trim(line, "=") == "" || strings.Trim(line, "-") == "")
+}
@@ -0,0 +1,168 @@
+package releases
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// maxHighlights limits the number of matching lines reported per release
+const maxHighlights = 10
+
+// Record pairs a release with the document it was indexed as
+type Record struct {
+	Release  *Release
+	Document *index.Document
+}
+
+// Query selects releases from a catalog
+type Query struct {
+	Repo               string // Repository name ("nats-server" or "nats-io/nats-server"); empty matches all
+	Range              Range  // Inclusive version range
+	Keyword            string // Words that must all appear in the release notes; empty matches all
+	IncludePrereleases bool   // Include pre-releases (release candidates, betas)
+}
+
+// Match is a release selected by a query with the note lines matching its keyword
+type Match struct {
+	*Record
+	Highlights []string
+}
+
+// Catalog holds releases ordered by version. It is built from index documents so
+// it can be restored from the documentation cache, and is immutable once created
+// so it is safe for concurrent use.
+type Catalog struct {
+	records []*Record
+}
+
+// NewCatalog creates a catalog from release documents. Documents without release
+// metadata are ignored.
+func NewCatalog(docs []*index.Document) *Catalog {
+	c := &Catalog{}
+	for _, doc := range docs {
+		if release := FromDocument(doc); release != nil {
+			c.records = append(c.records, &Record{Release: release, Document: doc})
+		}
+	}
+
+	sort.SliceStable(c.records, func(i, j int) bool {
+		a, b := c.records[i].Release, c.records[j].Release
+		if cmp := a.Version.Compare(b.Version); cmp != 0 {
+			return cmp < 0
+		}
+		return a.Repo < b.Repo
+	})
+
+	return c
+}
+
+// Count returns the number of releases in the catalog
+func (c *Catalog) Count() int {
+	return len(c.records)
+}
+
+// Query returns the releases matching q, oldest first, so the first match of a
+// keyword query is the release that introduced it.
+func (c *Catalog) Query(q Query) []*Match {
+	repo := strings.ToLower(strings.TrimSpace(q.Repo))
+	if i := strings.LastIndex(repo, "/"); i >= 0 {
+		repo = repo[i+1:]
+	}
+	terms := keywordTerms(q.Keyword)
+
+	var matches []*Match
+	for _, record := range c.records {
+		release := record.Release
+		if repo != "" && strings.ToLower(release.Repo) != repo {
+			continue
+		}
+		if release.Version.IsPrerelease() && !q.IncludePrereleases {
+			continue
+		}
+		if !q.Range.Contains(release.Version) {
+			continue
+		}
+
+		match := &Match{Record: record}
+		if len(terms) > 0 {
+			text := strings.ToLower(release.Name + "\n" + release.Notes)
+			if !containsAll(text, terms) {
+				continue
+			}
+			match.Highlights = highlights(release.Notes, terms)
+		}
+		matches = append(matches, match)
+	}
+
+	return matches
+}
+
+// keywordTerms splits a keyword query into lower-case stems, so "pausing
+// consumers" matches "Consumer pause" and "pause" matches "paused".
+func keywordTerms(keyword string) []string {
+	var terms []string
+	for _, word := range strings.Fields(strings.ToLower(keyword)) {
+		word = strings.Trim(word, `.,;:!?"'()`)
+		if word == "" {
+			continue
+		}
+		for _, suffix := range []string{"ing", "ed", "es", "s"} {
+			if stem := strings.TrimSuffix(word, suffix); stem != word && len(stem) >= 4 {
+				word = stem
+				break
+			}
+		}
+		if stem := strings.TrimSuffix(word, "e"); stem != word && len(stem) >= 4 {
+			word = stem
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// containsAll reports whether text contains every term
+func containsAll(text string, terms []string) bool {
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// highlights returns the note lines containing any term, preferring lines that
+// contain every term
+func highlights(notes string, terms []string) []string {
+	var all, some []string
+	for _, line := range strings.Split(notes, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lower := strings.ToLower(line)
+		switch {
+		case containsAll(lower, terms):
+			all = append(all, line)
+		case containsAny(lower, terms):
+			some = append(some, line)
+		}
+	}
+
+	lines := append(all, some...)
+	if len(lines) > maxHighlights {
+		lines = lines[:maxHighlights]
+	}
+	return lines
+}
+
+// containsAny reports whether text contains at least one term
+func containsAny(text string, terms []string) bool {
+	for _, term := range terms {
+		if strings.Contains(text, term) {
+			return true
+		}
+	}
+	return false
+}
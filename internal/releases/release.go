@@ -0,0 +1,248 @@
+// Package releases ingests release notes (GitHub releases and CHANGELOG files)
+// as one document per release with a parsed semantic version, so release history
+// can be filtered by version range and keyword.
+package releases
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// Metadata keys stored on release documents
+const (
+	MetaRepo       = "release_repo"
+	MetaTag        = "release_tag"
+	MetaName       = "release_name"
+	MetaVersion    = "release_version"
+	MetaDate       = "release_date"
+	MetaPrerelease = "release_prerelease"
+)
+
+// DocIDPrefix prefixes the IDs of release documents
+const DocIDPrefix = "release/"
+
+// dateLayout is the layout of release dates in metadata and changelog headings
+const dateLayout = "2006-01-02"
+
+// dateRe finds an ISO date in a changelog heading
+var dateRe = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+
+// Release is a single release of a repository
+type Release struct {
+	Repo    string    // Short repository name (e.g., "nats-server")
+	Tag     string    // Release tag (e.g., "v2.11.0")
+	Name    string    // Release title, if different from the tag
+	Version Version   // Version parsed from the tag
+	Date    time.Time // Publication date; zero when unknown
+	Notes   string    // Release notes (markdown)
+	URL     string    // Release page or changelog URL
+}
+
+// New creates a release, parsing its version from the tag or, failing that, the name
+func New(repo, tag, name, notes, url string, date time.Time) (*Release, error) {
+	version, err := ParseVersion(tag)
+	if err != nil {
+		var ok bool
+		if version, ok = FindVersion(tag); !ok {
+			if version, ok = FindVersion(name); !ok {
+				return nil, fmt.Errorf("no version in release %q", tag)
+			}
+		}
+	}
+
+	name = strings.TrimSpace(name)
+	if name == tag {
+		name = ""
+	}
+
+	return &Release{
+		Repo:    repo,
+		Tag:     tag,
+		Name:    name,
+		Version: version,
+		Date:    date,
+		Notes:   strings.TrimSpace(strings.ReplaceAll(notes, "\r\n", "\n")),
+		URL:     url,
+	}, nil
+}
+
+// IsChangelogPath reports whether a file is a changelog (e.g., "CHANGELOG.md",
+// "docs/changes.md")
+func IsChangelogPath(p string) bool {
+	base := strings.ToLower(path.Base(p))
+	if path.Ext(base) != ".md" {
+		return false
+	}
+	return strings.HasPrefix(base, "changelog") || strings.HasPrefix(base, "changes") ||
+		strings.HasPrefix(base, "history") || strings.HasPrefix(base, "release-notes") ||
+		strings.HasPrefix(base, "releasenotes")
+}
+
+// IsReleaseNotePath reports whether a markdown file is named after a single release
+// (e.g., "v2.11.0.md")
+func IsReleaseNotePath(p string) bool {
+	base := path.Base(p)
+	if !strings.EqualFold(path.Ext(base), ".md") {
+		return false
+	}
+	_, err := ParseVersion(strings.TrimSuffix(base, path.Ext(base)))
+	return err == nil
+}
+
+// ParseChangelog splits a changelog into one release per heading that contains a
+// version (e.g., "## [2.11.0] - 2025-03-17" or "# v2.10.0"). Notes run until the
+// next version heading of the same or a higher level.
+func ParseChangelog(content []byte, repo, sourceURL string) []*Release {
+	var result []*Release
+	var current *Release
+	var level int
+	var notes []string
+
+	flush := func() {
+		if current != nil {
+			current.Notes = strings.TrimSpace(strings.Join(notes, "\n"))
+			result = append(result, current)
+		}
+		current, notes = nil, nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n") {
+		if headingLevel := markdownHeadingLevel(line); headingLevel > 0 && (current == nil || headingLevel <= level) {
+			heading := strings.TrimSpace(strings.TrimLeft(line, "#"))
+			if version, ok := FindVersion(heading); ok {
+				flush()
+				current = &Release{
+					Repo:    repo,
+					Tag:     "v" + version.String(),
+					Version: version,
+					URL:     sourceURL,
+				}
+				if date, err := time.Parse(dateLayout, dateRe.FindString(heading)); err == nil {
+					current.Date = date
+				}
+				level = headingLevel
+				continue
+			}
+		}
+		if current != nil {
+			notes = append(notes, line)
+		}
+	}
+	flush()
+
+	return result
+}
+
+// markdownHeadingLevel returns the ATX heading level of a line, or 0
+func markdownHeadingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level == len(line) || line[level] != ' ' {
+		return 0
+	}
+	return level
+}
+
+// Title returns the display title (e.g., "nats-server v2.11.0: Release v2.11.0")
+func (r *Release) Title() string {
+	title := fmt.Sprintf("%s %s", r.Repo, r.Tag)
+	if r.Name != "" {
+		title += ": " + r.Name
+	}
+	return title
+}
+
+// DocumentID returns the ID of the release's document
+func (r *Release) DocumentID() string {
+	return DocIDPrefix + r.Repo + "/" + r.Tag
+}
+
+// Document converts the release to an index document with one section per
+// markdown heading of its notes
+func (r *Release) Document() *index.Document {
+	meta := map[string]string{
+		MetaRepo:    r.Repo,
+		MetaTag:     r.Tag,
+		MetaName:    r.Name,
+		MetaVersion: r.Version.String(),
+	}
+	if !r.Date.IsZero() {
+		meta[MetaDate] = r.Date.Format(dateLayout)
+	}
+	if r.Version.IsPrerelease() {
+		meta[MetaPrerelease] = "true"
+	}
+
+	updated := r.Date
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	return &index.Document{
+		ID:          r.DocumentID(),
+		Title:       r.Title(),
+		URL:         r.URL,
+		Content:     r.Notes,
+		Sections:    noteSections(r.Notes),
+		LastUpdated: updated,
+		Metadata:    meta,
+	}
+}
+
+// noteSections splits release notes on markdown headings
+func noteSections(notes string) []index.Section {
+	sections := []index.Section{{Heading: "Release Notes", Level: 1}}
+	var body []string
+
+	for _, line := range strings.Split(notes, "\n") {
+		if level := markdownHeadingLevel(line); level > 0 {
+			sections[len(sections)-1].Content = strings.TrimSpace(strings.Join(body, "\n"))
+			sections = append(sections, index.Section{
+				Heading: strings.TrimSpace(strings.TrimLeft(line, "#")),
+				Level:   level + 1,
+			})
+			body = nil
+			continue
+		}
+		body = append(body, line)
+	}
+	sections[len(sections)-1].Content = strings.TrimSpace(strings.Join(body, "\n"))
+
+	// Drop an empty leading section when the notes start with a heading
+	if sections[0].Content == "" && len(sections) > 1 {
+		sections = sections[1:]
+	}
+	return sections
+}
+
+// FromDocument restores a release from a document's metadata. It returns nil when
+// the document is not a release.
+func FromDocument(doc *index.Document) *Release {
+	if doc == nil || doc.Metadata == nil || doc.Metadata[MetaTag] == "" {
+		return nil
+	}
+	version, err := ParseVersion(doc.Metadata[MetaVersion])
+	if err != nil {
+		return nil
+	}
+
+	r := &Release{
+		Repo:    doc.Metadata[MetaRepo],
+		Tag:     doc.Metadata[MetaTag],
+		Name:    doc.Metadata[MetaName],
+		Version: version,
+		Notes:   doc.Content,
+		URL:     doc.URL,
+	}
+	if date, err := time.Parse(dateLayout, doc.Metadata[MetaDate]); err == nil {
+		r.Date = date
+	}
+	return r
+}
@@ -0,0 +1,229 @@
+package releases
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "v2.11.0", want: "2.11.0"},
+		{input: "2.10.22", want: "2.10.22"},
+		{input: "v2.11.0-RC.1", want: "2.11.0-RC.1"},
+		{input: "2.11", want: "2.11.0"},
+		{input: "v2", want: "2.0.0"},
+		{input: "nightly", wantErr: true},
+		{input: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		v, err := ParseVersion(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVersion(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if err == nil && v.String() != tt.want {
+			t.Errorf("ParseVersion(%q) = %s, want %s", tt.input, v, tt.want)
+		}
+	}
+}
+
+func TestFindVersion(t *testing.T) {
+	for input, want := range map[string]string{
+		"Release v2.11.0":                "2.11.0",
+		"[2.10.1] - 2024-01-02":          "2.10.1",
+		"nats-server-v2.9.25-RC.2":       "2.9.25-RC.2",
+		"Version 1.2 (with 3 new flags)": "1.2.0",
+	} {
+		v, ok := FindVersion(input)
+		if !ok || v.String() != want {
+			t.Errorf("FindVersion(%q) = %s, %v, want %s", input, v, ok, want)
+		}
+	}
+	if _, ok := FindVersion("Unreleased"); ok {
+		t.Error("expected no version in 'Unreleased'")
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	ordered := []string{"2.9.25", "2.10.0-beta.2", "2.10.0-beta.10", "2.10.0-RC.1", "2.10.0", "2.10.1", "2.11.0"}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := ParseVersion(ordered[i])
+		b, _ := ParseVersion(ordered[i+1])
+		if a.Compare(b) >= 0 || b.Compare(a) <= 0 {
+			t.Errorf("expected %s < %s", a, b)
+		}
+	}
+	a, _ := ParseVersion("v2.10.0")
+	b, _ := ParseVersion("2.10.0")
+	if a.Compare(b) != 0 {
+		t.Error("expected equal versions")
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	tests := []struct {
+		from, to string
+		version  string
+		want     bool
+	}{
+		{from: "2.11", to: "2.11", version: "2.11.7", want: true},
+		{from: "2.11", to: "2.11", version: "2.11.0-RC.1", want: true},
+		{from: "2.11", to: "2.11", version: "2.10.22", want: false},
+		{from: "2.11", to: "2.11", version: "2.12.0", want: false},
+		{from: "2.10.5", to: "", version: "2.10.4", want: false},
+		{from: "", to: "2.10.5", version: "2.10.5", want: true},
+		{from: "", to: "2.10.5", version: "2.10.6", want: false},
+		{from: "", to: "2", version: "2.99.0", want: true},
+		{from: "", to: "", version: "1.0.0", want: true},
+	}
+	for _, tt := range tests {
+		r, err := ParseRange(tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("ParseRange(%q, %q) failed: %v", tt.from, tt.to, err)
+		}
+		v, _ := ParseVersion(tt.version)
+		if got := r.Contains(v); got != tt.want {
+			t.Errorf("[%s, %s].Contains(%s) = %v, want %v", tt.from, tt.to, tt.version, got, tt.want)
+		}
+	}
+
+	if _, err := ParseRange("latest", ""); err == nil {
+		t.Error("expected error for invalid bound")
+	}
+}
+
+const testChangelog = `# Changelog
+
+## [Unreleased]
+
+## [2.1.0] - 2024-05-02
+
+### Added
+- Consumer pause support
+
+## [2.0.0] - 2024-01-15
+
+Initial release.
+`
+
+func TestParseChangelog(t *testing.T) {
+	releases := ParseChangelog([]byte(testChangelog), "nats.js", "https://example.com/CHANGELOG.md")
+	if len(releases) != 2 {
+		t.Fatalf("expected 2 releases, got %d", len(releases))
+	}
+
+	r := releases[0]
+	if r.Tag != "v2.1.0" || r.Repo != "nats.js" || r.URL != "https://example.com/CHANGELOG.md" {
+		t.Errorf("unexpected release: %+v", r)
+	}
+	if !r.Date.Equal(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected date: %v", r.Date)
+	}
+	if r.Notes != "### Added\n- Consumer pause support" {
+		t.Errorf("unexpected notes: %q", r.Notes)
+	}
+	if releases[1].Notes != "Initial release." {
+		t.Errorf("unexpected notes: %q", releases[1].Notes)
+	}
+}
+
+func TestPaths(t *testing.T) {
+	for p, want := range map[string]bool{"CHANGELOG.md": true, "docs/Changes.md": true, "README.md": false, "changelog.txt": false} {
+		if got := IsChangelogPath(p); got != want {
+			t.Errorf("IsChangelogPath(%q) = %v, want %v", p, got, want)
+		}
+	}
+	for p, want := range map[string]bool{"nats-server/v2.11.0.md": true, "2.10.1.md": true, "README.md": false, "v2.11.0.txt": false} {
+		if got := IsReleaseNotePath(p); got != want {
+			t.Errorf("IsReleaseNotePath(%q) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestDocumentRoundTrip(t *testing.T) {
+	r, err := New("nats-server", "v2.11.0", "Release v2.11.0", "## Highlights\n\nConsumer pausing.\n", "https://github.com/nats-io/nats-server/releases/tag/v2.11.0",
+		time.Date(2025, 3, 17, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	doc := r.Document()
+	if doc.ID != "release/nats-server/v2.11.0" || doc.Title != "nats-server v2.11.0: Release v2.11.0" {
+		t.Errorf("unexpected document: %s %q", doc.ID, doc.Title)
+	}
+	wantSections := []index.Section{{Heading: "Highlights", Content: "Consumer pausing.", Level: 3}}
+	if !reflect.DeepEqual(doc.Sections, wantSections) {
+		t.Errorf("unexpected sections: %+v", doc.Sections)
+	}
+
+	restored := FromDocument(doc)
+	if restored == nil || restored.Tag != r.Tag || restored.Version.Compare(r.Version) != 0 ||
+		restored.Notes != r.Notes || !restored.Date.Equal(time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected restored release: %+v", restored)
+	}
+
+	if _, err := New("nats-server", "nightly", "Nightly build", "", "", time.Time{}); err == nil {
+		t.Error("expected error for release without a version")
+	}
+}
+
+func TestCatalogQuery(t *testing.T) {
+	var docs []*index.Document
+	for _, rel := range []struct{ repo, tag, notes string }{
+		{"nats-server", "v2.11.0", "Added consumer pause and resume."},
+		{"nats-server", "v2.10.0", "Stream subject transforms."},
+		{"nats-server", "v2.11.0-RC.1", "Consumer pausing preview."},
+		{"nats-server", "v2.11.1", "Fixed paused consumers not resuming."},
+		{"nats.go", "v1.37.0", "Support pausing consumers."},
+	} {
+		r, err := New(rel.repo, rel.tag, "", rel.notes, "", time.Time{})
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		docs = append(docs, r.Document())
+	}
+	docs = append(docs, &index.Document{ID: "README.md"})
+
+	catalog := NewCatalog(docs)
+	if catalog.Count() != 5 {
+		t.Fatalf("expected 5 releases, got %d", catalog.Count())
+	}
+
+	tags := func(matches []*Match) []string {
+		var result []string
+		for _, m := range matches {
+			result = append(result, m.Release.Repo+"@"+m.Release.Tag)
+		}
+		return result
+	}
+
+	got := tags(catalog.Query(Query{Repo: "nats-io/nats-server", Keyword: "consumer pausing"}))
+	want := []string{"nats-server@v2.11.0", "nats-server@v2.11.1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("keyword query = %v, want %v", got, want)
+	}
+
+	got = tags(catalog.Query(Query{Keyword: "pause", IncludePrereleases: true}))
+	want = []string{"nats.go@v1.37.0", "nats-server@v2.11.0-RC.1", "nats-server@v2.11.0", "nats-server@v2.11.1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pre-release query = %v, want %v", got, want)
+	}
+
+	r, _ := ParseRange("2.10", "2.10")
+	got = tags(catalog.Query(Query{Range: r}))
+	if !reflect.DeepEqual(got, []string{"nats-server@v2.10.0"}) {
+		t.Errorf("range query = %v", got)
+	}
+
+	matches := catalog.Query(Query{Repo: "nats-server", Keyword: "resume"})
+	if len(matches) != 2 || !reflect.DeepEqual(matches[0].Highlights, []string{"Added consumer pause and resume."}) {
+		t.Errorf("unexpected highlights: %+v", matches)
+	}
+}
@@ -0,0 +1,184 @@
+package releases
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionRe matches a complete version string such as "v2.11.0" or "2.11.0-RC.1"
+var versionRe = regexp.MustCompile(`^[vV]?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z][0-9A-Za-z.\-]*))?$`)
+
+// embeddedVersionRe finds a version inside a tag, heading or file name
+var embeddedVersionRe = regexp.MustCompile(`(?:^|[^0-9A-Za-z.])([vV]?\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z][0-9A-Za-z.\-]*)?)`)
+
+// Version is a semantic version. Partial versions (e.g., "2.11") record how many
+// components were given so they can be used as inclusive range bounds.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	Pre   string // Pre-release identifier (e.g., "RC.1"); empty for final releases
+	parts int    // Number of numeric components given (1-3)
+}
+
+// ParseVersion parses a version such as "v2.11.0", "2.11.0-RC.1" or "2.11"
+func ParseVersion(s string) (Version, error) {
+	m := versionRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid version: %q", s)
+	}
+
+	v := Version{Pre: m[4], parts: 1}
+	v.Major, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		v.Minor, _ = strconv.Atoi(m[2])
+		v.parts = 2
+	}
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+		v.parts = 3
+	}
+	return v, nil
+}
+
+// FindVersion returns the first version embedded in s (e.g., the "v2.11.0" in
+// "Release v2.11.0 (2025-03-17)"). Embedded versions need at least major.minor.
+func FindVersion(s string) (Version, bool) {
+	m := embeddedVersionRe.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, false
+	}
+	v, err := ParseVersion(m[1])
+	if err != nil {
+		return Version{}, false
+	}
+	return v, true
+}
+
+// String formats the version without a "v" prefix, padding partial versions
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// IsPrerelease reports whether the version has a pre-release identifier
+func (v Version) IsPrerelease() bool {
+	return v.Pre != ""
+}
+
+// Compare returns -1, 0 or 1 when v is lower than, equal to or greater than o,
+// following semantic versioning precedence.
+func (v Version) Compare(o Version) int {
+	if c := compareInt(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+	return comparePre(v.Pre, o.Pre)
+}
+
+// compareInt compares two integers
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparePre compares pre-release identifiers; a final release sorts after its
+// pre-releases and numeric identifiers compare numerically.
+func comparePre(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareInt(an, bn)
+		case aErr == nil:
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(strings.ToLower(as[i]), strings.ToLower(bs[i]))
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(as), len(bs))
+}
+
+// Range is an inclusive version range. Nil bounds are open. A partial upper bound
+// covers every release it prefixes, so "2.11" includes 2.11.7.
+type Range struct {
+	From *Version
+	To   *Version
+}
+
+// ParseRange builds a range from optional lower and upper bound strings
+func ParseRange(from, to string) (Range, error) {
+	var r Range
+	if strings.TrimSpace(from) != "" {
+		v, err := ParseVersion(from)
+		if err != nil {
+			return Range{}, err
+		}
+		r.From = &v
+	}
+	if strings.TrimSpace(to) != "" {
+		v, err := ParseVersion(to)
+		if err != nil {
+			return Range{}, err
+		}
+		r.To = &v
+	}
+	return r, nil
+}
+
+// Contains reports whether v lies within the range
+func (r Range) Contains(v Version) bool {
+	if r.From != nil && v.Compare(*r.From) < 0 {
+		// Pre-releases of a partial lower bound (2.11.0-RC.1 for "2.11") are included
+		if !(r.From.parts < 3 && v.IsPrerelease() && r.From.prefixes(v)) {
+			return false
+		}
+	}
+	if r.To != nil && v.Compare(*r.To) > 0 && !r.To.prefixes(v) {
+		return false
+	}
+	return true
+}
+
+// prefixes reports whether the given components of a partial version match v
+func (v Version) prefixes(o Version) bool {
+	if v.parts >= 3 || v.Pre != "" {
+		return false
+	}
+	if v.Major != o.Major {
+		return false
+	}
+	return v.parts == 1 || v.Minor == o.Minor
+}
@@ -45,6 +45,12 @@ func NewOrchestrator(
 // The query is classified to determine which index/indices to search, then
 // results are merged and sorted by relevance score.
 func (o *Orchestrator) Search(query string, maxResults int) ([]SearchResult, error) {
+	return o.SearchFiltered(query, maxResults, nil)
+}
+
+// SearchFiltered performs a classified search like Search, considering only documents
+// accepted by filter (e.g., documents of a given version). A nil filter accepts all documents.
+func (o *Orchestrator) SearchFiltered(query string, maxResults int, filter index.SearchFilter) ([]SearchResult, error) {
 	if query == "" {
 		return nil, fmt.Errorf("search query cannot be empty")
 	}
@@ -59,13 +65,13 @@ func (o *Orchestrator) Search(query string, maxResults int) ([]SearchResult, err
 	// Route to appropriate index based on classification
 	switch source {
 	case classifier.SourceNATS:
-		return o.searchNATSIndex(query, maxResults)
+		return o.searchNATSIndex(query, maxResults, filter)
 	case classifier.SourceSynadia:
-		return o.searchSynadiaIndex(query, maxResults)
+		return o.searchSynadiaIndex(query, maxResults, filter)
 	case classifier.SourceGitHub:
-		return o.searchGitHubIndex(query, maxResults)
+		return o.searchGitHubIndex(query, maxResults, filter)
 	case classifier.SourceAll:
-		return o.searchAllIndices(query, maxResults)
+		return o.searchAllIndices(query, maxResults, filter)
 	default:
 		return nil, fmt.Errorf("unknown documentation source: %v", source)
 	}
@@ -76,6 +82,17 @@ func (o *Orchestrator) SearchSource(
 	query string,
 	source classifier.DocumentationSource,
 	maxResults int,
+) ([]SearchResult, error) {
+	return o.SearchSourceFiltered(query, source, maxResults, nil)
+}
+
+// SearchSourceFiltered performs a search against a specific documentation source,
+// considering only documents accepted by filter. A nil filter accepts all documents.
+func (o *Orchestrator) SearchSourceFiltered(
+	query string,
+	source classifier.DocumentationSource,
+	maxResults int,
+	filter index.SearchFilter,
 ) ([]SearchResult, error) {
 	if query == "" {
 		return nil, fmt.Errorf("search query cannot be empty")
@@ -87,21 +104,21 @@ func (o *Orchestrator) SearchSource(
 
 	switch source {
 	case classifier.SourceNATS:
-		return o.searchNATSIndex(query, maxResults)
+		return o.searchNATSIndex(query, maxResults, filter)
 	case classifier.SourceSynadia:
-		return o.searchSynadiaIndex(query, maxResults)
+		return o.searchSynadiaIndex(query, maxResults, filter)
 	case classifier.SourceGitHub:
-		return o.searchGitHubIndex(query, maxResults)
+		return o.searchGitHubIndex(query, maxResults, filter)
 	case classifier.SourceAll:
-		return o.searchAllIndices(query, maxResults)
+		return o.searchAllIndices(query, maxResults, filter)
 	default:
 		return nil, fmt.Errorf("unknown documentation source: %v", source)
 	}
 }
 
 // searchNATSIndex performs a search on the NATS index only
-func (o *Orchestrator) searchNATSIndex(query string, maxResults int) ([]SearchResult, error) {
-	natsResults, err := o.natsIndex.Search(query, maxResults)
+func (o *Orchestrator) searchNATSIndex(query string, maxResults int, filter index.SearchFilter) ([]SearchResult, error) {
+	natsResults, err := o.natsIndex.SearchFiltered(query, maxResults, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search NATS index: %w", err)
 	}
@@ -123,8 +140,8 @@ func (o *Orchestrator) searchNATSIndex(query string, maxResults int) ([]SearchRe
 }
 
 // searchSynadiaIndex performs a search on the syncp index only
-func (o *Orchestrator) searchSynadiaIndex(query string, maxResults int) ([]SearchResult, error) {
-	syncpResults, err := o.syadiaIndex.Search(query, maxResults)
+func (o *Orchestrator) searchSynadiaIndex(query string, maxResults int, filter index.SearchFilter) ([]SearchResult, error) {
+	syncpResults, err := o.syadiaIndex.SearchFiltered(query, maxResults, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search syncp index: %w", err)
 	}
@@ -146,8 +163,8 @@ func (o *Orchestrator) searchSynadiaIndex(query string, maxResults int) ([]Searc
 }
 
 // searchGitHubIndex performs a search on the GitHub index only
-func (o *Orchestrator) searchGitHubIndex(query string, maxResults int) ([]SearchResult, error) {
-	githubResults, err := o.githubIndex.Search(query, maxResults)
+func (o *Orchestrator) searchGitHubIndex(query string, maxResults int, filter index.SearchFilter) ([]SearchResult, error) {
+	githubResults, err := o.githubIndex.SearchFiltered(query, maxResults, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search GitHub index: %w", err)
 	}
@@ -169,11 +186,11 @@ func (o *Orchestrator) searchGitHubIndex(query string, maxResults int) ([]Search
 }
 
 // searchAllIndices performs searches on all indices and merges results
-func (o *Orchestrator) searchAllIndices(query string, maxResults int) ([]SearchResult, error) {
+func (o *Orchestrator) searchAllIndices(query string, maxResults int, filter index.SearchFilter) ([]SearchResult, error) {
 	// Search all indices (without applying limit to individual searches)
-	natsResults, natsErr := o.natsIndex.Search(query, maxResults*2)
-	syncpResults, syncpErr := o.syadiaIndex.Search(query, maxResults*2)
-	githubResults, githubErr := o.githubIndex.Search(query, maxResults*2)
+	natsResults, natsErr := o.natsIndex.SearchFiltered(query, maxResults*2, filter)
+	syncpResults, syncpErr := o.syadiaIndex.SearchFiltered(query, maxResults*2, filter)
+	githubResults, githubErr := o.githubIndex.SearchFiltered(query, maxResults*2, filter)
 
 	// Log errors but continue with available results
 	if natsErr != nil && syncpErr != nil && githubErr != nil {
@@ -236,6 +236,33 @@ func TestSearchSource_NATS(t *testing.T) {
 	}
 }
 
+func TestSearchFiltered(t *testing.T) {
+	orchestrator := createMockOrchestrator()
+
+	filter := func(doc *index.Document) bool { return doc.ID != "nats-jetstream" }
+
+	results, err := orchestrator.SearchFiltered("jetstream messaging", 10, filter)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("expected results from documents accepted by the filter")
+	}
+	for _, result := range results {
+		if result.DocumentID == "nats-jetstream" {
+			t.Error("filtered document returned in results")
+		}
+	}
+
+	results, err = orchestrator.SearchSourceFiltered("jetstream", classifier.SourceNATS, 10, filter)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results, got %d", len(results))
+	}
+}
+
 func TestSearchSource_Synadia(t *testing.T) {
 	orchestrator := createMockOrchestrator()
 
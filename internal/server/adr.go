@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/adr"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// applyADRMetadata parses an ADR file and records its number, status, authors, tags
+// and revision history on the document, prefixing the title with the ADR number.
+// Files that fail to parse are left as plain GitHub documents.
+func applyADRMetadata(doc *index.Document, content []byte, path string) {
+	record, err := adr.Parse(content, path)
+	if err != nil {
+		return
+	}
+	doc.Title = fmt.Sprintf("%s: %s", record.Name(), record.Title)
+	doc.Metadata = record.Metadata()
+}
+
+// adrStatusFilter accepts only ADRs of catalog whose status matches status
+func adrStatusFilter(catalog *adr.Catalog, status string) index.SearchFilter {
+	return func(doc *index.Document) bool {
+		record := catalog.ByDocumentID(doc.ID)
+		return record != nil && record.ADR.HasStatus(status)
+	}
+}
+
+// handleGetADRTool handles the get_nats_adr tool invocation.
+// It resolves an ADR number to the record's metadata and full content.
+func (s *Server) handleGetADRTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ref, err := request.RequireString("number")
+	if err != nil || strings.TrimSpace(ref) == "" {
+		return mcp.NewToolResultError("number parameter is required and must be a non-empty string"), nil
+	}
+
+	number, err := adr.ParseNumber(ref)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	catalog := s.docs().adrCatalog
+	if catalog == nil || catalog.Count() == 0 {
+		return mcp.NewToolResultError("no architecture decision records are loaded; add nats-io/nats-architecture-and-design to github.repositories"), nil
+	}
+
+	record := catalog.Get(number)
+	if record == nil {
+		s.logger.Info("ADR lookup found no record", "number", number)
+		return mcp.NewToolResultError(fmt.Sprintf("no ADR found for: %s", ref)), nil
+	}
+
+	s.logger.Info("ADR retrieved", "number", number, "title", record.ADR.Title)
+
+	return mcp.NewToolResultText(formatADR(record)), nil
+}
+
+// formatADR renders an ADR's metadata and revision history followed by its document
+func formatADR(record *adr.Record) string {
+	a := record.ADR
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("%s: %s\n", a.Name(), a.Title))
+	if a.Status != "" {
+		content.WriteString(fmt.Sprintf("Status: %s\n", a.Status))
+	}
+	if len(a.Authors) > 0 {
+		content.WriteString(fmt.Sprintf("Authors: %s\n", strings.Join(a.Authors, ", ")))
+	}
+	if len(a.Tags) > 0 {
+		content.WriteString(fmt.Sprintf("Tags: %s\n", strings.Join(a.Tags, ", ")))
+	}
+	if a.Date != "" {
+		content.WriteString(fmt.Sprintf("Date: %s\n", a.Date))
+	}
+	if a.Updated != "" {
+		content.WriteString(fmt.Sprintf("Updated: %s\n", a.Updated))
+	}
+
+	if len(a.Revisions) > 0 {
+		content.WriteString("\nRevision History:\n")
+		for _, rev := range a.Revisions {
+			content.WriteString(fmt.Sprintf("  - %s (%s, %s): %s\n", rev.Revision, rev.Date, rev.Author, rev.Info))
+		}
+	}
+
+	content.WriteString("\n")
+	content.WriteString(formatDocument(record.Document))
+	return content.String()
+}
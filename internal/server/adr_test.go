@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/adr"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// adrTestOptions index two ADRs and a README as GitHub documentation
+func adrTestOptions() testServerOptions {
+	files := map[string]string{
+		"adr/ADR-8.md": "# JetStream based Key-Value Stores\n\n|Metadata|Value|\n|--|--|\n|Date|2021-06-30|\n|Author|@ripienaar|\n|Status|Implemented|\n|Tags|jetstream, client, kv|\n\n## Revision History\n\n|Revision|Date|Author|Info|\n|--|--|--|--|\n|1|2021-06-30|@ripienaar|Initial design of the key-value store|\n\nThe key-value store is built on a stream.\n",
+		"adr/ADR-9.md": "# JetStream Consumer Health\n\n|Metadata|Value|\n|--|--|\n|Date|2021-07-01|\n|Author|@ripienaar|\n|Status|Approved|\n|Tags|jetstream, server|\n\nConsumer health is reported for the key-value store and streams.\n",
+		"README.md":    "# Architecture and Design\n\nThe key-value store ADRs are listed here.\n",
+	}
+
+	var docs []*index.Document
+	for path, content := range files {
+		doc := &index.Document{
+			ID:          "nats-architecture-and-design/" + path,
+			Title:       path,
+			URL:         "https://github.com/nats-io/nats-architecture-and-design/blob/main/" + path,
+			Content:     content,
+			Sections:    []index.Section{{Heading: "Content", Content: content, Level: 1}},
+			LastUpdated: time.Now(),
+		}
+		if adr.IsADRPath(path) {
+			applyADRMetadata(doc, []byte(content), path)
+		}
+		docs = append(docs, doc)
+	}
+
+	return testServerOptions{
+		github: docs,
+		initializers: []testInitializer{func(s *Server, ctx context.Context, st *docState, force bool) error {
+			st.adrCatalog = adr.NewCatalog(docs)
+			return nil
+		}},
+	}
+}
+
+func TestHandleGetADRTool(t *testing.T) {
+	srv := newTestServer(t, adrTestOptions())
+
+	tests := []struct {
+		name      string
+		number    interface{}
+		wantError bool
+		contains  []string
+	}{
+		{
+			name:   "by number",
+			number: "8",
+			contains: []string{
+				"ADR-8: JetStream based Key-Value Stores",
+				"Status: Implemented",
+				"Authors: @ripienaar",
+				"Tags: jetstream, client, kv",
+				"Revision History:",
+				"1 (2021-06-30, @ripienaar): Initial design of the key-value store",
+				"URL: https://github.com/nats-io/nats-architecture-and-design/blob/main/adr/ADR-8.md",
+			},
+		},
+		{name: "by name", number: "ADR-9", contains: []string{"Status: Approved"}},
+		{name: "unknown", number: "99", wantError: true},
+		{name: "invalid", number: "kv", wantError: true},
+		{name: "missing number", number: nil, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			if tt.number != nil {
+				request.Params.Arguments = map[string]interface{}{"number": tt.number}
+			}
+
+			result, err := srv.handleGetADRTool(context.Background(), request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.IsError != tt.wantError {
+				t.Fatalf("expected IsError=%v, got %v: %s", tt.wantError, result.IsError, resultText(t, result))
+			}
+
+			text := resultText(t, result)
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in result, got:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleSearchToolADRStatus(t *testing.T) {
+	srv := newTestServer(t, adrTestOptions())
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"query": "key-value store", "adr_status": "approved"}
+	result, err := srv.handleSearchTool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %s", resultText(t, result))
+	}
+
+	text := resultText(t, result)
+	if !strings.Contains(text, "Found 1 results") || !strings.Contains(text, "ADR-9: JetStream Consumer Health") {
+		t.Errorf("expected only ADR-9, got:\n%s", text)
+	}
+	if strings.Contains(text, "ADR-8") || strings.Contains(text, "README") {
+		t.Errorf("expected other documents to be filtered out, got:\n%s", text)
+	}
+}
+
+func TestHandleGetADRToolNotLoaded(t *testing.T) {
+	srv := newTestServer(t, testServerOptions{})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"number": "8"}
+	result, err := srv.handleGetADRTool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected error result when no ADRs are loaded")
+	}
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/configref"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// initializeConfigOptions builds the nats-server configuration option catalogue from
+// the option tables of the server configuration pages. Freshly fetched NATS pages are
+// used when available; otherwise the catalogue is loaded from the cache, and only the
+// configuration pages are fetched when the cache is missing or stale. Pages unchanged
+// since the last fetch keep the options previously extracted from them.
+// The catalogue is built into st; force revalidates a fresh cache.
+func (s *Server) initializeConfigOptions(ctx context.Context, st *docState, force bool, pages []fetcher.DocumentPage) error {
+	source := "nats-config-options"
+
+	var configPages []fetcher.DocumentPage
+	for _, page := range pages {
+		if configref.IsConfigPage(page.Path) {
+			configPages = append(configPages, page)
+		}
+	}
+
+	if len(configPages) == 0 {
+		if docs := s.loadCachedDocuments(source, force); docs != nil {
+			st.configCatalog = configref.NewCatalog(docs)
+			s.logger.Info("Configuration option catalogue loaded from cache", "count", st.configCatalog.Count())
+			return nil
+		}
+		fetched, err := s.multiFetcher.FetchNATSPages(ctx, configref.IsConfigPage)
+		if len(fetched) == 0 && err != nil {
+			return fmt.Errorf("failed to fetch configuration pages: %w", err)
+		}
+		configPages = fetched
+	}
+
+	docs := s.extractConfigOptions(ctx, source, configPages)
+	if len(docs) == 0 {
+		return fmt.Errorf("no configuration options found in %d pages", len(configPages))
+	}
+
+	// Save to cache (best-effort, log errors but don't fail)
+	if s.cache != nil {
+		if err := s.cache.Save(source, s.config.DocsBaseURL, docs); err != nil {
+			s.logger.Warn("Failed to save cache", "source", source, "error", err)
+		}
+	}
+
+	st.configCatalog = configref.NewCatalog(docs)
+	s.logger.Info("Configuration option catalogue built", "count", st.configCatalog.Count())
+
+	return nil
+}
+
+// extractConfigOptions returns the option documents of configuration pages. Unchanged
+// pages carry no content, so they reuse the options previously extracted from them,
+// found by their page URL; when no previous catalogue is cached they are fetched again.
+func (s *Server) extractConfigOptions(ctx context.Context, source string, pages []fetcher.DocumentPage) []*index.Document {
+	previous := s.loadPreviousFetch(source).documents
+	previousByURL := make(map[string][]*index.Document)
+	for _, doc := range previous {
+		previousByURL[doc.URL] = append(previousByURL[doc.URL], doc)
+	}
+
+	var docs []*index.Document
+	refetch := make(map[string]bool)
+	for _, page := range pages {
+		switch {
+		case !page.NotModified:
+			docs = append(docs, s.configOptionDocuments(page)...)
+		case previous != nil:
+			docs = append(docs, previousByURL[s.config.DocsBaseURL+page.Path]...)
+		default:
+			refetch[page.Path] = true
+		}
+	}
+
+	if len(refetch) > 0 {
+		fetched, err := s.multiFetcher.FetchNATSPages(ctx, func(path string) bool { return refetch[path] })
+		if err != nil {
+			s.logger.Warn("Failed to fetch unchanged configuration pages", "error", err)
+		}
+		for _, page := range fetched {
+			docs = append(docs, s.configOptionDocuments(page)...)
+		}
+	}
+
+	return docs
+}
+
+// configOptionDocuments returns the option documents extracted from a fetched
+// configuration page
+func (s *Server) configOptionDocuments(page fetcher.DocumentPage) []*index.Document {
+	options, err := configref.ExtractHTML(page.Content, s.config.DocsBaseURL+page.Path)
+	if err != nil {
+		s.logger.Warn("Failed to extract configuration options", "path", page.Path, "error", err)
+		return nil
+	}
+	docs := make([]*index.Document, 0, len(options))
+	for _, option := range options {
+		docs = append(docs, option.Document())
+	}
+	return docs
+}
+
+// handleLookupConfigOptionTool handles the lookup_nats_config_option tool invocation.
+// It resolves an option name or block path to structured option entries.
+func (s *Server) handleLookupConfigOptionTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := request.RequireString("name")
+	if err != nil || strings.TrimSpace(name) == "" {
+		return mcp.NewToolResultError("name parameter is required and must be a non-empty string"), nil
+	}
+
+	catalog := s.docs().configCatalog
+	if catalog == nil || catalog.Count() == 0 {
+		return mcp.NewToolResultError("configuration option catalogue is not available; try refresh_docs_cache"), nil
+	}
+
+	options := catalog.Lookup(name)
+	if len(options) == 0 {
+		s.logger.Info("Configuration option lookup found no option", "name", name)
+		return mcp.NewToolResultError(fmt.Sprintf("no configuration option found for: %s", name)), nil
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("Found %d option(s) for: %s\n\n", len(options), name))
+	for _, option := range options {
+		content.WriteString(formatConfigOption(option))
+		content.WriteString("\n")
+	}
+
+	s.logger.Info("Configuration option lookup completed", "name", name, "results", len(options))
+
+	return mcp.NewToolResultText(content.String()), nil
+}
+
+// formatConfigOption renders a configuration option as markdown
+func formatConfigOption(option *configref.Option) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("## %s\n\n", option.Path()))
+	block := option.Block
+	if block == "" {
+		block = "(top-level)"
+	}
+	b.WriteString(fmt.Sprintf("- Block: %s\n", block))
+	if option.Type != "" {
+		b.WriteString(fmt.Sprintf("- Type: %s\n", option.Type))
+	}
+	if option.Default != "" {
+		b.WriteString(fmt.Sprintf("- Default: %s\n", option.Default))
+	}
+	if option.SourceURL != "" {
+		b.WriteString(fmt.Sprintf("- Source: %s\n", option.SourceURL))
+	}
+	if option.Description != "" {
+		b.WriteString("\n")
+		b.WriteString(option.Description)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const testConfigurationPage = `<html><body>
+<h1>Configuring NATS Server</h1>
+<table>
+  <tr><th>Property</th><th>Description</th><th>Default</th></tr>
+  <tr><td>max_payload</td><td>Maximum number of bytes in a message payload.</td><td>1MB</td></tr>
+</table>
+<h2>jetstream Configuration Block</h2>
+<table>
+  <tr><th>Property</th><th>Description</th><th>Default</th></tr>
+  <tr><td>store_dir</td><td>Directory to use for JetStream storage.</td><td>/tmp/nats/jetstream</td></tr>
+</table>
+</body></html>`
+
+func TestHandleLookupConfigOptionTool(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.CacheDir = t.TempDir()
+
+	srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	// The catalogue is not available before initialization
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"name": "max_payload"}
+	result, err := srv.handleLookupConfigOptionTool(context.Background(), request)
+	if err != nil || !result.IsError {
+		t.Fatalf("expected error result before initialization, got %v (err: %v)", result, err)
+	}
+
+	pages := []fetcher.DocumentPage{
+		{Path: "/running-a-nats-service/configuration", Content: []byte(testConfigurationPage)},
+		// Pages outside the configuration reference are ignored
+		{Path: "/nats-concepts/overview", Content: []byte(testConfigurationPage)},
+	}
+	if err := srv.initializeConfigOptions(context.Background(), srv.state, false, pages); err != nil {
+		t.Fatalf("initializeConfigOptions failed: %v", err)
+	}
+	if srv.state.configCatalog.Count() != 2 {
+		t.Fatalf("expected 2 options, got %d", srv.state.configCatalog.Count())
+	}
+
+	tests := []struct {
+		name      string
+		query     interface{}
+		wantError bool
+		contains  []string
+	}{
+		{
+			name:     "top-level option",
+			query:    "max_payload",
+			contains: []string{"## max_payload", "Block: (top-level)", "Default: 1MB", "Type: size", "https://docs.nats.io/running-a-nats-service/configuration"},
+		},
+		{
+			name:     "option in block",
+			query:    "store_dir",
+			contains: []string{"## jetstream.store_dir", "Block: jetstream", "Default: /tmp/nats/jetstream"},
+		},
+		{
+			name:     "block prefix",
+			query:    "jetstream.",
+			contains: []string{"Found 1 option(s)", "jetstream.store_dir"},
+		},
+		{name: "unknown option", query: "no_such_option", wantError: true},
+		{name: "empty name", query: "", wantError: true},
+		{name: "missing name", query: nil, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			if tt.query != nil {
+				request.Params.Arguments = map[string]interface{}{"name": tt.query}
+			}
+
+			result, err := srv.handleLookupConfigOptionTool(context.Background(), request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.IsError != tt.wantError {
+				t.Fatalf("expected IsError=%v, got %v: %s", tt.wantError, result.IsError, resultText(t, result))
+			}
+
+			text := resultText(t, result)
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in result, got:\n%s", want, text)
+				}
+			}
+		})
+	}
+
+	// The catalogue is restored from the cache without fetching pages
+	restored, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	if err := restored.initializeConfigOptions(context.Background(), restored.state, false, nil); err != nil {
+		t.Fatalf("initializeConfigOptions from cache failed: %v", err)
+	}
+	if restored.state.configCatalog.Count() != 2 {
+		t.Errorf("expected 2 cached options, got %d", restored.state.configCatalog.Count())
+	}
+}
+
+func TestInitializeConfigOptionsMixesChangedAndUnchangedPages(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.DocsBaseURL = "http://127.0.0.1:1"
+
+	clusterPage := func(defaultPort string) []byte {
+		return []byte(`<html><body><h1>cluster Configuration Block</h1><table>
+<tr><th>Property</th><th>Description</th><th>Default</th></tr>
+<tr><td>port</td><td>Port for cluster connections.</td><td>` + defaultPort + `</td></tr>
+</table></body></html>`)
+	}
+	initialize := func(pages []fetcher.DocumentPage) *Server {
+		t.Helper()
+		srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+		if err != nil {
+			t.Fatalf("failed to create server: %v", err)
+		}
+		if err := srv.initializeConfigOptions(context.Background(), srv.state, false, pages); err != nil {
+			t.Fatalf("initializeConfigOptions failed: %v", err)
+		}
+		return srv
+	}
+
+	initialize([]fetcher.DocumentPage{
+		{Path: "/running-a-nats-service/configuration", Content: []byte(testConfigurationPage)},
+		{Path: "/running-a-nats-service/configuration/clustering", Content: clusterPage("6222")},
+	})
+
+	// The unchanged page keeps its options and the changed page is extracted again,
+	// without fetching anything (the base URL is unreachable)
+	srv := initialize([]fetcher.DocumentPage{
+		{Path: "/running-a-nats-service/configuration", NotModified: true},
+		{Path: "/running-a-nats-service/configuration/clustering", Content: clusterPage("7222")},
+	})
+	if count := srv.state.configCatalog.Count(); count != 3 {
+		t.Fatalf("expected the options of both pages, got %d", count)
+	}
+	if options := srv.state.configCatalog.Lookup("max_payload"); len(options) != 1 {
+		t.Errorf("expected the unchanged page to keep its options, got %v", options)
+	}
+	if options := srv.state.configCatalog.Lookup("cluster.port"); len(options) != 1 || options[0].Default != "7222" {
+		t.Errorf("expected the changed page to be extracted again, got %+v", options)
+	}
+
+	// The mixed catalogue is what the next unchanged fetch reuses
+	srv = initialize([]fetcher.DocumentPage{
+		{Path: "/running-a-nats-service/configuration", NotModified: true},
+		{Path: "/running-a-nats-service/configuration/clustering", NotModified: true},
+	})
+	if options := srv.state.configCatalog.Lookup("cluster.port"); srv.state.configCatalog.Count() != 3 || len(options) != 1 || options[0].Default != "7222" {
+		t.Errorf("expected the cached mixed catalogue, got %d options (cluster.port: %+v)", srv.state.configCatalog.Count(), options)
+	}
+}
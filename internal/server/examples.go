@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/examples"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/parser"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/search"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// exampleFilter accepts documents with a code example in language, e.g. "go" or
+// "golang". Languages are resolved as by search_nats_examples, so "ts" matches
+// JavaScript examples and "bash" matches shell sessions. An empty language accepts
+// every document.
+func exampleFilter(language string) (index.SearchFilter, error) {
+	if language == "" {
+		return nil, nil
+	}
+	group, err := examples.ParseLanguage(language)
+	if err != nil {
+		return nil, err
+	}
+	return func(doc *index.Document) bool {
+		return examples.HasLanguage(doc, group)
+	}, nil
+}
+
+// initializeExamples builds the code example catalogue of st from its indexed NATS,
+// Synadia and GitHub documents. Only the default branch of GitHub documentation is
+// included, so examples are not repeated for every indexed version.
+func (s *Server) initializeExamples(st *docState) {
+	var githubDocs []*index.Document
+	defaultVersion := s.versionFilter("")
+	for _, doc := range st.indexManager.GetGitHubIndex().ExportDocuments() {
+		if defaultVersion(doc) {
+			githubDocs = append(githubDocs, doc)
+		}
+	}
+
+	st.exampleCatalog = examples.NewCatalog(
+		examples.Documents{Source: "NATS", Documents: st.indexManager.GetNATSIndex().ExportDocuments()},
+		examples.Documents{Source: "Synadia", Documents: st.indexManager.GetSynadiaIndex().ExportDocuments()},
+		examples.Documents{Source: "GitHub", Documents: githubDocs},
+	)
+	s.logger.Info("Code example catalogue built", "count", st.exampleCatalog.Count())
+}
+
+// exampleSource labels an example with the source of its document, naming the
+// repository of GitHub documents
+func (st *docState) exampleSource(example *examples.Example) string {
+	return st.resultSource(search.SearchResult{DocumentID: example.DocID, Source: example.Source})
+}
+
+// handleSearchExamplesTool handles the search_nats_examples tool invocation
+func (s *Server) handleSearchExamplesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := request.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError("query parameter is required and must be a non-empty string"), nil
+	}
+	limit := request.GetInt("limit", 5)
+
+	language := ""
+	if name := strings.TrimSpace(request.GetString("language", "")); name != "" {
+		language, err = examples.ParseLanguage(name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	st := s.docs()
+	if st.exampleCatalog == nil {
+		return mcp.NewToolResultError("no code examples are indexed"), nil
+	}
+	results, err := st.exampleCatalog.Search(query, language, limit)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("search failed: %v", err)), nil
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("Found %d examples for query: %s", len(results), query))
+	if language != "" {
+		content.WriteString(fmt.Sprintf(" (language: %s)", language))
+	}
+	content.WriteString("\n\n")
+
+	for i, result := range results {
+		example := result.Example
+		title := example.Title
+		if example.Heading != "" && example.Heading != example.Title {
+			title += " > " + example.Heading
+		}
+		content.WriteString(fmt.Sprintf("%d. %s [%s]\n", i+1, title, st.exampleSource(example)))
+		content.WriteString(fmt.Sprintf("   URL: %s\n", example.URL))
+		content.WriteString(fmt.Sprintf("   Document: %s\n", example.DocID))
+		if example.Explanation != "" {
+			content.WriteString(fmt.Sprintf("   Explanation: %s\n", strings.ReplaceAll(example.Explanation, "\n\n", "\n   ")))
+		}
+		block := parser.CodeBlock{Language: example.Language, Content: example.Code, Caption: example.Caption}
+		content.WriteString("\n" + block.Fenced() + "\n\n")
+	}
+
+	s.logger.Info("Example search completed", "query", query, "language", language, "results", len(results))
+
+	return mcp.NewToolResultText(content.String()), nil
+}
@@ -0,0 +1,258 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/parser"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// initializeTestExamples builds the code example catalogue once the documents of a
+// test server are indexed
+func initializeTestExamples(s *Server, ctx context.Context, st *docState, force bool) error {
+	s.initializeExamples(st)
+	return nil
+}
+
+// examplesTestOptions index pages parsed from HTML with Go and Python code examples
+// as NATS documentation
+func examplesTestOptions(t *testing.T) testServerOptions {
+	t.Helper()
+
+	pages := map[string]string{
+		"using-nats/publish-go": `<html><head><title>Publishing from Go</title></head><body>
+<h1>Publishing messages</h1><p>Publish a message on a subject.</p>
+<pre><code class="language-go">nc.Publish("orders", data)</code></pre></body></html>`,
+		"using-nats/publish-python": `<html><head><title>Publishing from Python</title></head><body>
+<h1>Publishing messages</h1><p>Publish a message on a subject.</p>
+<pre><code class="language-python">await nc.publish("orders", data)</code></pre></body></html>`,
+	}
+	var docs []*index.Document
+	for id, page := range pages {
+		doc, err := parser.ParseHTML(strings.NewReader(page))
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", id, err)
+		}
+		docs = append(docs, &index.Document{
+			ID:          id,
+			Title:       doc.Title,
+			URL:         "https://docs.nats.io/" + id,
+			Content:     extractContent(doc),
+			Sections:    convertSections(doc.Sections),
+			LastUpdated: time.Now(),
+		})
+	}
+	return testServerOptions{nats: docs}
+}
+
+func TestHandleSearchToolHasExample(t *testing.T) {
+	srv := newTestServer(t, examplesTestOptions(t))
+
+	search := func(args map[string]interface{}) string {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := srv.handleSearchTool(context.Background(), request)
+		if err != nil || result.IsError {
+			t.Fatalf("search failed: %v %s", err, resultText(t, result))
+		}
+		return resultText(t, result)
+	}
+
+	text := search(map[string]interface{}{"query": "publish message subject"})
+	if !strings.Contains(text, "Found 2 results") {
+		t.Errorf("expected both pages without a filter, got:\n%s", text)
+	}
+
+	text = search(map[string]interface{}{"query": "publish message subject", "has_example": "golang"})
+	if !strings.Contains(text, "Found 1 results") || !strings.Contains(text, "Publishing from Go") {
+		t.Errorf("expected only the page with a Go example, got:\n%s", text)
+	}
+
+	text = search(map[string]interface{}{"query": "publish message subject", "has_example": "rust"})
+	if !strings.Contains(text, "Found 0 results") {
+		t.Errorf("expected no pages with a Rust example, got:\n%s", text)
+	}
+}
+
+func TestFormatDocumentRendersCodeFences(t *testing.T) {
+	srv := newTestServer(t, examplesTestOptions(t))
+
+	doc, err := srv.state.indexManager.GetNATSIndex().Get("using-nats/publish-go")
+	if err != nil {
+		t.Fatalf("failed to get document: %v", err)
+	}
+	if len(doc.Sections) != 1 || len(doc.Sections[0].CodeBlocks) != 1 || doc.Sections[0].CodeBlocks[0].Language != "go" {
+		t.Fatalf("expected one Go code block in the indexed document, got %+v", doc.Sections)
+	}
+	if text := formatDocument(doc); !strings.Contains(text, "```go\nnc.Publish(\"orders\", data)\n```") {
+		t.Errorf("expected the code example as a fenced block, got:\n%s", text)
+	}
+}
+
+func TestHandleSearchExamplesTool(t *testing.T) {
+	exampleFile := "package jetstream_test\n\n" +
+		"// Fetches a batch of messages from a pull consumer.\n" +
+		"func ExampleConsumer_Fetch() {\n\tmsgs, _ := cons.Fetch(10)\n\tfor msg := range msgs.Messages() {\n\t\tmsg.Ack()\n\t}\n}\n"
+	doc, err := parser.ParseFile([]byte(exampleFile), "jetstream/example_test.go")
+	if err != nil {
+		t.Fatalf("failed to parse example file: %v", err)
+	}
+	opts := examplesTestOptions(t)
+	opts.github = []*index.Document{{
+		ID:          "nats.go/jetstream/example_test.go",
+		Title:       doc.Title,
+		URL:         "https://github.com/nats-io/nats.go/blob/main/jetstream/example_test.go",
+		Content:     extractContent(doc),
+		Sections:    convertSections(doc.Sections),
+		LastUpdated: time.Now(),
+	}}
+	opts.initializers = []testInitializer{initializeTestExamples}
+	srv := newTestServer(t, opts)
+
+	search := func(args map[string]interface{}) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := srv.handleSearchExamplesTool(context.Background(), request)
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		return result
+	}
+
+	text := resultText(t, search(map[string]interface{}{"query": "pull consumer fetch", "language": "golang"}))
+	for _, want := range []string{
+		"Found 1 examples",
+		"jetstream examples > Example: Consumer.Fetch [GitHub]",
+		"URL: https://github.com/nats-io/nats.go/blob/main/jetstream/example_test.go",
+		"Explanation: Fetches a batch of messages from a pull consumer.",
+		"```go title=\"ExampleConsumer_Fetch\"\nmsgs, _ := cons.Fetch(10)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	text = resultText(t, search(map[string]interface{}{"query": "publish", "language": "python"}))
+	if !strings.Contains(text, "Found 1 examples") || !strings.Contains(text, "Publishing from Python > Publishing messages [NATS]") ||
+		!strings.Contains(text, "Explanation: Publish a message on a subject.") || !strings.Contains(text, "URL: https://docs.nats.io/using-nats/publish-python") {
+		t.Errorf("expected the Python example of the HTML docs, got:\n%s", text)
+	}
+
+	text = resultText(t, search(map[string]interface{}{"query": "publish"}))
+	if !strings.Contains(text, "Found 2 examples") {
+		t.Errorf("expected examples in every language without a filter, got:\n%s", text)
+	}
+
+	if result := search(map[string]interface{}{"query": "publish", "language": "cobol"}); !result.IsError {
+		t.Error("expected an error for an unsupported language")
+	}
+}
+
+func TestHasExampleMatchesSearchExamplesLanguage(t *testing.T) {
+	opts := examplesTestOptions(t)
+	opts.nats = append(opts.nats,
+		&index.Document{
+			ID:    "using-nats/publish-cli",
+			Title: "Publishing from the CLI",
+			URL:   "https://docs.nats.io/using-nats/publish-cli",
+			Sections: []index.Section{{
+				Heading:    "Publishing messages",
+				Content:    "Publish a message on a subject.",
+				CodeBlocks: []index.CodeBlock{{Content: "nats pub orders hello"}},
+			}},
+		},
+		&index.Document{
+			ID:    "using-nats/publish-ts",
+			Title: "Publishing from TypeScript",
+			URL:   "https://docs.nats.io/using-nats/publish-ts",
+			Sections: []index.Section{{
+				Heading:    "Publishing messages",
+				Content:    "Publish a message on a subject.",
+				CodeBlocks: []index.CodeBlock{{Language: "typescript", Content: "nc.publish(\"orders\", data);"}},
+			}},
+		},
+	)
+	opts.initializers = []testInitializer{initializeTestExamples}
+	srv := newTestServer(t, opts)
+
+	// urls returns the URLs listed by a tool result
+	urls := func(result *mcp.CallToolResult, err error) []string {
+		t.Helper()
+		if err != nil || result.IsError {
+			t.Fatalf("search failed: %v %s", err, resultText(t, result))
+		}
+		var found []string
+		for _, line := range strings.Split(resultText(t, result), "\n") {
+			if url, ok := strings.CutPrefix(strings.TrimSpace(line), "URL: "); ok {
+				found = append(found, url)
+			}
+		}
+		sort.Strings(found)
+		return found
+	}
+
+	for language, want := range map[string]string{
+		"bash": "https://docs.nats.io/using-nats/publish-cli",
+		"ts":   "https://docs.nats.io/using-nats/publish-ts",
+	} {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{"query": "publish orders", "has_example": language}
+		pages := urls(srv.handleSearchTool(context.Background(), request))
+
+		request.Params.Arguments = map[string]interface{}{"query": "publish orders", "language": language}
+		examplePages := urls(srv.handleSearchExamplesTool(context.Background(), request))
+
+		if len(pages) != 1 || pages[0] != want || !reflect.DeepEqual(pages, examplePages) {
+			t.Errorf("expected both tools to find %s for %q, got pages %v and examples %v", want, language, pages, examplePages)
+		}
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"query": "publish orders", "has_example": "cobol"}
+	if result, err := srv.handleSearchTool(context.Background(), request); err != nil || !result.IsError {
+		t.Error("expected an error for an unsupported language, as for search_nats_examples")
+	}
+}
+
+func TestHandleSearchExamplesToolCollidingDocumentIDs(t *testing.T) {
+	page := func(site string) []*index.Document {
+		return []*index.Document{{
+			ID:    "nats-tools/nsc",
+			Title: "NSC",
+			URL:   site + "/nats-tools/nsc",
+			Sections: []index.Section{{
+				Heading:    "Creating an operator",
+				Content:    "Create an operator with nsc.",
+				CodeBlocks: []index.CodeBlock{{Content: "nsc add operator --name " + site}},
+			}},
+		}}
+	}
+	srv := newTestServer(t, testServerOptions{
+		nats:         page("https://docs.nats.io"),
+		synadia:      page("https://docs.synadia.com"),
+		initializers: []testInitializer{initializeTestExamples},
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"query": "nsc add operator", "language": "cli"}
+	result, err := srv.handleSearchExamplesTool(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("search failed: %v %s", err, resultText(t, result))
+	}
+	text := resultText(t, result)
+	for _, want := range []string{
+		"Found 2 examples",
+		"NSC > Creating an operator [NATS]\n   URL: https://docs.nats.io/nats-tools/nsc",
+		"NSC > Creating an operator [Synadia]\n   URL: https://docs.synadia.com/nats-tools/nsc",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
+)
+
+// TestInitializeReplaysHTTPFixtures verifies that an initialization recorded against
+// a documentation site is reproduced from the fixtures once the site is gone
+func TestInitializeReplaysHTTPFixtures(t *testing.T) {
+	pages := map[string]string{
+		"/nats-concepts/jetstream": "<html><head><title>JetStream</title></head><body><h1>JetStream</h1><p>Streams persist messages.</p></body></html>",
+		"/using-nats/connecting":   "<html><head><title>Connecting</title></head><body><h1>Connecting</h1><p>Clients connect to servers.</p></body></html>",
+	}
+	var site *httptest.Server
+	site = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sitemap-pages.xml" {
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+			for path := range pages {
+				fmt.Fprintf(w, "<url><loc>%s%s</loc></url>", site.URL, path)
+			}
+			fmt.Fprint(w, "</urlset>")
+			return
+		}
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, page)
+	}))
+
+	fixtures := t.TempDir()
+	initialize := func(mode string) *Server {
+		t.Helper()
+		cfg := config.NewConfig()
+		cfg.DocsBaseURL = site.URL
+		cfg.CacheDir = t.TempDir()
+		cfg.HTTPFixturesMode = mode
+		cfg.HTTPFixturesDir = fixtures
+
+		srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+		if err != nil {
+			t.Fatalf("failed to create server: %v", err)
+		}
+		if err := srv.Initialize(context.Background()); err != nil {
+			t.Fatalf("Initialize in %s mode failed: %v", mode, err)
+		}
+		return srv
+	}
+
+	recorded := initialize("record")
+	site.Close()
+	replayed := initialize("replay")
+
+	natsIndex := replayed.state.indexManager.GetNATSIndex()
+	if natsIndex.Count() != len(pages) || natsIndex.Count() != recorded.state.indexManager.GetNATSIndex().Count() {
+		t.Fatalf("expected %d replayed documents, got %d", len(pages), natsIndex.Count())
+	}
+	doc, err := natsIndex.Get("nats-concepts/jetstream")
+	if err != nil {
+		t.Fatalf("expected the replayed page to be indexed: %v", err)
+	}
+	if doc.Title != "JetStream" {
+		t.Errorf("unexpected replayed title: %q", doc.Title)
+	}
+}
+
+// TestNewServerWithInvalidHTTPFixtures verifies that an invalid fixture configuration
+// fails the server instead of sending requests to the network
+func TestNewServerWithInvalidHTTPFixtures(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.HTTPFixturesMode = "rewind"
+	cfg.HTTPFixturesDir = t.TempDir()
+
+	if _, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil))); err == nil {
+		t.Error("expected an error for an unknown fixture mode")
+	}
+}
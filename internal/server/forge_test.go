@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher/githubtest"
+)
+
+func TestInitializeGitHubEnterpriseRepository(t *testing.T) {
+	ghe := githubtest.NewServer()
+	defer ghe.Close()
+	ghe.Token = "ghe-token"
+	ghe.AddRepo("ext", "nats-auth-callout", "main", map[string]string{
+		"README.md": "# Auth Callout Extension\n\nDelegates authentication to a service.\n",
+	})
+
+	cfg := config.NewConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.GitHubToken = "github-token"
+	cfg.GitHubRepositories = []config.GitHubRepository{{Owner: "ext", Name: "nats-auth-callout"}}
+	cfg.GitHubForges = []config.RepositoryForge{{
+		Repository: "ext/nats-auth-callout",
+		APIURL:     ghe.URL,
+		WebURL:     "https://ghe.example.com/{owner}/{repo}/blob/{ref}/{path}",
+		Token:      "ghe-token",
+	}}
+
+	srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	if err := srv.initializeGitHub(context.Background(), srv.state, true); err != nil {
+		t.Fatalf("initializeGitHub failed: %v", err)
+	}
+
+	doc, err := srv.state.indexManager.GetGitHubIndex().Get("nats-auth-callout/README.md")
+	if err != nil {
+		t.Fatalf("expected the enterprise README to be indexed: %v", err)
+	}
+	if want := "https://ghe.example.com/ext/nats-auth-callout/blob/main/README.md"; doc.URL != want {
+		t.Errorf("expected URL %s, got %s", want, doc.URL)
+	}
+
+	for _, req := range ghe.Requests() {
+		if req.Authorization != "token ghe-token" {
+			t.Errorf("expected the enterprise token on %s, got %q", req.Path, req.Authorization)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher/githubtest"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+func TestInitializeGitHubRepositoryFilters(t *testing.T) {
+	forge := githubtest.NewServer()
+	defer forge.Close()
+	forge.AddRepo("nats-io", "nats-server", "v2.11.0", map[string]string{
+		"README.md":                  "# NATS Server\n\nTop-level readme.\n",
+		"doc/adr/clustering.rst":     "Clustering\n==========\n\nRoutes connect servers.\n",
+		"doc/guide.adoc":             "= Operator Guide\n\n== Accounts\n\nAccounts isolate subjects.\n",
+		"doc/testdata/fixture.md":    "# Fixture\n",
+		"doc/CHANGELOG.md":           "# Changelog\n",
+		"doc/notes.txt":              "Release Notes\n\nPlain text notes.\n",
+		"server/jetstream_test.go":   "package server\n",
+		"doc/images/architecture.md": "# Architecture\n",
+	})
+
+	cfg := config.NewConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.GitHubRepositories = []config.GitHubRepository{{
+		Owner:       "nats-io",
+		Name:        "nats-server",
+		Ref:         "v2.11.0",
+		DocRoot:     "doc",
+		Exclude:     []string{"testdata/**", "CHANGELOG.md", "images"},
+		DisplayName: "NATS Server Design Docs",
+	}}
+	cfg.GitHubForges = []config.RepositoryForge{{Repository: "nats-io/nats-server", APIURL: forge.URL}}
+
+	srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	if err := srv.initializeGitHub(context.Background(), srv.state, true); err != nil {
+		t.Fatalf("initializeGitHub failed: %v", err)
+	}
+
+	githubIndex := srv.state.indexManager.GetGitHubIndex()
+	for _, id := range []string{"nats-server/doc/adr/clustering.rst", "nats-server/doc/guide.adoc", "nats-server/doc/notes.txt"} {
+		doc, err := githubIndex.Get(id)
+		if err != nil {
+			t.Errorf("expected %s to be indexed: %v", id, err)
+			continue
+		}
+		if doc.Metadata[index.MetaVersion] != "v2.11.0" {
+			t.Errorf("expected %s at the repository ref, got version %q", id, doc.Metadata[index.MetaVersion])
+		}
+		if doc.Metadata[index.MetaRepository] != "NATS Server Design Docs" {
+			t.Errorf("expected the display name on %s, got %q", id, doc.Metadata[index.MetaRepository])
+		}
+	}
+	for _, id := range []string{"nats-server/README.md", "nats-server/doc/testdata/fixture.md", "nats-server/doc/CHANGELOG.md", "nats-server/doc/images/architecture.md"} {
+		if _, err := githubIndex.Get(id); err == nil {
+			t.Errorf("expected %s to be filtered out", id)
+		}
+	}
+
+	doc, err := githubIndex.Get("nats-server/doc/guide.adoc")
+	if err != nil {
+		t.Fatalf("expected the AsciiDoc guide to be indexed: %v", err)
+	}
+	if doc.Title != "Operator Guide" {
+		t.Errorf("expected AsciiDoc title, got %q", doc.Title)
+	}
+	if !strings.Contains(formatDocument(doc), "Repository: NATS Server Design Docs") {
+		t.Error("expected the display name in the formatted document")
+	}
+
+	// Documents of the repository ref are the default version in search results
+	filter := srv.versionFilter("")
+	if !filter(doc) {
+		t.Error("expected documents at the repository ref to pass the default version filter")
+	}
+}
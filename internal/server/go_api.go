@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/goapi"
+)
+
+// initializeGoAPI builds the Go API reference from the configured local module paths
+// and indexes one document per exported type, function and method into st. force
+// revalidates a fresh cache.
+func (s *Server) initializeGoAPI(ctx context.Context, st *docState, force bool) error {
+	source := "go-api"
+
+	docs := s.loadCachedDocuments(source, force)
+	if docs == nil {
+		for _, module := range s.config.GoAPIModules {
+			symbols, err := goapi.LoadModule(ctx, module.Path, module.ImportPath)
+			if err != nil {
+				s.logger.Warn("Failed to load Go module", "path", module.Path, "error", err)
+				continue
+			}
+			for _, symbol := range symbols {
+				docs = append(docs, symbol.Document())
+			}
+			s.logger.Info("Loaded Go API reference", "path", module.Path, "symbols", len(symbols))
+		}
+
+		if len(docs) == 0 {
+			return fmt.Errorf("failed to load any Go API symbols")
+		}
+
+		// Save to cache (best-effort, log errors but don't fail)
+		if s.cache != nil {
+			if err := s.cache.Save(source, "https://pkg.go.dev/", docs); err != nil {
+				s.logger.Warn("Failed to save cache", "source", source, "error", err)
+			}
+		}
+	}
+
+	// Go API documents are derived from source repositories, so they are searchable
+	// alongside GitHub docs
+	if err := st.indexManager.IndexGitHub(docs); err != nil {
+		return fmt.Errorf("failed to index Go API reference: %w", err)
+	}
+
+	s.logger.Info("Go API reference indexed", "count", len(docs))
+	return nil
+}
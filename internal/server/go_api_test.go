@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestInitializeGoAPIFromLocalModule(t *testing.T) {
+	moduleDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module github.com/nats-io/nats.go\n",
+		"nats.go": "package nats\n\n// Conn represents a bare connection to a nats-server.\ntype Conn struct{}\n\n// Flush will perform a round trip to the server.\nfunc (nc *Conn) Flush() error { return nil }\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(moduleDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	cfg := config.NewConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.GoAPIEnabled = true
+	cfg.GoAPIModules = []config.GoModule{{Path: moduleDir}, {Path: filepath.Join(moduleDir, "missing")}}
+
+	srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	if err := srv.initializeGoAPI(context.Background(), srv.state, false); err != nil {
+		t.Fatalf("initializeGoAPI failed: %v", err)
+	}
+	srv.initialized = true
+
+	if count := srv.state.indexManager.GetGitHubIndex().Count(); count != 2 {
+		t.Fatalf("expected 2 Go API documents, got %d", count)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"doc_id": "go-api/github.com/nats-io/nats.go#Conn.Flush"}
+	result, err := srv.handleRetrieveTool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("handleRetrieveTool failed: %v", err)
+	}
+	text := resultText(t, result)
+	for _, want := range []string{
+		"https://pkg.go.dev/github.com/nats-io/nats.go#Conn.Flush",
+		"func (nc *Conn) Flush() error",
+		"Flush will perform a round trip to the server.",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in retrieved document, got:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// previousFetch holds what the last cached fetch of a source recorded, so a new
+// fetch can skip unchanged pages and reuse their parsed documents.
+type previousFetch struct {
+	documents  []*index.Document
+	byID       map[string]*index.Document
+	validators map[string]fetcher.Validator
+	failures   []fetcher.PageFailure
+}
+
+// loadPreviousFetch loads the cached documents and validators of a source regardless
+// of the cache age. It returns an empty previousFetch when nothing is cached.
+func (s *Server) loadPreviousFetch(source string) previousFetch {
+	var previous previousFetch
+	if s.cache == nil {
+		return previous
+	}
+
+	cached, err := s.cache.Load(source)
+	if err != nil {
+		return previous
+	}
+
+	previous.documents = cached.Documents
+	previous.validators = cached.Validators
+	previous.failures = cached.Failures
+	previous.byID = make(map[string]*index.Document, len(cached.Documents))
+	for _, doc := range cached.Documents {
+		previous.byID[doc.ID] = doc
+	}
+
+	return previous
+}
+
+// reuse returns the previously parsed document with the given ID when the page or
+// file it came from is unchanged, or nil when it must be parsed again.
+func (p previousFetch) reuse(notModified bool, id string) *index.Document {
+	if !notModified {
+		return nil
+	}
+	return p.byID[id]
+}
+
+// revalidate returns the validators of the pages or files whose documents were
+// cached, for the fetcher to skip or revalidate. docID maps a validator key to the
+// ID of the document parsed from it. A page without a cached document, e.g. after a
+// parse failure, is fetched in full even when its validators still match, since an
+// unchanged page would have no document to reuse.
+func (p previousFetch) revalidate(docID func(key string) string) map[string]fetcher.Validator {
+	validators := make(map[string]fetcher.Validator, len(p.validators))
+	for key, validator := range p.validators {
+		if _, ok := p.byID[docID(key)]; ok {
+			validators[key] = validator
+		}
+	}
+	return validators
+}
+
+// retry returns the paths of the pages that failed to fetch last time, so the next
+// fetch tries them first
+func (p previousFetch) retry() []string {
+	paths := make([]string, 0, len(p.failures))
+	for _, failure := range p.failures {
+		paths = append(paths, failure.Path)
+	}
+	return paths
+}
+
+// keepFailed returns the previously parsed documents of the pages that failed to
+// fetch, so a flaky page stays searchable until it is fetched again. docID maps the
+// path of a failure to the ID of the document parsed from it; an ID ending in "/"
+// is a prefix that keeps every document under it, e.g. those of a repository that
+// failed as a whole. Documents in fetched, e.g. those of a repository archive read
+// before its download failed, are not kept twice.
+func (p previousFetch) keepFailed(failures []fetcher.PageFailure, fetched []*index.Document, docID func(path string) string) []*index.Document {
+	if len(failures) == 0 {
+		return nil
+	}
+	skip := make(map[string]bool, len(fetched))
+	for _, doc := range fetched {
+		skip[doc.ID] = true
+	}
+
+	var kept []*index.Document
+	keep := func(doc *index.Document) {
+		if !skip[doc.ID] {
+			skip[doc.ID] = true
+			kept = append(kept, doc)
+		}
+	}
+	for _, failure := range failures {
+		id := docID(failure.Path)
+		if !strings.HasSuffix(id, "/") {
+			if doc, ok := p.byID[id]; ok {
+				keep(doc)
+			}
+			continue
+		}
+		for _, doc := range p.documents {
+			if strings.HasPrefix(doc.ID, id) {
+				keep(doc)
+			}
+		}
+	}
+	return kept
+}
+
+// partialFetch splits the error of a documentation fetch into the pages that failed
+// and the error that makes the fetch unusable, which is nil when enough pages were
+// fetched for the partial results to be indexed
+func partialFetch(err error) ([]fetcher.PageFailure, error) {
+	var partial *fetcher.PartialFetchError
+	if !errors.As(err, &partial) {
+		return nil, err
+	}
+	if partial.Tolerable() {
+		return partial.Failures, nil
+	}
+	return partial.Failures, err
+}
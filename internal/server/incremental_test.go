@@ -0,0 +1,402 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher/githubtest"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+func TestInitializeNATSSkipsUnchangedPages(t *testing.T) {
+	var mu sync.Mutex
+	lastmod := map[string]string{"/alpha": "2024-01-01", "/beta": "2024-01-01"}
+	requests := make(map[string]int)
+
+	var site *httptest.Server
+	site = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests[r.URL.Path]++
+
+		if r.URL.Path == "/sitemap-pages.xml" {
+			var sitemap strings.Builder
+			sitemap.WriteString(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+			for _, path := range []string{"/alpha", "/beta"} {
+				fmt.Fprintf(&sitemap, "<url><loc>%s%s</loc><lastmod>%s</lastmod></url>", site.URL, path, lastmod[path])
+			}
+			sitemap.WriteString(`</urlset>`)
+			_, _ = w.Write([]byte(sitemap.String()))
+			return
+		}
+		fmt.Fprintf(w, "<html><head><title>%s</title></head><body><h1>%s</h1><p>Revision %s</p></body></html>",
+			r.URL.Path, r.URL.Path, lastmod[r.URL.Path])
+	}))
+	defer site.Close()
+
+	cfg := config.NewConfig()
+	cfg.DocsBaseURL = site.URL
+	cfg.CacheDir = t.TempDir()
+
+	initialize := func() *Server {
+		t.Helper()
+		srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+		if err != nil {
+			t.Fatalf("failed to create server: %v", err)
+		}
+		if err := srv.initializeNATS(context.Background(), srv.state, true); err != nil {
+			t.Fatalf("initializeNATS failed: %v", err)
+		}
+		return srv
+	}
+
+	initialize()
+	if requests["/alpha"] != 1 || requests["/beta"] != 1 {
+		t.Fatalf("expected every page to be fetched once, got %v", requests)
+	}
+
+	// Only the page whose lastmod changed is downloaded again
+	mu.Lock()
+	lastmod["/beta"] = "2024-02-01"
+	mu.Unlock()
+
+	srv := initialize()
+	if requests["/alpha"] != 1 {
+		t.Errorf("expected unchanged page to be skipped, got %d requests", requests["/alpha"])
+	}
+	if requests["/beta"] != 2 {
+		t.Errorf("expected changed page to be fetched again, got %d requests", requests["/beta"])
+	}
+
+	if count := srv.state.indexManager.GetNATSIndex().Count(); count != 2 {
+		t.Fatalf("expected 2 indexed documents, got %d", count)
+	}
+	alpha, err := srv.state.indexManager.GetNATSIndex().Get("alpha")
+	if err != nil || !strings.Contains(alpha.Content, "Revision 2024-01-01") {
+		t.Errorf("expected unchanged page to be reused from cache, got %v (err: %v)", alpha, err)
+	}
+	beta, err := srv.state.indexManager.GetNATSIndex().Get("beta")
+	if err != nil || !strings.Contains(beta.Content, "Revision 2024-02-01") {
+		t.Errorf("expected changed page to be re-parsed, got %v (err: %v)", beta, err)
+	}
+
+	// Documents cached by an older parser are parsed again even when unchanged
+	cachePath := filepath.Join(cfg.CacheDir, "nats.json")
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("failed to read cache: %v", err)
+	}
+	var cached map[string]interface{}
+	if err := json.Unmarshal(data, &cached); err != nil {
+		t.Fatalf("failed to decode cache: %v", err)
+	}
+	cached["version"] = "1.2"
+	if data, err = json.Marshal(cached); err != nil {
+		t.Fatalf("failed to encode cache: %v", err)
+	}
+	if err := os.WriteFile(cachePath, data, 0644); err != nil {
+		t.Fatalf("failed to write cache: %v", err)
+	}
+
+	initialize()
+	if requests["/alpha"] != 2 {
+		t.Errorf("expected a page cached in an older format to be fetched again, got %d requests", requests["/alpha"])
+	}
+}
+
+func TestInitializeNATSFetchesUnchangedPagesMissingFromCache(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	var site *httptest.Server
+	site = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests[r.URL.Path]++
+
+		if r.URL.Path == "/sitemap-pages.xml" {
+			fmt.Fprintf(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>%s/alpha</loc><lastmod>2024-01-01</lastmod></url><url><loc>%s/beta</loc><lastmod>2024-01-01</lastmod></url></urlset>`, site.URL, site.URL)
+			return
+		}
+		fmt.Fprintf(w, "<html><head><title>%s</title></head><body><h1>%s</h1><p>Content</p></body></html>", r.URL.Path, r.URL.Path)
+	}))
+	defer site.Close()
+
+	cfg := config.NewConfig()
+	cfg.DocsBaseURL = site.URL
+	cfg.CacheDir = t.TempDir()
+
+	initialize := func() *Server {
+		t.Helper()
+		srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+		if err != nil {
+			t.Fatalf("failed to create server: %v", err)
+		}
+		if err := srv.initializeNATS(context.Background(), srv.state, true); err != nil {
+			t.Fatalf("initializeNATS failed: %v", err)
+		}
+		return srv
+	}
+	initialize()
+
+	// The cache keeps the validators of /beta but not its document
+	cachePath := filepath.Join(cfg.CacheDir, "nats.json")
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("failed to read cache: %v", err)
+	}
+	var cached map[string]interface{}
+	if err := json.Unmarshal(data, &cached); err != nil {
+		t.Fatalf("failed to decode cache: %v", err)
+	}
+	var docs []interface{}
+	for _, doc := range cached["documents"].([]interface{}) {
+		if doc.(map[string]interface{})["id"] != "beta" {
+			docs = append(docs, doc)
+		}
+	}
+	cached["documents"], cached["document_count"] = docs, len(docs)
+	if data, err = json.Marshal(cached); err != nil {
+		t.Fatalf("failed to encode cache: %v", err)
+	}
+	if err := os.WriteFile(cachePath, data, 0644); err != nil {
+		t.Fatalf("failed to write cache: %v", err)
+	}
+
+	srv := initialize()
+	if requests["/alpha"] != 1 || requests["/beta"] != 2 {
+		t.Errorf("expected only the page missing from the cache to be fetched again, got %v", requests)
+	}
+	if _, err := srv.state.indexManager.GetNATSIndex().Get("beta"); err != nil {
+		t.Errorf("expected the page missing from the cache to be indexed again: %v", err)
+	}
+}
+
+func TestRefreshCacheKeepsServingPreviousDocuments(t *testing.T) {
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sitemap-pages.xml" {
+			select {
+			case fetching <- struct{}{}:
+			default:
+			}
+			<-release
+		}
+		http.NotFound(w, r)
+	}))
+	defer site.Close()
+
+	cfg := config.NewConfig()
+	cfg.DocsBaseURL = site.URL
+	cfg.CacheDir = t.TempDir()
+
+	srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	if err := srv.docs().indexManager.IndexNATS([]*index.Document{{
+		ID:      "jetstream",
+		Title:   "JetStream",
+		URL:     site.URL + "/jetstream",
+		Content: "JetStream persistence",
+	}}); err != nil {
+		t.Fatalf("failed to index documents: %v", err)
+	}
+
+	refreshed := make(chan error, 1)
+	go func() {
+		_, err := srv.RefreshCache(context.Background())
+		refreshed <- err
+	}()
+
+	// Searches are answered from the previous documents while the refresh runs...
+	<-fetching
+	if _, err := srv.docs().indexManager.GetNATSIndex().Get("jetstream"); err != nil {
+		t.Errorf("expected the previous documents during the refresh: %v", err)
+	}
+	close(release)
+
+	// ...and after it fails
+	if err := <-refreshed; err == nil {
+		t.Fatal("expected the refresh to fail")
+	}
+	if _, err := srv.docs().indexManager.GetNATSIndex().Get("jetstream"); err != nil {
+		t.Errorf("expected a failed refresh to keep the previous documents: %v", err)
+	}
+}
+
+func TestInitializeGitHubKeepsFailedFiles(t *testing.T) {
+	forge := githubtest.NewServer()
+	defer forge.Close()
+	files := func(revision string) map[string]string {
+		return map[string]string{
+			"doc/streams.md":   "# Streams\n\nStreams store messages, revision " + revision + ".\n",
+			"doc/consumers.md": "# Consumers\n\nConsumers read streams.\n",
+		}
+	}
+	forge.AddRepo("nats-io", "nats-server", "main", files("1"))
+	forge.AddRepo("nats-io", "nats-server", "release/v2.11", files("1"))
+
+	cfg := config.NewConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.GitHubMinSuccessRatio = 0.5
+	cfg.GitHubRepositories = []config.GitHubRepository{{Owner: "nats-io", Name: "nats-server", Versions: []string{"release/v2.11"}}}
+	cfg.GitHubForges = []config.RepositoryForge{{Repository: "nats-io/nats-server", APIURL: forge.URL}}
+
+	initialize := func() *Server {
+		t.Helper()
+		srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+		if err != nil {
+			t.Fatalf("failed to create server: %v", err)
+		}
+		if err := srv.initializeGitHub(context.Background(), srv.state, true); err != nil {
+			t.Fatalf("initializeGitHub failed: %v", err)
+		}
+		return srv
+	}
+	initialize()
+
+	// The changed file fails to fetch at both versions
+	forge.AddRepo("nats-io", "nats-server", "main", files("2"))
+	forge.AddRepo("nats-io", "nats-server", "release/v2.11", files("2"))
+	forge.FailContents("nats-io", "nats-server", "doc/streams.md", http.StatusNotFound)
+	srv := initialize()
+
+	githubIndex := srv.state.indexManager.GetGitHubIndex()
+	for _, id := range []string{"nats-server/doc/streams.md", "nats-server@release/v2.11/doc/streams.md"} {
+		doc, err := githubIndex.Get(id)
+		if err != nil || !strings.Contains(doc.Content, "revision 1") {
+			t.Errorf("expected %s to keep its previously indexed version, got %v (err: %v)", id, doc, err)
+		}
+	}
+	if _, err := githubIndex.Get("nats-server/doc/consumers.md"); err != nil {
+		t.Errorf("expected the unchanged file to be indexed: %v", err)
+	}
+	status := srv.formatStatus(time.Now())
+	if !strings.Contains(status, "GitHub: 2 of 4 pages fetched") || !strings.Contains(status, "/blob/release/v2.11/doc/streams.md: ") {
+		t.Errorf("expected the failed files in the server status, got:\n%s", status)
+	}
+
+	// The kept documents stay cached, and the failed files are fetched again next time
+	forge.FailContents("nats-io", "nats-server", "doc/streams.md", 0)
+	srv = initialize()
+	doc, err := srv.state.indexManager.GetGitHubIndex().Get("nats-server/doc/streams.md")
+	if err != nil || !strings.Contains(doc.Content, "revision 2") {
+		t.Errorf("expected the recovered file to be re-parsed, got %v (err: %v)", doc, err)
+	}
+
+	// Below the minimum success ratio the fetch fails as a whole
+	cfg.GitHubMinSuccessRatio = 0.9
+	forge.AddRepo("nats-io", "nats-server", "main", files("3"))
+	forge.FailContents("nats-io", "nats-server", "doc/streams.md", http.StatusNotFound)
+	srv, err = NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	if err := srv.initializeGitHub(context.Background(), srv.state, true); !errors.Is(err, fetcher.ErrTooManyFailures) {
+		t.Errorf("expected ErrTooManyFailures, got %v", err)
+	}
+}
+
+func TestInitializeGitHubKeepsFailedRepositories(t *testing.T) {
+	forge := githubtest.NewServer()
+	defer forge.Close()
+	files := func(revision string) map[string]string {
+		return map[string]string{
+			"doc/streams.md":   "# Streams\n\nStreams store messages, revision " + revision + ".\n",
+			"doc/consumers.md": "# Consumers\n\nConsumers read streams, revision " + revision + ".\n",
+		}
+	}
+	forge.AddRepo("nats-io", "nats-server", "main", files("1"))
+	forge.AddRepo("nats-io", "nats-server", "release/v2.11", files("1"))
+
+	cfg := config.NewConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.GitHubMinSuccessRatio = 0.5
+	cfg.GitHubRepositories = []config.GitHubRepository{{Owner: "nats-io", Name: "nats-server", Versions: []string{"release/v2.11"}}}
+	cfg.GitHubForges = []config.RepositoryForge{{Repository: "nats-io/nats-server", APIURL: forge.URL}}
+
+	initialize := func() *Server {
+		t.Helper()
+		srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+		if err != nil {
+			t.Fatalf("failed to create server: %v", err)
+		}
+		if err := srv.initializeGitHub(context.Background(), srv.state, true); err != nil {
+			t.Fatalf("initializeGitHub failed: %v", err)
+		}
+		return srv
+	}
+	initialize()
+
+	// The file list of one version fails, so none of its files are fetched
+	forge.AddRepo("nats-io", "nats-server", "main", files("2"))
+	forge.AddRepo("nats-io", "nats-server", "release/v2.11", files("2"))
+	forge.FailRepo("nats-io", "nats-server", "release/v2.11", http.StatusNotFound)
+	srv := initialize()
+
+	githubIndex := srv.state.indexManager.GetGitHubIndex()
+	for id, revision := range map[string]string{
+		"nats-server/doc/streams.md":                 "revision 2",
+		"nats-server/doc/consumers.md":               "revision 2",
+		"nats-server@release/v2.11/doc/streams.md":   "revision 1",
+		"nats-server@release/v2.11/doc/consumers.md": "revision 1",
+	} {
+		doc, err := githubIndex.Get(id)
+		if err != nil || !strings.Contains(doc.Content, revision) {
+			t.Errorf("expected %s at %s, got %v (err: %v)", id, revision, doc, err)
+		}
+	}
+	status := srv.formatStatus(time.Now())
+	if !strings.Contains(status, "GitHub: 2 of 3 pages fetched") || !strings.Contains(status, "/blob/release/v2.11/: failed to discover files") {
+		t.Errorf("expected the failed repository in the server status, got:\n%s", status)
+	}
+
+	// The kept documents stay cached until the repository is fetched again
+	forge.FailRepo("nats-io", "nats-server", "release/v2.11", 0)
+	srv = initialize()
+	doc, err := srv.state.indexManager.GetGitHubIndex().Get("nats-server@release/v2.11/doc/streams.md")
+	if err != nil || !strings.Contains(doc.Content, "revision 2") {
+		t.Errorf("expected the recovered repository to be re-parsed, got %v (err: %v)", doc, err)
+	}
+}
+
+func TestKeepFailedSkipsFetchedDocuments(t *testing.T) {
+	docs := []*index.Document{
+		{ID: "nats-server/doc/streams.md"},
+		{ID: "nats-server/doc/consumers.md"},
+		{ID: "nats-server@release/v2.11/doc/streams.md"},
+		{ID: "nats.go/README.md"},
+	}
+	previous := previousFetch{documents: docs, byID: make(map[string]*index.Document)}
+	for _, doc := range docs {
+		previous.byID[doc.ID] = doc
+	}
+	docID := func(path string) string { return path }
+
+	// A whole repository keeps every document under its prefix, except those fetched
+	// before it failed; a failed file also under it is not kept twice
+	failures := []fetcher.PageFailure{{Path: "nats-server/"}, {Path: "nats-server/doc/consumers.md"}}
+	fetched := []*index.Document{{ID: "nats-server/doc/streams.md"}}
+	var ids []string
+	for _, doc := range previous.keepFailed(failures, fetched, docID) {
+		ids = append(ids, doc.ID)
+	}
+	if want := []string{"nats-server/doc/consumers.md"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("kept %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/jsapi"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// initializeJetStreamAPI loads the JetStream API JSON Schemas from a local directory
+// or GitHub, indexes one document per schema into st and builds the lookup catalogue.
+// force revalidates a fresh cache.
+func (s *Server) initializeJetStreamAPI(ctx context.Context, st *docState, force bool) error {
+	source := "jetstream-api"
+
+	docs := s.loadCachedDocuments(source, force)
+	if docs == nil {
+		files, baseURL, err := s.fetchJetStreamSchemaFiles(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch JetStream API schemas: %w", err)
+		}
+
+		for _, file := range files {
+			schemas, err := jsapi.ParseSchemas(file.Content, file.Path)
+			if err != nil {
+				s.logger.Warn("Failed to parse JetStream API schema", "path", file.Path, "error", err)
+				continue
+			}
+			for _, schema := range schemas {
+				docs = append(docs, schema.Document(baseURL+file.Path))
+			}
+		}
+
+		if len(docs) == 0 {
+			return fmt.Errorf("failed to parse any JetStream API schemas")
+		}
+
+		// Save to cache (best-effort, log errors but don't fail)
+		if s.cache != nil {
+			if err := s.cache.Save(source, baseURL, docs); err != nil {
+				s.logger.Warn("Failed to save cache", "source", source, "error", err)
+			}
+		}
+	}
+
+	// Schemas come from repositories, so they are searchable alongside GitHub docs
+	if err := st.indexManager.IndexGitHub(docs); err != nil {
+		return fmt.Errorf("failed to index JetStream API schemas: %w", err)
+	}
+
+	st.jsAPICatalog = jsapi.NewCatalog(docs)
+	s.logger.Info("JetStream API schemas indexed", "count", st.jsAPICatalog.Count())
+
+	return nil
+}
+
+// fetchJetStreamSchemaFiles reads the schema files from the configured local path,
+// or from the configured GitHub repository when no path is set. It returns the files
+// and the URL prefix used to build each schema's source URL.
+func (s *Server) fetchJetStreamSchemaFiles(ctx context.Context) ([]fetcher.GitHubFile, string, error) {
+	if path := s.config.JetStreamSchemasPath; path != "" {
+		s.logger.Info("Reading JetStream API schemas from local path", "path", path)
+		files, err := fetcher.ReadLocalFiles(ctx, path, jsapi.IsSchemaPath)
+		if err != nil {
+			return nil, "", err
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			absPath = path
+		}
+		return files, "file://" + filepath.ToSlash(absPath) + "/", nil
+	}
+
+	repo, err := parseGitHubRepo(s.config, s.config.JetStreamSchemasRepository, s.config.JetStreamSchemasRef)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dir := strings.Trim(s.config.JetStreamSchemasDir, "/")
+	match := func(path string) bool {
+		if dir != "" && !strings.HasPrefix(path, dir+"/") {
+			return false
+		}
+		return jsapi.IsSchemaPath(path)
+	}
+
+	files, err := s.multiFetcher.FetchGitHubFiles(ctx, repo, match)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return files, githubBlobURL(repo), nil
+}
+
+// handleLookupJetStreamAPITool handles the lookup_jetstream_api tool invocation.
+// It resolves an API subject or schema type name to the matching schema documents.
+func (s *Server) handleLookupJetStreamAPITool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := request.RequireString("name")
+	if err != nil || strings.TrimSpace(name) == "" {
+		return mcp.NewToolResultError("name parameter is required and must be a non-empty string"), nil
+	}
+
+	catalog := s.docs().jsAPICatalog
+	if catalog == nil || catalog.Count() == 0 {
+		return mcp.NewToolResultError("JetStream API schemas are not loaded; enable jetstream_schemas in the configuration"), nil
+	}
+
+	docs := catalog.Lookup(name)
+	if len(docs) == 0 {
+		s.logger.Info("JetStream API lookup found no schema", "name", name)
+		return mcp.NewToolResultError(fmt.Sprintf("no JetStream API schema found for: %s", name)), nil
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("Found %d schema(s) for: %s\n\n", len(docs), name))
+	for _, doc := range docs {
+		content.WriteString(formatDocument(doc))
+	}
+
+	s.logger.Info("JetStream API lookup completed", "name", name, "results", len(docs))
+
+	return mcp.NewToolResultText(content.String()), nil
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const testStreamInfoRequestSchema = `{
+  "description": "A request to the JetStream $JS.API.STREAM.INFO API",
+  "title": "io.nats.jetstream.api.v1.stream_info_request",
+  "type": "object",
+  "properties": {
+    "deleted_details": {"type": "boolean", "description": "Include deleted message details", "default": false},
+    "subjects_filter": {"type": "string", "description": "Subjects to report on"}
+  }
+}`
+
+// jetStreamAPITestOptions load JetStream schemas from a temporary directory
+func jetStreamAPITestOptions(t *testing.T) testServerOptions {
+	t.Helper()
+
+	schemaDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(schemaDir, "stream_info_request.json"), []byte(testStreamInfoRequestSchema), 0644); err != nil {
+		t.Fatalf("failed to write schema: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(schemaDir, "broken.json"), []byte("{"), 0644); err != nil {
+		t.Fatalf("failed to write schema: %v", err)
+	}
+
+	return testServerOptions{
+		configure: func(cfg *config.Config) {
+			cfg.JetStreamSchemasEnabled = true
+			cfg.JetStreamSchemasPath = schemaDir
+		},
+		initializers: []testInitializer{(*Server).initializeJetStreamAPI},
+	}
+}
+
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	if result == nil {
+		t.Fatal("expected result but got nil")
+	}
+	var text strings.Builder
+	for _, content := range result.Content {
+		if tc, ok := mcp.AsTextContent(content); ok {
+			text.WriteString(tc.Text)
+		}
+	}
+	return text.String()
+}
+
+func TestInitializeJetStreamAPIFromLocalPath(t *testing.T) {
+	srv := newTestServer(t, jetStreamAPITestOptions(t))
+
+	if srv.state.jsAPICatalog.Count() != 1 {
+		t.Fatalf("expected 1 schema, got %d", srv.state.jsAPICatalog.Count())
+	}
+
+	// Schemas are searchable and retrievable via the GitHub index
+	doc, err := srv.state.indexManager.GetGitHubIndex().Get("jetstream-api/stream_info_request")
+	if err != nil {
+		t.Fatalf("schema document not indexed: %v", err)
+	}
+	if !strings.HasPrefix(doc.URL, "file://") {
+		t.Errorf("expected file URL for local schema, got %s", doc.URL)
+	}
+
+	// A second initialization is served from the cache
+	cached := srv.loadCachedDocuments("jetstream-api", false)
+	if len(cached) != 1 {
+		t.Errorf("expected schema documents to be cached, got %d", len(cached))
+	}
+}
+
+func TestLookupJetStreamAPIToolHandler(t *testing.T) {
+	srv := newTestServer(t, jetStreamAPITestOptions(t))
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		expectError bool
+		contains    string
+	}{
+		{"by subject", map[string]interface{}{"name": "$JS.API.STREAM.INFO.ORDERS"}, false, "deleted_details"},
+		{"by type name", map[string]interface{}{"name": "io.nats.jetstream.api.v1.stream_info_request"}, false, "Default: false"},
+		{"unknown", map[string]interface{}{"name": "$JS.API.NOPE"}, true, "no JetStream API schema found"},
+		{"missing name", map[string]interface{}{}, true, "name parameter is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = tt.args
+
+			result, err := srv.handleLookupJetStreamAPITool(context.Background(), request)
+			if err != nil {
+				t.Fatalf("unexpected error from handler: %v", err)
+			}
+			if result.IsError != tt.expectError {
+				t.Errorf("expected IsError=%v, got %v", tt.expectError, result.IsError)
+			}
+			if text := resultText(t, result); !strings.Contains(text, tt.contains) {
+				t.Errorf("expected result to contain %q, got: %s", tt.contains, text)
+			}
+		})
+	}
+}
+
+func TestLookupJetStreamAPIToolNotLoaded(t *testing.T) {
+	srv := newTestServer(t, testServerOptions{})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"name": "stream_info_request"}
+
+	result, err := srv.handleLookupJetStreamAPITool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error from handler: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected error result when schemas are not loaded")
+	}
+}
@@ -0,0 +1,223 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/errref"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxRelatedDocs is the number of related documentation pages listed per error
+const maxRelatedDocs = 3
+
+// initializeNATSErrors loads the nats-server error definitions and the client error
+// variables, indexes one document per error and builds the lookup catalogue. Either
+// side may fail independently; initialization fails only when neither yields errors.
+// The documents and catalogue are built into st; force revalidates a fresh cache.
+func (s *Server) initializeNATSErrors(ctx context.Context, st *docState, force bool) error {
+	source := "nats-errors"
+
+	docs := s.loadCachedDocuments(source, force)
+	if docs == nil {
+		serverEntries, err := s.loadServerErrors(ctx)
+		if err != nil {
+			s.logger.Warn("Failed to load nats-server error definitions", "error", err)
+		}
+		clientEntries, err := s.loadClientErrors(ctx)
+		if err != nil {
+			s.logger.Warn("Failed to load client error definitions", "error", err)
+		}
+
+		for _, entry := range append(serverEntries, clientEntries...) {
+			docs = append(docs, entry.Document())
+		}
+
+		if len(docs) == 0 {
+			return fmt.Errorf("failed to load any NATS error definitions")
+		}
+
+		// Save to cache (best-effort, log errors but don't fail)
+		if s.cache != nil {
+			if err := s.cache.Save(source, "", docs); err != nil {
+				s.logger.Warn("Failed to save cache", "source", source, "error", err)
+			}
+		}
+	}
+
+	// Error definitions come from repositories, so they are searchable alongside GitHub docs
+	if err := st.indexManager.IndexGitHub(docs); err != nil {
+		return fmt.Errorf("failed to index NATS error definitions: %w", err)
+	}
+
+	st.errorCatalog = errref.NewCatalog(docs)
+	s.logger.Info("NATS error definitions indexed", "count", st.errorCatalog.Count())
+
+	return nil
+}
+
+// loadServerErrors reads server/errors.json from the configured local file, or from
+// the configured GitHub repository when no path is set
+func (s *Server) loadServerErrors(ctx context.Context) ([]*errref.Entry, error) {
+	if path := s.config.ErrorsServerPath; path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			absPath = path
+		}
+		return errref.ParseServerErrors(content, "file://"+filepath.ToSlash(absPath))
+	}
+
+	repo, err := parseGitHubRepo(s.config, s.config.ErrorsServerRepository, s.config.ErrorsServerRef)
+	if err != nil {
+		return nil, err
+	}
+	file := strings.Trim(s.config.ErrorsServerFile, "/")
+
+	files, err := s.multiFetcher.FetchGitHubFiles(ctx, repo, func(path string) bool {
+		return path == file
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s not found in %s", file, s.config.ErrorsServerRepository)
+	}
+
+	return errref.ParseServerErrors([]byte(files[0].Content), githubBlobURL(repo)+file)
+}
+
+// loadClientErrors extracts client error variables from the configured local checkout,
+// or from the configured GitHub repository when no path is set
+func (s *Server) loadClientErrors(ctx context.Context) ([]*errref.Entry, error) {
+	var files []fetcher.GitHubFile
+	var baseURL string
+
+	if path := s.config.ErrorsClientPath; path != "" {
+		var err error
+		files, err = fetcher.ReadLocalFiles(ctx, path, errref.IsClientSourcePath)
+		if err != nil {
+			return nil, err
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			absPath = path
+		}
+		baseURL = "file://" + filepath.ToSlash(absPath) + "/"
+	} else {
+		repo, err := parseGitHubRepo(s.config, s.config.ErrorsClientRepository, s.config.ErrorsClientRef)
+		if err != nil {
+			return nil, err
+		}
+		files, err = s.multiFetcher.FetchGitHubFiles(ctx, repo, errref.IsClientSourcePath)
+		if err != nil {
+			return nil, err
+		}
+		baseURL = githubBlobURL(repo)
+	}
+
+	var entries []*errref.Entry
+	for _, file := range files {
+		fileEntries, err := errref.ParseClientErrors([]byte(file.Content), file.Path, baseURL+file.Path)
+		if err != nil {
+			s.logger.Warn("Failed to parse client source", "path", file.Path, "error", err)
+			continue
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no client error variables found in %d files", len(files))
+	}
+	return entries, nil
+}
+
+// handleLookupNATSErrorTool handles the lookup_nats_error tool invocation.
+// It resolves an error code, constant name or message to error definitions and
+// links related documentation pages.
+func (s *Server) handleLookupNATSErrorTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := request.RequireString("query")
+	if err != nil || strings.TrimSpace(query) == "" {
+		return mcp.NewToolResultError("query parameter is required and must be a non-empty string"), nil
+	}
+
+	st := s.docs()
+	if st.errorCatalog == nil || st.errorCatalog.Count() == 0 {
+		return mcp.NewToolResultError("NATS error definitions are not loaded; enable nats_errors in the configuration"), nil
+	}
+
+	entries := st.errorCatalog.Lookup(query)
+	if len(entries) == 0 {
+		s.logger.Info("NATS error lookup found no definition", "query", query)
+		return mcp.NewToolResultError(fmt.Sprintf("no NATS error definition found for: %s", query)), nil
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("Found %d error definition(s) for: %s\n\n", len(entries), query))
+	for _, entry := range entries {
+		content.WriteString(formatErrorEntry(entry, s.relatedDocs(st, entry)))
+		content.WriteString("\n")
+	}
+
+	s.logger.Info("NATS error lookup completed", "query", query, "results", len(entries))
+
+	return mcp.NewToolResultText(content.String()), nil
+}
+
+// relatedDocs searches the NATS documentation of st for pages related to an error
+func (s *Server) relatedDocs(st *docState, entry *errref.Entry) []index.SearchResult {
+	query := strings.TrimSpace(strings.TrimPrefix(entry.Message, "nats: "))
+	if entry.Comment != "" {
+		query += " " + entry.Comment
+	}
+
+	results, err := st.indexManager.GetNATSIndex().Search(query, maxRelatedDocs)
+	if err != nil {
+		s.logger.Debug("Related documentation search failed", "query", query, "error", err)
+		return nil
+	}
+	return results
+}
+
+// formatErrorEntry renders an error definition and its related documentation as markdown
+func formatErrorEntry(entry *errref.Entry, related []index.SearchResult) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("## %s (%s)\n\n", entry.QualifiedName(), entry.Kind))
+	b.WriteString(fmt.Sprintf("- Message: %s\n", entry.Message))
+	if entry.ErrorCode != 0 {
+		b.WriteString(fmt.Sprintf("- JetStream error code: %d\n", entry.ErrorCode))
+	}
+	if entry.Code != 0 {
+		b.WriteString(fmt.Sprintf("- Status code: %d\n", entry.Code))
+	}
+	if entry.SourceURL != "" {
+		b.WriteString(fmt.Sprintf("- Defined in: %s\n", entry.SourceURL))
+	}
+	if entry.Comment != "" {
+		b.WriteString(fmt.Sprintf("\n%s\n", entry.Comment))
+	}
+	if entry.Help != "" {
+		b.WriteString(fmt.Sprintf("\n%s\n", entry.Help))
+	}
+
+	if entry.DocURL != "" || len(related) > 0 {
+		b.WriteString("\nRelated documentation:\n")
+		if entry.DocURL != "" {
+			b.WriteString(fmt.Sprintf("- %s\n", entry.DocURL))
+		}
+		for _, result := range related {
+			b.WriteString(fmt.Sprintf("- %s: %s\n", result.Title, result.URL))
+		}
+	}
+
+	return b.String()
+}
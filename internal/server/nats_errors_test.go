@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const testErrorsJSON = `[
+  {"constant": "JSStreamNameExistErr", "code": 400, "error_code": 10058,
+   "description": "stream name already in use with a different configuration",
+   "comment": "", "help": "", "url": "", "deprecates": ""}
+]`
+
+const testClientErrors = `package nats
+
+import "errors"
+
+var ErrNoResponders = errors.New("nats: no responders available for request")
+`
+
+// natsErrorsTestOptions load error definitions from temporary files
+func natsErrorsTestOptions(t *testing.T) testServerOptions {
+	t.Helper()
+
+	dir := t.TempDir()
+	errorsFile := filepath.Join(dir, "errors.json")
+	if err := os.WriteFile(errorsFile, []byte(testErrorsJSON), 0644); err != nil {
+		t.Fatalf("failed to write errors.json: %v", err)
+	}
+	clientDir := filepath.Join(dir, "nats.go")
+	if err := os.MkdirAll(clientDir, 0755); err != nil {
+		t.Fatalf("failed to create client dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(clientDir, "nats.go"), []byte(testClientErrors), 0644); err != nil {
+		t.Fatalf("failed to write client source: %v", err)
+	}
+
+	return testServerOptions{
+		configure: func(cfg *config.Config) {
+			cfg.ErrorsEnabled = true
+			cfg.ErrorsServerPath = errorsFile
+			cfg.ErrorsClientPath = clientDir
+		},
+		// Related documentation is searched in the NATS index
+		nats: []*index.Document{{
+			ID:          "nats-concepts/core-nats/reqreply",
+			Title:       "Request-Reply",
+			URL:         "https://docs.nats.io/nats-concepts/core-nats/reqreply",
+			Content:     "If there are no responders available for a request the client receives a no responders error.",
+			LastUpdated: time.Now(),
+		}},
+		initializers: []testInitializer{(*Server).initializeNATSErrors},
+	}
+}
+
+func TestInitializeNATSErrorsFromLocalPaths(t *testing.T) {
+	srv := newTestServer(t, natsErrorsTestOptions(t))
+
+	if srv.state.errorCatalog.Count() != 2 {
+		t.Fatalf("expected 2 error definitions, got %d", srv.state.errorCatalog.Count())
+	}
+	if _, err := srv.state.indexManager.GetGitHubIndex().Get("nats-error/client/nats.ErrNoResponders"); err != nil {
+		t.Errorf("client error not indexed: %v", err)
+	}
+}
+
+func TestHandleLookupNATSErrorTool(t *testing.T) {
+	srv := newTestServer(t, natsErrorsTestOptions(t))
+
+	tests := []struct {
+		name      string
+		query     interface{}
+		wantError bool
+		contains  []string
+	}{
+		{
+			name:     "by error code",
+			query:    "10058",
+			contains: []string{"## JSStreamNameExistErr (server)", "JetStream error code: 10058", "Status code: 400"},
+		},
+		{
+			name:     "by constant",
+			query:    "ErrNoResponders",
+			contains: []string{"## nats.ErrNoResponders (client)"},
+		},
+		{
+			name:  "by logged message",
+			query: "nats: no responders available for request",
+			contains: []string{
+				"nats.ErrNoResponders",
+				"Related documentation:",
+				"Request-Reply: https://docs.nats.io/nats-concepts/core-nats/reqreply",
+			},
+		},
+		{name: "unknown", query: "something else entirely", wantError: true},
+		{name: "missing query", query: nil, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			if tt.query != nil {
+				request.Params.Arguments = map[string]interface{}{"query": tt.query}
+			}
+
+			result, err := srv.handleLookupNATSErrorTool(context.Background(), request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.IsError != tt.wantError {
+				t.Fatalf("expected IsError=%v, got %v: %s", tt.wantError, result.IsError, resultText(t, result))
+			}
+
+			text := resultText(t, result)
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in result, got:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleLookupNATSErrorToolNotLoaded(t *testing.T) {
+	srv := newTestServer(t, testServerOptions{})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"query": "10058"}
+	result, err := srv.handleLookupNATSErrorTool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected error result when error definitions are not loaded")
+	}
+}
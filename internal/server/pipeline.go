@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// Pipeline sizes. At most pipelineBuffer fetched items wait for the parse workers and
+// as many parsed documents wait for the indexer, so raw page content never piles up
+// beyond a few dozen pages however large the source is.
+const (
+	pipelineBuffer = 16 // Items buffered between two stages
+	indexBatchSize = 50 // Documents indexed at once
+)
+
+// parsedItem is a fetched page or file turned into a document
+type parsedItem struct {
+	doc       *index.Document
+	key       string            // Key of the item's validator in the cache
+	validator fetcher.Validator // How the item is revalidated on the next fetch
+	reused    bool              // Unchanged since the last fetch; the cached document was reused
+}
+
+// pipelineResult is what a pipeline indexed
+type pipelineResult struct {
+	docs       []*index.Document
+	validators map[string]fetcher.Validator
+	fetched    int   // Items received from the fetcher, including unchanged ones
+	reused     int   // Documents reused from the cache
+	fetchErr   error // Error returned by the fetch stage; may only report partial failures
+}
+
+// pipeline streams the pages or files of a documentation source from the fetcher,
+// through a pool of parse workers, into batches added to the index, so fetching,
+// parsing and indexing overlap and memory stays bounded. Every stage stops promptly
+// when the context is cancelled or indexing fails.
+type pipeline[T any] struct {
+	// fetch sends the items of the source to out and returns when done; it must stop
+	// sending when ctx is cancelled and must not close out
+	fetch func(ctx context.Context, out chan<- T) error
+	// parse turns an item into a document; false skips the item. It is called
+	// concurrently from several workers.
+	parse func(item T) (parsedItem, bool)
+	// index adds a batch of documents to the index
+	index func(docs []*index.Document) error
+}
+
+// run runs the pipeline to completion. The fetch stage's error is returned in the
+// result; the returned error reports a failed index batch or a cancelled context.
+func (p pipeline[T]) run(ctx context.Context) (pipelineResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := pipelineResult{validators: make(map[string]fetcher.Validator)}
+
+	// Fetch stage
+	fetched := make(chan T, pipelineBuffer)
+	fetchDone := make(chan struct{})
+	go func() {
+		defer close(fetchDone)
+		defer close(fetched)
+		result.fetchErr = p.fetch(ctx, fetched)
+	}()
+
+	// Parse stage
+	parsed := make(chan parsedItem, pipelineBuffer)
+	var received atomic.Int64
+	var workers sync.WaitGroup
+	for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for item := range fetched {
+				received.Add(1)
+				if ctx.Err() != nil {
+					continue
+				}
+				doc, ok := p.parse(item)
+				if !ok {
+					continue
+				}
+				select {
+				case parsed <- doc:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(parsed)
+	}()
+
+	// Index stage: batches are indexed as they fill up; after a failed batch the rest
+	// is drained without indexing
+	var indexErr error
+	batch := make([]*index.Document, 0, indexBatchSize)
+	flush := func() {
+		if len(batch) == 0 || indexErr != nil {
+			return
+		}
+		if err := p.index(batch); err != nil {
+			indexErr = err
+			cancel()
+		}
+		batch = make([]*index.Document, 0, indexBatchSize)
+	}
+	for item := range parsed {
+		if indexErr != nil {
+			continue
+		}
+		result.docs = append(result.docs, item.doc)
+		if item.key != "" {
+			result.validators[item.key] = item.validator
+		}
+		if item.reused {
+			result.reused++
+		}
+		batch = append(batch, item.doc)
+		if len(batch) == indexBatchSize {
+			flush()
+		}
+	}
+	flush()
+	<-fetchDone
+	result.fetched = int(received.Load())
+
+	if indexErr != nil {
+		return result, indexErr
+	}
+	return result, ctx.Err()
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// sendItems returns a fetch stage that sends n numbered items
+func sendItems(n int) func(ctx context.Context, out chan<- int) error {
+	return func(ctx context.Context, out chan<- int) error {
+		for i := 0; i < n; i++ {
+			select {
+			case out <- i:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+}
+
+func parseItem(i int) (parsedItem, bool) {
+	if i%10 == 9 {
+		return parsedItem{}, false
+	}
+	return parsedItem{
+		doc:       &index.Document{ID: fmt.Sprintf("doc-%d", i)},
+		key:       fmt.Sprintf("/page-%d", i),
+		validator: fetcher.Validator{ETag: fmt.Sprintf("%d", i)},
+		reused:    i%2 == 0,
+	}, true
+}
+
+func TestPipelineIndexesInBatches(t *testing.T) {
+	var batches []int
+	result, err := pipeline[int]{
+		fetch: sendItems(120),
+		parse: parseItem,
+		index: func(docs []*index.Document) error {
+			batches = append(batches, len(docs))
+			return nil
+		},
+	}.run(context.Background())
+	if err != nil || result.fetchErr != nil {
+		t.Fatalf("unexpected errors: %v, %v", err, result.fetchErr)
+	}
+
+	if result.fetched != 120 {
+		t.Errorf("expected 120 fetched items, got %d", result.fetched)
+	}
+	if len(result.docs) != 108 || len(result.validators) != 108 {
+		t.Errorf("expected 108 documents and validators, got %d and %d", len(result.docs), len(result.validators))
+	}
+	if result.reused != 60 {
+		t.Errorf("expected 60 reused documents, got %d", result.reused)
+	}
+	if len(batches) != 3 || batches[0] != indexBatchSize || batches[1] != indexBatchSize || batches[2] != 8 {
+		t.Errorf("expected batches of %d, %d and 8 documents, got %v", indexBatchSize, indexBatchSize, batches)
+	}
+}
+
+func TestPipelineReturnsFetchError(t *testing.T) {
+	fetchErr := errors.New("sitemap unavailable")
+	result, err := pipeline[int]{
+		fetch: func(ctx context.Context, out chan<- int) error {
+			if err := sendItems(3)(ctx, out); err != nil {
+				return err
+			}
+			return fetchErr
+		},
+		parse: parseItem,
+		index: func(docs []*index.Document) error { return nil },
+	}.run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected pipeline error: %v", err)
+	}
+	if !errors.Is(result.fetchErr, fetchErr) {
+		t.Errorf("expected the fetch error in the result, got %v", result.fetchErr)
+	}
+	if len(result.docs) != 3 {
+		t.Errorf("expected the 3 fetched documents, got %d", len(result.docs))
+	}
+}
+
+func TestPipelineStopsOnIndexError(t *testing.T) {
+	indexErr := errors.New("index full")
+	calls := 0
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		_, err = pipeline[int]{
+			// The fetch stage never ends on its own, so the pipeline only returns if
+			// the failed batch cancels it
+			fetch: func(ctx context.Context, out chan<- int) error {
+				for i := 0; ; i++ {
+					select {
+					case out <- i:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+			},
+			parse: parseItem,
+			index: func(docs []*index.Document) error {
+				calls++
+				return indexErr
+			},
+		}.run(context.Background())
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pipeline did not stop after a failed index batch")
+	}
+	if !errors.Is(err, indexErr) {
+		t.Errorf("expected the index error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected indexing to stop after the failed batch, got %d calls", calls)
+	}
+}
+
+func TestPipelineStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	var result pipelineResult
+	var err error
+	go func() {
+		defer close(done)
+		result, err = pipeline[int]{
+			fetch: func(ctx context.Context, out chan<- int) error {
+				for i := 0; ; i++ {
+					if i == 10 {
+						cancel()
+					}
+					select {
+					case out <- i:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+			},
+			parse: parseItem,
+			index: func(docs []*index.Document) error { return nil },
+		}.run(ctx)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pipeline did not stop after the context was cancelled")
+	}
+	if !errors.Is(err, context.Canceled) || !errors.Is(result.fetchErr, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v and %v", err, result.fetchErr)
+	}
+}
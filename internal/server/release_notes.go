@@ -0,0 +1,297 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/releases"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxReleaseNotesChars limits the notes shown per release when no keyword is given
+const maxReleaseNotesChars = 1500
+
+// initializeReleaseNotes loads release notes from a local mirror or the GitHub
+// releases of the configured repositories, indexes one document per release and
+// builds the release catalogue, all into st. force revalidates a fresh cache.
+func (s *Server) initializeReleaseNotes(ctx context.Context, st *docState, force bool) error {
+	source := "release-notes"
+
+	docs := s.loadCachedDocuments(source, force)
+	if docs == nil {
+		var list []*releases.Release
+		var err error
+		if path := s.config.ReleaseNotesPath; path != "" {
+			list, err = s.loadLocalReleaseNotes(ctx, path)
+		} else {
+			list, err = s.loadGitHubReleaseNotes(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load release notes: %w", err)
+		}
+
+		// A release may be both published on GitHub and listed in a changelog
+		seen := make(map[string]bool)
+		for _, release := range list {
+			doc := release.Document()
+			if seen[doc.ID] {
+				continue
+			}
+			seen[doc.ID] = true
+			docs = append(docs, doc)
+		}
+
+		if len(docs) == 0 {
+			return fmt.Errorf("failed to load any release notes")
+		}
+
+		// Save to cache (best-effort, log errors but don't fail)
+		if s.cache != nil {
+			if err := s.cache.Save(source, "", docs); err != nil {
+				s.logger.Warn("Failed to save cache", "source", source, "error", err)
+			}
+		}
+	}
+
+	// Release notes come from repositories, so they are searchable alongside GitHub docs
+	if err := st.indexManager.IndexGitHub(docs); err != nil {
+		return fmt.Errorf("failed to index release notes: %w", err)
+	}
+
+	st.releaseCatalog = releases.NewCatalog(docs)
+	s.logger.Info("Release notes indexed", "count", st.releaseCatalog.Count())
+
+	return nil
+}
+
+// loadGitHubReleaseNotes fetches the GitHub releases of every configured repository,
+// falling back to the repository's CHANGELOG files when it publishes no releases.
+// Repositories that fail are logged and skipped.
+func (s *Server) loadGitHubReleaseNotes(ctx context.Context) ([]*releases.Release, error) {
+	var result []*releases.Release
+	for _, spec := range s.config.ReleaseNotesRepositories {
+		repo, err := parseGitHubRepo(s.config, spec, s.config.ReleaseNotesRef)
+		if err != nil {
+			return nil, err
+		}
+
+		published, err := s.multiFetcher.FetchGitHubReleases(ctx, repo)
+		if err != nil {
+			s.logger.Warn("Failed to fetch GitHub releases", "repository", spec, "error", err)
+		}
+		for _, gh := range published {
+			release, err := releases.New(repo.Name, gh.TagName, gh.Name, gh.Body, gh.HTMLURL, gh.PublishedAt)
+			if err != nil {
+				s.logger.Debug("Skipping release without a version", "repository", spec, "tag", gh.TagName)
+				continue
+			}
+			result = append(result, release)
+		}
+		if len(published) > 0 {
+			continue
+		}
+
+		// Only changelogs at the repository root describe the repository itself
+		files, err := s.multiFetcher.FetchGitHubFiles(ctx, repo, func(p string) bool {
+			return !strings.Contains(p, "/") && releases.IsChangelogPath(p)
+		})
+		if err != nil {
+			s.logger.Warn("Failed to fetch changelog", "repository", spec, "error", err)
+			continue
+		}
+		for _, file := range files {
+			result = append(result, releases.ParseChangelog(file.Content, repo.Name, githubBlobURL(repo)+file.Path)...)
+		}
+	}
+	return result, nil
+}
+
+// loadLocalReleaseNotes reads a local mirror of release notes. The mirror may hold
+// GitHub releases API responses (<repo>.json), changelogs (CHANGELOG.md) and notes
+// named after a release (v2.11.0.md). Files in a subdirectory belong to the
+// repository named by that directory, other files to the mirror itself.
+func (s *Server) loadLocalReleaseNotes(ctx context.Context, root string) ([]*releases.Release, error) {
+	files, err := fetcher.ReadLocalFiles(ctx, root, func(p string) bool {
+		return strings.EqualFold(path.Ext(p), ".json") || releases.IsChangelogPath(p) || releases.IsReleaseNotePath(p)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	absPath, err := filepath.Abs(root)
+	if err != nil {
+		absPath = root
+	}
+	baseURL := "file://" + filepath.ToSlash(absPath) + "/"
+
+	var result []*releases.Release
+	for _, file := range files {
+		repo := file.Repo
+		if dir, _, found := strings.Cut(file.Path, "/"); found {
+			repo = dir
+		}
+		fileURL := baseURL + file.Path
+		base := path.Base(file.Path)
+
+		switch {
+		case strings.EqualFold(path.Ext(base), ".json"):
+			var published []fetcher.GitHubRelease
+			if err := json.Unmarshal(file.Content, &published); err != nil {
+				s.logger.Warn("Failed to parse releases file", "path", file.Path, "error", err)
+				continue
+			}
+			if !strings.Contains(file.Path, "/") {
+				repo = strings.TrimSuffix(base, path.Ext(base))
+			}
+			for _, gh := range published {
+				if gh.Draft || gh.Upcoming {
+					continue
+				}
+				url := gh.HTMLURL
+				if url == "" {
+					url = fileURL
+				}
+				if release, err := releases.New(repo, gh.TagName, gh.Name, gh.Body, url, gh.PublishedAt); err == nil {
+					result = append(result, release)
+				}
+			}
+		case releases.IsChangelogPath(file.Path):
+			result = append(result, releases.ParseChangelog(file.Content, repo, fileURL)...)
+		default:
+			tag := strings.TrimSuffix(base, path.Ext(base))
+			if release, err := releases.New(repo, tag, "", string(file.Content), fileURL, time.Time{}); err == nil {
+				result = append(result, release)
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no releases found in %d files under %s", len(files), root)
+	}
+	return result, nil
+}
+
+// handleReleaseNotesTool handles the nats_release_notes tool invocation.
+// It filters releases by repository, version range and keyword.
+func (s *Server) handleReleaseNotesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	catalog := s.docs().releaseCatalog
+	if catalog == nil || catalog.Count() == 0 {
+		return mcp.NewToolResultError("release notes are not loaded; enable release_notes in the configuration"), nil
+	}
+
+	keyword := strings.TrimSpace(request.GetString("keyword", ""))
+	from := strings.TrimSpace(request.GetString("from", ""))
+	to := strings.TrimSpace(request.GetString("to", ""))
+	repo := strings.TrimSpace(request.GetString("repo", ""))
+	limit := request.GetInt("limit", 10)
+	if limit <= 0 {
+		limit = 10
+	}
+
+	versions, err := releases.ParseRange(from, to)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid version range: %v", err)), nil
+	}
+
+	matches := catalog.Query(releases.Query{
+		Repo:               repo,
+		Range:              versions,
+		Keyword:            keyword,
+		IncludePrereleases: request.GetBool("include_prereleases", false),
+	})
+
+	description := describeReleaseQuery(repo, from, to, keyword)
+	if len(matches) == 0 {
+		s.logger.Info("Release notes query found no releases", "query", description)
+		return mcp.NewToolResultError(fmt.Sprintf("no releases found for: %s", description)), nil
+	}
+
+	// Keyword queries answer "which release added X", so the earliest releases are
+	// kept; otherwise the most recent releases in the range are more useful
+	total := len(matches)
+	if total > limit {
+		if keyword != "" {
+			matches = matches[:limit]
+		} else {
+			matches = matches[total-limit:]
+		}
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("Found %d release(s) for: %s\n", total, description))
+	if total > len(matches) {
+		content.WriteString(fmt.Sprintf("Showing %d; narrow the version range or raise the limit to see more.\n", len(matches)))
+	}
+	content.WriteString("\n")
+	for _, match := range matches {
+		content.WriteString(formatReleaseMatch(match))
+	}
+
+	s.logger.Info("Release notes query completed", "query", description, "results", total)
+
+	return mcp.NewToolResultText(content.String()), nil
+}
+
+// describeReleaseQuery summarizes release query filters for result headers
+func describeReleaseQuery(repo, from, to, keyword string) string {
+	var parts []string
+	if repo != "" {
+		parts = append(parts, "repo "+repo)
+	}
+	switch {
+	case from != "" && to != "":
+		parts = append(parts, fmt.Sprintf("versions %s to %s", from, to))
+	case from != "":
+		parts = append(parts, "versions from "+from)
+	case to != "":
+		parts = append(parts, "versions up to "+to)
+	}
+	if keyword != "" {
+		parts = append(parts, fmt.Sprintf("keyword %q", keyword))
+	}
+	if len(parts) == 0 {
+		return "all releases"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatReleaseMatch renders a release with its keyword highlights, or the start of
+// its notes when the query had no keyword
+func formatReleaseMatch(match *releases.Match) string {
+	release := match.Release
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("## %s %s", release.Repo, release.Tag))
+	if !release.Date.IsZero() {
+		content.WriteString(fmt.Sprintf(" (%s)", release.Date.Format("2006-01-02")))
+	}
+	content.WriteString("\n")
+	if release.Name != "" {
+		content.WriteString(fmt.Sprintf("Title: %s\n", release.Name))
+	}
+	content.WriteString(fmt.Sprintf("URL: %s\n", release.URL))
+	content.WriteString(fmt.Sprintf("Document: %s\n", match.Document.ID))
+
+	switch {
+	case len(match.Highlights) > 0:
+		content.WriteString("Matching notes:\n")
+		for _, line := range match.Highlights {
+			content.WriteString(fmt.Sprintf("  - %s\n", strings.TrimLeft(line, "-* ")))
+		}
+	case release.Notes != "":
+		notes := release.Notes
+		if len(notes) > maxReleaseNotesChars {
+			notes = notes[:maxReleaseNotesChars] + "...\n(truncated; use retrieve_nats_doc with the document ID for the full notes)"
+		}
+		content.WriteString("\n" + notes + "\n")
+	}
+	content.WriteString("\n")
+
+	return content.String()
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const testReleasesJSON = `[
+  {"tag_name": "v2.11.0", "name": "Release v2.11.0", "html_url": "https://github.com/nats-io/nats-server/releases/tag/v2.11.0",
+   "published_at": "2025-03-17T12:00:00Z", "body": "## Added\n\n- Consumer pausing via the pause_until setting\n- Message TTLs"},
+  {"tag_name": "v2.11.0-RC.1", "name": "Release v2.11.0-RC.1", "published_at": "2025-02-01T12:00:00Z",
+   "prerelease": true, "body": "- Consumer pausing preview"},
+  {"tag_name": "v2.10.0", "name": "Release v2.10.0", "published_at": "2023-09-19T12:00:00Z",
+   "body": "## Added\n\n- Subject transforms for streams"},
+  {"tag_name": "v2.12.0", "draft": true, "body": "- Unpublished"}
+]`
+
+const testClientChangelog = `# Changelog
+
+## [1.37.0] - 2024-10-01
+
+- Support pausing consumers
+
+## [1.36.0] - 2024-07-01
+
+- Ordered consumer improvements
+`
+
+// releaseNotesTestOptions load release notes from a local mirror
+func releaseNotesTestOptions(t *testing.T) testServerOptions {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "nats-server.json"), []byte(testReleasesJSON), 0644); err != nil {
+		t.Fatalf("failed to write releases file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "nats.go"), 0755); err != nil {
+		t.Fatalf("failed to create client dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "nats.go", "CHANGELOG.md"), []byte(testClientChangelog), 0644); err != nil {
+		t.Fatalf("failed to write changelog: %v", err)
+	}
+
+	return testServerOptions{
+		configure: func(cfg *config.Config) {
+			cfg.ReleaseNotesEnabled = true
+			cfg.ReleaseNotesPath = dir
+		},
+		initializers: []testInitializer{(*Server).initializeReleaseNotes},
+	}
+}
+
+func TestInitializeReleaseNotesFromLocalMirror(t *testing.T) {
+	srv := newTestServer(t, releaseNotesTestOptions(t))
+
+	// Drafts are skipped
+	if srv.state.releaseCatalog.Count() != 5 {
+		t.Fatalf("expected 5 releases, got %d", srv.state.releaseCatalog.Count())
+	}
+	doc, err := srv.state.indexManager.GetGitHubIndex().Get("release/nats-server/v2.11.0")
+	if err != nil {
+		t.Fatalf("release not indexed: %v", err)
+	}
+	if doc.URL != "https://github.com/nats-io/nats-server/releases/tag/v2.11.0" {
+		t.Errorf("unexpected URL: %s", doc.URL)
+	}
+	if _, err := srv.state.indexManager.GetGitHubIndex().Get("release/nats.go/v1.37.0"); err != nil {
+		t.Errorf("changelog release not indexed: %v", err)
+	}
+}
+
+func TestHandleReleaseNotesTool(t *testing.T) {
+	srv := newTestServer(t, releaseNotesTestOptions(t))
+
+	tests := []struct {
+		name        string
+		args        map[string]interface{}
+		wantError   bool
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "which release added a feature",
+			args: map[string]interface{}{"keyword": "consumer pausing", "repo": "nats-server"},
+			contains: []string{
+				"Found 1 release(s) for: repo nats-server, keyword \"consumer pausing\"",
+				"## nats-server v2.11.0 (2025-03-17)",
+				"  - Consumer pausing via the pause_until setting",
+			},
+			notContains: []string{"RC.1", "nats.go"},
+		},
+		{
+			name:     "keyword across repositories with pre-releases",
+			args:     map[string]interface{}{"keyword": "pause", "include_prereleases": true},
+			contains: []string{"Found 3 release(s)", "nats.go v1.37.0", "nats-server v2.11.0-RC.1", "nats-server v2.11.0"},
+		},
+		{
+			name:        "version range",
+			args:        map[string]interface{}{"from": "2.10", "to": "2.10", "repo": "nats-io/nats-server"},
+			contains:    []string{"Found 1 release(s)", "## nats-server v2.10.0", "Subject transforms for streams"},
+			notContains: []string{"v2.11.0"},
+		},
+		{
+			name:     "limit keeps the latest releases",
+			args:     map[string]interface{}{"limit": 1},
+			contains: []string{"Found 4 release(s) for: all releases", "Showing 1", "## nats-server v2.11.0"},
+		},
+		{name: "invalid range", args: map[string]interface{}{"from": "latest"}, wantError: true},
+		{name: "no match", args: map[string]interface{}{"keyword": "quantum"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = tt.args
+
+			result, err := srv.handleReleaseNotesTool(context.Background(), request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.IsError != tt.wantError {
+				t.Fatalf("expected IsError=%v, got %v: %s", tt.wantError, result.IsError, resultText(t, result))
+			}
+
+			text := resultText(t, result)
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in result, got:\n%s", want, text)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(text, unwanted) {
+					t.Errorf("did not expect %q in result, got:\n%s", unwanted, text)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleReleaseNotesToolNotLoaded(t *testing.T) {
+	srv := newTestServer(t, testServerOptions{})
+
+	result, err := srv.handleReleaseNotesTool(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected error result when release notes are not loaded")
+	}
+}
@@ -8,11 +8,14 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/adr"
 	"github.com/j4ng5y/nats-docs-mcp-server/internal/cache"
 	"github.com/j4ng5y/nats-docs-mcp-server/internal/classifier"
 	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/configref"
 	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
 	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
 	"github.com/j4ng5y/nats-docs-mcp-server/internal/parser"
@@ -41,15 +44,20 @@ func normalizePath(p string) string {
 // Supports dual documentation sources (NATS and Synadia) with classification-based routing.
 type Server struct {
 	config       *config.Config
-	indexManager *index.Manager           // Dual-index manager for NATS and Synadia
-	orchestrator *search.Orchestrator     // Search orchestrator for multi-source search
-	classifier   classifier.Classifier    // Query classifier for routing
+	classifier   classifier.Classifier // Query classifier for routing
 	logger       *slog.Logger
 	mcpServer    *server.MCPServer
 	multiFetcher *fetcher.MultiSourceFetcher // Fetcher for both NATS and Synadia
 	transport    TransportStarter
-	cache        *cache.Cache  // Cache for persisting documentation
+	cache        *cache.Cache // Cache for persisting documentation
 	initialized  bool
+
+	stateMu   sync.RWMutex
+	state     *docState  // Indices and catalogues tool calls are answered from
+	refreshMu sync.Mutex // Serializes RefreshCache calls
+
+	statusMu sync.Mutex
+	sources  map[string]sourceStatus // Outcome of the last fetch of each documentation site
 }
 
 // NewServer creates a new MCP server instance with the provided configuration and logger.
@@ -80,9 +88,6 @@ func NewServer(cfg *config.Config, logger *slog.Logger) (*Server, error) {
 		"1.0.0",
 	)
 
-	// Create index manager for multiple indices (NATS, Synadia, and GitHub)
-	indexManager := index.NewManager()
-
 	// Create classifier with configured keywords
 	queryClassifier := classifier.NewKeywordClassifier(
 		cfg.SynadiaKeywords,
@@ -90,23 +95,37 @@ func NewServer(cfg *config.Config, logger *slog.Logger) (*Server, error) {
 		cfg.GitHubKeywords,
 	)
 
-	// Create search orchestrator
-	searchOrchestrator := search.NewOrchestrator(
-		indexManager.GetNATSIndex(),
-		indexManager.GetSynadiaIndex(),
-		indexManager.GetGitHubIndex(),
-		queryClassifier,
-	)
-
 	// Create zerolog logger for fetcher (use os.Stderr for structured logging)
 	zerologLogger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
 
+	// Load the secrets of sources that require credentials
+	natsAuth, err := fetchAuth(cfg.DocsAuth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docs_auth: %w", err)
+	}
+	syadiaAuth, err := fetchAuth(cfg.SynadiaAuth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid synadia.auth: %w", err)
+	}
+
 	// Create multi-source fetcher for both NATS and Synadia
 	natsConfig := fetcher.FetchConfig{
-		BaseURL:       cfg.DocsBaseURL,
-		MaxRetries:    5,
-		FetchTimeout:  time.Duration(cfg.FetchTimeout) * time.Second,
-		MaxConcurrent: cfg.MaxConcurrent,
+		BaseURL:           cfg.DocsBaseURL,
+		MaxRetries:        5,
+		FetchTimeout:      time.Duration(cfg.FetchTimeout) * time.Second,
+		MaxConcurrent:     cfg.MaxConcurrent,
+		RequestsPerSecond: cfg.RequestsPerSecond,
+		MaxPerHost:        cfg.MaxPerHost,
+		IncludePaths:      cfg.DocsIncludePaths,
+		ExcludePaths:      cfg.DocsExcludePaths,
+		Discovery:         cfg.DocsDiscovery,
+		Crawl: fetcher.CrawlLimits{
+			MaxDepth: cfg.DocsCrawlMaxDepth,
+			MaxPages: cfg.DocsCrawlMaxPages,
+		},
+		MinSuccessRatio: cfg.DocsMinSuccessRatio,
+		Transport:       fetchTransportConfig(cfg.DocsNetwork),
+		Auth:            natsAuth,
 	}
 
 	syadiaConfig := fetcher.FetchConfig{
@@ -114,33 +133,50 @@ func NewServer(cfg *config.Config, logger *slog.Logger) (*Server, error) {
 		MaxRetries:    5,
 		FetchTimeout:  time.Duration(cfg.SynadiaFetchTimeout) * time.Second,
 		MaxConcurrent: cfg.MaxConcurrent,
+		IncludePaths:  cfg.SynadiaIncludePaths,
+		ExcludePaths:  cfg.SynadiaExcludePaths,
+		Discovery:     cfg.SynadiaDiscovery,
+		Crawl: fetcher.CrawlLimits{
+			MaxDepth: cfg.SynadiaCrawlMaxDepth,
+			MaxPages: cfg.SynadiaCrawlMaxPages,
+		},
+		MinSuccessRatio: cfg.SynadiaMinSuccessRatio,
+		Transport:       fetchTransportConfig(cfg.SynadiaNetwork),
+		Auth:            syadiaAuth,
 	}
 
 	// Create GitHub fetcher config (always, for cache refresh support)
 	// The GitHubEnabled flag controls initialization on startup, but we always prepare
 	// the config so cache refresh can pull GitHub sources regardless of enable flags.
 	// A token is optional - unauthenticated requests work but have rate limits.
-	repos := make([]fetcher.GitHubRepo, len(cfg.GitHubRepositories))
-	for i, repoStr := range cfg.GitHubRepositories {
-		parts := strings.Split(repoStr, "/")
-		if len(parts) == 2 {
-			repos[i] = fetcher.GitHubRepo{
-				Owner:     parts[0],
-				Name:      parts[1],
-				Branch:    cfg.GitHubBranch,
-				ShortName: parts[1],
-			}
-		}
-	}
 	githubConfig := fetcher.GitHubFetchConfig{
 		Token:         cfg.GitHubToken,
-		Repositories:  repos,
+		Repositories:  githubDocRepos(cfg),
 		MaxRetries:    5,
 		FetchTimeout:  time.Duration(cfg.GitHubFetchTimeout) * time.Second,
 		MaxConcurrent: cfg.MaxConcurrent,
+		Strategy:      cfg.GitHubFetchStrategy,
+		Transport:     fetchTransportConfig(cfg.GitHubNetwork),
+
+		MinSuccessRatio: cfg.GitHubMinSuccessRatio,
 	}
 
-	multiFetcher := fetcher.NewMultiSourceFetcher(natsConfig, syadiaConfig, githubConfig, zerologLogger)
+	// Record or replay the requests of every source, and fail fast on hosts that keep
+	// failing whichever source requests them
+	fetchOptions := []fetcher.MultiSourceOption{
+		fetcher.WithFixtures(fetcher.FixtureConfig{
+			Mode: cfg.HTTPFixturesMode,
+			Dir:  cfg.HTTPFixturesDir,
+		}),
+	}
+	if cfg.BreakerEnabled {
+		fetchOptions = append(fetchOptions, fetcher.WithBreaker(breakerConfig(cfg)))
+	}
+
+	multiFetcher, err := fetcher.NewMultiSourceFetcher(natsConfig, syadiaConfig, githubConfig, zerologLogger, fetchOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fetcher: %w", err)
+	}
 
 	// Create transport based on configuration
 	transport, err := NewTransport(cfg, logger)
@@ -158,8 +194,7 @@ func NewServer(cfg *config.Config, logger *slog.Logger) (*Server, error) {
 
 	return &Server{
 		config:       cfg,
-		indexManager: indexManager,
-		orchestrator: searchOrchestrator,
+		state:        newDocState(queryClassifier),
 		classifier:   queryClassifier,
 		logger:       logger,
 		mcpServer:    mcpServer,
@@ -185,14 +220,18 @@ func (s *Server) Initialize(ctx context.Context) error {
 
 	s.logger.Info("Starting server initialization")
 
+	// Cached documents are revalidated against the network when a refresh is configured
+	force := s.config.RefreshCache
+	st := newDocState(s.classifier)
+
 	// Initialize NATS documentation
-	if err := s.initializeNATS(ctx); err != nil {
+	if err := s.initializeNATS(ctx, st, force); err != nil {
 		return err
 	}
 
 	// Initialize Synadia documentation (if enabled)
 	if s.config.SynadiaEnabled {
-		if err := s.initializeSynadia(ctx); err != nil {
+		if err := s.initializeSynadia(ctx, st, force); err != nil {
 			s.logger.Warn("Failed to initialize Synadia docs", "error", err)
 			// Continue without Synadia (graceful degradation)
 		}
@@ -200,332 +239,360 @@ func (s *Server) Initialize(ctx context.Context) error {
 
 	// Initialize GitHub documentation (if enabled)
 	if s.config.GitHubEnabled {
-		if err := s.initializeGitHub(ctx); err != nil {
+		if err := s.initializeGitHub(ctx, st, force); err != nil {
 			s.logger.Warn("Failed to initialize GitHub docs", "error", err)
 			// Continue without GitHub (graceful degradation)
 		}
 	}
 
-	// Report index statistics
-	stats := s.indexManager.Stats()
-	s.logger.Info("Documentation indexing complete",
-		"nats_docs", stats.NATSDocCount,
-		"syncp_docs", stats.SynadiaDocCount,
-		"total_docs", stats.TotalDocCount)
-
-	s.initialized = true
-	return nil
-}
-
-// initializeNATS initializes NATS documentation, using cache if available and valid.
-func (s *Server) initializeNATS(ctx context.Context) error {
-	source := "nats"
-
-	// Check if we should use cache
-	if !s.config.RefreshCache && s.cache != nil {
-		maxAge := time.Duration(s.config.CacheMaxAge) * 24 * time.Hour
-		valid, err := s.cache.IsValid(source, maxAge)
-
-		if err != nil {
-			s.logger.Warn("Cache validation failed, will fetch from network",
-				"source", source, "error", err)
-		} else if valid {
-			// Load from cache
-			s.logger.Info("Loading NATS docs from cache", "source", source)
-			cached, err := s.cache.Load(source)
-			if err == nil && len(cached.Documents) > 0 {
-				// Import documents into index
-				if err := s.indexManager.GetNATSIndex().ImportDocuments(cached.Documents); err == nil {
-					s.logger.Info("Loaded NATS docs from cache",
-						"count", len(cached.Documents),
-						"cached_at", cached.CachedAt)
-					return nil
-				}
-				s.logger.Warn("Failed to import cached docs, will fetch", "error", err)
-			}
+	// Initialize JetStream API schemas (if enabled)
+	if s.config.JetStreamSchemasEnabled {
+		if err := s.initializeJetStreamAPI(ctx, st, force); err != nil {
+			s.logger.Warn("Failed to initialize JetStream API schemas", "error", err)
+			// Continue without the JetStream API lookup (graceful degradation)
 		}
 	}
 
-	// Cache miss or refresh requested - fetch from network
-	s.logger.Info("Fetching NATS documentation from network",
-		"base_url", s.config.DocsBaseURL)
-
-	natsPages, err := s.multiFetcher.FetchNATS(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to fetch NATS documentation: %w", err)
-	}
-
-	s.logger.Info("Fetched NATS documentation pages", "count", len(natsPages))
-
-	// Parse and index documents
-	natsIndexDocs := make([]*index.Document, 0)
-	for _, page := range natsPages {
-		doc, err := parser.ParseHTML(strings.NewReader(string(page.Content)))
-		if err != nil {
-			s.logger.Warn("Failed to parse NATS page", "path", page.Path, "error", err)
-			continue
-		}
-
-		indexDoc := &index.Document{
-			ID:          normalizePath(page.Path),
-			Title:       doc.Title,
-			URL:         s.config.DocsBaseURL + page.Path,
-			Content:     extractContent(doc),
-			Sections:    convertSections(doc.Sections),
-			LastUpdated: time.Now(),
+	// Initialize Go API reference (if enabled)
+	if s.config.GoAPIEnabled {
+		if err := s.initializeGoAPI(ctx, st, force); err != nil {
+			s.logger.Warn("Failed to initialize Go API reference", "error", err)
+			// Continue without the Go API reference (graceful degradation)
 		}
-		natsIndexDocs = append(natsIndexDocs, indexDoc)
 	}
 
-	if len(natsIndexDocs) == 0 {
-		return fmt.Errorf("failed to parse any NATS documentation pages")
+	// Initialize NATS error definitions (if enabled)
+	if s.config.ErrorsEnabled {
+		if err := s.initializeNATSErrors(ctx, st, force); err != nil {
+			s.logger.Warn("Failed to initialize NATS error definitions", "error", err)
+			// Continue without the error lookup (graceful degradation)
+		}
 	}
 
-	if err := s.indexManager.IndexNATS(natsIndexDocs); err != nil {
-		return fmt.Errorf("failed to index NATS documentation: %w", err)
+	// Initialize release notes (if enabled)
+	if s.config.ReleaseNotesEnabled {
+		if err := s.initializeReleaseNotes(ctx, st, force); err != nil {
+			s.logger.Warn("Failed to initialize release notes", "error", err)
+			// Continue without release notes (graceful degradation)
+		}
 	}
 
-	s.logger.Info("NATS documentation indexed", "count", len(natsIndexDocs))
+	// Build the code example catalogue from every indexed source
+	s.initializeExamples(st)
 
-	// Save to cache (best-effort, log errors but don't fail)
-	if s.cache != nil {
-		if err := s.cache.Save(source, s.config.DocsBaseURL, natsIndexDocs); err != nil {
-			s.logger.Warn("Failed to save cache", "source", source, "error", err)
-		} else {
-			s.logger.Info("Saved NATS docs to cache", "count", len(natsIndexDocs))
-		}
-	}
+	// Report index statistics
+	stats := st.indexManager.Stats()
+	s.logger.Info("Documentation indexing complete",
+		"nats_docs", stats.NATSDocCount,
+		"syncp_docs", stats.SynadiaDocCount,
+		"total_docs", stats.TotalDocCount)
 
+	s.swapDocs(st)
+	s.initialized = true
 	return nil
 }
 
-// initializeSynadia initializes Synadia documentation, using cache if available and valid.
-func (s *Server) initializeSynadia(ctx context.Context) error {
-	source := "syncp"
-
-	// Check if we should use cache
-	if !s.config.RefreshCache && s.cache != nil {
-		maxAge := time.Duration(s.config.CacheMaxAge) * 24 * time.Hour
-		valid, err := s.cache.IsValid(source, maxAge)
-
-		if err != nil {
-			s.logger.Warn("Cache validation failed, will fetch from network",
-				"source", source, "error", err)
-		} else if valid {
-			// Load from cache
-			s.logger.Info("Loading Synadia docs from cache", "source", source)
-			cached, err := s.cache.Load(source)
-			if err == nil && len(cached.Documents) > 0 {
-				// Import documents into index
-				if err := s.indexManager.GetSynadiaIndex().ImportDocuments(cached.Documents); err == nil {
-					s.logger.Info("Loaded Synadia docs from cache",
-						"count", len(cached.Documents),
-						"cached_at", cached.CachedAt)
-					return nil
-				}
-				s.logger.Warn("Failed to import cached docs, will fetch", "error", err)
+// initializeNATS indexes NATS documentation into st, using cache if available and valid
+// unless force is set.
+func (s *Server) initializeNATS(ctx context.Context, st *docState, force bool) error {
+	// Configuration pages are kept for the option catalogue
+	profile := s.config.DocsExtraction.ParserProfile(s.config.DocsBaseURL)
+	var configMu sync.Mutex
+	configPages := []fetcher.DocumentPage{}
+	_, err := initializeSource(ctx, s, force, docSource[fetcher.DocumentPage]{
+		name:      "NATS",
+		cacheKey:  "nats",
+		sourceURL: s.config.DocsBaseURL,
+		items:     "pages",
+		logAttrs:  []any{"base_url", s.config.DocsBaseURL, "extraction_profile", profile.Name},
+		docID:     normalizePath,
+		fetch: func(ctx context.Context, previous previousFetch, out chan<- fetcher.DocumentPage) error {
+			return s.multiFetcher.StreamNATSChanged(ctx, previous.revalidate(normalizePath), previous.retry(), out)
+		},
+		parse: func(previous previousFetch, page fetcher.DocumentPage) (parsedItem, bool) {
+			if configref.IsConfigPage(page.Path) {
+				configMu.Lock()
+				configPages = append(configPages, page)
+				configMu.Unlock()
 			}
-		}
+			return s.parsePage("NATS", s.config.DocsBaseURL, profile, previous, page)
+		},
+		cached: st.indexManager.GetNATSIndex(),
+		index:  st.indexManager.IndexNATS,
+		reset:  st.indexManager.ResetNATS,
+	})
+	if err != nil {
+		return err
 	}
 
-	// Cache miss or refresh requested - fetch from network
-	s.logger.Info("Fetching Synadia documentation from network",
-		"base_url", s.config.SynadiaBaseURL)
-
-	syadiaPages, err := s.multiFetcher.FetchSynadia(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to fetch Synadia documentation: %w", err)
+	// Configuration option catalogue (best-effort, reuses the fetched pages)
+	if err := s.initializeConfigOptions(ctx, st, force, configPages); err != nil {
+		s.logger.Warn("Failed to build configuration option catalogue", "error", err)
 	}
 
-	s.logger.Info("Fetched Synadia documentation pages", "count", len(syadiaPages))
+	return nil
+}
 
-	// Parse and index documents
-	syadiaIndexDocs := make([]*index.Document, 0)
-	for _, page := range syadiaPages {
-		doc, err := parser.ParseHTML(strings.NewReader(string(page.Content)))
-		if err != nil {
-			s.logger.Warn("Failed to parse Synadia page", "path", page.Path, "error", err)
-			continue
-		}
+// initializeSynadia indexes Synadia documentation into st, using cache if available and valid
+// unless force is set.
+func (s *Server) initializeSynadia(ctx context.Context, st *docState, force bool) error {
+	profile := s.config.SynadiaExtraction.ParserProfile(s.config.SynadiaBaseURL)
+	_, err := initializeSource(ctx, s, force, docSource[fetcher.DocumentPage]{
+		name:      "Synadia",
+		cacheKey:  "syncp",
+		sourceURL: s.config.SynadiaBaseURL,
+		items:     "pages",
+		logAttrs:  []any{"base_url", s.config.SynadiaBaseURL, "extraction_profile", profile.Name},
+		docID:     normalizePath,
+		fetch: func(ctx context.Context, previous previousFetch, out chan<- fetcher.DocumentPage) error {
+			return s.multiFetcher.StreamSynadiaChanged(ctx, previous.revalidate(normalizePath), previous.retry(), out)
+		},
+		parse: func(previous previousFetch, page fetcher.DocumentPage) (parsedItem, bool) {
+			return s.parsePage("Synadia", s.config.SynadiaBaseURL, profile, previous, page)
+		},
+		cached: st.indexManager.GetSynadiaIndex(),
+		index:  st.indexManager.IndexSynadia,
+		reset:  st.indexManager.ResetSynadia,
+	})
+	return err
+}
 
-		indexDoc := &index.Document{
-			ID:          normalizePath(page.Path),
-			Title:       doc.Title,
-			URL:         s.config.SynadiaBaseURL + page.Path,
-			Content:     extractContent(doc),
-			Sections:    convertSections(doc.Sections),
-			LastUpdated: time.Now(),
-		}
-		syadiaIndexDocs = append(syadiaIndexDocs, indexDoc)
+// parsePage turns a fetched documentation page into a document, indexing the content
+// selected by profile, or reuses the cached document when the page is unchanged.
+// source names the site in log messages.
+func (s *Server) parsePage(source, baseURL string, profile parser.Profile, previous previousFetch, page fetcher.DocumentPage) (parsedItem, bool) {
+	item := parsedItem{key: page.Path, validator: page.Validator}
+	if doc := previous.reuse(page.NotModified, normalizePath(page.Path)); doc != nil {
+		item.doc, item.reused = doc, true
+		return item, true
 	}
-
-	if len(syadiaIndexDocs) == 0 {
-		return fmt.Errorf("failed to parse any Synadia documentation pages")
+	if page.NotModified {
+		s.logger.Warn("Unchanged "+source+" page missing from cache", "path", page.Path)
+		return item, false
 	}
 
-	if err := s.indexManager.IndexSynadia(syadiaIndexDocs); err != nil {
-		return fmt.Errorf("failed to index Synadia documentation: %w", err)
+	doc, err := parser.ParseHTMLWithProfile(strings.NewReader(string(page.Content)), profile)
+	if err != nil {
+		s.logger.Warn("Failed to parse "+source+" page", "path", page.Path, "error", err)
+		return item, false
 	}
 
-	s.logger.Info("Synadia documentation indexed", "count", len(syadiaIndexDocs))
+	item.doc = &index.Document{
+		ID:          normalizePath(page.Path),
+		Title:       doc.Title,
+		URL:         baseURL + page.Path,
+		Content:     extractContent(doc),
+		Sections:    convertSections(doc.Sections),
+		LastUpdated: time.Now(),
+	}
+	return item, true
+}
 
-	// Save to cache (best-effort, log errors but don't fail)
-	if s.cache != nil {
-		if err := s.cache.Save(source, s.config.SynadiaBaseURL, syadiaIndexDocs); err != nil {
-			s.logger.Warn("Failed to save cache", "source", source, "error", err)
-		} else {
-			s.logger.Info("Saved Synadia docs to cache", "count", len(syadiaIndexDocs))
-		}
+// initializeGitHub indexes GitHub documentation into st, using cache if available and valid
+// unless force is set.
+func (s *Server) initializeGitHub(ctx context.Context, st *docState, force bool) error {
+	docs, err := initializeSource(ctx, s, force, docSource[fetcher.GitHubFile]{
+		name:      "GitHub",
+		cacheKey:  "github",
+		sourceURL: "github",
+		items:     "files",
+		docID:     s.githubFileDocID,
+		fetch: func(ctx context.Context, previous previousFetch, out chan<- fetcher.GitHubFile) error {
+			return s.multiFetcher.StreamGitHubChanged(ctx, previous.revalidate(s.githubFileDocID), out)
+		},
+		parse:  s.parseGitHubFile,
+		cached: st.indexManager.GetGitHubIndex(),
+		index:  st.indexManager.IndexGitHub,
+		reset:  st.indexManager.ResetGitHub,
+	})
+	if err != nil {
+		return err
 	}
 
+	st.adrCatalog = adr.NewCatalog(docs)
+	s.logger.Info("ADR catalogue built", "count", st.adrCatalog.Count())
 	return nil
 }
 
-// initializeGitHub initializes GitHub documentation, using cache if available and valid.
-func (s *Server) initializeGitHub(ctx context.Context) error {
-	source := "github"
-
-	// Check if we should use cache
-	if !s.config.RefreshCache && s.cache != nil {
-		maxAge := time.Duration(s.config.CacheMaxAge) * 24 * time.Hour
-		valid, err := s.cache.IsValid(source, maxAge)
-
-		if err != nil {
-			s.logger.Warn("Cache validation failed, will fetch from network",
-				"source", source, "error", err)
-		} else if valid {
-			// Load from cache
-			s.logger.Info("Loading GitHub docs from cache", "source", source)
-			cached, err := s.cache.Load(source)
-			if err == nil && len(cached.Documents) > 0 {
-				// Import documents into index
-				if err := s.indexManager.GetGitHubIndex().ImportDocuments(cached.Documents); err == nil {
-					s.logger.Info("Loaded GitHub docs from cache",
-						"count", len(cached.Documents),
-						"cached_at", cached.CachedAt)
-					return nil
-				}
-				s.logger.Warn("Failed to import cached docs, will fetch", "error", err)
-			}
-		}
+// parseGitHubFile turns a fetched GitHub file into a versioned document, reusing the
+// cached document when the file is unchanged
+func (s *Server) parseGitHubFile(previous previousFetch, file fetcher.GitHubFile) (parsedItem, bool) {
+	defaultVersion := s.defaultVersion(file.Repo)
+	version := file.Ref
+	if version == "" {
+		version = defaultVersion
 	}
+	docID := versionedDocID(file.Repo+"/"+file.Path, version, defaultVersion)
 
-	// Cache miss or refresh requested - fetch from network
-	s.logger.Info("Fetching GitHub documentation from network")
+	item := parsedItem{key: file.Key(), validator: fetcher.Validator{SHA: file.SHA}}
+	if doc := previous.reuse(file.NotModified, docID); doc != nil {
+		item.doc, item.reused = doc, true
+		return item, true
+	}
+	if file.NotModified {
+		s.logger.Warn("Unchanged GitHub file missing from cache", "repo", file.Repo, "path", file.Path)
+		return item, false
+	}
 
-	githubFiles, err := s.multiFetcher.FetchGitHub(ctx)
+	doc, err := parser.ParseFile(file.Content, file.Path)
 	if err != nil {
-		return fmt.Errorf("failed to fetch GitHub documentation: %w", err)
+		s.logger.Warn("Failed to parse GitHub documentation file", "path", file.Path, "error", err)
+		return item, false
 	}
 
-	s.logger.Info("Fetched GitHub documentation files", "count", len(githubFiles))
-
-	// Parse and index documents
-	githubIndexDocs := make([]*index.Document, 0)
-	for _, file := range githubFiles {
-		doc, err := parser.ParseMarkdown(file.Content, file.Path)
-		if err != nil {
-			s.logger.Warn("Failed to parse GitHub markdown file", "path", file.Path, "error", err)
-			continue
-		}
+	indexDoc := &index.Document{
+		ID:          docID,
+		Title:       doc.Title,
+		URL:         s.githubFileURL(file, version),
+		Content:     extractContent(doc),
+		Sections:    convertSections(doc.Sections),
+		LastUpdated: time.Now(),
+	}
+	// ADRs are only tracked on the default branch
+	if version == defaultVersion && adr.IsADRPath(file.Path) {
+		applyADRMetadata(indexDoc, file.Content, file.Path)
+	}
+	if indexDoc.Metadata == nil {
+		indexDoc.Metadata = make(map[string]string)
+	}
+	indexDoc.Metadata[index.MetaVersion] = version
+	if repo, ok := s.githubRepository(file.Repo); ok && repo.DisplayName != "" {
+		indexDoc.Metadata[index.MetaRepository] = repo.DisplayName
+	}
+	item.doc = indexDoc
+	return item, true
+}
 
-		// Build GitHub URL
-		parts := strings.Split(file.Repo, "/")
-		var owner, name string
-		if len(parts) == 2 {
-			owner = parts[0]
-			name = parts[1]
-		} else {
-			// Try to find the repo in the configured repositories
-			for _, repoStr := range s.config.GitHubRepositories {
-				repoParts := strings.Split(repoStr, "/")
-				if len(repoParts) == 2 && repoParts[1] == file.Repo {
-					owner = repoParts[0]
-					name = repoParts[1]
-					break
-				}
-			}
+// githubFileDocID returns the ID of the document parsed from the GitHub file with the
+// given key (see fetcher.GitHubFile.Key). Refs may contain slashes, so the longest
+// configured repository and ref matching the key wins. The key of a whole repository
+// (see fetcher.GitHubRepo.Key) yields the ID prefix of its documents.
+func (s *Server) githubFileDocID(key string) string {
+	docID, matched := key, ""
+	for _, repo := range githubDocRepos(s.config) {
+		if key == repo.Key() {
+			return versionedDocID(repo.ShortName+"/", repo.Branch, s.defaultVersion(repo.ShortName))
 		}
-
-		githubURL := fmt.Sprintf("https://github.com/%s/%s/blob/%s/%s",
-			owner, name, s.config.GitHubBranch, file.Path)
-
-		indexDoc := &index.Document{
-			ID:          file.Repo + "/" + file.Path,
-			Title:       doc.Title,
-			URL:         githubURL,
-			Content:     extractContent(doc),
-			Sections:    convertSections(doc.Sections),
-			LastUpdated: time.Now(),
+		prefix := repo.ShortName + "@" + repo.Branch + "/"
+		if path, ok := strings.CutPrefix(key, prefix); ok && len(prefix) > len(matched) {
+			docID = versionedDocID(repo.ShortName+"/"+path, repo.Branch, s.defaultVersion(repo.ShortName))
+			matched = prefix
 		}
-		githubIndexDocs = append(githubIndexDocs, indexDoc)
 	}
+	return docID
+}
 
-	if len(githubIndexDocs) == 0 {
-		return fmt.Errorf("failed to parse any GitHub documentation files")
+// loadCachedDocuments returns the cached documents for a source if the cache is
+// enabled, not being revalidated (force), and still valid. It returns nil otherwise.
+func (s *Server) loadCachedDocuments(source string, force bool) []*index.Document {
+	if force || s.cache == nil {
+		return nil
 	}
 
-	if err := s.indexManager.IndexGitHub(githubIndexDocs); err != nil {
-		return fmt.Errorf("failed to index GitHub documentation: %w", err)
+	maxAge := time.Duration(s.config.CacheMaxAge) * 24 * time.Hour
+	valid, err := s.cache.IsValid(source, maxAge)
+	if err != nil {
+		s.logger.Warn("Cache validation failed, will fetch",
+			"source", source, "error", err)
+		return nil
+	}
+	if !valid {
+		return nil
 	}
 
-	s.logger.Info("GitHub documentation indexed", "count", len(githubIndexDocs))
-
-	// Save to cache (best-effort, log errors but don't fail)
-	if s.cache != nil {
-		if err := s.cache.Save(source, "github", githubIndexDocs); err != nil {
-			s.logger.Warn("Failed to save cache", "source", source, "error", err)
-		} else {
-			s.logger.Info("Saved GitHub docs to cache", "count", len(githubIndexDocs))
-		}
+	cached, err := s.cache.Load(source)
+	if err != nil || len(cached.Documents) == 0 {
+		return nil
 	}
 
-	return nil
+	s.logger.Info("Loaded documents from cache",
+		"source", source,
+		"count", len(cached.Documents),
+		"cached_at", cached.CachedAt)
+	return cached.Documents
 }
 
-// RefreshCache performs a cache refresh by clearing the cache and re-fetching documentation.
+// RefreshCache performs a cache refresh by re-fetching documentation.
+// Pages and files recorded in the cache are revalidated, so only changed ones are
+// downloaded and re-parsed.
 // Unlike Initialize, this always attempts to refresh all available sources regardless of enable flags,
 // since the user is explicitly requesting a cache refresh.
+// The refreshed documentation is indexed separately and replaces the served
+// documentation only once every source is done, so searches are answered from the
+// previous documentation meanwhile, and keep it if the refresh fails.
 func (s *Server) RefreshCache(ctx context.Context) (int, error) {
 	if s.cache == nil {
 		return 0, fmt.Errorf("cache is not configured")
 	}
 
-	s.logger.Info("Manual cache refresh requested - pulling all documentation")
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
 
-	// Clear existing caches
-	if err := s.cache.ClearAll(); err != nil {
-		return 0, fmt.Errorf("failed to clear cache: %w", err)
-	}
+	s.logger.Info("Manual cache refresh requested - pulling all documentation")
 
-	// Reset indices
-	s.indexManager.Reset()
+	// Cached documents are revalidated rather than used as-is
+	const force = true
+	st := newDocState(s.classifier)
 
 	// Re-initialize NATS (always)
-	if err := s.initializeNATS(ctx); err != nil {
+	if err := s.initializeNATS(ctx, st, force); err != nil {
 		return 0, fmt.Errorf("failed to refresh NATS cache: %w", err)
 	}
 
-	docsRefreshed := s.indexManager.GetNATSIndex().Count()
+	docsRefreshed := st.indexManager.GetNATSIndex().Count()
 
 	// Re-initialize Synadia (always attempt, regardless of enable flag)
 	// This ensures all documentation is available when explicitly refreshing
-	if err := s.initializeSynadia(ctx); err != nil {
+	if err := s.initializeSynadia(ctx, st, force); err != nil {
 		s.logger.Warn("Failed to refresh Synadia cache during refresh operation", "error", err)
 		// Don't fail the entire refresh, continue with other sources
 	} else {
-		docsRefreshed += s.indexManager.GetSynadiaIndex().Count()
+		docsRefreshed += st.indexManager.GetSynadiaIndex().Count()
 	}
 
 	// Re-initialize GitHub (always attempt, regardless of enable flag)
 	// This ensures all documentation is available when explicitly refreshing
-	if err := s.initializeGitHub(ctx); err != nil {
+	if err := s.initializeGitHub(ctx, st, force); err != nil {
 		s.logger.Warn("Failed to refresh GitHub cache during refresh operation", "error", err)
 		// Don't fail the entire refresh, continue with other sources
 	} else {
-		docsRefreshed += s.indexManager.GetGitHubIndex().Count()
+		docsRefreshed += st.indexManager.GetGitHubIndex().Count()
 	}
 
+	// Reference sources are opt-in, so they are only refreshed when enabled
+	if s.config.JetStreamSchemasEnabled {
+		if err := s.initializeJetStreamAPI(ctx, st, force); err != nil {
+			s.logger.Warn("Failed to refresh JetStream API schemas during refresh operation", "error", err)
+		} else {
+			docsRefreshed += st.jsAPICatalog.Count()
+		}
+	}
+	if s.config.GoAPIEnabled {
+		before := st.indexManager.GetGitHubIndex().Count()
+		if err := s.initializeGoAPI(ctx, st, force); err != nil {
+			s.logger.Warn("Failed to refresh Go API reference during refresh operation", "error", err)
+		} else {
+			docsRefreshed += st.indexManager.GetGitHubIndex().Count() - before
+		}
+	}
+	if s.config.ErrorsEnabled {
+		if err := s.initializeNATSErrors(ctx, st, force); err != nil {
+			s.logger.Warn("Failed to refresh NATS error definitions during refresh operation", "error", err)
+		} else {
+			docsRefreshed += st.errorCatalog.Count()
+		}
+	}
+	if s.config.ReleaseNotesEnabled {
+		if err := s.initializeReleaseNotes(ctx, st, force); err != nil {
+			s.logger.Warn("Failed to refresh release notes during refresh operation", "error", err)
+		} else {
+			docsRefreshed += st.releaseCatalog.Count()
+		}
+	}
+
+	s.initializeExamples(st)
+	s.swapDocs(st)
+
 	s.logger.Info("Cache refresh complete", "docs_refreshed", docsRefreshed)
 	return docsRefreshed, nil
 }
@@ -552,6 +619,15 @@ func (s *Server) RegisterTools() error {
 		mcp.WithNumber("limit",
 			mcp.Description("Maximum number of results (default: 10)"),
 		),
+		mcp.WithString("adr_status",
+			mcp.Description("Only return Architecture Decision Records with this status (e.g., 'Approved', 'Implemented', 'Deprecated')"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Only return GitHub documentation indexed from this branch or tag (e.g., 'v2.10.0'); by default only the default branch is searched"),
+		),
+		mcp.WithString("has_example",
+			mcp.Description("Only return pages with a code example in this language as accepted by search_nats_examples: go, js, python, rust, java or cli"),
+		),
 	)
 
 	s.mcpServer.AddTool(searchTool, s.handleSearchTool)
@@ -564,10 +640,52 @@ func (s *Server) RegisterTools() error {
 			mcp.Required(),
 			mcp.Description("Document ID or URL path (e.g., 'nats-concepts/overview')"),
 		),
+		mcp.WithString("version",
+			mcp.Description("Branch or tag of a GitHub document to retrieve (e.g., 'v2.10.0'); defaults to the default branch"),
+		),
 	)
 
 	s.mcpServer.AddTool(retrieveTool, s.handleRetrieveTool)
 
+	// Register search_nats_examples tool
+	examplesTool := mcp.NewTool(
+		"search_nats_examples",
+		mcp.WithDescription("Search only the code examples of the NATS, Synadia and GitHub documentation, including Go example_test.go files. Each hit returns the code, the explanation around it and its source URL."),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("What the example should show (e.g., 'pull consumer fetch')"),
+		),
+		mcp.WithString("language",
+			mcp.Description("Only return examples in this language: go, js, python, rust, java or cli (shell sessions)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of examples (default: 5)"),
+		),
+	)
+
+	s.mcpServer.AddTool(examplesTool, s.handleSearchExamplesTool)
+
+	// Register compare_doc_versions tool (only when additional versions are indexed)
+	if s.config.GitHubEnabled && hasGitHubVersions(s.config) {
+		compareTool := mcp.NewTool(
+			"compare_doc_versions",
+			mcp.WithDescription("Compare the same GitHub documentation page across two indexed versions (branches or tags). Returns a unified diff of the page content."),
+			mcp.WithString("doc_id",
+				mcp.Required(),
+				mcp.Description("Document ID of the page (e.g., 'nats.docs/nats-concepts/jetstream/streams.md')"),
+			),
+			mcp.WithString("from_version",
+				mcp.Required(),
+				mcp.Description("Version to compare from (e.g., 'v2.10.0')"),
+			),
+			mcp.WithString("to_version",
+				mcp.Description("Version to compare to (default: the default branch)"),
+			),
+		)
+
+		s.mcpServer.AddTool(compareTool, s.handleCompareDocVersionsTool)
+	}
+
 	// Register refresh_docs_cache tool
 	refreshTool := mcp.NewTool(
 		"refresh_docs_cache",
@@ -576,6 +694,96 @@ func (s *Server) RegisterTools() error {
 
 	s.mcpServer.AddTool(refreshTool, s.handleRefreshCacheTool)
 
+	// Register get_server_status tool
+	statusTool := mcp.NewTool(
+		"get_server_status",
+		mcp.WithDescription("Report the server status: indexed document counts per source, the outcome of the last fetch of each documentation site including the pages that failed, and the circuit breaker state of every documentation host. Use this to find out why a source or page is missing or stale."),
+	)
+
+	s.mcpServer.AddTool(statusTool, s.handleServerStatusTool)
+
+	// Register lookup_nats_config_option tool
+	configOptionTool := mcp.NewTool(
+		"lookup_nats_config_option",
+		mcp.WithDescription("Look up nats-server configuration options by name or block path. Returns the option's block, type, default value, description and source page. Exact matches are preferred; otherwise options starting with the given name are listed."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Option name or dotted block path (e.g., 'max_payload', 'jetstream.store_dir' or 'cluster.' to list a block)"),
+		),
+	)
+
+	s.mcpServer.AddTool(configOptionTool, s.handleLookupConfigOptionTool)
+
+	// Register get_nats_adr tool (only when GitHub docs are enabled)
+	if s.config.GitHubEnabled {
+		adrTool := mcp.NewTool(
+			"get_nats_adr",
+			mcp.WithDescription("Get a NATS Architecture Decision Record (ADR) by number. Returns its title, status, authors, tags, revision history and full content. Requires nats-io/nats-architecture-and-design in the GitHub repositories."),
+			mcp.WithString("number",
+				mcp.Required(),
+				mcp.Description("ADR number (e.g., '8' or 'ADR-8')"),
+			),
+		)
+
+		s.mcpServer.AddTool(adrTool, s.handleGetADRTool)
+	}
+
+	// Register lookup_jetstream_api tool (only when JetStream API schemas are enabled)
+	if s.config.JetStreamSchemasEnabled {
+		jsAPITool := mcp.NewTool(
+			"lookup_jetstream_api",
+			mcp.WithDescription("Look up the JSON Schema of a JetStream API request or response by $JS.API subject or type name. Returns every property with its type, default, allowed values and description."),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("API subject (e.g., '$JS.API.CONSUMER.CREATE.ORDERS') or schema type name (e.g., 'consumer_create_request' or 'io.nats.jetstream.api.v1.stream_info_response')"),
+			),
+		)
+
+		s.mcpServer.AddTool(jsAPITool, s.handleLookupJetStreamAPITool)
+	}
+
+	// Register lookup_nats_error tool (only when NATS error definitions are enabled)
+	if s.config.ErrorsEnabled {
+		errorTool := mcp.NewTool(
+			"lookup_nats_error",
+			mcp.WithDescription("Explain a NATS error. Matches nats-server JetStream error definitions and client library error variables by error code, constant name or (fuzzy) message text, and links related documentation pages."),
+			mcp.WithString("query",
+				mcp.Required(),
+				mcp.Description("JetStream API error code (e.g., '10058'), constant name (e.g., 'JSStreamNotFoundErr' or 'ErrNoResponders') or error message (e.g., 'nats: no responders available for request')"),
+			),
+		)
+
+		s.mcpServer.AddTool(errorTool, s.handleLookupNATSErrorTool)
+	}
+
+	// Register nats_release_notes tool (only when release notes are enabled)
+	if s.config.ReleaseNotesEnabled {
+		releaseNotesTool := mcp.NewTool(
+			"nats_release_notes",
+			mcp.WithDescription("Query NATS release notes by version range and keyword, e.g. to find which release added a feature or what changed between two versions. Releases are listed oldest first; keyword queries return the matching note lines of each release."),
+			mcp.WithString("keyword",
+				mcp.Description("Words that must all appear in the release notes (e.g., 'consumer pause')"),
+			),
+			mcp.WithString("from",
+				mcp.Description("Lowest version to include (e.g., '2.10' or 'v2.10.4')"),
+			),
+			mcp.WithString("to",
+				mcp.Description("Highest version to include; partial versions include all their patches (e.g., '2.11' includes 2.11.7)"),
+			),
+			mcp.WithString("repo",
+				mcp.Description("Repository to restrict to (e.g., 'nats-server' or 'nats-io/nats.go')"),
+			),
+			mcp.WithBoolean("include_prereleases",
+				mcp.Description("Include release candidates and other pre-releases (default: false)"),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description("Maximum number of releases (default: 10)"),
+			),
+		)
+
+		s.mcpServer.AddTool(releaseNotesTool, s.handleReleaseNotesTool)
+	}
+
 	s.logger.Info("MCP tools registered successfully")
 	return nil
 }
@@ -644,6 +852,20 @@ func convertSections(sections []parser.Section) []index.Section {
 			Content: s.Content,
 			Level:   s.Level,
 		}
+		for _, block := range s.CodeBlocks {
+			result[i].CodeBlocks = append(result[i].CodeBlocks, index.CodeBlock{
+				Language: block.Language,
+				Content:  block.Content,
+				Caption:  block.Caption,
+			})
+		}
+		for _, table := range s.Tables {
+			result[i].Tables = append(result[i].Tables, index.Table{
+				Caption: table.Caption,
+				Header:  table.Header,
+				Rows:    table.Rows,
+			})
+		}
 	}
 	return result
 }
@@ -660,8 +882,32 @@ func (s *Server) handleSearchTool(ctx context.Context, request mcp.CallToolReque
 	// Extract limit parameter (optional, default to 10)
 	limit := request.GetInt("limit", 10)
 
+	st := s.docs()
+
+	// Version and ADR status filters only apply to GitHub documentation, so they
+	// bypass classification and search the GitHub index directly
+	version := strings.TrimSpace(request.GetString("version", ""))
+	status := strings.TrimSpace(request.GetString("adr_status", ""))
+	hasExample, err := exampleFilter(strings.TrimSpace(request.GetString("has_example", "")))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	filters := []index.SearchFilter{s.versionFilter(version), hasExample}
+	if status != "" {
+		if st.adrCatalog == nil || st.adrCatalog.Count() == 0 {
+			return mcp.NewToolResultError("no architecture decision records are loaded"), nil
+		}
+		filters = append(filters, adrStatusFilter(st.adrCatalog, status))
+	}
+	filter := allFilters(filters...)
+
 	// Perform multi-source search using orchestrator
-	results, err := s.orchestrator.Search(query, limit)
+	var results []search.SearchResult
+	if version != "" || status != "" {
+		results, err = st.orchestrator.SearchSourceFiltered(query, classifier.SourceGitHub, limit, filter)
+	} else {
+		results, err = st.orchestrator.SearchFiltered(query, limit, filter)
+	}
 	if err != nil {
 		s.logger.Error("Search failed", "query", query, "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("search failed: %v", err)), nil
@@ -672,10 +918,21 @@ func (s *Server) handleSearchTool(ctx context.Context, request mcp.CallToolReque
 	content.WriteString(fmt.Sprintf("Found %d results for query: %s\n\n", len(results), query))
 
 	for i, result := range results {
-		content.WriteString(fmt.Sprintf("%d. %s [%s]\n", i+1, result.Title, result.Source))
+		content.WriteString(fmt.Sprintf("%d. %s [%s]\n", i+1, result.Title, st.resultSource(result)))
 		content.WriteString(fmt.Sprintf("   URL: %s\n", result.URL))
 		content.WriteString(fmt.Sprintf("   Relevance: %.2f\n", result.Score))
-		content.WriteString(fmt.Sprintf("   Summary: %s\n\n", result.Snippet))
+		content.WriteString(fmt.Sprintf("   Summary: %s\n", result.Snippet))
+		if doc := st.resultDocument(result); doc != nil {
+			rows := doc.FindTableRows(query)
+			for j, row := range rows {
+				if j == maxTableRows {
+					content.WriteString(fmt.Sprintf("   ... %d more matching table rows\n", len(rows)-maxTableRows))
+					break
+				}
+				content.WriteString(fmt.Sprintf("   Table row (%s): %s\n", row.Heading, formatTableRow(row)))
+			}
+		}
+		content.WriteString("\n")
 	}
 
 	s.logger.Info("Search completed", "query", query, "results", len(results))
@@ -694,15 +951,27 @@ func (s *Server) handleRetrieveTool(ctx context.Context, request mcp.CallToolReq
 
 	// Normalize the document ID to handle leading/trailing slashes
 	normalizedID := normalizePath(docID)
+	st := s.docs()
+
+	// Versioned documents are only held in the GitHub index
+	if version := strings.TrimSpace(request.GetString("version", "")); version != "" {
+		doc, err := s.getVersionedDocument(st, normalizedID, version)
+		if err != nil {
+			s.logger.Warn("Versioned document not found", "doc_id", docID, "version", version)
+			return mcp.NewToolResultError(fmt.Sprintf("document not found: %s (version %s)", docID, version)), nil
+		}
+		s.logger.Info("Document retrieved", "doc_id", docID, "version", version, "title", doc.Title)
+		return mcp.NewToolResultText(formatDocument(doc)), nil
+	}
 
 	// Try to retrieve from NATS index first
-	doc, err := s.indexManager.GetNATSIndex().Get(normalizedID)
+	doc, err := st.indexManager.GetNATSIndex().Get(normalizedID)
 	if err != nil {
 		// Try Synadia index if NATS fails
-		doc, err = s.indexManager.GetSynadiaIndex().Get(normalizedID)
+		doc, err = st.indexManager.GetSynadiaIndex().Get(normalizedID)
 		if err != nil {
 			// Try GitHub index if Synadia fails
-			doc, err = s.indexManager.GetGitHubIndex().Get(normalizedID)
+			doc, err = st.indexManager.GetGitHubIndex().Get(normalizedID)
 			if err != nil {
 				s.logger.Warn("Document not found in any index", "doc_id", docID, "normalized_id", normalizedID)
 				return mcp.NewToolResultError(fmt.Sprintf("document not found: %s", docID)), nil
@@ -710,10 +979,20 @@ func (s *Server) handleRetrieveTool(ctx context.Context, request mcp.CallToolReq
 		}
 	}
 
-	// Format document content
+	s.logger.Info("Document retrieved", "doc_id", docID, "title", doc.Title)
+
+	return mcp.NewToolResultText(formatDocument(doc)), nil
+}
+
+// formatDocument renders a document as markdown with its title, URL and sections
+func formatDocument(doc *index.Document) string {
 	var content strings.Builder
 	content.WriteString(fmt.Sprintf("# %s\n\n", doc.Title))
-	content.WriteString(fmt.Sprintf("URL: %s\n\n", doc.URL))
+	content.WriteString(fmt.Sprintf("URL: %s\n", doc.URL))
+	if repository := doc.Metadata[index.MetaRepository]; repository != "" {
+		content.WriteString(fmt.Sprintf("Repository: %s\n", repository))
+	}
+	content.WriteString("\n")
 
 	// Add sections
 	for _, section := range doc.Sections {
@@ -721,9 +1000,210 @@ func (s *Server) handleRetrieveTool(ctx context.Context, request mcp.CallToolReq
 		content.WriteString(fmt.Sprintf("%s\n\n", section.Content))
 	}
 
-	s.logger.Info("Document retrieved", "doc_id", docID, "title", doc.Title)
+	return content.String()
+}
 
-	return mcp.NewToolResultText(content.String()), nil
+// parseGitHubRepo builds a repository reference from an "owner/repo" string, applying
+// the forge settings configured for it
+func parseGitHubRepo(cfg *config.Config, spec, ref string) (fetcher.GitHubRepo, error) {
+	parts := strings.Split(spec, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fetcher.GitHubRepo{}, fmt.Errorf("invalid repository %q, expected owner/repo", spec)
+	}
+	repo := fetcher.GitHubRepo{
+		Owner:     parts[0],
+		Name:      parts[1],
+		Branch:    ref,
+		ShortName: parts[1],
+	}
+	if forge, ok := cfg.ForgeFor(spec); ok {
+		repo.Forge = forge.Type
+		repo.APIURL = forge.APIURL
+		repo.WebURL = forge.WebURL
+		repo.Token = forge.Token
+	}
+	return repo, nil
+}
+
+// parseDocRepo builds the reference of a documentation repository at ref, carrying
+// its documentation root and path filters
+func parseDocRepo(cfg *config.Config, docRepo config.GitHubRepository, ref string) (fetcher.GitHubRepo, error) {
+	repo, err := parseGitHubRepo(cfg, docRepo.FullName(), ref)
+	if err != nil {
+		return fetcher.GitHubRepo{}, err
+	}
+	repo.DocRoot = docRepo.DocRoot
+	repo.Include = docRepo.Include
+	repo.Exclude = docRepo.Exclude
+	return repo, nil
+}
+
+// fetchTransportConfig converts the proxy and TLS settings of a source to the fetcher's
+// transport settings
+func fetchTransportConfig(network config.NetworkConfig) fetcher.TransportConfig {
+	return fetcher.TransportConfig{
+		ProxyURL:      network.ProxyURL,
+		NoProxy:       network.NoProxy,
+		CAFiles:       network.CAFiles,
+		ClientCert:    network.ClientCert,
+		ClientKey:     network.ClientKey,
+		TLSMinVersion: network.TLSMinVersion,
+	}
+}
+
+// breakerConfig returns the circuit breaker settings shared by every source
+func breakerConfig(cfg *config.Config) fetcher.BreakerConfig {
+	return fetcher.BreakerConfig{
+		Window:         cfg.BreakerWindow,
+		MinRequests:    cfg.BreakerMinRequests,
+		FailureRatio:   cfg.BreakerFailureRatio,
+		CoolDown:       time.Duration(cfg.BreakerCoolDown) * time.Second,
+		HalfOpenProbes: cfg.BreakerHalfOpenProbes,
+	}
+}
+
+// fetchAuth loads the secrets of a source's credentials and converts them to the
+// fetcher's credentials
+func fetchAuth(auth config.AuthConfig) (fetcher.Auth, error) {
+	resolved, err := auth.Resolve()
+	if err != nil {
+		return fetcher.Auth{}, err
+	}
+	return fetcher.Auth{
+		Username:    resolved.Username,
+		Password:    resolved.Password,
+		BearerToken: resolved.BearerToken,
+		Headers:     resolved.Headers,
+		CookieFile:  resolved.CookieFile,
+	}, nil
+}
+
+// repositoryRef returns the branch or tag indexed as the default version of a
+// documentation repository
+func repositoryRef(cfg *config.Config, docRepo config.GitHubRepository) string {
+	if docRepo.Ref != "" {
+		return docRepo.Ref
+	}
+	return cfg.GitHubBranch
+}
+
+// githubDocRepos returns the documentation repositories to fetch. Every additional
+// version of a repository is fetched as its own copy of it.
+func githubDocRepos(cfg *config.Config) []fetcher.GitHubRepo {
+	repos := make([]fetcher.GitHubRepo, 0, len(cfg.GitHubRepositories))
+	for _, docRepo := range cfg.GitHubRepositories {
+		refs := []string{repositoryRef(cfg, docRepo)}
+		for _, version := range docRepo.Versions {
+			if version != refs[0] {
+				refs = append(refs, version)
+			}
+		}
+		for _, ref := range refs {
+			if repo, err := parseDocRepo(cfg, docRepo, ref); err == nil {
+				repos = append(repos, repo)
+			}
+		}
+	}
+	return repos
+}
+
+// hasGitHubVersions reports whether any documentation repository is indexed at
+// additional versions
+func hasGitHubVersions(cfg *config.Config) bool {
+	for _, repo := range cfg.GitHubRepositories {
+		if len(repo.Versions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// githubRepository returns the configured documentation repository with the given
+// short name
+func (s *Server) githubRepository(name string) (config.GitHubRepository, bool) {
+	for _, repo := range s.config.GitHubRepositories {
+		if repo.Name == name {
+			return repo, true
+		}
+	}
+	return config.GitHubRepository{}, false
+}
+
+// defaultVersion returns the version whose documents keep plain IDs in a repository
+func (s *Server) defaultVersion(name string) string {
+	if repo, ok := s.githubRepository(name); ok {
+		return repositoryRef(s.config, repo)
+	}
+	return s.config.GitHubBranch
+}
+
+// resultDocument returns the indexed document of a search result, or nil if it is
+// no longer indexed
+func (st *docState) resultDocument(result search.SearchResult) *index.Document {
+	var idx *index.DocumentationIndex
+	switch result.Source {
+	case "NATS":
+		idx = st.indexManager.GetNATSIndex()
+	case "Synadia":
+		idx = st.indexManager.GetSynadiaIndex()
+	case "GitHub":
+		idx = st.indexManager.GetGitHubIndex()
+	default:
+		return nil
+	}
+	doc, err := idx.Get(result.DocumentID)
+	if err != nil {
+		return nil
+	}
+	return doc
+}
+
+// maxTableRows is the number of matching table rows listed per search result
+const maxTableRows = 3
+
+// formatTableRow renders a table row as "Header: cell" pairs
+func formatTableRow(row index.TableRow) string {
+	pairs := make([]string, 0, len(row.Cells))
+	for i, cell := range row.Cells {
+		if cell == "" {
+			continue
+		}
+		if i < len(row.Header) && row.Header[i] != "" {
+			cell = row.Header[i] + ": " + cell
+		}
+		pairs = append(pairs, cell)
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// resultSource labels a search result with its source, naming the repository of
+// GitHub documents that have a display name
+func (st *docState) resultSource(result search.SearchResult) string {
+	if result.Source != "GitHub" {
+		return result.Source
+	}
+	doc, err := st.indexManager.GetGitHubIndex().Get(result.DocumentID)
+	if err != nil || doc.Metadata[index.MetaRepository] == "" {
+		return result.Source
+	}
+	return result.Source + ": " + doc.Metadata[index.MetaRepository]
+}
+
+// githubBlobURL returns the URL prefix of files in a repository
+func githubBlobURL(repo fetcher.GitHubRepo) string {
+	return repo.FileURL("")
+}
+
+// githubFileURL returns the web URL of a documentation file fetched from one of the
+// configured repositories at version
+func (s *Server) githubFileURL(file fetcher.GitHubFile, version string) string {
+	for _, docRepo := range s.config.GitHubRepositories {
+		repo, err := parseDocRepo(s.config, docRepo, version)
+		if err == nil && repo.ShortName == file.Repo {
+			return repo.FileURL(file.Path)
+		}
+	}
+	return ""
 }
 
 // handleRefreshCacheTool handles requests to refresh the documentation cache.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -417,6 +418,38 @@ func TestNewServerWithInvalidTransport(t *testing.T) {
 	}
 }
 
+// TestNewServerWithInvalidSourceTransport tests that NewServer fails when a source's
+// CA bundle cannot be loaded instead of fetching with the default transport
+func TestNewServerWithInvalidSourceTransport(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	cfg := config.NewConfig()
+	cfg.DocsNetwork.CAFiles = []string{caFile}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	if _, err := NewServer(cfg, logger); err == nil {
+		t.Errorf("expected error for an invalid CA bundle, got nil")
+	}
+}
+
+// TestNewServerWithInvalidSourceAuth tests that NewServer fails when a source's
+// cookie file cannot be loaded instead of fetching without credentials
+func TestNewServerWithInvalidSourceAuth(t *testing.T) {
+	cookieFile := filepath.Join(t.TempDir(), "cookies.txt")
+	if err := os.WriteFile(cookieFile, []byte("not a cookie file\n"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	cfg := config.NewConfig()
+	cfg.DocsAuth.CookieFile = cookieFile
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	if _, err := NewServer(cfg, logger); err == nil {
+		t.Errorf("expected error for an invalid cookie file, got nil")
+	}
+}
+
 // TestStartCallsTransport tests that Start calls the transport's Start method
 func TestStartCallsTransport(t *testing.T) {
 	cfg := config.NewConfig()
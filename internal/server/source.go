@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// docSource describes a documentation source indexed through the pipeline: a
+// documentation site of pages or a set of repositories of files
+type docSource[T any] struct {
+	name      string // Name of the source in logs and the server status (e.g., "NATS")
+	cacheKey  string // Name of the source in the cache (e.g., "nats")
+	sourceURL string // URL recorded with the cached documents
+	items     string // What the source's items are called in logs: "pages" or "files"
+	logAttrs  []any  // Extra attributes of the log message announcing a network fetch
+
+	// docID maps the validator key or failure path of an item to the ID of the
+	// document parsed from it
+	docID func(key string) string
+	// fetch sends the items of the source to out, skipping or revalidating those
+	// unchanged since previous
+	fetch func(ctx context.Context, previous previousFetch, out chan<- T) error
+	// parse turns an item into a document, reusing the one in previous when the item
+	// is unchanged
+	parse func(previous previousFetch, item T) (parsedItem, bool)
+
+	cached *index.DocumentationIndex          // Index cached documents are imported into
+	index  func(docs []*index.Document) error // Adds a batch of documents to the index
+	reset  func()                             // Drops every document of the index
+}
+
+// initializeSource indexes a documentation source, loading it from the cache when the
+// cache is valid unless force is set. Otherwise items unchanged since the last cached
+// fetch are not downloaded or parsed again, and stream from the fetcher through the
+// parse workers into the index, so only a bounded number of them is held in memory.
+// Items that failed to fetch keep their previously indexed version until they are
+// fetched again. It returns the indexed documents.
+func initializeSource[T any](ctx context.Context, s *Server, force bool, src docSource[T]) ([]*index.Document, error) {
+	if docs := s.importCachedSource(src.name, src.cacheKey, src.cached, force); docs != nil {
+		return docs, nil
+	}
+
+	// Cache miss or refresh requested - fetch from network
+	s.logger.Info("Fetching "+src.name+" documentation from network", src.logAttrs...)
+
+	previous := s.loadPreviousFetch(src.cacheKey)
+	result, err := pipeline[T]{
+		fetch: func(ctx context.Context, out chan<- T) error {
+			return src.fetch(ctx, previous, out)
+		},
+		parse: func(item T) (parsedItem, bool) {
+			return src.parse(previous, item)
+		},
+		index: src.index,
+	}.run(ctx)
+	failures, fetchErr := partialFetch(result.fetchErr)
+	if fetchErr != nil {
+		// Drop the batches indexed before the fetch failed
+		src.reset()
+		err = fmt.Errorf("failed to fetch %s documentation: %w", src.name, fetchErr)
+		s.recordSourceStatus(src.name, sourceStatus{FetchedAt: time.Now(), Failures: failures, Error: err.Error()})
+		return nil, err
+	}
+	if err != nil {
+		src.reset()
+		return nil, fmt.Errorf("failed to index %s documentation: %w", src.name, err)
+	}
+	if len(failures) > 0 {
+		s.logger.Warn("Some "+src.name+" "+src.items+" failed to fetch, indexing partial results",
+			"failed", len(failures),
+			"fetched", result.fetched)
+	}
+
+	// Items that failed keep their previously indexed version until they are fetched again
+	docs := result.docs
+	kept := previous.keepFailed(failures, docs, src.docID)
+	if len(kept) > 0 {
+		if err := src.index(kept); err != nil {
+			return nil, fmt.Errorf("failed to index %s documentation: %w", src.name, err)
+		}
+		docs = append(docs, kept...)
+	}
+
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("failed to parse any %s documentation %s", src.name, src.items)
+	}
+
+	s.logger.Info(src.name+" documentation indexed", "count", len(docs), "unchanged", result.reused, "kept", len(kept))
+	s.recordSourceStatus(src.name, sourceStatus{
+		FetchedAt: time.Now(),
+		Documents: len(docs),
+		Pages:     result.fetched,
+		Failures:  failures,
+	})
+
+	// Save to cache (best-effort, log errors but don't fail)
+	if s.cache != nil {
+		if err := s.cache.SaveWithFailures(src.cacheKey, src.sourceURL, docs, result.validators, failures); err != nil {
+			s.logger.Warn("Failed to save cache", "source", src.cacheKey, "error", err)
+		} else {
+			s.logger.Info("Saved "+src.name+" docs to cache", "count", len(docs))
+		}
+	}
+
+	return docs, nil
+}
+
+// importCachedSource imports the cached documents of a source into idx when the cache
+// is enabled, not being revalidated (force), and still valid. It returns the imported
+// documents, or nil when the source must be fetched.
+func (s *Server) importCachedSource(name, cacheKey string, idx *index.DocumentationIndex, force bool) []*index.Document {
+	if force || s.cache == nil {
+		return nil
+	}
+
+	maxAge := time.Duration(s.config.CacheMaxAge) * 24 * time.Hour
+	valid, err := s.cache.IsValid(cacheKey, maxAge)
+	if err != nil {
+		s.logger.Warn("Cache validation failed, will fetch from network",
+			"source", cacheKey, "error", err)
+		return nil
+	}
+	if !valid {
+		return nil
+	}
+
+	// Load from cache
+	s.logger.Info("Loading "+name+" docs from cache", "source", cacheKey)
+	cached, err := s.cache.Load(cacheKey)
+	if err != nil || len(cached.Documents) == 0 {
+		return nil
+	}
+	// Import documents into index
+	if err := idx.ImportDocuments(cached.Documents); err != nil {
+		s.logger.Warn("Failed to import cached docs, will fetch", "error", err)
+		return nil
+	}
+	s.logger.Info("Loaded "+name+" docs from cache",
+		"count", len(cached.Documents),
+		"cached_at", cached.CachedAt)
+	s.recordSourceStatus(name, sourceStatus{
+		FetchedAt: cached.CachedAt,
+		FromCache: true,
+		Documents: len(cached.Documents),
+		Failures:  cached.Failures,
+	})
+	return cached.Documents
+}
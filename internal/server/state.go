@@ -0,0 +1,58 @@
+package server
+
+import (
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/adr"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/classifier"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/configref"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/errref"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/examples"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/jsapi"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/releases"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/search"
+)
+
+// docState holds the indices and catalogues built from the documentation sources.
+// Initialization and RefreshCache build a new state and swap it in once every source
+// is indexed, so tool calls keep answering from the previous documentation while a
+// refresh runs, and keep it when the refresh fails.
+type docState struct {
+	indexManager   *index.Manager       // Indices of the NATS, Synadia and GitHub documentation
+	orchestrator   *search.Orchestrator // Search orchestrator over indexManager's indices
+	jsAPICatalog   *jsapi.Catalog       // JetStream API schema lookup (nil unless enabled)
+	configCatalog  *configref.Catalog   // nats-server configuration option lookup
+	errorCatalog   *errref.Catalog      // NATS error definition lookup (nil unless enabled)
+	adrCatalog     *adr.Catalog         // Architecture Decision Records found in GitHub docs
+	releaseCatalog *releases.Catalog    // Release notes (nil unless enabled)
+	exampleCatalog *examples.Catalog    // Code examples of the indexed documentation
+}
+
+// newDocState returns an empty documentation state whose searches are routed by
+// queryClassifier
+func newDocState(queryClassifier classifier.Classifier) *docState {
+	indexManager := index.NewManager()
+	return &docState{
+		indexManager: indexManager,
+		orchestrator: search.NewOrchestrator(
+			indexManager.GetNATSIndex(),
+			indexManager.GetSynadiaIndex(),
+			indexManager.GetGitHubIndex(),
+			queryClassifier,
+		),
+	}
+}
+
+// docs returns the documentation state tool calls are answered from. A state is not
+// modified once swapped in, so callers may use it without holding the lock.
+func (s *Server) docs() *docState {
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	return s.state
+}
+
+// swapDocs replaces the documentation state tool calls are answered from
+func (s *Server) swapDocs(state *docState) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	s.state = state
+}
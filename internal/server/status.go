@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxListedFailures bounds the failed pages listed per source in the server status
+const maxListedFailures = 10
+
+// sourceStatus records the outcome of the last fetch of a documentation site
+type sourceStatus struct {
+	FetchedAt time.Time
+	FromCache bool                  // Loaded from a fresh cache without fetching
+	Documents int                   // Documents indexed
+	Pages     int                   // Pages fetched, including unchanged ones
+	Failures  []fetcher.PageFailure // Pages that failed to fetch
+	Error     string                // Why the source could not be indexed, if it failed
+}
+
+// recordSourceStatus records the outcome of fetching a documentation site for the
+// server status
+func (s *Server) recordSourceStatus(source string, status sourceStatus) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	if s.sources == nil {
+		s.sources = make(map[string]sourceStatus)
+	}
+	s.sources[source] = status
+}
+
+// handleServerStatusTool handles the get_server_status tool invocation. It reports
+// the indexed document counts, the outcome of the last fetch of each documentation
+// site with the pages that failed, and the circuit breaker state of every fetched host.
+func (s *Server) handleServerStatusTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText(s.formatStatus(time.Now())), nil
+}
+
+// formatStatus renders the server status as of now
+func (s *Server) formatStatus(now time.Time) string {
+	var content strings.Builder
+	content.WriteString("Server status\n\n")
+	content.WriteString(fmt.Sprintf("Initialized: %t\n", s.initialized))
+
+	stats := s.docs().indexManager.Stats()
+	content.WriteString(fmt.Sprintf("Documents: %d NATS, %d Synadia, %d GitHub (%d total)\n",
+		stats.NATSDocCount, stats.SynadiaDocCount, stats.GitHubDocCount, stats.TotalDocCount))
+
+	content.WriteString("\nDocumentation sources:\n")
+	s.statusMu.Lock()
+	sources := make(map[string]sourceStatus, len(s.sources))
+	for source, status := range s.sources {
+		sources[source] = status
+	}
+	s.statusMu.Unlock()
+	if len(sources) == 0 {
+		content.WriteString("- none fetched yet\n")
+	}
+	for _, source := range []string{"NATS", "Synadia", "GitHub"} {
+		if status, ok := sources[source]; ok {
+			content.WriteString(formatSourceStatus(source, status))
+		}
+	}
+
+	content.WriteString("\nCircuit breakers:\n")
+	if !s.config.BreakerEnabled {
+		content.WriteString("- disabled\n")
+		return content.String()
+	}
+	hosts := s.multiFetcher.BreakerStatus()
+	if len(hosts) == 0 {
+		content.WriteString("- no hosts contacted yet\n")
+	}
+	for _, host := range hosts {
+		content.WriteString(formatBreakerStatus(host, now))
+	}
+	return content.String()
+}
+
+// formatBreakerStatus renders the circuit breaker of a host as a list item
+func formatBreakerStatus(host fetcher.HostStatus, now time.Time) string {
+	var line strings.Builder
+	line.WriteString(fmt.Sprintf("- %s: %s", host.Host, host.State))
+	if host.State == fetcher.BreakerOpen {
+		line.WriteString(fmt.Sprintf(", probing again in %s", host.OpenUntil.Sub(now).Round(time.Second)))
+	}
+	if host.State == fetcher.BreakerClosed {
+		line.WriteString(fmt.Sprintf(", %d of the last %d requests failed", host.Failures, host.Requests))
+	}
+	if host.Trips > 0 {
+		line.WriteString(fmt.Sprintf(", opened %d time(s)", host.Trips))
+	}
+	if host.LastError != "" && host.State != fetcher.BreakerClosed {
+		line.WriteString(fmt.Sprintf(", last error: %s", host.LastError))
+	}
+	line.WriteString("\n")
+	return line.String()
+}
+
+// formatSourceStatus renders the last fetch of a documentation site as a list item
+// followed by the pages that failed, which the next fetch retries first
+func formatSourceStatus(source string, status sourceStatus) string {
+	var item strings.Builder
+	fetchedAt := status.FetchedAt.UTC().Format(time.RFC3339)
+	switch {
+	case status.Error != "":
+		item.WriteString(fmt.Sprintf("- %s: failed at %s: %s\n", source, fetchedAt, status.Error))
+	case status.FromCache:
+		item.WriteString(fmt.Sprintf("- %s: %d documents loaded from the cache written at %s", source, status.Documents, fetchedAt))
+		if len(status.Failures) > 0 {
+			item.WriteString(fmt.Sprintf(", %d page(s) failed in that fetch", len(status.Failures)))
+		}
+		item.WriteString("\n")
+	default:
+		total := status.Pages + len(status.Failures)
+		item.WriteString(fmt.Sprintf("- %s: %d of %d pages fetched at %s, %d documents indexed",
+			source, status.Pages, total, fetchedAt, status.Documents))
+		if len(status.Failures) > 0 {
+			item.WriteString(fmt.Sprintf(", %d failed (%.1f%% succeeded)", len(status.Failures), 100*float64(status.Pages)/float64(total)))
+		}
+		item.WriteString("\n")
+	}
+
+	for i, failure := range status.Failures {
+		if i == maxListedFailures {
+			item.WriteString(fmt.Sprintf("  - ... and %d more\n", len(status.Failures)-maxListedFailures))
+			break
+		}
+		item.WriteString(fmt.Sprintf("  - %s: %s\n", failure.URL, failure.Error))
+	}
+	return item.String()
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/fetcher"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestServerStatusReportsOpenCircuitBreaker(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer site.Close()
+
+	cfg := config.NewConfig()
+	cfg.DocsBaseURL = site.URL
+	cfg.CacheDir = t.TempDir()
+	cfg.BreakerMinRequests = 1
+	cfg.BreakerWindow = 1
+
+	srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	_ = srv.Initialize(context.Background())
+
+	result, err := srv.handleServerStatusTool(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("get_server_status failed: %v", err)
+	}
+	text := resultText(t, result)
+	host := strings.TrimPrefix(site.URL, "http://")
+	if !strings.Contains(text, "- "+host+": open, probing again in") {
+		t.Errorf("expected the dead host's breaker to be reported open, got:\n%s", text)
+	}
+	if !strings.Contains(text, "HTTP 502") {
+		t.Errorf("expected the last error to be reported, got:\n%s", text)
+	}
+}
+
+func TestFormatBreakerStatus(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		status fetcher.HostStatus
+		want   string
+	}{
+		{
+			fetcher.HostStatus{Host: "docs.nats.io", State: fetcher.BreakerClosed, Requests: 12, Failures: 1, LastError: "timeout"},
+			"- docs.nats.io: closed, 1 of the last 12 requests failed\n",
+		},
+		{
+			fetcher.HostStatus{Host: "docs.synadia.com", State: fetcher.BreakerOpen, Trips: 2, OpenUntil: now.Add(30 * time.Second), LastError: "server error: HTTP 503"},
+			"- docs.synadia.com: open, probing again in 30s, opened 2 time(s), last error: server error: HTTP 503\n",
+		},
+		{
+			fetcher.HostStatus{Host: "github.com", State: fetcher.BreakerHalfOpen, Trips: 1},
+			"- github.com: half-open, opened 1 time(s)\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := formatBreakerStatus(tt.status, now); got != tt.want {
+			t.Errorf("formatBreakerStatus(%s) = %q, want %q", tt.status.Host, got, tt.want)
+		}
+	}
+}
+
+func TestServerStatusWithBreakersDisabled(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.BreakerEnabled = false
+
+	srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	status := srv.formatStatus(time.Now())
+	if !strings.Contains(status, "Circuit breakers:\n- disabled") {
+		t.Errorf("expected disabled breakers to be reported, got:\n%s", status)
+	}
+}
+
+// TestInitializeToleratesFailedPages verifies that a page failing to fetch does not
+// abort initialization, keeps its previously indexed version, is reported in the
+// server status and is retried first by the next refresh
+func TestInitializeToleratesFailedPages(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	broken := ""
+
+	var site *httptest.Server
+	site = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sitemap-pages.xml" {
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+			for i := 1; i <= 10; i++ {
+				fmt.Fprintf(w, "<url><loc>%s/page%d</loc></url>", site.URL, i)
+			}
+			fmt.Fprint(w, "</urlset>")
+			return
+		}
+		if !strings.HasPrefix(r.URL.Path, "/page") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		failing := r.URL.Path == broken
+		mu.Unlock()
+		if failing {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<html><head><title>Page %s</title></head><body><h1>Page</h1><p>Content of %s.</p></body></html>", r.URL.Path, r.URL.Path)
+	}))
+	defer site.Close()
+
+	cfg := config.NewConfig()
+	cfg.DocsBaseURL = site.URL
+	cfg.CacheDir = t.TempDir()
+	cfg.MaxConcurrent = 1
+	cfg.RequestsPerSecond = 1000
+
+	srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	if err := srv.Initialize(context.Background()); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	// Refresh the NATS documentation only; the other sources are not reachable here
+	refresh := func() error {
+		st := newDocState(srv.classifier)
+		if err := srv.initializeNATS(context.Background(), st, true); err != nil {
+			return err
+		}
+		srv.swapDocs(st)
+		return nil
+	}
+
+	mu.Lock()
+	broken = "/page3"
+	mu.Unlock()
+	if err := refresh(); err != nil {
+		t.Fatalf("refresh with one failing page failed: %v", err)
+	}
+	if _, err := srv.state.indexManager.GetNATSIndex().Get("page3"); err != nil {
+		t.Errorf("expected the failed page to keep its previously indexed version: %v", err)
+	}
+	status := srv.formatStatus(time.Now())
+	if !strings.Contains(status, "9 of 10 pages fetched") || !strings.Contains(status, site.URL+"/page3: ") {
+		t.Errorf("expected the failed page in the server status, got:\n%s", status)
+	}
+
+	mu.Lock()
+	requested, broken = nil, ""
+	mu.Unlock()
+	if err := refresh(); err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if len(requested) == 0 || requested[0] != "/page3" {
+		t.Errorf("expected the failed page to be retried first, got %v", requested)
+	}
+	if status := srv.formatStatus(time.Now()); !strings.Contains(status, "10 of 10 pages fetched") {
+		t.Errorf("expected the retried page to be fetched, got:\n%s", status)
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+)
+
+// testInitializer loads a source into a test server's documentation state, e.g.
+// (*Server).initializeJetStreamAPI
+type testInitializer func(s *Server, ctx context.Context, st *docState, force bool) error
+
+// testServerOptions describe the documentation a test server is created with
+type testServerOptions struct {
+	configure    func(cfg *config.Config) // Adjusts the default configuration
+	nats         []*index.Document        // Documents indexed as NATS documentation
+	synadia      []*index.Document        // Documents indexed as Synadia documentation
+	github       []*index.Document        // Documents indexed as GitHub documentation
+	initializers []testInitializer        // Run in order once the documents are indexed
+}
+
+// newTestServer creates an initialized server with a temporary cache directory,
+// indexing the documents of opts and running its initializers without fetching
+// anything not configured by the test
+func newTestServer(t *testing.T, opts testServerOptions) *Server {
+	t.Helper()
+
+	cfg := config.NewConfig()
+	cfg.CacheDir = t.TempDir()
+	if opts.configure != nil {
+		opts.configure(cfg)
+	}
+
+	srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	st := srv.state
+	if len(opts.nats) > 0 {
+		if err := st.indexManager.IndexNATS(opts.nats); err != nil {
+			t.Fatalf("failed to index NATS docs: %v", err)
+		}
+	}
+	if len(opts.synadia) > 0 {
+		if err := st.indexManager.IndexSynadia(opts.synadia); err != nil {
+			t.Fatalf("failed to index Synadia docs: %v", err)
+		}
+	}
+	if len(opts.github) > 0 {
+		if err := st.indexManager.IndexGitHub(opts.github); err != nil {
+			t.Fatalf("failed to index GitHub docs: %v", err)
+		}
+	}
+	for _, initialize := range opts.initializers {
+		if err := initialize(srv, context.Background(), st, false); err != nil {
+			t.Fatalf("failed to initialize test server: %v", err)
+		}
+	}
+
+	srv.initialized = true
+	return srv
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
 	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/parser"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
@@ -155,7 +156,7 @@ func TestSearchToolHandler(t *testing.T) {
 		},
 	}
 
-	if err := srv.indexManager.IndexNATS(testDocs); err != nil {
+	if err := srv.state.indexManager.IndexNATS(testDocs); err != nil {
 		t.Fatalf("failed to index documents: %v", err)
 	}
 
@@ -243,7 +244,7 @@ func TestRetrieveToolHandler(t *testing.T) {
 		},
 	}
 
-	if err := srv.indexManager.IndexNATS([]*index.Document{testDoc}); err != nil {
+	if err := srv.state.indexManager.IndexNATS([]*index.Document{testDoc}); err != nil {
 		t.Fatalf("failed to index document: %v", err)
 	}
 
@@ -293,6 +294,60 @@ func TestRetrieveToolHandler(t *testing.T) {
 	}
 }
 
+// TestToolHandlersWithTables tests that table rows are found by their key column and
+// that tables are retrieved as Markdown tables
+func TestToolHandlersWithTables(t *testing.T) {
+	srv, err := NewServer(config.NewConfig(), slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	srv.initialized = true
+
+	page := `<html><head><title>Limits</title></head><body>
+<h1>Limits</h1><p>Limits protect the server from misbehaving clients.</p>
+<table>
+	<tr><th>Property</th><th>Description</th><th>Default</th></tr>
+	<tr><td><code>max_payload</code></td><td>Maximum number of bytes in a message payload</td><td>1MB</td></tr>
+	<tr><td><code>max_connections</code></td><td>Maximum number of active client connections</td><td>64K</td></tr>
+</table></body></html>`
+	doc, err := parser.ParseHTML(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("failed to parse page: %v", err)
+	}
+	if err := srv.state.indexManager.IndexNATS([]*index.Document{{
+		ID:       "running-a-nats-service/configuration/limits",
+		Title:    doc.Title,
+		URL:      "https://docs.nats.io/running-a-nats-service/configuration/limits",
+		Content:  extractContent(doc),
+		Sections: convertSections(doc.Sections),
+	}}); err != nil {
+		t.Fatalf("failed to index document: %v", err)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"query": "max_payload"}
+	result, err := srv.handleSearchTool(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("search failed: %v %s", err, resultText(t, result))
+	}
+	text := resultText(t, result)
+	if !strings.Contains(text, "Table row (Limits): Property: max_payload; Description: Maximum number of bytes in a message payload; Default: 1MB") {
+		t.Errorf("expected the matching table row, got:\n%s", text)
+	}
+	if strings.Contains(text, "max_connections;") {
+		t.Errorf("expected only the row with the queried key, got:\n%s", text)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"doc_id": "running-a-nats-service/configuration/limits"}
+	result, err = srv.handleRetrieveTool(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("retrieve failed: %v %s", err, resultText(t, result))
+	}
+	if text := resultText(t, result); !strings.Contains(text, "| Property | Description | Default |\n| --- | --- | --- |\n| max_payload |") {
+		t.Errorf("expected the table as a Markdown table, got:\n%s", text)
+	}
+}
+
 // TestToolHandlerConcurrency tests that tool handlers can be called concurrently
 func TestToolHandlerConcurrency(t *testing.T) {
 	cfg := config.NewConfig()
@@ -316,7 +371,7 @@ func TestToolHandlerConcurrency(t *testing.T) {
 			},
 		}
 	}
-	if err := srv.indexManager.IndexNATS(testDocs); err != nil {
+	if err := srv.state.indexManager.IndexNATS(testDocs); err != nil {
 		t.Fatalf("failed to index documents: %v", err)
 	}
 
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/textdiff"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change
+const diffContextLines = 3
+
+// versionedDocID returns the ID of a GitHub document at a version. Documents of the
+// default version keep their plain "repo/path" ID; other versions are stored as
+// "repo@version/path".
+func versionedDocID(id, version, defaultVersion string) string {
+	if version == "" || version == defaultVersion {
+		return id
+	}
+	repo, path, found := strings.Cut(id, "/")
+	if !found {
+		return id + "@" + version
+	}
+	return repo + "@" + version + "/" + path
+}
+
+// docRepository returns the repository short name of a GitHub document ID in
+// "repo/path" or "repo@version/path" form
+func docRepository(id string) string {
+	repo, _, _ := strings.Cut(id, "/")
+	repo, _, _ = strings.Cut(repo, "@")
+	return repo
+}
+
+// versionFilter accepts documents of the given version. Without a version only
+// unversioned documents and those of the default branch are accepted, so pages
+// indexed at several versions appear once.
+func (s *Server) versionFilter(version string) index.SearchFilter {
+	return func(doc *index.Document) bool {
+		docVersion := doc.Metadata[index.MetaVersion]
+		if version == "" {
+			return docVersion == "" || docVersion == s.defaultVersion(docRepository(doc.ID))
+		}
+		return docVersion == version
+	}
+}
+
+// allFilters combines search filters; a document must be accepted by every filter
+func allFilters(filters ...index.SearchFilter) index.SearchFilter {
+	return func(doc *index.Document) bool {
+		for _, filter := range filters {
+			if filter != nil && !filter(doc) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// baseDocID strips the version from the ID of a versioned GitHub document, so IDs
+// copied from versioned search results can be used with any version
+func (s *Server) baseDocID(st *docState, id string) string {
+	doc, err := st.indexManager.GetGitHubIndex().Get(id)
+	if err != nil {
+		return id
+	}
+	version := doc.Metadata[index.MetaVersion]
+	if version == "" || version == s.defaultVersion(docRepository(id)) {
+		return id
+	}
+	return strings.Replace(id, "@"+version, "", 1)
+}
+
+// getVersionedDocument returns a GitHub document at the given version
+func (s *Server) getVersionedDocument(st *docState, id, version string) (*index.Document, error) {
+	baseID := s.baseDocID(st, id)
+	versionedID := versionedDocID(baseID, version, s.defaultVersion(docRepository(baseID)))
+	doc, err := st.indexManager.GetGitHubIndex().Get(versionedID)
+	if err != nil {
+		return nil, err
+	}
+	if docVersion := doc.Metadata[index.MetaVersion]; docVersion != "" && docVersion != version {
+		return nil, fmt.Errorf("document %s is not indexed at version %s", id, version)
+	}
+	return doc, nil
+}
+
+// handleCompareDocVersionsTool handles the compare_doc_versions tool invocation.
+// It diffs the same GitHub document across two indexed versions.
+func (s *Server) handleCompareDocVersionsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	docID, err := request.RequireString("doc_id")
+	if err != nil || strings.TrimSpace(docID) == "" {
+		return mcp.NewToolResultError("doc_id parameter is required and must be a non-empty string"), nil
+	}
+	fromVersion, err := request.RequireString("from_version")
+	if err != nil || strings.TrimSpace(fromVersion) == "" {
+		return mcp.NewToolResultError("from_version parameter is required and must be a non-empty string"), nil
+	}
+	fromVersion = strings.TrimSpace(fromVersion)
+	defaultVersion := s.defaultVersion(docRepository(normalizePath(docID)))
+	toVersion := strings.TrimSpace(request.GetString("to_version", defaultVersion))
+	if toVersion == "" {
+		toVersion = defaultVersion
+	}
+	if fromVersion == toVersion {
+		return mcp.NewToolResultError("from_version and to_version must differ"), nil
+	}
+
+	id := normalizePath(docID)
+	st := s.docs()
+	fromDoc, err := s.getVersionedDocument(st, id, fromVersion)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("document not found: %s (version %s)", docID, fromVersion)), nil
+	}
+	toDoc, err := s.getVersionedDocument(st, id, toVersion)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("document not found: %s (version %s)", docID, toVersion)), nil
+	}
+
+	diff := textdiff.Compare(fromVersion, toVersion, documentText(fromDoc), documentText(toDoc), diffContextLines)
+
+	s.logger.Info("Document versions compared", "doc_id", docID, "from", fromVersion, "to", toVersion,
+		"added", diff.Added, "removed", diff.Removed)
+
+	baseID := s.baseDocID(st, id)
+	if diff.Unified == "" {
+		return mcp.NewToolResultText(fmt.Sprintf("No differences in %s between %s and %s\n", baseID, fromVersion, toVersion)), nil
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("Comparing %s from %s to %s: %d line(s) added, %d line(s) removed\n\n",
+		baseID, fromVersion, toVersion, diff.Added, diff.Removed))
+	content.WriteString(fmt.Sprintf("From: %s\n", fromDoc.URL))
+	content.WriteString(fmt.Sprintf("To: %s\n\n", toDoc.URL))
+	content.WriteString("```diff\n")
+	content.WriteString(diff.Unified)
+	content.WriteString("```\n")
+
+	return mcp.NewToolResultText(content.String()), nil
+}
+
+// documentText renders a document's title and sections for comparison. Unlike
+// formatDocument it leaves out the URL, which always differs between versions.
+func documentText(doc *index.Document) string {
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("# %s\n\n", doc.Title))
+	for _, section := range doc.Sections {
+		content.WriteString(fmt.Sprintf("%s %s\n\n", strings.Repeat("#", section.Level+1), section.Heading))
+		content.WriteString(fmt.Sprintf("%s\n\n", section.Content))
+	}
+	return content.String()
+}
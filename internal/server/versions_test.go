@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/config"
+	"github.com/j4ng5y/nats-docs-mcp-server/internal/index"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// versionsTestOptions index the same page at the default branch and at v2.10.0 as
+// GitHub documentation
+func versionsTestOptions() testServerOptions {
+	defaultBranch := config.NewConfig().GitHubBranch
+	page := func(version, content string) *index.Document {
+		return &index.Document{
+			ID:          versionedDocID("nats.docs/jetstream/streams.md", version, defaultBranch),
+			Title:       "Streams",
+			URL:         "https://github.com/nats-io/nats.docs/blob/" + version + "/jetstream/streams.md",
+			Content:     content,
+			Sections:    []index.Section{{Heading: "Configuration", Content: content, Level: 1}},
+			LastUpdated: time.Now(),
+			Metadata:    map[string]string{index.MetaVersion: version},
+		}
+	}
+	return testServerOptions{
+		configure: func(cfg *config.Config) {
+			for i, repo := range cfg.GitHubRepositories {
+				if repo.Name == "nats.docs" {
+					cfg.GitHubRepositories[i].Versions = []string{"v2.10.0"}
+				}
+			}
+		},
+		github: []*index.Document{
+			page("main", "Streams retain messages.\nMaxAge limits retention.\nAllowDirect enables direct get."),
+			page("v2.10.0", "Streams retain messages.\nMaxAge limits retention."),
+		},
+	}
+}
+
+func TestVersionedDocID(t *testing.T) {
+	tests := []struct {
+		id, version, want string
+	}{
+		{"nats.docs/jetstream/streams.md", "main", "nats.docs/jetstream/streams.md"},
+		{"nats.docs/jetstream/streams.md", "", "nats.docs/jetstream/streams.md"},
+		{"nats.docs/jetstream/streams.md", "v2.10.0", "nats.docs@v2.10.0/jetstream/streams.md"},
+		{"nats.docs/README.md", "release/v2.11", "nats.docs@release/v2.11/README.md"},
+	}
+	for _, tt := range tests {
+		if got := versionedDocID(tt.id, tt.version, "main"); got != tt.want {
+			t.Errorf("versionedDocID(%q, %q) = %q, want %q", tt.id, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestGitHubDocReposVersions(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.GitHubRepositories = []config.GitHubRepository{
+		{Owner: "nats-io", Name: "nats-server", Versions: []string{"v2.10.0"}},
+		{Owner: "nats-io", Name: "nats.docs"},
+	}
+
+	var refs []string
+	for _, repo := range githubDocRepos(cfg) {
+		refs = append(refs, repo.ShortName+"@"+repo.Branch)
+	}
+	want := []string{"nats-server@main", "nats-server@v2.10.0", "nats.docs@main"}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("repositories = %q, want %q", refs, want)
+	}
+}
+
+func TestHandleSearchToolVersion(t *testing.T) {
+	srv := newTestServer(t, versionsTestOptions())
+
+	search := func(args map[string]interface{}) string {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := srv.handleSearchTool(context.Background(), request)
+		if err != nil || result.IsError {
+			t.Fatalf("search failed: %v %s", err, resultText(t, result))
+		}
+		return resultText(t, result)
+	}
+
+	// Without a version each page appears once, from the default branch
+	text := search(map[string]interface{}{"query": "streams retention github"})
+	if !strings.Contains(text, "Found 1 results") || !strings.Contains(text, "/blob/main/") {
+		t.Errorf("expected only the default branch page, got:\n%s", text)
+	}
+
+	text = search(map[string]interface{}{"query": "streams retention", "version": "v2.10.0"})
+	if !strings.Contains(text, "Found 1 results") || !strings.Contains(text, "/blob/v2.10.0/") {
+		t.Errorf("expected only the v2.10.0 page, got:\n%s", text)
+	}
+
+	text = search(map[string]interface{}{"query": "streams", "version": "v9.9.9"})
+	if !strings.Contains(text, "Found 0 results") {
+		t.Errorf("expected no results for an unknown version, got:\n%s", text)
+	}
+}
+
+func TestHandleRetrieveToolVersion(t *testing.T) {
+	srv := newTestServer(t, versionsTestOptions())
+
+	tests := []struct {
+		name      string
+		docID     string
+		version   string
+		wantError bool
+		wantURL   string
+	}{
+		{name: "default", docID: "nats.docs/jetstream/streams.md", wantURL: "/blob/main/"},
+		{name: "older version", docID: "nats.docs/jetstream/streams.md", version: "v2.10.0", wantURL: "/blob/v2.10.0/"},
+		{name: "versioned ID", docID: "nats.docs@v2.10.0/jetstream/streams.md", version: "main", wantURL: "/blob/main/"},
+		{name: "unknown version", docID: "nats.docs/jetstream/streams.md", version: "v1.0.0", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = map[string]interface{}{"doc_id": tt.docID, "version": tt.version}
+
+			result, err := srv.handleRetrieveTool(context.Background(), request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.IsError != tt.wantError {
+				t.Fatalf("expected IsError=%v, got %v: %s", tt.wantError, result.IsError, resultText(t, result))
+			}
+			if text := resultText(t, result); !strings.Contains(text, tt.wantURL) {
+				t.Errorf("expected %q in result, got:\n%s", tt.wantURL, text)
+			}
+		})
+	}
+}
+
+func TestHandleCompareDocVersionsTool(t *testing.T) {
+	srv := newTestServer(t, versionsTestOptions())
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantError bool
+		contains  []string
+	}{
+		{
+			name: "against default branch",
+			args: map[string]interface{}{"doc_id": "nats.docs/jetstream/streams.md", "from_version": "v2.10.0"},
+			contains: []string{
+				"Comparing nats.docs/jetstream/streams.md from v2.10.0 to main: 1 line(s) added, 0 line(s) removed",
+				"--- v2.10.0\n+++ main\n",
+				"+AllowDirect enables direct get.",
+			},
+		},
+		{
+			name:     "reverse",
+			args:     map[string]interface{}{"doc_id": "nats.docs/jetstream/streams.md", "from_version": "main", "to_version": "v2.10.0"},
+			contains: []string{"-AllowDirect enables direct get."},
+		},
+		{name: "same version", args: map[string]interface{}{"doc_id": "nats.docs/jetstream/streams.md", "from_version": "main"}, wantError: true},
+		{name: "unknown version", args: map[string]interface{}{"doc_id": "nats.docs/jetstream/streams.md", "from_version": "v1.0.0"}, wantError: true},
+		{name: "missing doc_id", args: map[string]interface{}{"from_version": "v2.10.0"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = tt.args
+
+			result, err := srv.handleCompareDocVersionsTool(context.Background(), request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.IsError != tt.wantError {
+				t.Fatalf("expected IsError=%v, got %v: %s", tt.wantError, result.IsError, resultText(t, result))
+			}
+
+			text := resultText(t, result)
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("expected %q in result, got:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Package textdiff computes line-based differences between two texts and renders
+// them as unified diffs.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxCells bounds the size of the longest-common-subsequence table. Larger inputs
+// are diffed as a single replacement of their differing middle part.
+const maxCells = 4_000_000
+
+// Result is the difference between two texts
+type Result struct {
+	Unified string // Unified diff; empty when the texts are equal
+	Added   int    // Number of added lines
+	Removed int    // Number of removed lines
+}
+
+// op is a single line of an edit script
+type op struct {
+	kind byte // ' ' (equal), '-' (removed) or '+' (added)
+	line string
+	ai   int // Index of the next line of a before this op
+	bi   int // Index of the next line of b before this op
+}
+
+// Compare diffs a and b line by line and renders hunks with the given number of
+// context lines, labelling the sides fromName and toName.
+func Compare(fromName, toName, a, b string, context int) Result {
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var result Result
+	var changes []int
+	for i, o := range ops {
+		switch o.kind {
+		case '-':
+			result.Removed++
+			changes = append(changes, i)
+		case '+':
+			result.Added++
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return result
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+
+	for start := 0; start < len(changes); {
+		// Extend the hunk while the next change is within two context windows
+		end := start
+		for end+1 < len(changes) && changes[end+1]-changes[end] <= 2*context+1 {
+			end++
+		}
+
+		first := max(0, changes[start]-context)
+		last := min(len(ops)-1, changes[end]+context)
+		writeHunk(&out, ops[first:last+1])
+
+		start = end + 1
+	}
+
+	result.Unified = out.String()
+	return result
+}
+
+// writeHunk renders a hunk header and its lines
+func writeHunk(out *strings.Builder, ops []op) {
+	var aCount, bCount int
+	for _, o := range ops {
+		if o.kind != '+' {
+			aCount++
+		}
+		if o.kind != '-' {
+			bCount++
+		}
+	}
+
+	// Empty ranges start at the line before, as in diff -u
+	aStart, bStart := ops[0].ai, ops[0].bi
+	if aCount > 0 {
+		aStart++
+	}
+	if bCount > 0 {
+		bStart++
+	}
+
+	out.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount))
+	for _, o := range ops {
+		out.WriteByte(o.kind)
+		out.WriteString(o.line)
+		out.WriteByte('\n')
+	}
+}
+
+// diffLines returns an edit script turning a into b
+func diffLines(a, b []string) []op {
+	// Common prefix and suffix need no table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, op{kind: ' ', line: a[i], ai: i, bi: i})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	ops = append(ops, diffMiddle(midA, midB, prefix, prefix)...)
+
+	for k := 0; k < suffix; k++ {
+		ai, bi := len(a)-suffix+k, len(b)-suffix+k
+		ops = append(ops, op{kind: ' ', line: a[ai], ai: ai, bi: bi})
+	}
+	return ops
+}
+
+// diffMiddle diffs the differing middle parts using a longest common subsequence
+// table. aOff and bOff are the line offsets of the parts.
+func diffMiddle(a, b []string, aOff, bOff int) []op {
+	var ops []op
+	n, m := len(a), len(b)
+
+	if n*m > maxCells {
+		for i, line := range a {
+			ops = append(ops, op{kind: '-', line: line, ai: aOff + i, bi: bOff})
+		}
+		for j, line := range b {
+			ops = append(ops, op{kind: '+', line: line, ai: aOff + n, bi: bOff + j})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:], stored row-major
+	lcs := make([]int32, (n+1)*(m+1))
+	at := func(i, j int) int32 { return lcs[i*(m+1)+j] }
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*(m+1)+j] = at(i+1, j+1) + 1
+			} else {
+				lcs[i*(m+1)+j] = max(at(i+1, j), at(i, j+1))
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			ops = append(ops, op{kind: ' ', line: a[i], ai: aOff + i, bi: bOff + j})
+			i++
+			j++
+		case j == m || (i < n && at(i+1, j) >= at(i, j+1)):
+			ops = append(ops, op{kind: '-', line: a[i], ai: aOff + i, bi: bOff + j})
+			i++
+		default:
+			ops = append(ops, op{kind: '+', line: b[j], ai: aOff + i, bi: bOff + j})
+			j++
+		}
+	}
+	return ops
+}
+
+// splitLines splits text into lines without their terminators
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
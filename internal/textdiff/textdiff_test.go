@@ -0,0 +1,66 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCompareEqual(t *testing.T) {
+	result := Compare("a", "b", "one\ntwo\n", "one\ntwo", 3)
+	if result.Unified != "" || result.Added != 0 || result.Removed != 0 {
+		t.Errorf("expected no differences, got %+v", result)
+	}
+}
+
+func TestCompareUnified(t *testing.T) {
+	a := "# Streams\nline 1\nline 2\nline 3\nmax_age: 1h\nline 5\n"
+	b := "# Streams\nline 1\nline 2\nline 3\nmax_age: 24h\nallow_direct: true\nline 5\n"
+
+	result := Compare("v2.10.0", "main", a, b, 1)
+	want := `--- v2.10.0
++++ main
+@@ -4,3 +4,4 @@
+ line 3
+-max_age: 1h
++max_age: 24h
++allow_direct: true
+ line 5
+`
+	if result.Unified != want {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", result.Unified, want)
+	}
+	if result.Added != 2 || result.Removed != 1 {
+		t.Errorf("unexpected stats: +%d -%d", result.Added, result.Removed)
+	}
+}
+
+func TestCompareSeparateHunks(t *testing.T) {
+	var a, b []string
+	for i := 1; i <= 20; i++ {
+		a = append(a, fmt.Sprintf("line %d", i))
+		b = append(b, fmt.Sprintf("line %d", i))
+	}
+	b[1] = "changed 2"
+	b[17] = "changed 18"
+
+	result := Compare("a", "b", strings.Join(a, "\n"), strings.Join(b, "\n"), 2)
+	if got := strings.Count(result.Unified, "@@ -"); got != 2 {
+		t.Fatalf("expected 2 hunks, got %d:\n%s", got, result.Unified)
+	}
+	if !strings.Contains(result.Unified, "@@ -1,4 +1,4 @@") || !strings.Contains(result.Unified, "@@ -16,5 +16,5 @@") {
+		t.Errorf("unexpected hunk headers:\n%s", result.Unified)
+	}
+}
+
+func TestCompareEmptySides(t *testing.T) {
+	result := Compare("a", "b", "", "new\n", 3)
+	if !strings.Contains(result.Unified, "@@ -0,0 +1,1 @@\n+new\n") || result.Added != 1 {
+		t.Errorf("unexpected diff for added file:\n%s", result.Unified)
+	}
+
+	result = Compare("a", "b", "old\n", "", 3)
+	if !strings.Contains(result.Unified, "@@ -1,1 +0,0 @@\n-old\n") || result.Removed != 1 {
+		t.Errorf("unexpected diff for removed file:\n%s", result.Unified)
+	}
+}